@@ -6,14 +6,14 @@ import (
 	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 )
 
-func ErrAttr(err error) slog.Attr {
-	return core.ErrAttr(err)
+// ErrChainAttr renders the complete unwrap chain of err with error types and messages
+// Text output shows a compact "type: message -> type: message" line, JSON output an array
+func ErrChainAttr(err error) slog.Attr {
+	return core.ErrChainAttr(err)
 }
 
-func GetLvlFromStr(s string) slog.Level {
-	return core.GetLvlFromStr(s)
-}
-
-func GetBoolFromStr(s string) bool {
-	return core.GetBoolFromStr(s)
+// JSONAttr renders an arbitrary value as a JSON string attribute with cycle detection,
+// unexported fields, panic safety and a size cap (see core.DefaultJSONAttrMaxBytes)
+func JSONAttr(key string, v any) slog.Attr {
+	return core.JSONAttr(key, v)
 }
@@ -0,0 +1,219 @@
+package logbundle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// BatchConfig configures StartBatch
+type BatchConfig struct {
+	// Logger for batch logs (if nil, uses the default bundle logger)
+	Logger *slog.Logger
+	// EnqueuedAt is when the oldest item of the batch was enqueued; when set, the consumer lag is
+	// logged at start and exported as the queue_consumer_lag_seconds{batch} gauge
+	EnqueuedAt time.Time
+	// ProgressInterval is the minimum time between progress logs of long batches (default: 10s)
+	ProgressInterval time.Duration
+	// MaxErrorGroups bounds the distinct error messages in the summary (default: 10)
+	MaxErrorGroups int
+	// MaxErrors bounds the item errors kept for the returned lgerr.Aggregate (default: 1000); its message,
+	// detail and "failed" context still count every failed item
+	MaxErrors int
+}
+
+// BatchErrorGroup is one distinct item error in the batch summary
+type BatchErrorGroup struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// BatchLogger logs one batch of a queue consumer or worker: a start log with the size (and lag),
+// rate-limited progress logs, and a single summary with per-item errors aggregated by message
+// instead of one log per failed item. It is safe for concurrent use
+type BatchLogger struct {
+	ctx   context.Context
+	name  string
+	total int
+	cfg   BatchConfig
+	start time.Time
+
+	mu           sync.Mutex
+	processed    int
+	failed       int
+	errs         []error
+	groups       map[string]int
+	otherErrors  int
+	lastProgress time.Time
+	finished     bool
+}
+
+// StartBatch logs the start of a batch of size items and returns its logger
+// Report every item with Item and call Finish once the batch is done
+//
+// Usage:
+//
+//	batch := logbundle.StartBatch(ctx, "import_orders", len(msgs), logbundle.BatchConfig{EnqueuedAt: msgs[0].Timestamp})
+//	for _, m := range msgs {
+//	    batch.Item(importOrder(ctx, m))
+//	}
+//	if err := batch.Finish(); err != nil {
+//	    // err is an *lgerr.Error (lgerr.Aggregate) wrapping the item errors
+//	}
+func StartBatch(ctx context.Context, name string, size int, cfg ...BatchConfig) *BatchLogger {
+	var c BatchConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Logger == nil {
+		c.Logger = Default().Logger()
+	}
+	if c.ProgressInterval <= 0 {
+		c.ProgressInterval = 10 * time.Second
+	}
+	if c.MaxErrorGroups <= 0 {
+		c.MaxErrorGroups = 10
+	}
+	if c.MaxErrors <= 0 {
+		c.MaxErrors = 1000
+	}
+
+	b := &BatchLogger{ctx: ctx, name: name, total: size, cfg: c, start: core.Now()}
+	b.lastProgress = b.start
+
+	attrs := []any{
+		slog.String("batch", name),
+		slog.Int("size", size),
+	}
+	if !c.EnqueuedAt.IsZero() {
+		lag := b.start.Sub(c.EnqueuedAt)
+		attrs = append(attrs, slog.Duration("lag", lag))
+		metrics.SetGauge("queue_consumer_lag_seconds", metrics.Labels{"batch": name}, lag.Seconds())
+	}
+	c.Logger.InfoContext(ctx, "Batch started", attrs...)
+
+	return b
+}
+
+// Item reports a processed item; a nil err is a success
+func (b *BatchLogger) Item(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.processed++
+	if err != nil {
+		b.failed++
+		if len(b.errs) < b.cfg.MaxErrors {
+			b.errs = append(b.errs, err)
+		}
+
+		msg := err.Error()
+		if b.groups == nil {
+			b.groups = make(map[string]int, b.cfg.MaxErrorGroups)
+		}
+		if _, ok := b.groups[msg]; ok || len(b.groups) < b.cfg.MaxErrorGroups {
+			b.groups[msg]++
+		} else {
+			b.otherErrors++
+		}
+	}
+
+	if now := core.Now(); now.Sub(b.lastProgress) >= b.cfg.ProgressInterval && b.processed < b.total {
+		b.lastProgress = now
+		b.logProgress(now)
+	}
+}
+
+// logProgress logs the progress with the throughput and estimated remaining time (caller holds b.mu)
+func (b *BatchLogger) logProgress(now time.Time) {
+	elapsed := now.Sub(b.start)
+	rate := float64(b.processed) / elapsed.Seconds()
+
+	attrs := []any{
+		slog.String("batch", b.name),
+		slog.Int("processed", b.processed),
+		slog.Int("size", b.total),
+		slog.Int("failed", b.failed),
+		slog.Float64("items_per_second", rate),
+	}
+	if rate > 0 && b.total > b.processed {
+		attrs = append(attrs, slog.Duration("eta", time.Duration(float64(b.total-b.processed)/rate*float64(time.Second))))
+	}
+	b.cfg.Logger.InfoContext(b.ctx, "Batch progress", attrs...)
+}
+
+// Finish logs the batch summary and returns nil if every item succeeded, otherwise an lgerr.Aggregate
+// wrapping the item errors. The summary is logged at Info, at Warn on partial failure and at Error when
+// every item failed. Later calls return nil without logging
+func (b *BatchLogger) Finish() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished {
+		return nil
+	}
+	b.finished = true
+
+	duration := core.Since(b.start)
+	labels := metrics.Labels{"batch": b.name}
+	metrics.Observe("batch_duration_ms", labels, float64(duration.Microseconds())/1000)
+	metrics.AddCounter("batch_items_total", metrics.Labels{"batch": b.name, "result": "success"}, float64(b.processed-b.failed))
+	metrics.AddCounter("batch_items_total", metrics.Labels{"batch": b.name, "result": "failure"}, float64(b.failed))
+
+	attrs := []any{
+		slog.String("batch", b.name),
+		slog.Int("size", b.total),
+		slog.Int("processed", b.processed),
+		slog.Int("succeeded", b.processed-b.failed),
+		slog.Int("failed", b.failed),
+		slog.Duration("duration", duration),
+	}
+
+	if b.failed == 0 {
+		b.cfg.Logger.InfoContext(b.ctx, "Batch finished", attrs...)
+		return nil
+	}
+
+	attrs = append(attrs, slog.Any("errors", b.errorGroups()))
+	level := slog.LevelWarn
+	if b.failed == b.processed {
+		level = slog.LevelError
+	}
+	b.cfg.Logger.Log(b.ctx, level, "Batch finished with failures", attrs...)
+
+	// Aggregate counts the errors it is given, which MaxErrors may have capped, so the real count is set here
+	opts := []lgerr.ErrorOption{
+		lgerr.WithMessage(fmt.Sprintf("%s: %d of %d items failed", b.name, b.failed, b.processed)),
+		lgerr.WithDetail(fmt.Sprintf("%d of %d items could not be processed", b.failed, b.processed)),
+		lgerr.WithContext("failed", b.failed),
+	}
+	if len(b.errs) < b.failed {
+		opts = append(opts, lgerr.WithContext("errors_kept", len(b.errs)))
+	}
+	return lgerr.Aggregate(b.name, b.processed, b.errs, opts...)
+}
+
+// errorGroups returns the distinct item errors, most frequent first (caller holds b.mu)
+func (b *BatchLogger) errorGroups() []BatchErrorGroup {
+	groups := make([]BatchErrorGroup, 0, len(b.groups)+1)
+	for msg, count := range b.groups {
+		groups = append(groups, BatchErrorGroup{Error: msg, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Error < groups[j].Error
+	})
+	if b.otherErrors > 0 {
+		groups = append(groups, BatchErrorGroup{Error: "other", Count: b.otherErrors})
+	}
+	return groups
+}
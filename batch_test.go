@@ -0,0 +1,55 @@
+package logbundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+func TestBatchFinishCountsEveryFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxErrors int
+		failures  int
+		wantKept  any
+	}{
+		{name: "below cap", maxErrors: 10, failures: 3, wantKept: nil},
+		{name: "capped", maxErrors: 2, failures: 5, wantKept: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			batch := StartBatch(context.Background(), "import", 8, BatchConfig{
+				Logger:    slog.New(slog.NewTextHandler(&buf, nil)),
+				MaxErrors: tt.maxErrors,
+			})
+			for i := range 8 {
+				var err error
+				if i < tt.failures {
+					err = fmt.Errorf("item %d: %w", i, errors.New("invalid"))
+				}
+				batch.Item(err)
+			}
+
+			var lgErr *lgerr.Error
+			if !errors.As(batch.Finish(), &lgErr) {
+				t.Fatal("Finish did not return an *lgerr.Error")
+			}
+			wantDetail := fmt.Sprintf("%d of 8 items could not be processed", tt.failures)
+			if lgErr.Detail() != wantDetail {
+				t.Fatalf("detail = %q, want %q", lgErr.Detail(), wantDetail)
+			}
+			if got := lgErr.Context()["failed"]; got != tt.failures {
+				t.Fatalf("failed context = %v, want %d", got, tt.failures)
+			}
+			if got := lgErr.Context()["errors_kept"]; got != tt.wantKept {
+				t.Fatalf("errors_kept context = %v, want %v", got, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package benchmarks holds logbundle's hot-path benchmarks and their allocation budgets
+//
+// Usage:
+//
+//	go test ./benchmarks -bench . -benchmem
+//	go test ./benchmarks -run TestAllocationBudgets
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgfiber"
+)
+
+// Case is a benchmarked hot path with its allocation budget
+// BenchmarkHotPaths measures every case and TestAllocationBudgets fails when one exceeds its budget
+type Case struct {
+	Name string
+	// MaxAllocs caps allocations per operation; raising it should be a deliberate decision
+	MaxAllocs float64
+	// Setup prepares the case and returns the operation to measure
+	Setup func() func()
+}
+
+// Cases returns the benchmark suite
+func Cases() []Case {
+	return []Case{
+		{Name: "handler/text", MaxAllocs: 24, Setup: setupHandlerText},
+		{Name: "handler/trace_id", MaxAllocs: 24, Setup: setupHandlerTraceID},
+		{Name: "lgfiber/body_validation", MaxAllocs: 12, Setup: setupBodyValidation},
+		{Name: "lgfiber/error_handler", MaxAllocs: 90, Setup: setupErrorHandler},
+		{Name: "lgfiber/sentry_capture", MaxAllocs: 160, Setup: setupSentryCapture},
+	}
+}
+
+func setupHandlerText() func() {
+	log := slog.New(handler.NewCustomHandler(io.Discard, slog.LevelInfo, false))
+	ctx := context.Background()
+	return func() {
+		log.LogAttrs(ctx, slog.LevelInfo, "request handled",
+			slog.String("route", "/users/:id"),
+			slog.Int("status_code", 200),
+			slog.String("method", "GET"),
+		)
+	}
+}
+
+func setupHandlerTraceID() func() {
+	log := slog.New(handler.NewTraceIDHandler(handler.NewCustomHandler(io.Discard, slog.LevelInfo, false)))
+	ctx := core.WithTraceID(context.Background(), core.NewTraceID())
+	return func() {
+		log.LogAttrs(ctx, slog.LevelInfo, "request handled",
+			slog.String("route", "/users/:id"),
+			slog.Int("status_code", 200),
+		)
+	}
+}
+
+type createUserRequest struct {
+	Name  string `json:"name" validate:"required,min=2"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"gte=0,lte=150"`
+}
+
+func setupBodyValidation() func() {
+	app := fiber.New()
+	app.Post("/users", lgfiber.BodyValidationMiddleware[createUserRequest](), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	h := app.Handler()
+	body := []byte(`{"name":"Ada","email":"ada@example.com","age":36}`)
+
+	var fctx fasthttp.RequestCtx
+	return func() {
+		fctx.Request.Reset()
+		fctx.Response.Reset()
+		fctx.Request.Header.SetMethod(fiber.MethodPost)
+		fctx.Request.Header.SetContentType(fiber.MIMEApplicationJSON)
+		fctx.Request.SetRequestURI("/users")
+		fctx.Request.SetBody(body)
+		h(&fctx)
+	}
+}
+
+func setupErrorHandler() func() {
+	config.Default().SetMiddlewareLogger(slog.New(handler.NewCustomHandler(io.Discard, slog.LevelInfo, false)))
+
+	app := fiber.New(fiber.Config{ErrorHandler: lgfiber.ErrorHandler})
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return lgerr.NotFound("user", c.Params("id"))
+	})
+	h := app.Handler()
+
+	var fctx fasthttp.RequestCtx
+	return func() {
+		fctx.Request.Reset()
+		fctx.Response.Reset()
+		fctx.Request.SetRequestURI("/users/42")
+		h(&fctx)
+	}
+}
+
+// discardTransport drops events so the capture path is measured without network I/O
+type discardTransport struct{}
+
+func (discardTransport) Configure(sentry.ClientOptions)          {}
+func (discardTransport) SendEvent(*sentry.Event)                 {}
+func (discardTransport) Flush(_ time.Duration) bool              { return true }
+func (discardTransport) FlushWithContext(_ context.Context) bool { return true }
+func (discardTransport) Close()                                  {}
+
+func setupSentryCapture() func() {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:       "https://key@example.com/1",
+		Transport: discardTransport{},
+	})
+	if err != nil {
+		panic(err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+	settings := config.NewSettings()
+	settings.SetSentryEnabled(true)
+	settings.SetMiddlewareLogger(slog.New(handler.NewCustomHandler(io.Discard, slog.LevelInfo, false)))
+	ctx := config.WithSettings(sentry.SetHubOnContext(context.Background(), hub), settings)
+
+	cause := errors.New("connection refused")
+	return func() {
+		lgfiber.HandleError(ctx, lgerr.Database("query failed").Wrap(cause))
+	}
+}
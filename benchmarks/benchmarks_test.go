@@ -0,0 +1,31 @@
+package benchmarks
+
+import (
+	"testing"
+)
+
+func BenchmarkHotPaths(b *testing.B) {
+	for _, c := range Cases() {
+		b.Run(c.Name, func(b *testing.B) {
+			op := c.Setup()
+			b.ReportAllocs()
+			for b.Loop() {
+				op()
+			}
+		})
+	}
+}
+
+func TestAllocationBudgets(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector adds allocations")
+	}
+	for _, c := range Cases() {
+		t.Run(c.Name, func(t *testing.T) {
+			op := c.Setup()
+			if allocs := testing.AllocsPerRun(200, op); allocs > c.MaxAllocs {
+				t.Errorf("%.1f allocs/op, budget %.0f", allocs, c.MaxAllocs)
+			}
+		})
+	}
+}
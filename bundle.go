@@ -0,0 +1,387 @@
+package logbundle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// LogBundle is an isolated logbundle instance: a logger plus the settings used by middlewares and
+// integrations (middleware logger, Sentry enablement and minimum status). The package-level functions
+// operate on Default(); libraries and tests can build their own instances instead of mutating globals
+//
+// The Sentry SDK client itself stays process-wide; a bundle only controls whether and how logbundle reports to it
+type LogBundle struct {
+	logger   atomic.Pointer[slog.Logger] // Swapped atomically so re-initialization is safe while logging
+	settings *config.Settings
+
+	closersMu sync.Mutex
+	closers   []io.Closer // Outputs opened by Build, closed in order by Close
+}
+
+var defaultBundle = &LogBundle{settings: config.Default()}
+
+// Default returns the process-wide instance behind the package-level functions
+func Default() *LogBundle {
+	return defaultBundle
+}
+
+// Logger returns the bundle's logger
+// For Default() before InitLog this is the middleware logger if set, otherwise slog.Default()
+func (b *LogBundle) Logger() *slog.Logger {
+	if log := b.logger.Load(); log != nil {
+		return log
+	}
+	if log := b.settings.MiddlewareLogger(); log != nil {
+		return log
+	}
+	return slog.Default()
+}
+
+// SetLogger atomically replaces the bundle's logger; goroutines logging concurrently see either
+// the old or the new logger, never a partially initialized one. nil restores the fallback
+func (b *LogBundle) SetLogger(logger *slog.Logger) {
+	b.logger.Store(logger)
+}
+
+// Settings returns the bundle's settings, e.g. for lgfiber.SettingsMiddleware
+func (b *LogBundle) Settings() *config.Settings {
+	return b.settings
+}
+
+// Context returns ctx carrying the bundle's settings; logbundle code handling it uses this bundle
+// instead of the defaults (lgsentry captures, lgreport.HandleError, RecoverGoroutinePanic, ...)
+func (b *LogBundle) Context(ctx context.Context) context.Context {
+	return config.WithSettings(ctx, b.settings)
+}
+
+// SetMiddlewareLogger sets the logger used by middlewares handling this bundle's requests
+func (b *LogBundle) SetMiddlewareLogger(logger *slog.Logger) {
+	b.settings.SetMiddlewareLogger(logger)
+}
+
+// MiddlewareLogger returns the middleware logger, or nil if not set
+func (b *LogBundle) MiddlewareLogger() *slog.Logger {
+	return b.settings.MiddlewareLogger()
+}
+
+// IsSentryEnabled returns whether Sentry reporting is enabled for this bundle
+func (b *LogBundle) IsSentryEnabled() bool {
+	return b.settings.SentryEnabled()
+}
+
+// SetSentryEnabled enables or disables Sentry reporting for this bundle
+func (b *LogBundle) SetSentryEnabled(enabled bool) {
+	b.settings.SetSentryEnabled(enabled)
+}
+
+// SentryMinHTTPStatus returns the minimum HTTP status code reported to Sentry
+func (b *LogBundle) SentryMinHTTPStatus() int {
+	return b.settings.SentryMinHTTPStatus()
+}
+
+// SetSentryMinHTTPStatus sets the minimum HTTP status code reported to Sentry
+func (b *LogBundle) SetSentryMinHTTPStatus(minStatus int) {
+	b.settings.SetSentryMinHTTPStatus(minStatus)
+}
+
+// IPAnonymization returns whether client IPs are truncated for this bundle
+func (b *LogBundle) IPAnonymization() bool {
+	return b.settings.IPAnonymization()
+}
+
+// SetIPAnonymization enables or disables client IP truncation for this bundle
+func (b *LogBundle) SetIPAnonymization(enabled bool) {
+	b.settings.SetIPAnonymization(enabled)
+}
+
+// Flush waits until the outputs opened by Build (see Close) have written the records logged so far,
+// or until ctx is done
+func (b *LogBundle) Flush(ctx context.Context) error {
+	b.closersMu.Lock()
+	closers := b.closers
+	b.closersMu.Unlock()
+
+	var errs []error
+	for _, c := range closers {
+		switch f := c.(type) {
+		case interface{ Flush(context.Context) error }:
+			errs = append(errs, f.Flush(ctx))
+		case interface{ Flush() error }:
+			errs = append(errs, f.Flush())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes and closes the outputs opened by Build, e.g. the handler.TeeWriter of WithSinks, and
+// stops their goroutines; writers passed to WithOutput or in a Sink stay open. Records logged afterwards
+// are dropped. Close returns ctx.Err() when ctx is done before the outputs are drained; only the first
+// call has an effect
+//
+// Usage:
+//
+//	bundle := logbundle.New(logbundle.WithSink(handler.Sink{Name: "collector", Writer: httpSink}))
+//	defer func() {
+//	    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	    defer cancel()
+//	    _ = bundle.Close(ctx)
+//	}()
+func (b *LogBundle) Close(ctx context.Context) error {
+	b.closersMu.Lock()
+	closers := b.closers
+	b.closers = nil
+	b.closersMu.Unlock()
+
+	if len(closers) == 0 {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		for _, c := range closers {
+			errs = append(errs, c.Close())
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Builder configures and creates a LogBundle
+type Builder struct {
+	loggerConfig  LoggerConfig
+	output        io.Writer
+	outputSet     bool // WithOutput was called, so output is written next to sinks and level outputs
+	sinks         []handler.Sink
+	closers       []io.Closer // Outputs the bundle owns, e.g. files opened for a BundleConfig
+	sentryEnabled bool
+	minHTTPStatus *int
+	anonymizeIPs  bool
+}
+
+// NewBuilder starts building an isolated LogBundle
+//
+// Usage:
+//
+//	bundle := logbundle.NewBuilder().
+//	    WithLevel(slog.LevelDebug).
+//	    WithOutput(&buf).
+//	    Build()
+//
+//	app.Use(lgfiber.SettingsMiddleware(bundle.Settings()))
+//	bundle.Logger().Info("ready")
+func NewBuilder() *Builder {
+	return &Builder{
+		loggerConfig: LoggerConfig{Level: slog.LevelInfo},
+		output:       os.Stdout,
+	}
+}
+
+// WithConfig replaces the logger configuration
+func (b *Builder) WithConfig(loggerConfig LoggerConfig) *Builder {
+	b.loggerConfig = loggerConfig
+	return b
+}
+
+// WithLevel sets the minimum log level (default: Info)
+func (b *Builder) WithLevel(level slog.Level) *Builder {
+	b.loggerConfig.Level = level
+	return b
+}
+
+// WithSource includes source file and line in records
+func (b *Builder) WithSource(addSource bool) *Builder {
+	b.loggerConfig.AddSource = addSource
+	return b
+}
+
+// WithFormat sets the output format (default: handler.FormatText)
+func (b *Builder) WithFormat(format handler.Format) *Builder {
+	b.loggerConfig.Format = format
+	return b
+}
+
+// WithRuntimeMetadata appends host and process metadata to every record
+func (b *Builder) WithRuntimeMetadata(enabled bool) *Builder {
+	b.loggerConfig.AddRuntimeMetadata = enabled
+	return b
+}
+
+// WithAttrSchema enables strict attribute checking against the schema
+func (b *Builder) WithAttrSchema(schema *handler.AttrSchema) *Builder {
+	b.loggerConfig.AttrSchema = schema
+	return b
+}
+
+// WithLargeAttrs compacts attribute values larger than the configured threshold
+func (b *Builder) WithLargeAttrs(opts handler.LargeAttrOptions) *Builder {
+	b.loggerConfig.LargeAttrs = &opts
+	return b
+}
+
+// WithSecretDetection masks likely secrets in messages and attributes
+func (b *Builder) WithSecretDetection(enabled bool) *Builder {
+	b.loggerConfig.DetectSecrets = enabled
+	return b
+}
+
+// WithPII sets how attributes tagged with PII are written (see handler.PIIOptions)
+func (b *Builder) WithPII(opts handler.PIIOptions) *Builder {
+	b.loggerConfig.PII = &opts
+	return b
+}
+
+// WithDeltaAttrs omits attributes repeated within a trace to cut the volume of chatty request flows
+func (b *Builder) WithDeltaAttrs(opts handler.DeltaOptions) *Builder {
+	b.loggerConfig.DeltaAttrs = &opts
+	return b
+}
+
+// WithSampler writes only a fraction of the records below opts.KeepLevel, keeping or dropping whole
+// traces (see handler.SamplingHandler)
+func (b *Builder) WithSampler(opts handler.SamplingOptions) *Builder {
+	b.loggerConfig.Sampling = &opts
+	return b
+}
+
+// WithOutput sets the log destination (default: os.Stdout, unless sinks or level outputs are set)
+func (b *Builder) WithOutput(w io.Writer) *Builder {
+	b.output = w
+	b.outputSet = true
+	return b
+}
+
+// WithSinks adds outputs written through a handler.TeeWriter, next to WithOutput and WithLevelOutputs
+// when those are set; the TeeWriter is owned by the bundle (see LogBundle.Close)
+func (b *Builder) WithSinks(sinks ...handler.Sink) *Builder {
+	b.sinks = append(b.sinks, sinks...)
+	return b
+}
+
+// WithLevelOutputs routes records by level to several writers, next to WithOutput and WithSinks when
+// those are set
+//
+// Usage:
+//
+//	bundle := logbundle.NewBuilder().WithLevelOutputs(handler.StdStreamRoutes()...).Build()
+func (b *Builder) WithLevelOutputs(routes ...handler.LevelRoute) *Builder {
+	b.loggerConfig.LevelOutputs = routes
+	return b
+}
+
+// WithSentry enables Sentry reporting for the bundle (the SDK must be initialized separately)
+func (b *Builder) WithSentry(enabled bool) *Builder {
+	b.sentryEnabled = enabled
+	return b
+}
+
+// WithSentryMinHTTPStatus sets the minimum HTTP status code reported to Sentry (default: 500)
+func (b *Builder) WithSentryMinHTTPStatus(minStatus int) *Builder {
+	b.minHTTPStatus = &minStatus
+	return b
+}
+
+// WithIPAnonymization truncates client IPs in logs and Sentry data (default: false)
+func (b *Builder) WithIPAnonymization(enabled bool) *Builder {
+	b.anonymizeIPs = enabled
+	return b
+}
+
+// Build creates the LogBundle; its logger is also used as the bundle's middleware logger
+// Every record goes to each configured destination: the output (when set explicitly or nothing else
+// is), the sinks and the level outputs
+func (b *Builder) Build() *LogBundle {
+	var outputs []io.Writer
+	if b.outputSet || (len(b.sinks) == 0 && len(b.loggerConfig.LevelOutputs) == 0) {
+		outputs = append(outputs, b.output)
+	}
+	closers := slices.Clone(b.closers)
+	if len(b.sinks) > 0 {
+		tee := handler.NewTeeWriter(b.sinks)
+		outputs = append(outputs, tee)
+		// Closed before the other outputs, which may be the writers of its sinks
+		closers = append([]io.Closer{tee}, closers...)
+	}
+	logger := newLogger(b.loggerConfig, outputs...)
+
+	settings := config.NewSettings()
+	settings.SetMiddlewareLogger(logger)
+	settings.SetSentryEnabled(b.sentryEnabled)
+	settings.SetIPAnonymization(b.anonymizeIPs)
+	if b.minHTTPStatus != nil {
+		settings.SetSentryMinHTTPStatus(*b.minHTTPStatus)
+	}
+
+	bundle := &LogBundle{settings: settings, closers: closers}
+	bundle.logger.Store(logger)
+	return bundle
+}
+
+// newLogger creates a logger with logbundle's handler and trace ID injection, writing every record to
+// the level outputs of loggerConfig and to each of outputs
+func newLogger(loggerConfig LoggerConfig, outputs ...io.Writer) *slog.Logger {
+	opts := handler.HandlerOptions{
+		Level:              loggerConfig.Level,
+		AddSource:          loggerConfig.AddSource,
+		AddRuntimeMetadata: loggerConfig.AddRuntimeMetadata,
+		Format:             loggerConfig.Format,
+	}
+	var handlers []slog.Handler
+	if len(loggerConfig.LevelOutputs) > 0 {
+		handlers = append(handlers, handler.NewLevelRouterHandler(loggerConfig.LevelOutputs, opts))
+	}
+	for _, w := range outputs {
+		if tee, ok := w.(*handler.TeeWriter); ok {
+			// Sinks may override the format; enrichment below still runs once per record
+			handlers = append(handlers, handler.NewTeeHandler(tee, opts))
+		} else {
+			handlers = append(handlers, handler.NewCustomHandlerWithOptions(w, opts))
+		}
+	}
+
+	var h slog.Handler
+	switch len(handlers) {
+	case 0:
+		h = handler.NewCustomHandlerWithOptions(os.Stdout, opts)
+	case 1:
+		h = handlers[0]
+	default:
+		h = handler.NewFanoutHandler(handlers...)
+	}
+	if loggerConfig.DeltaAttrs != nil {
+		// Innermost, so values are compared after the handlers below masked, pseudonymized or dropped them
+		h = handler.NewDeltaHandler(h, *loggerConfig.DeltaAttrs)
+	}
+	if loggerConfig.LargeAttrs != nil {
+		h = handler.NewLargeAttrHandler(h, *loggerConfig.LargeAttrs)
+	}
+	if loggerConfig.DetectSecrets {
+		h = handler.NewSecretsHandler(h)
+	}
+	if loggerConfig.AttrSchema != nil {
+		h = handler.NewSchemaHandler(h, loggerConfig.AttrSchema)
+	}
+	if loggerConfig.PII != nil {
+		h = handler.NewPIIHandler(h, *loggerConfig.PII)
+	}
+	if loggerConfig.Sampling != nil {
+		// Outermost, so records sampled out skip the redaction work
+		h = handler.NewSamplingHandler(h, *loggerConfig.Sampling)
+	}
+	return slog.New(handler.NewTraceIDHandler(h))
+}
@@ -0,0 +1,419 @@
+package logbundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// BundleConfig is the file schema of InitFromConfigFile, covering the whole bundle so platform teams can
+// standardize logging through config management. Files ending in .yaml or .yml are read as YAML, others
+// as JSON; both use the keys below, and unknown keys are rejected. Omitted fields keep the preset (or
+// builder) defaults
+//
+// Example file:
+//
+//	{
+//	    "preset": "production",
+//	    "logging": {"level": "info", "format": "json", "module_levels": "github.com/ourapp/billing=debug"},
+//	    "sinks": [
+//	        {"name": "stdout", "type": "stdout"},
+//	        {"name": "collector", "type": "http", "url": "https://logs.internal/ingest", "compression": "zstd"}
+//	    ],
+//	    "sampling": {"rate": 0.1, "keep_level": "warn"},
+//	    "redaction": {"detect_secrets": true, "pii": "pseudonymize", "pii_salt_env": "LOG_PII_SALT"},
+//	    "sentry": {"enabled": true, "min_http_status": 500, "anonymize_ips": true},
+//	    "fiber": {"recover": true, "sentry": true, "trace_id": true, "metrics": true, "skip_paths": ["/health"]}
+//	}
+//
+// or in YAML:
+//
+//	preset: production
+//	logging:
+//	  level: info
+//	sampling:
+//	  rate: 0.1
+//	sinks:
+//	  - {name: stdout, type: stdout}
+type BundleConfig struct {
+	// Preset is the base configuration: production, staging, development or test (see Preset)
+	Preset    string                  `json:"preset,omitempty"`
+	Logging   LoggingFileConfig       `json:"logging"`
+	Sinks     []SinkFileConfig        `json:"sinks,omitempty"`
+	Sampling  *SamplingFileConfig     `json:"sampling,omitempty"`
+	Redaction RedactionFileConfig     `json:"redaction"`
+	Sentry    SentryFileConfig        `json:"sentry"`
+	Fiber     config.FiberMiddlewares `json:"fiber"`
+}
+
+// LoggingFileConfig holds the logger settings of a BundleConfig
+type LoggingFileConfig struct {
+	// Level is debug, info, warn or error
+	Level string `json:"level,omitempty"`
+	// Format is text or json
+	Format          string `json:"format,omitempty"`
+	AddSource       *bool  `json:"add_source,omitempty"`
+	RuntimeMetadata *bool  `json:"runtime_metadata,omitempty"`
+	// ModuleLevels overrides levels per package (see core.SetModuleLevels); applied by InitFromConfigFile
+	ModuleLevels string `json:"module_levels,omitempty"`
+}
+
+// SinkFileConfig is an output of a BundleConfig; several sinks are combined in a handler.TeeWriter
+type SinkFileConfig struct {
+	Name string `json:"name"`
+	// Type is stdout, stderr, file or http
+	Type string `json:"type"`
+	// Path of a file sink, opened for appending (created with mode 0600)
+	Path string `json:"path,omitempty"`
+	// URL of an http sink (see handler.HTTPSink)
+	URL string `json:"url,omitempty"`
+	// Compression of an http sink: gzip, zstd or empty
+	Compression string `json:"compression,omitempty"`
+	// Format overrides the logging format for this sink: text or json
+	Format string `json:"format,omitempty"`
+	// Color writes colorized console lines
+	Color bool `json:"color,omitempty"`
+}
+
+// SamplingFileConfig holds the sampling settings of a BundleConfig (see handler.SamplingHandler)
+type SamplingFileConfig struct {
+	// Rate is the fraction of traces whose records below KeepLevel are written, from 0 to 1
+	Rate float64 `json:"rate"`
+	// KeepLevel is the level from which every record is written: debug, info, warn or error (default: warn)
+	KeepLevel string `json:"keep_level,omitempty"`
+}
+
+// RedactionFileConfig holds the redaction settings of a BundleConfig
+type RedactionFileConfig struct {
+	DetectSecrets *bool `json:"detect_secrets,omitempty"`
+	// PII is keep, pseudonymize or drop
+	PII string `json:"pii,omitempty"`
+	// PIISaltEnv names the environment variable holding the pseudonymization salt (required for pseudonymize)
+	PIISaltEnv string `json:"pii_salt_env,omitempty"`
+	// QueryDenyList replaces the scrubbed query parameters; applied by InitFromConfigFile
+	QueryDenyList []string `json:"query_deny_list,omitempty"`
+}
+
+// SentryFileConfig holds the Sentry settings of a BundleConfig
+type SentryFileConfig struct {
+	Enabled       *bool `json:"enabled,omitempty"`
+	MinHTTPStatus *int  `json:"min_http_status,omitempty"`
+	AnonymizeIPs  *bool `json:"anonymize_ips,omitempty"`
+}
+
+// LoadBundleConfig reads and validates a BundleConfig file without applying it
+// Validation errors name the offending field, e.g. `sinks[1].url: required for type "http"`
+func LoadBundleConfig(path string) (BundleConfig, error) {
+	var c BundleConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return c, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if err := decodeBundleConfig(data, &c); err != nil {
+			return c, fmt.Errorf("parse %s: %w", path, err)
+		}
+	default:
+		if err := decodeBundleConfig(data, &c); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line := 1 + bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+				return c, fmt.Errorf("parse %s: line %d: %w", path, line, err)
+			}
+			return c, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return c, fmt.Errorf("invalid %s:\n%w", path, err)
+	}
+	return c, nil
+}
+
+// decodeBundleConfig decodes a single JSON document, rejecting unknown keys and trailing data
+func decodeBundleConfig(data []byte, c *BundleConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after the configuration object")
+	}
+	return nil
+}
+
+// yamlToJSON converts a single YAML document to JSON, so both formats share the json tags and the
+// unknown-key check of decodeBundleConfig
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&doc); err != nil && err != io.EOF {
+		return nil, err
+	}
+	var extra any
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("unexpected second YAML document")
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return json.Marshal(doc)
+}
+
+// Validate checks the configuration and returns every problem found, one per line
+func (c BundleConfig) Validate() error {
+	var errs []error
+	check := func(field string, ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+		}
+	}
+	oneOf := func(field, value string, allowed ...string) {
+		if value == "" {
+			return
+		}
+		for _, a := range allowed {
+			if strings.EqualFold(value, a) {
+				return
+			}
+		}
+		check(field, false, "unknown value %q (want %s)", value, strings.Join(allowed, ", "))
+	}
+
+	oneOf("preset", c.Preset, PresetProduction, PresetStaging, PresetDevelopment, PresetTest)
+	if c.Logging.Level != "" {
+		_, err := core.ParseLevel(c.Logging.Level)
+		check("logging.level", err == nil, "unknown level %q (want debug, info, warn, error)", c.Logging.Level)
+	}
+	oneOf("logging.format", c.Logging.Format, "text", "json")
+	if err := core.ValidateModuleLevels(c.Logging.ModuleLevels); err != nil {
+		check("logging.module_levels", false, "%v", err)
+	}
+
+	names := make(map[string]bool, len(c.Sinks))
+	for i, s := range c.Sinks {
+		field := fmt.Sprintf("sinks[%d]", i)
+		check(field+".name", s.Name != "", "required")
+		check(field+".name", !names[s.Name], "duplicate sink %q", s.Name)
+		names[s.Name] = true
+		oneOf(field+".type", s.Type, "stdout", "stderr", "file", "http")
+		check(field+".type", s.Type != "", "required")
+		check(field+".path", s.Type != "file" || s.Path != "", "required for type \"file\"")
+		check(field+".url", s.Type != "http" || s.URL != "", "required for type \"http\"")
+		oneOf(field+".compression", s.Compression, "gzip", "zstd")
+		check(field+".compression", s.Compression == "" || s.Type == "http", "only supported for type \"http\"")
+		oneOf(field+".format", s.Format, "text", "json")
+	}
+
+	if c.Sampling != nil {
+		check("sampling.rate", c.Sampling.Rate >= 0 && c.Sampling.Rate <= 1, "%v is not between 0 and 1", c.Sampling.Rate)
+		if c.Sampling.KeepLevel != "" {
+			_, err := core.ParseLevel(c.Sampling.KeepLevel)
+			check("sampling.keep_level", err == nil, "unknown level %q (want debug, info, warn, error)", c.Sampling.KeepLevel)
+		}
+	}
+
+	oneOf("redaction.pii", c.Redaction.PII, "keep", "pseudonymize", "drop")
+	if strings.EqualFold(c.Redaction.PII, "pseudonymize") {
+		check("redaction.pii_salt_env", c.Redaction.PIISaltEnv != "", "required for pii \"pseudonymize\"")
+	}
+	return errors.Join(errs...)
+}
+
+// Builder returns a Builder configured from c, starting from its preset (or NewBuilder's defaults)
+// It opens the file and http sinks, which the bundle built from it owns and closes (see LogBundle.Close),
+// so build it once; process-wide settings (module levels, query deny-list) are only applied by
+// InitFromConfigFile
+func (c BundleConfig) Builder() (*Builder, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	b := NewBuilder()
+	if c.Preset != "" {
+		b = Preset(c.Preset)
+	}
+
+	lc := &b.loggerConfig
+	if c.Logging.Level != "" {
+		lc.Level, _ = core.ParseLevel(c.Logging.Level)
+	}
+	if c.Logging.Format != "" {
+		lc.Format = parseFileFormat(c.Logging.Format)
+	}
+	if c.Logging.AddSource != nil {
+		lc.AddSource = *c.Logging.AddSource
+	}
+	if c.Logging.RuntimeMetadata != nil {
+		lc.AddRuntimeMetadata = *c.Logging.RuntimeMetadata
+	}
+
+	if c.Sampling != nil {
+		opts := handler.SamplingOptions{Rate: c.Sampling.Rate}
+		if c.Sampling.KeepLevel != "" {
+			opts.KeepLevel, _ = core.ParseLevel(c.Sampling.KeepLevel)
+		}
+		lc.Sampling = &opts
+	}
+
+	if c.Redaction.DetectSecrets != nil {
+		lc.DetectSecrets = *c.Redaction.DetectSecrets
+	}
+	switch strings.ToLower(c.Redaction.PII) {
+	case "keep":
+		lc.PII = nil
+	case "drop":
+		lc.PII = &handler.PIIOptions{Mode: handler.PIIDrop}
+	case "pseudonymize":
+		salt := os.Getenv(c.Redaction.PIISaltEnv)
+		if salt == "" {
+			return nil, fmt.Errorf("redaction.pii_salt_env: $%s is empty", c.Redaction.PIISaltEnv)
+		}
+		lc.PII = &handler.PIIOptions{Mode: handler.PIIPseudonymize, Salt: []byte(salt)}
+	}
+
+	if c.Sentry.Enabled != nil {
+		b.WithSentry(*c.Sentry.Enabled)
+	}
+	if c.Sentry.MinHTTPStatus != nil {
+		b.WithSentryMinHTTPStatus(*c.Sentry.MinHTTPStatus)
+	}
+	if c.Sentry.AnonymizeIPs != nil {
+		b.WithIPAnonymization(*c.Sentry.AnonymizeIPs)
+	}
+
+	if len(c.Sinks) > 0 {
+		output, closers, err := c.output()
+		if err != nil {
+			return nil, err
+		}
+		b.WithOutput(output)
+		b.closers = append(b.closers, closers...)
+	}
+	return b, nil
+}
+
+// output opens the sinks; a single sink without overrides is written directly, others through a TeeWriter
+// closers close the TeeWriter and the opened files and http sinks, in that order. When a sink fails to
+// open, the ones already opened are closed
+func (c BundleConfig) output() (_ io.Writer, closers []io.Closer, err error) {
+	sinks := make([]handler.Sink, 0, len(c.Sinks))
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, closer := range closers {
+			_ = closer.Close()
+		}
+	}()
+
+	for i, s := range c.Sinks {
+		var w io.Writer
+		switch strings.ToLower(s.Type) {
+		case "stdout":
+			w = os.Stdout
+		case "stderr":
+			w = os.Stderr
+		case "file":
+			f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+			if err != nil {
+				return nil, closers, fmt.Errorf("sinks[%d]: %w", i, err)
+			}
+			w = f
+			closers = append(closers, f)
+		case "http":
+			sink, err := handler.NewHTTPSink(handler.HTTPSinkOptions{
+				Name:        s.Name,
+				URL:         s.URL,
+				Compression: handler.Compression(strings.ToLower(s.Compression)),
+			})
+			if err != nil {
+				return nil, closers, fmt.Errorf("sinks[%d]: %w", i, err)
+			}
+			w = sink
+			closers = append(closers, sink)
+		}
+
+		sink := handler.Sink{Name: s.Name, Writer: w, Color: s.Color}
+		if s.Format != "" {
+			format := parseFileFormat(s.Format)
+			sink.Format = &format
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) == 1 && sinks[0].Format == nil && !sinks[0].Color && c.Sinks[0].Type != "http" {
+		return sinks[0].Writer, closers, nil
+	}
+	tee := handler.NewTeeWriter(sinks)
+	// The TeeWriter drains into the sinks, so it is closed first
+	return tee, append([]io.Closer{tee}, closers...), nil
+}
+
+// parseFileFormat maps a validated format name
+func parseFileFormat(name string) handler.Format {
+	if strings.EqualFold(name, "json") {
+		return handler.FormatJSON
+	}
+	return handler.FormatText
+}
+
+// InitFromConfigFile loads a BundleConfig file (JSON or YAML) and installs it on Default(): the logger (also used by the
+// middlewares), the Sentry and IP settings, module levels and the query deny-list. Nothing is changed when
+// the file is invalid. The returned config carries the Fiber middleware toggles for lgfiber.Middlewares;
+// the returned bundle owns the file and http sinks Default() now writes to, close it on shutdown to
+// flush them
+//
+// Usage:
+//
+//	bundle, cfg, err := logbundle.InitFromConfigFile("/etc/app/logbundle.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer bundle.Close(context.Background())
+func InitFromConfigFile(path string) (*LogBundle, BundleConfig, error) {
+	c, err := LoadBundleConfig(path)
+	if err != nil {
+		return nil, c, err
+	}
+	b, err := c.Builder()
+	if err != nil {
+		return nil, c, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	bundle := b.Build()
+
+	// Already validated, so this cannot fail
+	_ = core.SetModuleLevels(c.Logging.ModuleLevels)
+	if len(c.Redaction.QueryDenyList) > 0 {
+		core.SetQueryDenyList(c.Redaction.QueryDenyList...)
+	}
+
+	d := Default()
+	d.SetLogger(bundle.Logger())
+	d.SetMiddlewareLogger(bundle.Logger())
+	d.SetSentryEnabled(bundle.IsSentryEnabled())
+	d.SetSentryMinHTTPStatus(bundle.SentryMinHTTPStatus())
+	d.SetIPAnonymization(bundle.IPAnonymization())
+	return bundle, c, nil
+}
@@ -0,0 +1,164 @@
+package logbundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBundleConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+		check   func(t *testing.T, c BundleConfig)
+	}{
+		{
+			name: "json",
+			file: "logbundle.json",
+			data: `{"logging": {"level": "debug"}, "sampling": {"rate": 0.25}}`,
+			check: func(t *testing.T, c BundleConfig) {
+				if c.Logging.Level != "debug" || c.Sampling == nil || c.Sampling.Rate != 0.25 {
+					t.Fatalf("unexpected config: %+v", c)
+				}
+			},
+		},
+		{
+			name: "yaml",
+			file: "logbundle.yaml",
+			data: "logging:\n  level: warn\nsampling:\n  rate: 0.5\n  keep_level: error\nsinks:\n  - {name: out, type: stdout}\n",
+			check: func(t *testing.T, c BundleConfig) {
+				if c.Logging.Level != "warn" || c.Sampling.KeepLevel != "error" || len(c.Sinks) != 1 {
+					t.Fatalf("unexpected config: %+v", c)
+				}
+			},
+		},
+		{
+			name: "min status zero reports all errors",
+			file: "logbundle.json",
+			data: `{"sentry": {"min_http_status": 0}}`,
+			check: func(t *testing.T, c BundleConfig) {
+				if c.Sentry.MinHTTPStatus == nil || *c.Sentry.MinHTTPStatus != 0 {
+					t.Fatalf("min_http_status = %v, want 0", c.Sentry.MinHTTPStatus)
+				}
+			},
+		},
+		{name: "json trailing data", file: "logbundle.json", data: `{"preset": "test"} {"preset": "production"}`, wantErr: "unexpected data"},
+		{name: "yaml second document", file: "logbundle.yml", data: "preset: test\n---\npreset: production\n", wantErr: "second YAML document"},
+		{name: "yaml unknown key", file: "logbundle.yaml", data: "logging:\n  colour: true\n", wantErr: "unknown field"},
+		{name: "sampling rate out of range", file: "logbundle.json", data: `{"sampling": {"rate": 2}}`, wantErr: "sampling.rate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			c, err := LoadBundleConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestBundleConfigClosesSinksOnError(t *testing.T) {
+	dir := t.TempDir()
+	c := BundleConfig{Sinks: []SinkFileConfig{
+		{Name: "audit", Type: "file", Path: filepath.Join(dir, "audit.log")},
+		{Name: "broken", Type: "file", Path: filepath.Join(dir, "missing", "app.log")},
+	}}
+	if _, _, err := c.output(); err == nil {
+		t.Fatal("output() succeeded with an unopenable sink")
+	}
+	if fd, open := openFD(t, "audit.log"); open {
+		t.Fatalf("audit.log still open as fd %s", fd)
+	}
+}
+
+func TestInitFromConfigFileReturnsClosableBundle(t *testing.T) {
+	d := Default()
+	prevLogger, prevMiddleware := d.logger.Load(), d.MiddlewareLogger()
+	prevSentry, prevMinStatus, prevIPs := d.IsSentryEnabled(), d.SentryMinHTTPStatus(), d.IPAnonymization()
+	t.Cleanup(func() {
+		d.SetLogger(prevLogger)
+		d.SetMiddlewareLogger(prevMiddleware)
+		d.SetSentryEnabled(prevSentry)
+		d.SetSentryMinHTTPStatus(prevMinStatus)
+		d.SetIPAnonymization(prevIPs)
+	})
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "closable.log")
+	cfgPath := filepath.Join(dir, "logbundle.json")
+	// The format override routes the file through a TeeWriter
+	data := fmt.Sprintf(`{"sinks": [{"name": "app", "type": "file", "path": %q, "format": "json"}]}`, logPath)
+	if err := os.WriteFile(cfgPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, _, err := InitFromConfigFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	GetLogger().Info("from config file")
+	if err := bundle.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "from config file") {
+		t.Fatalf("record not flushed by Close: %q", written)
+	}
+	if fd, open := openFD(t, "closable.log"); open {
+		t.Fatalf("closable.log still open as fd %s after Close", fd)
+	}
+}
+
+// openFD returns the descriptor of an open file whose path ends with suffix; skips without /proc
+func openFD(t *testing.T, suffix string) (string, bool) {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("no /proc/self/fd")
+	}
+	for _, e := range entries {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", e.Name())); err == nil && strings.HasSuffix(target, suffix) {
+			return e.Name(), true
+		}
+	}
+	return "", false
+}
+
+func TestBundleConfigFileSinkIsPrivate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	_, closers, err := BundleConfig{Sinks: []SinkFileConfig{{Name: "app", Type: "file", Path: path}}}.output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, closer := range closers {
+		defer closer.Close()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("file sink mode = %o, want 600", perm)
+	}
+}
@@ -0,0 +1,163 @@
+package logbundle
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+func TestDeltaAttrsKeepPIIRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(
+		WithOutput(&buf),
+		WithPII(handler.PIIOptions{Mode: handler.PIIDrop}),
+		WithDeltaAttrs(handler.DeltaOptions{}),
+	).Logger()
+
+	ctx := core.WithTraceID(context.Background(), "trace-1")
+	logger.InfoContext(ctx, "signup", core.PII("email", "alice@example.com"))
+	logger.InfoContext(ctx, "signup", core.PII("email", "alice@example.com"))
+
+	if strings.Contains(buf.String(), "alice@example.com") {
+		t.Fatalf("PII written with delta attributes enabled:\n%s", buf.String())
+	}
+}
+
+func TestDeltaAttrsIgnoreFilteredRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(
+		WithOutput(&buf),
+		WithDeltaAttrs(handler.DeltaOptions{}),
+	).Logger()
+
+	ctx := core.WithTraceID(context.Background(), "trace-2")
+	// Below the Info level: never written, so it must not count as the trace's previous value
+	hidden := slog.NewRecord(core.Now(), slog.LevelDebug, "hidden", 0)
+	hidden.AddAttrs(slog.String("route", "/orders"))
+	if err := logger.Handler().Handle(ctx, hidden); err != nil {
+		t.Fatal(err)
+	}
+	logger.InfoContext(ctx, "visible", slog.String("route", "/orders"))
+
+	if !strings.Contains(buf.String(), "route=/orders") {
+		t.Fatalf("route elided against a filtered record:\n%s", buf.String())
+	}
+}
+
+func TestWithSamplerKeepsCriticalRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(
+		WithOutput(&buf),
+		WithSampler(handler.SamplingOptions{Rate: 0}),
+	).Logger()
+
+	logger.Info("sampled out")
+	if err := LogCritical(context.Background(), logger, slog.LevelInfo, "audited"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Warn("kept")
+
+	out := buf.String()
+	if strings.Contains(out, "sampled out") || !strings.Contains(out, "audited") || !strings.Contains(out, "kept") {
+		t.Fatalf("unexpected sampling result:\n%s", out)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for the sink goroutines of a TeeWriter
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestBundleFlushAndCloseSinks(t *testing.T) {
+	var sink lockedBuffer
+	bundle := New(WithSink(handler.Sink{Name: "test", Writer: &sink}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bundle.Logger().Info("flushed")
+	if err := bundle.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sink.String(), "flushed") {
+		t.Fatalf("record not written after Flush: %q", sink.String())
+	}
+
+	bundle.Logger().Info("closed")
+	if err := bundle.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sink.String(), "closed") {
+		t.Fatalf("record not written after Close: %q", sink.String())
+	}
+
+	bundle.Logger().Info("after close")
+	if err := bundle.Close(ctx); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if strings.Contains(sink.String(), "after close") {
+		t.Fatal("record written after Close")
+	}
+}
+
+func TestBuildMergesOutputsSinksAndLevelOutputs(t *testing.T) {
+	var output, sink, infos, warnings lockedBuffer
+	bundle := New(
+		WithOutput(&output),
+		WithSink(handler.Sink{Name: "test", Writer: &sink}),
+		WithLevelOutputs(
+			handler.LevelRoute{MinLevel: slog.LevelDebug, Writer: &infos},
+			handler.LevelRoute{MinLevel: slog.LevelWarn, Writer: &warnings},
+		),
+	)
+	defer bundle.Close(context.Background())
+
+	bundle.Logger().Info("info record")
+	bundle.Logger().Warn("warn record")
+	if err := bundle.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tt := range map[string]struct {
+		buf  *lockedBuffer
+		want []string
+		skip []string
+	}{
+		"output":     {buf: &output, want: []string{"info record", "warn record"}},
+		"sink":       {buf: &sink, want: []string{"info record", "warn record"}},
+		"info route": {buf: &infos, want: []string{"info record"}, skip: []string{"warn record"}},
+		"warn route": {buf: &warnings, want: []string{"warn record"}, skip: []string{"info record"}},
+	} {
+		got := tt.buf.String()
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s misses %q:\n%s", name, want, got)
+			}
+		}
+		for _, skip := range tt.skip {
+			if strings.Contains(got, skip) {
+				t.Errorf("%s has %q:\n%s", name, skip, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Command logbundle-fmt pretty-prints JSON logs (FormatJSON) from stdin in the colorized console format
+//
+// Usage:
+//
+//	kubectl logs -f deploy/api | logbundle-fmt --level warn --trace 4bf92f3577b34da6a3ce929d0e0e4736
+//
+// Flags:
+//
+//	--level     minimum level (debug, info, warn, error)
+//	--trace     only records with this trace ID
+//	--route     only records with this route (e.g. /users/:id)
+//	--no-color  disable colors (default when stdout is not a terminal)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+func main() {
+	level := flag.String("level", "", "minimum level (debug, info, warn, error)")
+	traceID := flag.String("trace", "", "only records with this trace ID")
+	route := flag.String("route", "", "only records with this route")
+	noColor := flag.Bool("no-color", false, "disable colors")
+	flag.Parse()
+
+	opts := handler.PrettyOptions{
+		TraceID: *traceID,
+		Route:   *route,
+		Color:   !*noColor && isTerminal(os.Stdout),
+	}
+	if *level != "" {
+		l, err := core.ParseLevel(*level)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "logbundle-fmt:", err)
+			os.Exit(2)
+		}
+		opts.Level = l
+	}
+
+	if err := handler.PrettyPrint(os.Stdin, os.Stdout, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "logbundle-fmt:", err)
+		os.Exit(1)
+	}
+}
+
+// isTerminal reports whether f is a character device (an interactive terminal)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
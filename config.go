@@ -1,58 +1,108 @@
 package logbundle
 
 import (
+	"context"
+	"io"
 	"log/slog"
 	"os"
+	"time"
 
-	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
 )
 
 // LoggerConfig holds configuration options for creating a logger instance
 type LoggerConfig struct {
 	Level     slog.Level // Minimum log level to output (Debug, Info, Warn, Error)
 	AddSource bool       // Whether to include source file and line number in logs
+	// Format selects text (schema_version=1, default) or ECS-style JSON (schema_version "2") output
+	// Every record carries schema_version so parsers can branch during the migration
+	Format handler.Format
+	// AddRuntimeMetadata appends host.name, process.pid, go.version and app.version to every record
+	// Use lgsentry.EnableRuntimeMetadata() to attach the same metadata to Sentry events
+	AddRuntimeMetadata bool
+	// AttrSchema enables strict mode: attribute keys and types are checked against the schema
+	// and violations are reported (see handler.AttrSchema)
+	AttrSchema *handler.AttrSchema
+	// LargeAttrs compacts oversized attribute values (gzip+base64 inline, or offloaded to a blob sink)
+	LargeAttrs *handler.LargeAttrOptions
+	// DetectSecrets masks likely secrets (API keys, JWTs, private keys, high-entropy tokens) in messages and
+	// string attributes. Use lgsentry.EnableSecretScrubbing() to apply the same detection to Sentry events
+	DetectSecrets bool
+	// PII controls attributes tagged with PII: kept, pseudonymized with a (per-tenant) salt or dropped,
+	// optionally per environment (see handler.PIIOptions)
+	PII *handler.PIIOptions
+	// DeltaAttrs omits attributes repeating the previous value within a trace (see handler.DeltaHandler)
+	DeltaAttrs *handler.DeltaOptions
+	// Sampling writes only a fraction of the records below a level, per trace (see handler.SamplingHandler)
+	Sampling *handler.SamplingOptions
+	// LevelOutputs routes records by level to several writers, replacing the default stdout, e.g.
+	// handler.StdStreamRoutes() for Warn+ on stderr and the rest on stdout
+	LevelOutputs []handler.LevelRoute
 }
 
 // CreateLogger creates a new logger instance with the provided configuration
 // If setAsMiddlewareLogger is true, this logger will be used by all middlewares
 func CreateLogger(loggerConfig LoggerConfig, setAsMiddlewareLogger ...bool) *slog.Logger {
-	h := handler.NewCustomHandler(os.Stdout, loggerConfig.Level, loggerConfig.AddSource)
-	logger := slog.New(h)
+	var outputs []io.Writer
+	if len(loggerConfig.LevelOutputs) == 0 {
+		outputs = append(outputs, os.Stdout)
+	}
+	logger := newLogger(loggerConfig, outputs...)
 
 	// If setAsMiddlewareLogger is true, set this logger for middleware use
 	if len(setAsMiddlewareLogger) > 0 && setAsMiddlewareLogger[0] {
-		config.SetMiddlewareLogger(logger)
+		Default().SetMiddlewareLogger(logger)
 	}
 
 	return logger
 }
 
+// InitLog creates the application logger and installs it as Default().Logger(); safe to call again at
+// runtime (e.g. after a config reload) while other goroutines are logging. Read it with GetLogger
+// If setAsMiddlewareLogger is true, it is also used by all middlewares
+//
+// Usage:
+//
+//	logbundle.InitLog(logbundle.LoggerConfig{Level: slog.LevelInfo}, true)
+//	logbundle.GetLogger().Info("ready")
+func InitLog(loggerConfig LoggerConfig, setAsMiddlewareLogger ...bool) *slog.Logger {
+	logger := CreateLogger(loggerConfig, setAsMiddlewareLogger...)
+	Default().SetLogger(logger)
+	return logger
+}
+
+// GetLogger returns the logger installed by InitLog, falling back to the middleware logger or slog.Default()
+func GetLogger() *slog.Logger {
+	return Default().Logger()
+}
+
 // SetMiddlewareLogger sets the logger to be used by all middlewares
 // If not set, middlewares will use the internal logger
 func SetMiddlewareLogger(logger *slog.Logger) {
-	config.SetMiddlewareLogger(logger)
+	Default().SetMiddlewareLogger(logger)
 }
 
 // GetMiddlewareLogger returns the configured middleware logger, or nil if not set
 func GetMiddlewareLogger() *slog.Logger {
-	return config.GetMiddlewareLogger()
+	return Default().MiddlewareLogger()
 }
 
 // IsSentryEnabled returns whether Sentry integration is currently enabled
 func IsSentryEnabled() bool {
-	return config.IsSentryEnabled()
+	return Default().IsSentryEnabled()
 }
 
 // SetSentryEnabled enables or disables Sentry integration globally
 // When disabled, no events will be sent to Sentry from any part of the library
 func SetSentryEnabled(enabled bool) {
-	config.SetSentryEnabled(enabled)
+	Default().SetSentryEnabled(enabled)
 }
 
 // GetSentryMinHTTPStatus returns the minimum HTTP status code to send to Sentry
 func GetSentryMinHTTPStatus() int {
-	return config.GetSentryMinHTTPStatus()
+	return Default().SentryMinHTTPStatus()
 }
 
 // SetSentryMinHTTPStatus sets the minimum HTTP status code to send to Sentry
@@ -61,5 +111,152 @@ func GetSentryMinHTTPStatus() int {
 //   - 400: Client and server errors (4xx and 5xx)
 //   - 0: All errors regardless of status code
 func SetSentryMinHTTPStatus(minStatus int) {
-	config.SetSentryMinHTTPStatus(minStatus)
+	Default().SetSentryMinHTTPStatus(minStatus)
+}
+
+// IsIPAnonymizationEnabled returns whether client IPs are truncated in logs and Sentry data
+func IsIPAnonymizationEnabled() bool {
+	return Default().IPAnonymization()
+}
+
+// SetIPAnonymization enables or disables client IP truncation globally: the last octet of IPv4
+// addresses and everything after the /64 prefix of IPv6 addresses are zeroed in request logs,
+// breadcrumbs and Sentry request contexts. Use lgsentry.EnableIPAnonymization() to also truncate
+// the IPs the Sentry SDK collects (user IP, REMOTE_ADDR, forwarding headers)
+func SetIPAnonymization(enabled bool) {
+	Default().SetIPAnonymization(enabled)
+}
+
+// SetQueryDenyList replaces the query parameters whose values are replaced with "[Filtered]" in logged
+// URLs, Sentry query_params and request data (default: core.DefaultQueryDenyList). Names are
+// case-insensitive; parameters containing "token", "secret" or "password" are always scrubbed
+//
+// Usage:
+//
+//	logbundle.SetQueryDenyList(append(core.DefaultQueryDenyList, "invite", "otp")...)
+func SetQueryDenyList(params ...string) {
+	core.SetQueryDenyList(params...)
+}
+
+// AddQueryDenyList adds query parameters to the deny-list
+func AddQueryDenyList(params ...string) {
+	core.AddQueryDenyList(params...)
+}
+
+// SetFrameFilters replaces the rules that skip wrapper frames when logbundle locates the application
+// code in a stack trace (panic locations, error_location). Defaults to core.DefaultFrameFilters
+//
+// Usage:
+//
+//	f := core.DefaultFrameFilters
+//	f.FunctionPrefixes = append(slices.Clone(f.FunctionPrefixes), "github.com/org/errkit/")
+//	logbundle.SetFrameFilters(f)
+func SetFrameFilters(f core.FrameFilters) {
+	core.SetFrameFilters(f)
+}
+
+// SetMetricsRecorder sets the recorder receiving metrics emitted by logbundle middlewares
+// Defaults to an in-memory registry; pass nil to disable metrics
+func SetMetricsRecorder(recorder metrics.Recorder) {
+	metrics.SetRecorder(recorder)
+}
+
+// LogStartupBanner logs the module version, VCS revision, dirty flag and Go version at Info
+// Call once at startup so every deployment is identifiable in the logs
+func LogStartupBanner(logger *slog.Logger) {
+	logger.Info("Application starting", core.BuildInfoAttrs()...)
+}
+
+// LogStartupConfig logs the effective configuration struct once at Info with secrets masked
+// Fields tagged `log:"secret"` or with sensitive names (password, token, secret, dsn, ...) are masked,
+// `log:"-"` fields are skipped. Uses the given logger, the middleware logger, or slog.Default()
+//
+// Usage:
+//
+//	type Config struct {
+//	    Port        int    `env:"PORT"`
+//	    DatabaseURL string `env:"DATABASE_URL"`            // password in URL is masked
+//	    StripeKey   string `env:"STRIPE_KEY" log:"secret"` // masked
+//	}
+//
+//	logbundle.LogStartupConfig(cfg)
+func LogStartupConfig(cfg any, logger ...*slog.Logger) {
+	log := Default().Logger()
+	if len(logger) > 0 && logger[0] != nil {
+		log = logger[0]
+	}
+
+	attrs := core.ConfigAttrs(cfg)
+	args := make([]any, 0, len(attrs))
+	for _, a := range attrs {
+		args = append(args, a)
+	}
+	log.Info("Startup configuration", args...)
+}
+
+// WithDebugScope returns a context in which logbundle loggers emit records at every level
+// Use it to debug a single operation in production without changing the global level
+func WithDebugScope(ctx context.Context) context.Context {
+	return core.WithDebugScope(ctx)
+}
+
+// GenerateDebugToken creates a signed, expiring token for lgfiber.DebugScopeMiddleware's X-Debug-Token header
+func GenerateDebugToken(secret []byte, ttl time.Duration) string {
+	return core.GenerateDebugToken(secret, ttl)
+}
+
+// SetModuleLevels overrides the minimum level per source package, e.g.
+// "pkg/integrations/lgfiber=debug,github.com/ourapp/billing=info". Safe to call at runtime;
+// an empty spec removes all overrides
+func SetModuleLevels(spec string) error {
+	return core.SetModuleLevels(spec)
+}
+
+// GetModuleLevels returns the active per-package level overrides
+func GetModuleLevels() string {
+	return core.GetModuleLevels()
+}
+
+// PII returns an attribute tagged as personal data. Loggers configured with LoggerConfig.PII keep,
+// pseudonymize or drop it; other handlers log the value unchanged
+//
+// Usage:
+//
+//	logger.Info("password reset requested", logbundle.PII("email", email), logbundle.PII("ip", c.IP()))
+func PII(key string, value any) slog.Attr {
+	return core.PII(key, value)
+}
+
+// HashUserID returns the HMAC of a user ID keyed with lgsentry.EnableUserIDHashing, i.e. the ID Sentry
+// receives, so logs can be correlated with events without the raw ID (returns id unchanged when disabled)
+func HashUserID(id string) string {
+	return core.HashUserID(id)
+}
+
+// WithSentryTags returns a context whose tags are added to every Sentry event captured with it:
+// log-based captures (lgsentry), the Fiber error handler, HandleError and panic recovery.
+// Nested calls inherit and extend the parent's tags, which covers background work without a Fiber scope
+//
+// Usage:
+//
+//	ctx = logbundle.WithSentryTags(ctx, map[string]string{"job": "invoice_sync", "tenant_id": tenantID})
+//	defer lgreport.RecoverGoroutinePanic(ctx, "invoice_sync")
+func WithSentryTags(ctx context.Context, tags map[string]string) context.Context {
+	return core.WithSentryTags(ctx, tags)
+}
+
+// SetTraceIDFieldName sets the field name of trace IDs in log records and Sentry tags (default "log_trace_id")
+// The context key is fixed, so renaming the field at runtime never breaks correlation
+func SetTraceIDFieldName(name string) {
+	core.SetTraceIDFieldName(name)
+}
+
+// WithTraceID returns a context carrying the trace ID, e.g. for background jobs started outside a request
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return core.WithTraceID(ctx, traceID)
+}
+
+// GetLogTraceID returns the trace ID stored in the context, or "" if none
+func GetLogTraceID(ctx context.Context) string {
+	return core.TraceIDFromContext(ctx)
 }
@@ -0,0 +1,216 @@
+package logbundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// RuntimeConfig is the part of the logging configuration that can be reloaded at runtime from a JSON or
+// YAML file (.yaml/.yml): the global level, per-module levels and the Sentry switches of the default
+// bundle. Sampling, sinks and redaction are fixed when the logger is built (see LoadBundleConfig) and
+// changing them needs a restart. Omitted Level and ModuleLevels clear the corresponding override; omitted
+// Sentry fields are left unchanged
+//
+// Example file:
+//
+//	{
+//	    "level": "info",
+//	    "module_levels": "pkg/integrations/lgfiber=debug,github.com/ourapp/billing=warn",
+//	    "sentry": {"enabled": true, "min_http_status": 500}
+//	}
+type RuntimeConfig struct {
+	Level        string               `json:"level,omitempty"`
+	ModuleLevels string               `json:"module_levels,omitempty"`
+	Sentry       *RuntimeSentryConfig `json:"sentry,omitempty"`
+}
+
+// RuntimeSentryConfig holds the reloadable Sentry settings
+type RuntimeSentryConfig struct {
+	Enabled       *bool `json:"enabled,omitempty"`
+	MinHTTPStatus *int  `json:"min_http_status,omitempty"`
+}
+
+// ConfigWatcherConfig holds configuration for WatchConfigFile
+type ConfigWatcherConfig struct {
+	// Path to the JSON or YAML config file (required)
+	Path string
+	// Interval between file modification checks (default: 5 seconds)
+	Interval time.Duration
+	// Logger for reload results (if nil, uses middleware logger or slog.Default())
+	Logger *slog.Logger
+}
+
+// LoadRuntimeConfig reads and validates a RuntimeConfig file without applying it; files ending in .yaml or
+// .yml are read as YAML, others as JSON. Unknown keys are rejected so typos don't silently leave settings
+// unchanged
+func LoadRuntimeConfig(path string) (RuntimeConfig, error) {
+	var rc RuntimeConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rc, err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return rc, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rc); err != nil {
+		return rc, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return rc, fmt.Errorf("parse %s: unexpected data after the configuration object", path)
+	}
+
+	if err := rc.validate(); err != nil {
+		return rc, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return rc, nil
+}
+
+// ApplyRuntimeConfig validates rc and applies it; nothing is changed if validation fails
+// The global and per-module levels are replaced in one swap (see core.SetLevels), so a concurrent log call
+// sees either the old or the new levels, never a mix. The Sentry switches are applied after them
+func ApplyRuntimeConfig(rc RuntimeConfig) error {
+	if err := rc.validate(); err != nil {
+		return err
+	}
+
+	var global *slog.Level
+	if rc.Level != "" {
+		level, _ := core.ParseLevel(rc.Level)
+		global = &level
+	}
+	// Already validated, so this cannot fail
+	_ = core.SetLevels(global, rc.ModuleLevels)
+
+	if rc.Sentry != nil {
+		if rc.Sentry.Enabled != nil {
+			Default().SetSentryEnabled(*rc.Sentry.Enabled)
+		}
+		if rc.Sentry.MinHTTPStatus != nil {
+			Default().SetSentryMinHTTPStatus(*rc.Sentry.MinHTTPStatus)
+		}
+	}
+	return nil
+}
+
+// ReloadConfigFile loads the file and applies it, keeping the previous configuration on error
+func ReloadConfigFile(path string) error {
+	rc, err := LoadRuntimeConfig(path)
+	if err != nil {
+		return err
+	}
+	return ApplyRuntimeConfig(rc)
+}
+
+// WatchConfigFile applies the RuntimeConfig file now and again whenever it changes or the process receives
+// SIGHUP, so levels and Sentry reporting can be tuned without a deploy (sampling, sinks and redaction cannot,
+// see RuntimeConfig). Invalid files are logged and ignored, keeping the last good configuration. Returns an
+// error only if the initial load fails
+//
+// Usage:
+//
+//	stop, err := logbundle.WatchConfigFile(logbundle.ConfigWatcherConfig{Path: "/etc/app/logging.json"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stop()
+func WatchConfigFile(cfg ConfigWatcherConfig) (stop func(), err error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+
+	if err := ReloadConfigFile(cfg.Path); err != nil {
+		return nil, err
+	}
+	lastMod, lastSize := configFileStamp(cfg.Path)
+
+	signals := make(chan os.Signal, 1)
+	if reloadSignal != nil {
+		signal.Notify(signals, reloadSignal)
+	}
+	done := make(chan struct{})
+
+	reload := func(reason string) {
+		log := cfg.Logger
+		if log == nil {
+			log = Default().Logger()
+		}
+
+		if err := ReloadConfigFile(cfg.Path); err != nil {
+			log.Error("Logging config reload failed, keeping previous configuration",
+				slog.String("path", cfg.Path),
+				slog.String("reason", reason),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		log.Info("Logging config reloaded",
+			slog.String("path", cfg.Path),
+			slog.String("reason", reason),
+		)
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				lastMod, lastSize = configFileStamp(cfg.Path)
+				reload("signal")
+			case <-ticker.C:
+				mod, size := configFileStamp(cfg.Path)
+				if mod.IsZero() || (mod.Equal(lastMod) && size == lastSize) {
+					continue
+				}
+				lastMod, lastSize = mod, size
+				reload("file changed")
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}, nil
+}
+
+func (rc RuntimeConfig) validate() error {
+	if rc.Level != "" {
+		if _, err := core.ParseLevel(rc.Level); err != nil {
+			return err
+		}
+	}
+	return core.ValidateModuleLevels(rc.ModuleLevels)
+}
+
+// configFileStamp returns the file modification time and size (zero values if the file is unavailable)
+func configFileStamp(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
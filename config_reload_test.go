@@ -0,0 +1,97 @@
+package logbundle
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+func TestLoadRuntimeConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+		check   func(t *testing.T, rc RuntimeConfig)
+	}{
+		{
+			name: "json",
+			file: "logging.json",
+			data: `{"level": "debug", "sentry": {"enabled": false}}`,
+			check: func(t *testing.T, rc RuntimeConfig) {
+				if rc.Level != "debug" || rc.Sentry == nil || rc.Sentry.Enabled == nil || *rc.Sentry.Enabled {
+					t.Fatalf("unexpected config: %+v", rc)
+				}
+			},
+		},
+		{
+			name: "yaml",
+			file: "logging.yaml",
+			data: "level: warn\nmodule_levels: pkg/integrations/lgfiber=debug\nsentry:\n  min_http_status: 400\n",
+			check: func(t *testing.T, rc RuntimeConfig) {
+				if rc.Level != "warn" || rc.ModuleLevels == "" || *rc.Sentry.MinHTTPStatus != 400 {
+					t.Fatalf("unexpected config: %+v", rc)
+				}
+			},
+		},
+		{name: "sampling is not reloadable", file: "logging.yml", data: "sampling:\n  rate: 0.1\n", wantErr: "unknown field"},
+		{name: "json trailing data", file: "logging.json", data: `{"level": "info"} {}`, wantErr: "unexpected data"},
+		{name: "invalid level", file: "logging.yaml", data: "level: loud\n", wantErr: "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			rc, err := LoadRuntimeConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, rc)
+		})
+	}
+}
+
+func TestApplyRuntimeConfigSwapsLevelsTogether(t *testing.T) {
+	t.Cleanup(func() { _ = core.SetLevels(nil, "") })
+	configs := []RuntimeConfig{
+		{Level: "debug", ModuleLevels: "github.com/ourapp/billing=error"},
+		{Level: "warn"},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 2000 {
+			if err := ApplyRuntimeConfig(configs[i%2]); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		levels := core.Levels()
+		global, ok := levels.GlobalLevel()
+		_, hasModules := levels.MinModuleLevel()
+		if ok && (global == slog.LevelDebug) != hasModules {
+			t.Fatalf("global level %v seen with module overrides %v", global, hasModules)
+		}
+	}
+}
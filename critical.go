@@ -0,0 +1,52 @@
+package logbundle
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// LogCritical logs a record that must not be dropped (audit and security events) and returns the
+// delivery result. The record bypasses level filtering and sampling, carries critical=true and is
+// written synchronously with retries by writers implementing handler.SyncWriter (e.g. handler.TeeWriter);
+// other writers are written to directly. A nil logger uses GetLogger()
+//
+// Usage:
+//
+//	if err := logbundle.LogCritical(ctx, log, slog.LevelInfo, "role granted",
+//	    fields.UserID(actorID), slog.String("role", "admin")); err != nil {
+//	    return fmt.Errorf("audit log: %w", err)
+//	}
+func LogCritical(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, args ...any) error {
+	if logger == nil {
+		logger = GetLogger()
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+
+	r := slog.NewRecord(core.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	r.AddAttrs(slog.Bool("critical", true))
+
+	err := logger.Handler().Handle(core.WithCritical(ctx), r)
+
+	result := "delivered"
+	if err != nil {
+		result = "failed"
+	}
+	metrics.IncCounter("log_critical_total", metrics.Labels{"result": result})
+	return err
+}
+
+// WithCritical returns a context whose records are treated like LogCritical records, for code that logs
+// through slog directly; use logger.Handler().Handle to observe the delivery result
+func WithCritical(ctx context.Context) context.Context {
+	return core.WithCritical(ctx)
+}
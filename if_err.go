@@ -0,0 +1,90 @@
+package logbundle
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"runtime"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+)
+
+// LogIfErr logs msg with err at level on the default bundle logger and reports whether it logged;
+// a nil err is a no-op. Meant for non-critical cleanup paths where the error is only worth a log
+//
+// Usage:
+//
+//	defer func() { logbundle.WarnIfErr(ctx, "close file", f.Close(), slog.String("path", path)) }()
+func LogIfErr(ctx context.Context, level slog.Level, msg string, err error, args ...any) bool {
+	if err == nil {
+		return false
+	}
+	logIfErr(ctx, level, msg, err, args)
+	return true
+}
+
+// WarnIfErr logs msg with err at Warn unless err is nil
+//
+// Usage:
+//
+//	defer func() { logbundle.WarnIfErr(ctx, "rollback", tx.Rollback()) }()
+func WarnIfErr(ctx context.Context, msg string, err error, args ...any) bool {
+	if err == nil {
+		return false
+	}
+	logIfErr(ctx, slog.LevelWarn, msg, err, args)
+	return true
+}
+
+// ErrorIfErr logs msg with err at Error unless err is nil
+func ErrorIfErr(ctx context.Context, msg string, err error, args ...any) bool {
+	if err == nil {
+		return false
+	}
+	logIfErr(ctx, slog.LevelError, msg, err, args)
+	return true
+}
+
+// CloseWithLog closes c and logs a failure at Warn with the resource name; a nil c (including a nil
+// pointer such as an unopened *os.File) is a no-op
+// The trace ID of ctx is added by the handler like on any other record
+//
+// Usage:
+//
+//	rows, err := db.QueryContext(ctx, query)
+//	if err != nil {
+//	    return err
+//	}
+//	defer logbundle.CloseWithLog(ctx, rows, "order rows")
+func CloseWithLog(ctx context.Context, c io.Closer, name string) {
+	if c == nil {
+		return
+	}
+	if v := reflect.ValueOf(c); v.Kind() == reflect.Pointer && v.IsNil() {
+		return
+	}
+	if err := c.Close(); err != nil {
+		logIfErr(ctx, slog.LevelWarn, "Failed to close resource", err, []any{slog.String("resource", name)})
+	}
+}
+
+// logIfErr logs with the source of the exported helper's caller
+func logIfErr(ctx context.Context, level slog.Level, msg string, err error, args []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := GetLogger()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	r := slog.NewRecord(core.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	r.AddAttrs(fields.Err(err))
+	_ = logger.Handler().Handle(ctx, r)
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"runtime"
-	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 )
 
 // log is the unified internal logging function that handles both context and non-context calls
@@ -19,9 +20,14 @@ func log(ctx context.Context, logger *slog.Logger, level slog.Level, msg string,
 		var pcs [1]uintptr
 		runtime.Callers(3, pcs[:])
 		pc = pcs[0]
+	} else if _, ok := core.MinModuleLevel(); ok {
+		// No source in the output, but per-module levels still need the caller (see core.WithModulePC)
+		var pcs [1]uintptr
+		runtime.Callers(3, pcs[:])
+		ctx = core.WithModulePC(ctx, pcs[0])
 	}
 
-	r := slog.NewRecord(time.Now(), level, msg, pc)
+	r := slog.NewRecord(core.Now(), level, msg, pc)
 	r.Add(args...)
 	_ = logger.Handler().Handle(ctx, r)
 }
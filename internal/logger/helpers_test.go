@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+func TestLogNoSourceAppliesModuleLevels(t *testing.T) {
+	defer core.SetModuleLevels("")
+
+	tests := []struct {
+		name    string
+		spec    string
+		level   slog.Level
+		written bool
+	}{
+		{"no override", "", slog.LevelDebug, false},
+		{"lowered for the package", "internal/logger=debug", slog.LevelDebug, true},
+		{"raised for the package", "internal/logger=error", slog.LevelWarn, false},
+		{"other package", "pkg/integrations/lgfiber=debug", slog.LevelDebug, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := core.SetModuleLevels(tt.spec); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			log := slog.New(handler.NewCustomHandler(&buf, slog.LevelInfo, true))
+
+			LogNoSource(log, tt.level, "hello")
+
+			out := buf.String()
+			if got := strings.Contains(out, "hello"); got != tt.written {
+				t.Fatalf("written = %v, want %v: %q", got, tt.written, out)
+			}
+			if strings.Contains(out, "helpers_test.go") {
+				t.Fatalf("source written for a record logged without source: %q", out)
+			}
+		})
+	}
+}
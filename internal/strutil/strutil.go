@@ -0,0 +1,15 @@
+// Package strutil holds string helpers shared by logbundle packages
+package strutil
+
+// Truncate truncates s to at most maxChars characters (runes)
+func Truncate(s string, maxChars int) string {
+	if maxChars <= 0 {
+		return ""
+	}
+
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+	return string(runes[:maxChars])
+}
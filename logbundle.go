@@ -1 +1,24 @@
+// Package logbundle sets up structured logging with trace IDs, Sentry reporting and redaction for Go
+// services, and is the entry point to the integrations under pkg/integrations
+//
+// # API stability
+//
+// From v1.0.0 the module follows semantic versioning: exported identifiers of stable packages are not
+// removed, renamed or changed incompatibly within v1. Superseded identifiers are marked "Deprecated:"
+// with their replacement and kept until v2. Minor releases may add identifiers, struct fields and
+// options; log record fields follow the schema_version of the output format (see pkg/fields)
+//
+// Stable packages:
+//   - logbundle (this package)
+//   - pkg/config, pkg/core, pkg/fields, pkg/handler, pkg/metrics
+//   - pkg/integrations/lgerr, lgfiber, lgreport, lgsentry, lgtest
+//
+// Experimental packages, which may change in minor releases until they are declared stable:
+//   - pkg/breaker, pkg/chaos (fault injection only with the logbundle_chaos build tag), pkg/errspike
+//   - pkg/integrations/lgcron, lgdb, lgfasthttp, lgtemporal
+//
+// Helpers only the library itself needs live under internal/ and are not part of the API. Identifiers
+// that duplicated a stable one with different behavior were removed before v1: use fields.Err for
+// ErrAttr, core.ParseLevel for GetLvlFromStr, strconv.ParseBool for GetBoolFromStr and the
+// config.Settings methods (config.Default(), config.FromContext) for the package-level config accessors
 package logbundle
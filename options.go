@@ -0,0 +1,133 @@
+package logbundle
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// Option configures a LogBundle created with New (or a Builder through Apply). Options are applied in
+// order, so later ones override earlier ones; new capabilities are added as new options without
+// breaking LoggerConfig struct literals
+type Option func(*Builder)
+
+// New creates an isolated LogBundle from the defaults of NewBuilder and the options:
+// Info level, text format, no source locations, os.Stdout, Sentry disabled (reports from status 500
+// once enabled), client IPs kept
+//
+// Usage:
+//
+//	bundle := logbundle.New(
+//	    logbundle.WithLevel(slog.LevelDebug),
+//	    logbundle.WithFormat(handler.FormatJSON),
+//	    logbundle.WithSentry(true),
+//	)
+//	bundle.Logger().Info("ready")
+func New(opts ...Option) *LogBundle {
+	return NewBuilder().Apply(opts...).Build()
+}
+
+// Apply applies options to the builder, e.g. on top of a Preset
+func (b *Builder) Apply(opts ...Option) *Builder {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	return b
+}
+
+// WithConfig replaces the logger configuration with a LoggerConfig; later options adjust it
+func WithConfig(loggerConfig LoggerConfig) Option {
+	return func(b *Builder) { b.WithConfig(loggerConfig) }
+}
+
+// WithLevel sets the minimum log level (default: Info)
+func WithLevel(level slog.Level) Option {
+	return func(b *Builder) { b.WithLevel(level) }
+}
+
+// WithSource includes source file and line in records (default: false)
+func WithSource(addSource bool) Option {
+	return func(b *Builder) { b.WithSource(addSource) }
+}
+
+// WithFormat sets the output format (default: handler.FormatText)
+func WithFormat(format handler.Format) Option {
+	return func(b *Builder) { b.WithFormat(format) }
+}
+
+// WithRuntimeMetadata appends host and process metadata to every record (default: false)
+func WithRuntimeMetadata(enabled bool) Option {
+	return func(b *Builder) { b.WithRuntimeMetadata(enabled) }
+}
+
+// WithAttrSchema enables strict attribute checking against the schema (default: off)
+func WithAttrSchema(schema *handler.AttrSchema) Option {
+	return func(b *Builder) { b.WithAttrSchema(schema) }
+}
+
+// WithLargeAttrs compacts attribute values larger than the configured threshold (default: off)
+func WithLargeAttrs(opts handler.LargeAttrOptions) Option {
+	return func(b *Builder) { b.WithLargeAttrs(opts) }
+}
+
+// WithSecretDetection masks likely secrets in messages and attributes (default: false)
+func WithSecretDetection(enabled bool) Option {
+	return func(b *Builder) { b.WithSecretDetection(enabled) }
+}
+
+// WithPII sets how attributes tagged with PII are written (default: kept)
+func WithPII(opts handler.PIIOptions) Option {
+	return func(b *Builder) { b.WithPII(opts) }
+}
+
+// WithDeltaAttrs omits attributes repeated within a trace (default: off)
+func WithDeltaAttrs(opts handler.DeltaOptions) Option {
+	return func(b *Builder) { b.WithDeltaAttrs(opts) }
+}
+
+// WithSampler samples records below opts.KeepLevel per trace (default: off, every record is written)
+func WithSampler(opts handler.SamplingOptions) Option {
+	return func(b *Builder) { b.WithSampler(opts) }
+}
+
+// WithOutput sets the log destination (default: os.Stdout, unless sinks or level outputs are set)
+func WithOutput(w io.Writer) Option {
+	return func(b *Builder) { b.WithOutput(w) }
+}
+
+// WithSink adds an output written through a handler.TeeWriter, next to WithOutput and WithLevelOutputs
+// when those are set; repeat it for several sinks. Close the bundle on shutdown to flush them
+//
+// Usage:
+//
+//	bundle := logbundle.New(
+//	    logbundle.WithSink(handler.Sink{Name: "stdout", Writer: os.Stdout, Color: true}),
+//	    logbundle.WithSink(handler.Sink{Name: "collector", Writer: httpSink}),
+//	)
+func WithSink(sink handler.Sink) Option {
+	return func(b *Builder) { b.WithSinks(sink) }
+}
+
+// WithLevelOutputs routes records by level to several writers, next to WithOutput and WithSink when
+// those are set
+func WithLevelOutputs(routes ...handler.LevelRoute) Option {
+	return func(b *Builder) { b.WithLevelOutputs(routes...) }
+}
+
+// WithSentry enables Sentry reporting (default: false; the SDK must be initialized separately)
+func WithSentry(enabled bool) Option {
+	return func(b *Builder) { b.WithSentry(enabled) }
+}
+
+// WithSentryMinHTTPStatus sets the minimum HTTP status code reported to Sentry (default: 500)
+func WithSentryMinHTTPStatus(minStatus int) Option {
+	return func(b *Builder) { b.WithSentryMinHTTPStatus(minStatus) }
+}
+
+// WithIPAnonymization truncates client IPs in logs and Sentry data (default: false)
+func WithIPAnonymization(enabled bool) Option {
+	return func(b *Builder) { b.WithIPAnonymization(enabled) }
+}
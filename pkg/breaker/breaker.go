@@ -0,0 +1,378 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgsentry"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets calls through and tracks their failure rate
+	StateClosed State = iota
+	// StateOpen rejects calls until OpenTimeout has passed
+	StateOpen
+	// StateHalfOpen lets a limited number of trial calls through to probe the service
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config holds configuration for a circuit breaker
+type Config struct {
+	// Logger for state transitions (if nil, uses middleware logger or slog.Default())
+	Logger *slog.Logger
+	// FailureRatio opens the breaker when reached within a window (default: 0.5)
+	FailureRatio float64
+	// MinRequests is the number of calls in a window before the ratio is evaluated (default: 10)
+	MinRequests int
+	// Window is the length of the counting window in the closed state (default: 1 minute)
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before probing (default: 30 seconds)
+	OpenTimeout time.Duration
+	// HalfOpenCalls is the number of successful trial calls needed to close again (default: 1)
+	HalfOpenCalls int
+	// IsFailure decides whether an error counts as a failure (default: any error except client aborts)
+	IsFailure func(error) bool
+}
+
+// Breaker tracks the health of one external service and short-circuits calls while it is failing
+type Breaker struct {
+	service string
+	cfg     Config
+
+	mu          sync.Mutex
+	state       State
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int    // Trial calls admitted in the half-open state
+	successes   int    // Successful trial calls in the half-open state
+	generation  uint64 // Incremented on every transition, so calls admitted before it are not counted after it
+}
+
+// outcome is how a finished call counts towards the breaker
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored does not count either way and frees a half-open trial slot (the caller canceled or fn panicked)
+	outcomeIgnored
+)
+
+// New creates a breaker for service with defaults applied and registers it for diagnostics
+// Creating a breaker for an already registered service replaces it
+func New(service string, cfg ...Config) *Breaker {
+	var c Config
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.FailureRatio <= 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.Window <= 0 {
+		c.Window = time.Minute
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.HalfOpenCalls <= 0 {
+		c.HalfOpenCalls = 1
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = isFailure
+	}
+
+	b := &Breaker{service: service, cfg: c, windowStart: core.Now()}
+
+	registryMu.Lock()
+	registry[service] = b
+	registryMu.Unlock()
+
+	metrics.SetGauge("circuit_breaker_state", metrics.Labels{"service": service}, float64(StateClosed))
+	return b
+}
+
+// Service returns the name of the protected service
+func (b *Breaker) Service() string {
+	return b.service
+}
+
+// State returns the current state, moving from open to half-open once OpenTimeout has passed
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advanceLocked(core.Now())
+	return b.state
+}
+
+// Do runs fn if the breaker allows it and records the outcome
+// While the breaker is open fn is not called and an lgerr.External error is returned
+// The context passed to fn carries the breaker state as Sentry tags, and errors returned by fn are
+// converted to lgerr.External (lgerr errors are kept) with the breaker state in their context
+// A call canceled by the caller or a panic in fn counts neither as success nor failure, and results of
+// calls admitted before a state change are not counted after it
+//
+// Usage:
+//
+//	var payments = breaker.New("payments")
+//
+//	err := payments.Do(ctx, func(ctx context.Context) error {
+//	    return client.Charge(ctx, order)
+//	})
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	state, generation, ok := b.allow()
+	if !ok {
+		metrics.IncCounter("circuit_breaker_rejected_total", metrics.Labels{"service": b.service})
+		return lgerr.External(b.service, "circuit breaker is "+state.String(),
+			lgerr.WithContext("circuit_breaker_state", state.String()),
+			lgerr.WithIgnoreSentry(),
+		)
+	}
+
+	ctx = core.WithSentryTags(ctx, map[string]string{
+		"circuit_breaker":       b.service,
+		"circuit_breaker_state": state.String(),
+	})
+
+	finished := false
+	defer func() {
+		if !finished {
+			b.record(generation, outcomeIgnored)
+		}
+	}()
+
+	err := fn(ctx)
+	finished = true
+	b.record(generation, b.outcome(ctx, err))
+	if err == nil {
+		return nil
+	}
+
+	var lgErr *lgerr.Error
+	if !errors.As(err, &lgErr) {
+		lgErr = lgerr.External(b.service, err.Error()).Wrap(err)
+	}
+	return lgErr.WithContext("circuit_breaker_state", state.String())
+}
+
+// allow reports whether a call may proceed, the state it proceeds in and the generation it belongs to
+func (b *Breaker) allow() (State, uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advanceLocked(core.Now())
+	switch b.state {
+	case StateOpen:
+		return StateOpen, b.generation, false
+	case StateHalfOpen:
+		if b.trials >= b.cfg.HalfOpenCalls {
+			return StateHalfOpen, b.generation, false
+		}
+		b.trials++
+	}
+	return b.state, b.generation, true
+}
+
+// outcome classifies the result of fn; cancellation by the caller says nothing about the service
+func (b *Breaker) outcome(ctx context.Context, err error) outcome {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		return outcomeIgnored
+	case b.cfg.IsFailure(err):
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+// record updates the counters with the outcome of a call admitted in generation
+func (b *Breaker) record(generation uint64, result outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := core.Now()
+	b.advanceLocked(now)
+	if generation != b.generation {
+		// The state changed while the call ran; its trial slot and counters were already reset
+		return
+	}
+
+	if result == outcomeIgnored {
+		if b.state == StateHalfOpen {
+			b.trials--
+		}
+		return
+	}
+	failed := result == outcomeFailure
+
+	switch b.state {
+	case StateClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRatio {
+			b.transitionLocked(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failed {
+			b.transitionLocked(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenCalls {
+			b.transitionLocked(StateClosed, now)
+		}
+	}
+}
+
+// advanceLocked applies time-based changes: window rollover and the open timeout
+func (b *Breaker) advanceLocked(now time.Time) {
+	switch b.state {
+	case StateClosed:
+		if now.Sub(b.windowStart) >= b.cfg.Window {
+			b.windowStart = now
+			b.requests, b.failures = 0, 0
+		}
+	case StateOpen:
+		if now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+			b.transitionLocked(StateHalfOpen, now)
+		}
+	}
+}
+
+// transitionLocked moves to a new state, resetting counters and reporting the change
+func (b *Breaker) transitionLocked(to State, now time.Time) {
+	from := b.state
+	failures, requests := b.failures, b.requests
+
+	b.state = to
+	b.generation++
+	b.windowStart = now
+	b.requests, b.failures = 0, 0
+	b.trials, b.successes = 0, 0
+	if to == StateOpen {
+		b.openedAt = now
+	}
+
+	metrics.SetGauge("circuit_breaker_state", metrics.Labels{"service": b.service}, float64(to))
+	metrics.IncCounter("circuit_breaker_transitions_total", metrics.Labels{
+		"service": b.service,
+		"from":    from.String(),
+		"to":      to.String(),
+	})
+
+	log := b.cfg.Logger
+	if log == nil {
+		if log = config.Default().MiddlewareLogger(); log == nil {
+			log = slog.Default()
+		}
+	}
+
+	level := slog.LevelInfo
+	if to == StateOpen {
+		level = slog.LevelWarn
+	}
+	log.Log(context.Background(), level, fmt.Sprintf("Circuit breaker %s", to),
+		slog.String("service", b.service),
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+		slog.Int("window_requests", requests),
+		slog.Int("window_failures", failures),
+	)
+}
+
+// isFailure counts every error except client aborts (the caller gave up, the service did not fail)
+func isFailure(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
+
+var (
+	registry   = make(map[string]*Breaker)
+	registryMu sync.RWMutex
+)
+
+// Get returns the registered breaker for service, or nil
+func Get(service string) *Breaker {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[service]
+}
+
+// States returns the current state of every registered breaker keyed by service
+func States() map[string]State {
+	registryMu.RLock()
+	breakers := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		breakers = append(breakers, b)
+	}
+	registryMu.RUnlock()
+
+	states := make(map[string]State, len(breakers))
+	for _, b := range breakers {
+		states[b.service] = b.State()
+	}
+	return states
+}
+
+// Summary returns "service=state" entries for all registered breakers, sorted by service
+func Summary() []string {
+	states := States()
+	out := make([]string, 0, len(states))
+	for service, state := range states {
+		out = append(out, service+"="+state.String())
+	}
+	sort.Strings(out)
+	return out
+}
+
+// EnableSentryTags tags every Sentry event with the state of breakers that are not closed
+// ("circuit_breaker.<service>": "open" or "half-open"), so errors during an outage show its cause
+// Requires lgsentry.Init (the tagging runs as an event processor)
+func EnableSentryTags() {
+	lgsentry.AddEventProcessor("circuit_breakers", func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		for service, state := range States() {
+			if state == StateClosed {
+				continue
+			}
+			if event.Tags == nil {
+				event.Tags = make(map[string]string)
+			}
+			event.Tags["circuit_breaker."+service] = state.String()
+		}
+		return event
+	}, lgsentry.OrderEnrich)
+}
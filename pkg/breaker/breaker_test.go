@@ -0,0 +1,139 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// halfOpen returns a breaker that has been tripped and moved to the half-open state
+func halfOpen(t *testing.T) (*Breaker, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	core.SetClock(clock)
+	t.Cleanup(func() { core.SetClock(nil) })
+
+	b := New(t.Name(), Config{MinRequests: 1, OpenTimeout: time.Second})
+	_ = b.Do(context.Background(), func(context.Context) error { return errors.New("down") })
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after failure = %s, want open", got)
+	}
+	clock.Advance(time.Second)
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state after open timeout = %s, want half-open", got)
+	}
+	return b, clock
+}
+
+func TestPanicReleasesTrialSlot(t *testing.T) {
+	b, _ := halfOpen(t)
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = b.Do(context.Background(), func(context.Context) error { panic("boom") })
+	}()
+
+	called := false
+	if err := b.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	}); err != nil || !called {
+		t.Fatalf("trial after panic: called=%v err=%v, want a new trial", called, err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state = %s, want closed", got)
+	}
+}
+
+func TestCancellationIsNotSuccess(t *testing.T) {
+	b, _ := halfOpen(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = b.Do(ctx, func(ctx context.Context) error { return ctx.Err() })
+
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state after canceled trial = %s, want half-open", got)
+	}
+	if _, _, ok := b.allow(); !ok {
+		t.Fatal("canceled trial kept its slot")
+	}
+}
+
+func TestLateResultIsNotCounted(t *testing.T) {
+	b, clock := halfOpen(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Do(context.Background(), func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	// The breaker trips again and reaches a new half-open state while the first trial is still running
+	b.mu.Lock()
+	b.transitionLocked(StateOpen, clock.Now())
+	b.mu.Unlock()
+	clock.Advance(time.Second)
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state = %s, want half-open", got)
+	}
+
+	close(release)
+	<-done
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state after late success = %s, want half-open", got)
+	}
+}
+
+func TestClosedStateOpensOnFailureRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		errs    []error
+		want    State
+		minReqs int
+	}{
+		{name: "all successes", errs: []error{nil, nil, nil, nil}, want: StateClosed, minReqs: 4},
+		{name: "half failures", errs: []error{nil, errors.New("x"), nil, errors.New("x")}, want: StateOpen, minReqs: 4},
+		{name: "canceled calls ignored", errs: []error{context.Canceled, context.Canceled, context.Canceled, context.Canceled}, want: StateClosed, minReqs: 1},
+		{name: "below min requests", errs: []error{errors.New("x")}, want: StateClosed, minReqs: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(t.Name(), Config{MinRequests: tt.minReqs})
+			for _, err := range tt.errs {
+				_ = b.Do(context.Background(), func(context.Context) error { return err })
+			}
+			if got := b.State(); got != tt.want {
+				t.Fatalf("state = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+//go:build logbundle_chaos
+
+package chaos
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Faults describes the failures to simulate
+type Faults struct {
+	// SentrySendError is returned instead of posting envelopes to Sentry (lgsentry.SpoolTransport, user feedback)
+	SentrySendError error
+	// SentrySendLatency delays every envelope post
+	SentrySendLatency time.Duration
+	// SinkWriteError is returned by TeeWriter sink writes
+	SinkWriteError error
+	// SinkLatency delays every TeeWriter sink write
+	SinkLatency time.Duration
+	// SentryQueueFull makes lgsentry.SpoolTransport treat its send queue as full, forcing events to the spool
+	SentryQueueFull bool
+	// SinkQueueFull makes TeeWriter treat sink queues as full, forcing drops or disk overflow
+	SinkQueueFull bool
+	// Sinks limits the sink faults to these sink names (empty means all sinks)
+	Sinks []string
+}
+
+var active atomic.Pointer[Faults]
+
+// Inject activates faults, replacing any previously injected ones, and returns a function restoring
+// the previous faults
+func Inject(f Faults) (restore func()) {
+	f.Sinks = slices.Clone(f.Sinks)
+	prev := active.Swap(&f)
+	return func() {
+		active.Store(prev)
+	}
+}
+
+// Reset removes all injected faults
+func Reset() {
+	active.Store(nil)
+}
+
+// Active returns the injected faults, or nil if none
+func Active() *Faults {
+	return active.Load()
+}
+
+// SentrySend applies the Sentry faults: it sleeps for SentrySendLatency and returns SentrySendError
+func SentrySend() error {
+	f := active.Load()
+	if f == nil {
+		return nil
+	}
+	if f.SentrySendLatency > 0 {
+		time.Sleep(f.SentrySendLatency)
+	}
+	return f.SentrySendError
+}
+
+// SentryQueueFull reports whether the Sentry send queue should be treated as full
+func SentryQueueFull() bool {
+	f := active.Load()
+	return f != nil && f.SentryQueueFull
+}
+
+// SinkWrite applies the sink faults for the named sink: it sleeps for SinkLatency and returns SinkWriteError
+func SinkWrite(sink string) error {
+	f := active.Load()
+	if f == nil || !f.targets(sink) {
+		return nil
+	}
+	if f.SinkLatency > 0 {
+		time.Sleep(f.SinkLatency)
+	}
+	return f.SinkWriteError
+}
+
+// SinkQueueFull reports whether the named sink's queue should be treated as full
+func SinkQueueFull(sink string) bool {
+	f := active.Load()
+	return f != nil && f.SinkQueueFull && f.targets(sink)
+}
+
+func (f *Faults) targets(sink string) bool {
+	return len(f.Sinks) == 0 || slices.Contains(f.Sinks, sink)
+}
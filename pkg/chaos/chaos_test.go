@@ -0,0 +1,38 @@
+//go:build logbundle_chaos
+
+package chaos
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInjectTargetsNamedSinks(t *testing.T) {
+	errDown := errors.New("loki down")
+	restore := Inject(Faults{SinkWriteError: errDown, SinkQueueFull: true, SentryQueueFull: true, Sinks: []string{"loki"}})
+
+	tests := []struct {
+		sink      string
+		wantErr   error
+		wantQueue bool
+	}{
+		{sink: "loki", wantErr: errDown, wantQueue: true},
+		{sink: "stdout", wantErr: nil, wantQueue: false},
+	}
+	for _, tt := range tests {
+		if err := SinkWrite(tt.sink); !errors.Is(err, tt.wantErr) {
+			t.Errorf("SinkWrite(%q) = %v, want %v", tt.sink, err, tt.wantErr)
+		}
+		if got := SinkQueueFull(tt.sink); got != tt.wantQueue {
+			t.Errorf("SinkQueueFull(%q) = %v, want %v", tt.sink, got, tt.wantQueue)
+		}
+	}
+	if !SentryQueueFull() {
+		t.Error("SentryQueueFull() = false with the fault injected")
+	}
+
+	restore()
+	if Active() != nil || SentryQueueFull() || SinkWrite("loki") != nil {
+		t.Fatal("faults still active after restore")
+	}
+}
@@ -0,0 +1,12 @@
+// Package chaos injects faults into logbundle's delivery paths so integration tests can assert the
+// library's degradation behavior (drops counted, spooling and overflow fallbacks used)
+//
+// The injection API (Inject, Reset, Active) only exists in builds with the logbundle_chaos tag; without it
+// the hooks called from the delivery paths are constant no-ops the compiler inlines away
+//
+// Usage (go test -tags logbundle_chaos ./...):
+//
+//	restore := chaos.Inject(chaos.Faults{SinkWriteError: errors.New("loki down"), Sinks: []string{"loki"}})
+//	defer restore()
+//	// log, then assert on tee.Stats() and metrics.Snapshot()
+package chaos
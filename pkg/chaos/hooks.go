@@ -0,0 +1,23 @@
+//go:build !logbundle_chaos
+
+package chaos
+
+// SentrySend is a no-op without the logbundle_chaos build tag
+func SentrySend() error {
+	return nil
+}
+
+// SentryQueueFull is a no-op without the logbundle_chaos build tag
+func SentryQueueFull() bool {
+	return false
+}
+
+// SinkWrite is a no-op without the logbundle_chaos build tag
+func SinkWrite(string) error {
+	return nil
+}
+
+// SinkQueueFull is a no-op without the logbundle_chaos build tag
+func SinkQueueFull(string) bool {
+	return false
+}
@@ -0,0 +1,23 @@
+package config
+
+// FiberMiddlewares selects the logbundle Fiber middlewares, e.g. from a bundle config file
+// Pass it to lgfiber.Middlewares to get the handlers in the order they must be registered
+type FiberMiddlewares struct {
+	// Recover turns panics into 500 responses (lgfiber.RecoverMiddleware)
+	Recover bool `json:"recover,omitempty"`
+	// Sentry attaches a per-request Sentry hub (sentryfiber, re-panicking to Recover)
+	Sentry bool `json:"sentry,omitempty"`
+	// TraceID assigns request trace IDs (lgfiber.TraceIDMiddleware)
+	TraceID bool `json:"trace_id,omitempty"`
+	// TrustIncomingTraceID reuses a valid incoming X-Request-ID
+	TrustIncomingTraceID bool `json:"trust_incoming_trace_id,omitempty"`
+	// Breadcrumbs records request start and end breadcrumbs (lgfiber.BreadcrumbsMiddleware)
+	Breadcrumbs bool `json:"breadcrumbs,omitempty"`
+	// Metrics records request metrics (lgfiber.MetricsMiddleware)
+	Metrics bool `json:"metrics,omitempty"`
+	// RequestLogger buffers a request's logs into one block (lgfiber.RequestLoggerMiddleware)
+	RequestLogger bool `json:"request_logger,omitempty"`
+	// SkipPaths and SkipPrefixes are ignored by the middlewares built from these settings (see lgfiber.Middlewares)
+	SkipPaths    []string `json:"skip_paths,omitempty"`
+	SkipPrefixes []string `json:"skip_prefixes,omitempty"`
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Settings holds the configuration shared by logbundle middlewares and integrations
+// The package-level functions operate on Default(); create separate instances with NewSettings
+// for isolated setups (tests, multi-tenant processes) and attach them with WithSettings
+type Settings struct {
+	mu                  sync.RWMutex
+	middlewareLogger    *slog.Logger
+	sentryEnabled       bool
+	sentryMinHTTPStatus int
+	ipAnonymization     bool
+}
+
+var defaultSettings = NewSettings()
+
+// NewSettings creates settings with defaults: no middleware logger, Sentry disabled, minimum status 500
+func NewSettings() *Settings {
+	return &Settings{sentryMinHTTPStatus: 500}
+}
+
+// Default returns the process-wide settings used by the package-level functions
+func Default() *Settings {
+	return defaultSettings
+}
+
+type settingsKey struct{}
+
+// WithSettings returns a context carrying s; logbundle code handling that context uses s instead of Default()
+func WithSettings(ctx context.Context, s *Settings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, s)
+}
+
+// FromContext returns the settings attached with WithSettings, or Default()
+func FromContext(ctx context.Context) *Settings {
+	if ctx != nil {
+		if s, ok := ctx.Value(settingsKey{}).(*Settings); ok && s != nil {
+			return s
+		}
+	}
+	return defaultSettings
+}
+
+// MiddlewareLogger returns the configured middleware logger, or nil if not set
+func (s *Settings) MiddlewareLogger() *slog.Logger {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.middlewareLogger
+}
+
+// SetMiddlewareLogger sets the logger used by middlewares
+func (s *Settings) SetMiddlewareLogger(logger *slog.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middlewareLogger = logger
+}
+
+// SentryEnabled reports whether Sentry integration is active
+func (s *Settings) SentryEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sentryEnabled
+}
+
+// SetSentryEnabled enables or disables Sentry integration
+func (s *Settings) SetSentryEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sentryEnabled = enabled
+}
+
+// SentryMinHTTPStatus returns the minimum HTTP status code sent to Sentry
+func (s *Settings) SentryMinHTTPStatus() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sentryMinHTTPStatus
+}
+
+// SetSentryMinHTTPStatus sets the minimum HTTP status code sent to Sentry
+func (s *Settings) SetSentryMinHTTPStatus(minStatus int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sentryMinHTTPStatus = minStatus
+}
+
+// IPAnonymization reports whether client IPs are truncated in logs and Sentry data (see core.AnonymizeIP)
+func (s *Settings) IPAnonymization() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ipAnonymization
+}
+
+// SetIPAnonymization enables or disables client IP truncation
+func (s *Settings) SetIPAnonymization(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ipAnonymization = enabled
+}
+
+// ClientIP returns ip, anonymized when IP anonymization is enabled
+func (s *Settings) ClientIP(ip string) string {
+	if !s.IPAnonymization() {
+		return ip
+	}
+	return core.AnonymizeIP(ip)
+}
+
+func init() {
+	core.RegisterContextInspector("settings", func(ctx context.Context) (any, bool) {
+		s := FromContext(ctx)
+		return map[string]any{
+			"scoped":                ctx.Value(settingsKey{}) != nil,
+			"sentry_enabled":        s.SentryEnabled(),
+			"middleware_logger_set": s.MiddlewareLogger() != nil,
+			"ip_anonymization":      s.IPAnonymization(),
+		}, true
+	})
+}
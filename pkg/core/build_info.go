@@ -0,0 +1,94 @@
+package core
+
+import (
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/strutil"
+)
+
+// BuildInfo describes the running binary, read from debug.ReadBuildInfo
+type BuildInfo struct {
+	ModulePath string
+	Version    string // Module version, "(devel)" for local builds
+	Revision   string // VCS revision
+	Time       string // VCS commit time
+	Dirty      bool   // Whether the working tree had uncommitted changes
+	GoVersion  string
+}
+
+var (
+	buildInfo     BuildInfo
+	buildInfoOnce sync.Once
+)
+
+// GetBuildInfo returns the build info of the running binary, computed once on first use
+func GetBuildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		buildInfo.GoVersion = runtime.Version()
+
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		buildInfo.ModulePath = info.Main.Path
+		buildInfo.Version = info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				buildInfo.Revision = setting.Value
+			case "vcs.time":
+				buildInfo.Time = setting.Value
+			case "vcs.modified":
+				buildInfo.Dirty = setting.Value == "true"
+			}
+		}
+	})
+	return buildInfo
+}
+
+// Release returns a Sentry-style release name ("module@version"), or empty if nothing identifies the build
+// Tagged versions are used as-is; development builds use the short VCS revision with a "-dirty" suffix
+// when the working tree was modified
+func (b BuildInfo) Release() string {
+	version := b.AppVersion()
+	if version == "" {
+		return ""
+	}
+	if b.ModulePath == "" {
+		return version
+	}
+	return b.ModulePath + "@" + version
+}
+
+// AppVersion returns the module version, falling back to the short VCS revision for development builds
+func (b BuildInfo) AppVersion() string {
+	if b.Version != "" && b.Version != "(devel)" {
+		return b.Version
+	}
+	if b.Revision == "" {
+		return ""
+	}
+
+	version := strutil.Truncate(b.Revision, 12)
+	if b.Dirty {
+		version += "-dirty"
+	}
+	return version
+}
+
+// BuildInfoAttrs returns the build info as log attributes for startup banners
+func BuildInfoAttrs() []any {
+	b := GetBuildInfo()
+	return []any{
+		slog.String("module", b.ModulePath),
+		slog.String("version", b.Version),
+		slog.String("vcs_revision", b.Revision),
+		slog.String("vcs_time", b.Time),
+		slog.Bool("vcs_dirty", b.Dirty),
+		slog.String("go_version", b.GoVersion),
+	}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the current time; logbundle reads time through it so tests can inject a fake clock
+// (see lgtest.Clock) and check sampling windows, rate limits and slow-request detection deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockHolder gives atomic.Pointer a single concrete type for any Clock
+type clockHolder struct {
+	clock  Clock
+	system bool
+}
+
+var clock atomic.Pointer[clockHolder]
+
+func init() {
+	SetClock(nil)
+}
+
+// SetClock replaces the clock used for record timestamps, breadcrumbs, request durations, rate limiters,
+// samplers and TTLs; nil restores the system clock
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(&clockHolder{clock: systemClock{}, system: true})
+		return
+	}
+	clock.Store(&clockHolder{clock: c})
+}
+
+// Now returns the current time of the active clock
+func Now() time.Time {
+	return clock.Load().clock.Now()
+}
+
+// Since returns the time elapsed since t on the active clock
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// IsSystemClock reports whether the system clock is active
+func IsSystemClock() bool {
+	return clock.Load().system
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ContextInspector extracts one value from a context for DumpContext; ok is false when the context
+// does not carry it
+type ContextInspector func(ctx context.Context) (value any, ok bool)
+
+var (
+	contextInspectorsMu sync.RWMutex
+	contextInspectors   = map[string]ContextInspector{}
+)
+
+// RegisterContextInspector adds a named entry to DumpContext
+// Packages core cannot import (config, lgsentry) register their context values this way
+func RegisterContextInspector(name string, fn ContextInspector) {
+	contextInspectorsMu.Lock()
+	defer contextInspectorsMu.Unlock()
+	contextInspectors[name] = fn
+}
+
+// DumpContext returns the logbundle-known values carried by ctx, for debugging lost context
+// (e.g. "why is my log missing log_trace_id"): the trace ID and its field name, the attributes the
+// handlers add to records logged with ctx ("attrs"), Sentry tags, the critical and debug scope marks,
+// the deadline and cancellation state, plus the registered inspectors (settings, Sentry hub, span and
+// baggage once those packages are imported)
+//
+// Usage:
+//
+//	slog.DebugContext(ctx, "context before enqueue", slog.Any("context", core.DumpContext(ctx)))
+func DumpContext(ctx context.Context) map[string]any {
+	out := map[string]any{
+		"trace_id_field": GetTraceIDFieldName(),
+	}
+	if ctx == nil {
+		out["nil_context"] = true
+		return out
+	}
+
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		out["trace_id"] = traceID
+	}
+	out["attrs"] = contextAttrs(ctx)
+	if tags := SentryTagsFromContext(ctx); len(tags) > 0 {
+		out["sentry_tags"] = tags
+	}
+	if IsCritical(ctx) {
+		out["critical"] = true
+	}
+	if IsDebugScope(ctx) {
+		out["debug_scope"] = true
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		out["deadline"] = deadline.Format(time.RFC3339Nano)
+		out["deadline_in"] = deadline.Sub(Now()).String()
+	}
+	if err := ctx.Err(); err != nil {
+		out["done"] = err.Error()
+	}
+
+	contextInspectorsMu.RLock()
+	defer contextInspectorsMu.RUnlock()
+	for name, inspect := range contextInspectors {
+		if value, ok := inspect(ctx); ok {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+// contextAttrs returns the attributes taken from ctx and added to each record, keyed as written
+// (see handler.TraceIDHandler); empty when ctx carries none, which is what a dump needs to show
+func contextAttrs(ctx context.Context) map[string]any {
+	attrs := map[string]any{}
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		attrs[GetTraceIDFieldName()] = traceID
+	}
+	return attrs
+}
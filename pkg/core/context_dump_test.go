@@ -0,0 +1,19 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDumpContextAttrs(t *testing.T) {
+	if attrs := DumpContext(context.Background())["attrs"]; !reflect.DeepEqual(attrs, map[string]any{}) {
+		t.Fatalf("attrs without a trace ID = %v, want empty", attrs)
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	want := map[string]any{GetTraceIDFieldName(): "trace-1"}
+	if attrs := DumpContext(ctx)["attrs"]; !reflect.DeepEqual(attrs, want) {
+		t.Fatalf("attrs = %v, want %v", attrs, want)
+	}
+}
@@ -0,0 +1,20 @@
+package core
+
+import "context"
+
+type criticalKey struct{}
+
+// WithCritical marks records logged with the returned context as critical: they bypass level filtering
+// and sampling and are written synchronously by writers that support it (see handler.SyncWriter)
+func WithCritical(ctx context.Context) context.Context {
+	return context.WithValue(ctx, criticalKey{}, true)
+}
+
+// IsCritical reports whether ctx was marked with WithCritical
+func IsCritical(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	critical, _ := ctx.Value(criticalKey{}).(bool)
+	return critical
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type debugScopeKey struct{}
+
+// WithDebugScope marks the context so that records logged with it are emitted at every level,
+// regardless of the handler's minimum level
+func WithDebugScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugScopeKey{}, true)
+}
+
+// IsDebugScope reports whether the context was marked with WithDebugScope
+func IsDebugScope(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(debugScopeKey{}).(bool)
+	return enabled
+}
+
+// GenerateDebugToken creates a token valid for ttl, in the form "<expiry unix>.<hex HMAC-SHA256>"
+// Hand it to the engineer debugging a request; it is accepted by lgfiber.DebugScopeMiddleware
+func GenerateDebugToken(secret []byte, ttl time.Duration) string {
+	expiry := strconv.FormatInt(Now().Add(ttl).Unix(), 10)
+	return expiry + "." + signDebugToken(secret, expiry)
+}
+
+// VerifyDebugToken checks a token produced by GenerateDebugToken
+func VerifyDebugToken(secret []byte, token string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || Now().Unix() > unix {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(signDebugToken(secret, expiry)))
+}
+
+func signDebugToken(secret []byte, expiry string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
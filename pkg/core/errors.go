@@ -3,142 +3,64 @@ package core
 import (
 	"fmt"
 	"log/slog"
-	"runtime"
 	"strings"
 )
 
-func ErrAttr(err error) slog.Attr {
-	return slog.Any("error", err)
-}
+// maxErrChainDepth guards against self-referencing Unwrap implementations
+const maxErrChainDepth = 32
 
-func GetLinePositionStringWithSkip(skip int) string {
-	_, file, line, ok := runtime.Caller(skip)
-	if !ok {
-		return "[unknown:0]"
-	}
-	return fmt.Sprintf("[%s:%d]", file, line)
+// ErrChainEntry describes a single error in an unwrap chain
+type ErrChainEntry struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
 
-// shouldSkipFrame determines if a stack frame should be filtered out
-func shouldSkipFrame(line, normalizedPath string) bool {
-	// Skip runtime and internal frames
-	internalPaths := []string{
-		"runtime/",
-		"/runtime.",
-		"logbundle-go/",
-		"/logbundle-go/",
-		"\\logbundle-go\\",
-	}
-
-	for _, path := range internalPaths {
-		if strings.Contains(normalizedPath, path) {
-			return true
-		}
-	}
-
-	// Skip middleware and panic frames
-	skipFunctions := []string{
-		"FiberRecoverMiddleware",
-		"RecoverMiddleware",
-		"RecoverWithContext",
-		"panic",
-		"(*Ctx).Next",
-	}
+// ErrChain is an unwrap chain, outermost error first
+// It renders as a compact single line in text output (String) and as an array in JSON output
+type ErrChain []ErrChainEntry
 
-	for _, fn := range skipFunctions {
-		if strings.Contains(line, fn) {
-			return true
+// String renders the chain as "type: message -> type: message"
+func (c ErrChain) String() string {
+	var builder strings.Builder
+	for i, e := range c {
+		if i > 0 {
+			builder.WriteString(" -> ")
 		}
+		builder.WriteString(e.Type)
+		builder.WriteString(": ")
+		builder.WriteString(e.Message)
 	}
-
-	return false
+	return builder.String()
 }
 
-// parseFileLocation extracts file path and line number from a stack trace line
-func parseFileLocation(nextLine string) (filePath, file string, lineNum int) {
-	parts := strings.Fields(nextLine)
-	if len(parts) == 0 {
-		return "", "", 0
-	}
-
-	filePath = parts[0]
-	fileLineParts := strings.Split(filePath, ":")
-
-	if len(fileLineParts) >= 2 {
-		file = fileLineParts[0]
-		fmt.Sscanf(fileLineParts[1], "%d", &lineNum)
-		return filePath, file, lineNum
-	}
-
-	return filePath, filePath, 0
+// GetErrChain walks err's unwrap chain (including errors.Join trees, depth-first) and returns every error in it
+func GetErrChain(err error) ErrChain {
+	var chain ErrChain
+	appendErrChain(&chain, err, 0)
+	return chain
 }
 
-// ExtractErrorLocationWithDetails extracts the error location from a stack trace string,
-// filtering out internal runtime and middleware frames to find the actual application code location
-func ExtractErrorLocationWithDetails(stackTrace string) (string, string, int) {
-	// Use early exit optimization: search for first .go: occurrence, then validate
-	const (
-		goExtension = ".go:"
-		goroutine   = "goroutine "
-	)
-
-	var i int
-	for i < len(stackTrace) {
-		// Find next line start
-		lineStart := i
-		lineEnd := strings.IndexByte(stackTrace[i:], '\n')
-		if lineEnd == -1 {
-			lineEnd = len(stackTrace)
-		} else {
-			lineEnd += i
-		}
-
-		line := strings.TrimSpace(stackTrace[lineStart:lineEnd])
-		i = lineEnd + 1
-
-		// Skip empty lines and goroutine headers
-		if line == "" || strings.HasPrefix(line, goroutine) {
-			continue
-		}
-
-		// Check if next line contains file location (peek ahead without full split)
-		if i < len(stackTrace) {
-			nextLineStart := i
-			nextLineEnd := strings.IndexByte(stackTrace[i:], '\n')
-			if nextLineEnd == -1 {
-				nextLineEnd = len(stackTrace)
-			} else {
-				nextLineEnd += i
-			}
-
-			nextLine := strings.TrimSpace(stackTrace[nextLineStart:nextLineEnd])
-
-			// Fast path: check for .go: without allocation
-			if idx := strings.Index(nextLine, goExtension); idx != -1 {
-				normalizedPath := strings.ReplaceAll(nextLine, "\\", "/")
+func appendErrChain(chain *ErrChain, err error, depth int) {
+	if err == nil || depth >= maxErrChainDepth {
+		return
+	}
 
-				// Skip internal and middleware frames
-				if !shouldSkipFrame(line, normalizedPath) {
-					return parseFileLocation(nextLine)
-				}
-			}
+	*chain = append(*chain, ErrChainEntry{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+	})
 
-			i = nextLineEnd + 1
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			appendErrChain(chain, inner, depth+1)
 		}
+	case interface{ Unwrap() error }:
+		appendErrChain(chain, e.Unwrap(), depth+1)
 	}
-
-	return "unknown location", "", 0
 }
 
-// TruncateString truncates the input string to the specified maximum number of characters (runes).
-func TruncateString(s string, maxChars int) string {
-	if maxChars <= 0 {
-		return ""
-	}
-
-	runes := []rune(s)
-	if len(runes) <= maxChars {
-		return s
-	}
-	return string(runes[:maxChars])
+// ErrChainAttr returns an "error_chain" attribute with every error in err's unwrap chain and its type
+func ErrChainAttr(err error) slog.Attr {
+	return slog.Any("error_chain", GetErrChain(err))
 }
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// AnonymizeIP truncates an IP address: the last octet of IPv4 addresses and everything after
+// the /64 prefix of IPv6 addresses are zeroed ("203.0.113.42" -> "203.0.113.0",
+// "2001:db8:1:2:3:4:5:6" -> "2001:db8:1:2::"). Comma-separated lists (X-Forwarded-For) and
+// host:port forms are handled; values that are not IP addresses are returned unchanged
+func AnonymizeIP(ip string) string {
+	if strings.Contains(ip, ",") {
+		parts := strings.Split(ip, ",")
+		for i, part := range parts {
+			parts[i] = AnonymizeIP(strings.TrimSpace(part))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		return anonymizeAddr(addr).String()
+	}
+	if addrPort, err := netip.ParseAddrPort(ip); err == nil {
+		return netip.AddrPortFrom(anonymizeAddr(addrPort.Addr()), addrPort.Port()).String()
+	}
+	return ip
+}
+
+func anonymizeAddr(addr netip.Addr) netip.Addr {
+	addr = addr.Unmap().WithZone("")
+	bits := 64
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return addr
+	}
+	return prefix.Addr()
+}
@@ -0,0 +1,27 @@
+package core
+
+import "testing"
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "203.0.113.42", want: "203.0.113.0"},
+		{in: "2001:db8:1:2:3:4:5:6", want: "2001:db8:1:2::"},
+		{in: "::ffff:203.0.113.42", want: "203.0.113.0"},
+		{in: "fe80::1:2:3:4%eth0", want: "fe80::"},
+		{in: "203.0.113.42:8080", want: "203.0.113.0:8080"},
+		{in: "[2001:db8:1:2:3:4:5:6]:443", want: "[2001:db8:1:2::]:443"},
+		{in: "203.0.113.42, 198.51.100.7", want: "203.0.113.0, 198.51.100.0"},
+		{in: "unknown", want: "unknown"},
+		{in: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := AnonymizeIP(tt.in); got != tt.want {
+				t.Fatalf("AnonymizeIP(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
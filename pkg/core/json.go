@@ -0,0 +1,233 @@
+package core
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultJSONAttrMaxBytes is the default size cap of values rendered by JSONAttr
+	DefaultJSONAttrMaxBytes = 4096
+	// jsonAttrMaxDepth limits nesting to keep deeply recursive structures readable
+	jsonAttrMaxDepth = 10
+	// jsonAttrMaxItems limits the number of rendered slice/map elements
+	jsonAttrMaxItems = 100
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// JSONAttr renders v as a JSON string attribute, capped at DefaultJSONAttrMaxBytes
+// Unlike slog.Any it never prints bare pointer addresses, includes unexported fields,
+// marks reference cycles as "<cycle>" and never panics (marshaling panics are reported in the value)
+func JSONAttr(key string, v any) slog.Attr {
+	return JSONAttrWithLimit(key, v, DefaultJSONAttrMaxBytes)
+}
+
+// JSONAttrWithLimit is like JSONAttr with a custom size cap (maxBytes <= 0 disables the cap)
+func JSONAttrWithLimit(key string, v any, maxBytes int) slog.Attr {
+	return slog.String(key, SafeJSON(v, maxBytes))
+}
+
+// SafeJSON marshals v into JSON, handling cycles, unexported fields and panics
+// The result is truncated to maxBytes (maxBytes <= 0 disables truncation)
+func SafeJSON(v any, maxBytes int) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = fmt.Sprintf(`"<marshal panic: %v>"`, r)
+		}
+	}()
+
+	w := &jsonWalker{visited: make(map[uintptr]struct{})}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(w.walk(reflect.ValueOf(v), 0)); err != nil {
+		return strconv.Quote("<marshal error: " + err.Error() + ">")
+	}
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+
+	if maxBytes > 0 && len(data) > maxBytes {
+		return strings.ToValidUTF8(string(data[:maxBytes]), "") + "...(truncated)"
+	}
+	return string(data)
+}
+
+// jsonWalker converts arbitrary values into a JSON-safe tree of maps, slices and primitives
+type jsonWalker struct {
+	// visited holds pointers on the current path to detect cycles
+	visited map[uintptr]struct{}
+}
+
+func (w *jsonWalker) walk(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > jsonAttrMaxDepth {
+		return "<max depth>"
+	}
+
+	// Prefer custom representations when the value can be safely accessed
+	if v.CanInterface() {
+		if out, ok := w.custom(v); ok {
+			return out
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if _, seen := w.visited[ptr]; seen {
+				return "<cycle>"
+			}
+			w.visited[ptr] = struct{}{}
+			defer delete(w.visited, ptr)
+		}
+		return w.walk(v.Elem(), depth+1)
+
+	case reflect.Struct:
+		return w.walkStruct(v, depth)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		ptr := v.Pointer()
+		if _, seen := w.visited[ptr]; seen {
+			return "<cycle>"
+		}
+		w.visited[ptr] = struct{}{}
+		defer delete(w.visited, ptr)
+
+		out := make(map[string]any, min(v.Len(), jsonAttrMaxItems))
+		iter := v.MapRange()
+		for iter.Next() {
+			if len(out) >= jsonAttrMaxItems {
+				out["<truncated>"] = v.Len() - jsonAttrMaxItems
+				break
+			}
+			out[fmt.Sprint(w.walk(iter.Key(), depth+1))] = w.walk(iter.Value(), depth+1)
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("<%d bytes>", v.Len())
+		}
+		n := min(v.Len(), jsonAttrMaxItems)
+		out := make([]any, 0, n+1)
+		for i := 0; i < n; i++ {
+			out = append(out, w.walk(v.Index(i), depth+1))
+		}
+		if v.Len() > n {
+			out = append(out, fmt.Sprintf("<%d more>", v.Len()-n))
+		}
+		return out
+
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != f || f > 1e308 || f < -1e308 { // NaN and Inf are not valid JSON
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return f
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			return nil
+		}
+		return "<" + v.Type().String() + ">"
+	default:
+		return "<" + v.Kind().String() + ">"
+	}
+}
+
+// walkStruct renders exported fields using their JSON names and unexported fields by Go name
+func (w *jsonWalker) walkStruct(v reflect.Value, depth int) any {
+	t := v.Type()
+	out := make(map[string]any, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Name
+
+		if field.IsExported() {
+			tag, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == "-" && opts == "" {
+				continue
+			}
+			if tag != "" && tag != "-" {
+				name = tag
+			}
+			if strings.Contains(opts, "omitempty") && v.Field(i).IsZero() {
+				continue
+			}
+		}
+
+		out[name] = w.walk(v.Field(i), depth+1)
+	}
+
+	return out
+}
+
+// custom returns the representation of types with their own marshaling or string form
+func (w *jsonWalker) custom(v reflect.Value) (out any, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, ok = fmt.Sprintf("<marshal panic: %v>", r), true
+		}
+	}()
+
+	t := v.Type()
+	if (t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false
+	}
+
+	switch {
+	case t == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), true
+	case t.Implements(jsonMarshalerType):
+		data, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil || !json.Valid(data) {
+			return nil, false
+		}
+		return json.RawMessage(data), true
+	case t.Implements(errorType):
+		return v.Interface().(error).Error(), true
+	case t.Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	return nil, false
+}
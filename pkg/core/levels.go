@@ -1,33 +1,23 @@
 package core
 
 import (
+	"fmt"
 	"log/slog"
-	"os"
 	"strings"
 )
 
-func GetLvlFromEnv(key string) slog.Level {
-	if value := os.Getenv(key); value != "" {
-		return GetLvlFromStr(value)
-	}
-	return slog.LevelWarn
-}
-
-func GetLvlFromStr(s string) slog.Level {
+// ParseLevel parses a level name (debug, info, warn or warning, error), rejecting unknown names
+func ParseLevel(s string) (slog.Level, error) {
 	switch strings.ToLower(s) {
 	case "debug":
-		return slog.LevelDebug
+		return slog.LevelDebug, nil
 	case "info":
-		return slog.LevelInfo
+		return slog.LevelInfo, nil
 	case "warn", "warning":
-		return slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "error":
-		return slog.LevelError
+		return slog.LevelError, nil
 	default:
-		return slog.LevelWarn
+		return 0, fmt.Errorf("unknown level %q", s)
 	}
 }
-
-func GetBoolFromStr(s string) bool {
-	return strings.ToLower(s) == "true"
-}
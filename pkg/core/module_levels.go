@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// moduleLevelRule overrides the minimum level for packages matching pattern
+type moduleLevelRule struct {
+	pattern string
+	level   slog.Level
+}
+
+// moduleLevels is an immutable rule set; replacing it also resets the per-PC cache
+type moduleLevels struct {
+	rules    []moduleLevelRule // Sorted by pattern length, longest first
+	minLevel slog.Level        // Lowest level enabled by any rule
+	cache    sync.Map          // uintptr (PC) -> moduleLevelMatch
+}
+
+type moduleLevelMatch struct {
+	level slog.Level
+	ok    bool
+}
+
+// SetModuleLevels configures per-package level overrides from a spec like
+// "pkg/integrations/lgfiber=debug,github.com/ourapp/billing=info"
+// A pattern matches the record's source package exactly, as a path suffix, or as a parent path;
+// the longest matching pattern wins. The package comes from the record's PC, or from the caller
+// carried by the context for records logged without source (see WithModulePC); records with neither
+// only get the handler level. An empty spec removes all overrides. Safe to call at runtime
+func SetModuleLevels(spec string) error {
+	ml, err := parseModuleLevels(spec)
+	if err != nil {
+		return err
+	}
+	updateLevels(func(s *LevelState) { s.modules = ml })
+	return nil
+}
+
+// ValidateModuleLevels checks a spec accepted by SetModuleLevels without applying it
+func ValidateModuleLevels(spec string) error {
+	_, err := parseModuleLevels(spec)
+	return err
+}
+
+// parseModuleLevels builds a rule set from spec (nil for an empty spec)
+func parseModuleLevels(spec string) (*moduleLevels, error) {
+	var rules []moduleLevelRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pattern, levelStr, ok := strings.Cut(part, "=")
+		pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid module level rule %q: expected <package>=<level>", part)
+		}
+
+		level, err := ParseLevel(strings.TrimSpace(levelStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid module level rule %q: %w", part, err)
+		}
+
+		rules = append(rules, moduleLevelRule{pattern: pattern, level: level})
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].pattern) > len(rules[j].pattern)
+	})
+
+	ml := &moduleLevels{rules: rules, minLevel: rules[0].level}
+	for _, r := range rules {
+		ml.minLevel = min(ml.minLevel, r.level)
+	}
+	return ml, nil
+}
+
+// GetModuleLevels returns the current override spec in normalized form
+func GetModuleLevels() string {
+	ml := Levels().modules
+	if ml == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(ml.rules))
+	for _, r := range ml.rules {
+		parts = append(parts, r.pattern+"="+strings.ToLower(r.level.String()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// MinModuleLevel returns the lowest level enabled by any override, and false if no overrides are set
+func MinModuleLevel() (slog.Level, bool) {
+	return Levels().MinModuleLevel()
+}
+
+// MinModuleLevel returns the lowest level enabled by any override, and false if no overrides are set
+func (s *LevelState) MinModuleLevel() (slog.Level, bool) {
+	ml := s.modules
+	if ml == nil {
+		return 0, false
+	}
+	return ml.minLevel, true
+}
+
+// modulePCKey carries the caller of a record logged without source
+type modulePCKey struct{}
+
+// WithModulePC returns ctx carrying pc as the caller of the record about to be handled, so module
+// overrides apply to records whose slog.Record.PC is left zero to keep the source out of the output
+// Use it only for the Handle call of that record
+func WithModulePC(ctx context.Context, pc uintptr) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, modulePCKey{}, pc)
+}
+
+// ModuleLevelForRecord returns the override level for a record with pc, using the caller carried by
+// ctx (see WithModulePC) when pc is zero
+func ModuleLevelForRecord(ctx context.Context, pc uintptr) (slog.Level, bool) {
+	return Levels().ModuleLevelForRecord(ctx, pc)
+}
+
+// ModuleLevelForRecord returns the override level for a record with pc, using the caller carried by
+// ctx (see WithModulePC) when pc is zero
+func (s *LevelState) ModuleLevelForRecord(ctx context.Context, pc uintptr) (slog.Level, bool) {
+	if pc == 0 && ctx != nil {
+		pc, _ = ctx.Value(modulePCKey{}).(uintptr)
+	}
+	return s.ModuleLevelForPC(pc)
+}
+
+// ModuleLevelForPC returns the override level for the package containing pc, and false if no rule matches
+// Results are cached per PC until the rules change
+func ModuleLevelForPC(pc uintptr) (slog.Level, bool) {
+	return Levels().ModuleLevelForPC(pc)
+}
+
+// ModuleLevelForPC returns the override level for the package containing pc, and false if no rule matches
+func (s *LevelState) ModuleLevelForPC(pc uintptr) (slog.Level, bool) {
+	ml := s.modules
+	if ml == nil || pc == 0 {
+		return 0, false
+	}
+
+	if cached, ok := ml.cache.Load(pc); ok {
+		m := cached.(moduleLevelMatch)
+		return m.level, m.ok
+	}
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	pkg := packagePath(frame.Function)
+
+	var m moduleLevelMatch
+	for _, r := range ml.rules {
+		if matchesPackage(pkg, r.pattern) {
+			m = moduleLevelMatch{level: r.level, ok: true}
+			break
+		}
+	}
+
+	ml.cache.Store(pc, m)
+	return m.level, m.ok
+}
+
+// packagePath extracts the import path from a fully qualified function name
+// e.g. "github.com/org/app/billing.(*Service).Charge" -> "github.com/org/app/billing"
+func packagePath(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[lastSlash+1:], '.'); dot != -1 {
+		return function[:lastSlash+1+dot]
+	}
+	return function
+}
+
+// matchesPackage reports whether pkg equals pattern, ends with "/pattern", or lives under it
+func matchesPackage(pkg, pattern string) bool {
+	if pkg == pattern || strings.HasPrefix(pkg, pattern+"/") {
+		return true
+	}
+	if strings.HasSuffix(pkg, "/"+pattern) {
+		return true
+	}
+	return strings.Contains(pkg, "/"+pattern+"/")
+}
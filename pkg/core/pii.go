@@ -0,0 +1,42 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+)
+
+// PIIValue marks a log attribute value as personal data
+// Handlers without PII support log the underlying value unchanged (it implements slog.LogValuer)
+type PIIValue struct {
+	Value slog.Value
+}
+
+// LogValue returns the underlying value
+func (p PIIValue) LogValue() slog.Value {
+	return p.Value
+}
+
+// PII returns an attribute tagged as personal data
+func PII(key string, value any) slog.Attr {
+	return slog.Any(key, PIIValue{Value: slog.AnyValue(value)})
+}
+
+// AsPII reports whether v is a PII-tagged value and returns it
+func AsPII(v slog.Value) (PIIValue, bool) {
+	if v.Kind() != slog.KindLogValuer {
+		return PIIValue{}, false
+	}
+	p, ok := v.LogValuer().(PIIValue)
+	return p, ok
+}
+
+// Pseudonymize returns a stable pseudonym for value: the first 16 bytes of HMAC-SHA256(salt, value),
+// hex encoded. The same value and salt always give the same pseudonym, so records stay correlatable
+// without exposing the value; discarding the salt makes existing pseudonyms unlinkable
+func Pseudonymize(salt []byte, value string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
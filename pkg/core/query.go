@@ -0,0 +1,110 @@
+package core
+
+import (
+	"maps"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// FilteredValue replaces scrubbed query parameter values
+const FilteredValue = "[Filtered]"
+
+// DefaultQueryDenyList lists query parameters whose values are scrubbed by default
+// Parameters whose name contains "token", "secret" or "password" are always scrubbed
+var DefaultQueryDenyList = []string{
+	"access_token", "refresh_token", "id_token", "token", "api_key", "apikey", "key", "secret",
+	"client_secret", "password", "passwd", "pwd", "auth", "authorization", "code", "signature", "sig",
+	"session", "sessionid", "session_id", "jwt", "x_amz_signature", "x_amz_credential", "x_amz_security_token",
+}
+
+// queryDenyList holds normalized parameter names (see normalizeQueryParam)
+var queryDenyList atomic.Pointer[map[string]struct{}]
+
+func init() {
+	SetQueryDenyList(DefaultQueryDenyList...)
+}
+
+// SetQueryDenyList replaces the query parameters whose values are scrubbed
+// Names are case-insensitive and "-" matches "_"
+func SetQueryDenyList(params ...string) {
+	list := make(map[string]struct{}, len(params))
+	for _, p := range params {
+		list[normalizeQueryParam(p)] = struct{}{}
+	}
+	queryDenyList.Store(&list)
+}
+
+// AddQueryDenyList adds query parameters to the deny-list
+func AddQueryDenyList(params ...string) {
+	list := maps.Clone(*queryDenyList.Load())
+	for _, p := range params {
+		list[normalizeQueryParam(p)] = struct{}{}
+	}
+	queryDenyList.Store(&list)
+}
+
+// IsSensitiveQueryParam reports whether the value of the query parameter name is scrubbed
+func IsSensitiveQueryParam(name string) bool {
+	name = normalizeQueryParam(name)
+	if _, ok := (*queryDenyList.Load())[name]; ok {
+		return true
+	}
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "password")
+}
+
+// ScrubQuery replaces the values of sensitive parameters in a raw query string with FilteredValue
+// Parameter order and the encoding of other parameters are preserved
+func ScrubQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	parts := strings.Split(rawQuery, "&")
+	changed := false
+	for i, part := range parts {
+		name, _, hasValue := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if hasValue && IsSensitiveQueryParam(name) {
+			parts[i] = part[:strings.IndexByte(part, '=')+1] + FilteredValue
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rawQuery
+	}
+	return strings.Join(parts, "&")
+}
+
+// ScrubURL applies ScrubQuery to the query string of a URL or request URI
+func ScrubURL(rawURL string) string {
+	path, query, ok := strings.Cut(rawURL, "?")
+	if !ok {
+		return rawURL
+	}
+	query, fragment, hasFragment := strings.Cut(query, "#")
+	out := path + "?" + ScrubQuery(query)
+	if hasFragment {
+		out += "#" + fragment
+	}
+	return out
+}
+
+// ScrubQueryParams returns a copy of params with sensitive values replaced by FilteredValue
+func ScrubQueryParams(params map[string]string) map[string]string {
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if IsSensitiveQueryParam(k) {
+			v = FilteredValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func normalizeQueryParam(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+}
@@ -0,0 +1,44 @@
+package core
+
+import "testing"
+
+func TestScrubURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "/cb?access_token=abc&page=2", want: "/cb?access_token=[Filtered]&page=2"},
+		{in: "/cb?Refresh-Token=abc", want: "/cb?Refresh-Token=[Filtered]"},
+		{in: "/cb?my_api_secret=abc&q=go%20lang", want: "/cb?my_api_secret=[Filtered]&q=go%20lang"},
+		{in: "/cb?access%5Ftoken=abc", want: "/cb?access%5Ftoken=[Filtered]"},
+		{in: "/cb?code=xyz#section", want: "/cb?code=[Filtered]#section"},
+		{in: "/cb?token", want: "/cb?token"},
+		{in: "/search?q=token", want: "/search?q=token"},
+		{in: "/plain", want: "/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := ScrubURL(tt.in); got != tt.want {
+				t.Fatalf("ScrubURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryDenyList(t *testing.T) {
+	t.Cleanup(func() { SetQueryDenyList(DefaultQueryDenyList...) })
+
+	AddQueryDenyList("Tenant-Ref")
+	params := ScrubQueryParams(map[string]string{"tenant_ref": "t-1", "page": "2", "api_key": "k"})
+	if params["tenant_ref"] != FilteredValue || params["api_key"] != FilteredValue || params["page"] != "2" {
+		t.Fatalf("params = %v, want tenant_ref and api_key filtered", params)
+	}
+
+	SetQueryDenyList("page")
+	if IsSensitiveQueryParam("api_key") {
+		t.Fatal("api_key still scrubbed after the deny list was replaced")
+	}
+	if !IsSensitiveQueryParam("page") || !IsSensitiveQueryParam("reset_password") {
+		t.Fatal("listed or password-like parameter not scrubbed")
+	}
+}
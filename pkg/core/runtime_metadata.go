@@ -0,0 +1,86 @@
+package core
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// RuntimeMetadata describes the running process
+type RuntimeMetadata struct {
+	HostName   string
+	PID        int
+	GoVersion  string
+	AppVersion string
+}
+
+var (
+	runtimeMetadata     RuntimeMetadata
+	runtimeMetadataOnce sync.Once
+)
+
+// environmentVariables are read by EnvironmentName, in order
+var environmentVariables = []string{"APP_ENV", "ENVIRONMENT", "GO_ENV"}
+
+// EnvironmentName returns the deployment environment from the first non-empty of APP_ENV, ENVIRONMENT
+// and GO_ENV, or "" if none is set
+func EnvironmentName() string {
+	for _, key := range environmentVariables {
+		if env := os.Getenv(key); env != "" {
+			return env
+		}
+	}
+	return ""
+}
+
+// GetRuntimeMetadata returns process metadata, computed once on first use
+func GetRuntimeMetadata() RuntimeMetadata {
+	runtimeMetadataOnce.Do(func() {
+		hostName, err := os.Hostname()
+		if err != nil {
+			hostName = "unknown"
+		}
+
+		runtimeMetadata = RuntimeMetadata{
+			HostName:   hostName,
+			PID:        os.Getpid(),
+			GoVersion:  runtime.Version(),
+			AppVersion: getAppVersion(),
+		}
+	})
+	return runtimeMetadata
+}
+
+// RuntimeMetadataAttrs returns the metadata as log attributes
+// (host.name, process.pid, go.version, app.version)
+func RuntimeMetadataAttrs() []slog.Attr {
+	md := GetRuntimeMetadata()
+	return []slog.Attr{
+		slog.String("host.name", md.HostName),
+		slog.Int("process.pid", md.PID),
+		slog.String("go.version", md.GoVersion),
+		slog.String("app.version", md.AppVersion),
+	}
+}
+
+// RuntimeMetadataTags returns the metadata as string tags (e.g. for Sentry)
+func RuntimeMetadataTags() map[string]string {
+	md := GetRuntimeMetadata()
+	return map[string]string{
+		"host.name":   md.HostName,
+		"process.pid": strconv.Itoa(md.PID),
+		"go.version":  md.GoVersion,
+		"app.version": md.AppVersion,
+	}
+}
+
+// getAppVersion returns the main module version from build info,
+// falling back to the VCS revision for development builds
+func getAppVersion() string {
+	if version := GetBuildInfo().AppVersion(); version != "" {
+		return version
+	}
+	return "unknown"
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// secretMinTokenLength is the shortest token checked by the entropy heuristic
+	secretMinTokenLength = 24
+	// secretMinEntropy is the Shannon entropy (bits per character) above which a token looks random
+	secretMinEntropy = 4.0
+)
+
+// secretPattern detects a known secret format
+type secretPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+var (
+	secretPatterns = []secretPattern{
+		{"private_key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)},
+		{"jwt", regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+		{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{"github_token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{"slack_token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+		{"stripe_key", regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}\b`)},
+		{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+		{"bearer_token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`)},
+	}
+
+	// secretTokenRe finds candidate tokens for the entropy heuristic
+	secretTokenRe = regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`)
+)
+
+// MaskSecrets replaces likely secrets in s with "****[kind]" and returns the kinds found (sorted, unique)
+// Detection covers private key blocks, JWTs, well-known API key formats and, as a fallback,
+// long high-entropy tokens mixing upper case, lower case and digits
+func MaskSecrets(s string) (string, []string) {
+	if len(s) < 16 {
+		return s, nil
+	}
+
+	found := make(map[string]struct{})
+	for _, p := range secretPatterns {
+		if !p.re.MatchString(s) {
+			continue
+		}
+		found[p.kind] = struct{}{}
+		s = p.re.ReplaceAllString(s, MaskedValue+"["+p.kind+"]")
+	}
+
+	s = secretTokenRe.ReplaceAllStringFunc(s, func(token string) string {
+		if !isHighEntropyToken(token) {
+			return token
+		}
+		found["high_entropy"] = struct{}{}
+		return MaskedValue + "[high_entropy]"
+	})
+
+	if len(found) == 0 {
+		return s, nil
+	}
+	kinds := make([]string, 0, len(found))
+	for k := range found {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return s, kinds
+}
+
+// isHighEntropyToken reports whether a token looks like a random credential
+func isHighEntropyToken(token string) bool {
+	token = strings.TrimRight(token, "=")
+	if len(token) < secretMinTokenLength {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for i := 0; i < len(token); i++ {
+		c := token[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+
+	return shannonEntropy(token) >= secretMinEntropy
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	n := float64(len(s))
+	entropy := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"maps"
+)
+
+type sentryTagsKey struct{}
+
+// WithSentryTags returns a context carrying tags that logbundle adds to every Sentry event captured with it
+// Tags from parent contexts are inherited; on conflicts the innermost value wins
+func WithSentryTags(ctx context.Context, tags map[string]string) context.Context {
+	if len(tags) == 0 {
+		return ctx
+	}
+
+	parent := SentryTagsFromContext(ctx)
+	merged := make(map[string]string, len(parent)+len(tags))
+	maps.Copy(merged, parent)
+	maps.Copy(merged, tags)
+
+	return context.WithValue(ctx, sentryTagsKey{}, merged)
+}
+
+// SentryTagsFromContext returns the tags stored with WithSentryTags (nil if none)
+// The returned map must not be modified
+func SentryTagsFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	tags, _ := ctx.Value(sentryTagsKey{}).(map[string]string)
+	return tags
+}
+
+// SentryScopeTags returns the tags to apply to a Sentry scope for ctx: WithSentryTags values plus
+// the trace ID under its configured field name
+func SentryScopeTags(ctx context.Context) map[string]string {
+	tags := SentryTagsFromContext(ctx)
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		return tags
+	}
+
+	merged := make(map[string]string, len(tags)+1)
+	maps.Copy(merged, tags)
+	merged[GetTraceIDFieldName()] = traceID
+	return merged
+}
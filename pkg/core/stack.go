@@ -0,0 +1,231 @@
+package core
+
+import (
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackFrames bounds the frames captured by CallerFrames
+const maxStackFrames = 64
+
+// Frame is a single stack frame
+type Frame struct {
+	Function string `json:"function"` // Fully qualified function, e.g. "github.com/org/app/pkg.(*T).Method"
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Location returns "file:line" (just the file when the line is unknown)
+func (f Frame) Location() string {
+	if f.Line <= 0 {
+		return f.File
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// CallerFrames returns the frames of the calling goroutine, skipping skip frames above the caller
+// (0 starts at the function calling CallerFrames)
+func CallerFrames(skip int) []Frame {
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return nil
+	}
+	iter := runtime.CallersFrames(pcs[:n])
+
+	frames := make([]Frame, 0, n)
+	for {
+		f, more := iter.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// ParseStack parses a textual goroutine trace (debug.Stack(), panic output) into frames
+// Only the first goroutine is parsed. Paths may contain spaces or Windows drive letters; malformed
+// lines are skipped, so any input yields a (possibly empty) result without panicking
+func ParseStack(trace string) []Frame {
+	var frames []Frame
+	var function string
+	seenHeader := false
+
+	// A stray carriage return also ends a line, so frames never carry line breaks into log output
+	lines := strings.FieldsFunc(trace, func(r rune) bool { return r == '\n' || r == '\r' })
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "goroutine ") && strings.HasSuffix(line, ":") {
+			if seenHeader {
+				break
+			}
+			seenHeader = true
+			continue
+		}
+
+		// File lines are indented; function lines are not
+		if line[0] != '\t' && line[0] != ' ' {
+			function = parseFunctionLine(line)
+			continue
+		}
+		if function == "" {
+			continue
+		}
+
+		file, lineNum := parseFileLine(strings.TrimSpace(line))
+		if file != "" {
+			frames = append(frames, Frame{Function: function, File: file, Line: lineNum})
+		}
+		function = ""
+	}
+
+	return frames
+}
+
+// parseFunctionLine returns the function name of "pkg.Func(args...)" or "created by pkg.Func in goroutine N"
+func parseFunctionLine(line string) string {
+	if rest, ok := strings.CutPrefix(line, "created by "); ok {
+		if i := strings.Index(rest, " in goroutine "); i >= 0 {
+			rest = rest[:i]
+		}
+		return rest
+	}
+
+	// Arguments never contain parentheses, so the last "(" opens the argument list
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndexByte(line, '('); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseFileLine parses "path/to/file.go:123 +0x1d"; the line number follows the last colon,
+// so paths with spaces or drive letters ("C:\src\app.go:12") are kept intact
+func parseFileLine(s string) (string, int) {
+	if i := strings.LastIndex(s, " +0x"); i >= 0 {
+		s = s[:i]
+	}
+
+	i := strings.LastIndexByte(s, ':')
+	if i <= 0 {
+		return s, 0
+	}
+	lineNum, err := strconv.Atoi(s[i+1:])
+	if err != nil || lineNum < 0 {
+		return s, 0
+	}
+	return s[:i], lineNum
+}
+
+// FrameFilters selects the frames skipped when locating the application code in a stack trace
+type FrameFilters struct {
+	// FunctionPrefixes skips frames whose fully qualified function starts with a prefix
+	// (e.g. "runtime.", "github.com/org/wrapper/")
+	FunctionPrefixes []string
+	// FunctionPatterns skips frames whose function contains a pattern (e.g. "(*Ctx).Next")
+	FunctionPatterns []string
+	// FilePrefixes skips frames whose file path starts with a prefix (backslashes match "/")
+	FilePrefixes []string
+}
+
+// DefaultFrameFilters skips the Go runtime, logbundle itself, panic machinery and fiber's handler chaining
+var DefaultFrameFilters = FrameFilters{
+	FunctionPrefixes: []string{"runtime.", "runtime/", "github.com/aeternitas-infinita/logbundle-go/", "github.com/aeternitas-infinita/logbundle-go."},
+	FunctionPatterns: []string{"(*Ctx).Next"},
+}
+
+var frameFilters atomic.Pointer[FrameFilters]
+
+func init() {
+	SetFrameFilters(DefaultFrameFilters)
+}
+
+// SetFrameFilters replaces the frame-skipping rules used by FirstAppFrame and the error location helpers
+// Extend the defaults to hide other wrapper libraries:
+//
+//	f := core.DefaultFrameFilters
+//	f.FunctionPrefixes = append(slices.Clone(f.FunctionPrefixes), "github.com/org/errkit/")
+//	core.SetFrameFilters(f)
+func SetFrameFilters(f FrameFilters) {
+	f.FunctionPrefixes = slices.Clone(f.FunctionPrefixes)
+	f.FunctionPatterns = slices.Clone(f.FunctionPatterns)
+	f.FilePrefixes = make([]string, len(f.FilePrefixes))
+	for i, p := range f.FilePrefixes {
+		f.FilePrefixes[i] = strings.ReplaceAll(p, "\\", "/")
+	}
+	frameFilters.Store(&f)
+}
+
+// GetFrameFilters returns the active frame-skipping rules
+func GetFrameFilters() FrameFilters {
+	f := *frameFilters.Load()
+	f.FunctionPrefixes = slices.Clone(f.FunctionPrefixes)
+	f.FunctionPatterns = slices.Clone(f.FunctionPatterns)
+	f.FilePrefixes = slices.Clone(f.FilePrefixes)
+	return f
+}
+
+// Skips reports whether the frame is filtered out; the "panic" builtin is always skipped
+func (ff *FrameFilters) Skips(f Frame) bool {
+	if f.Function == "panic" {
+		return true
+	}
+	for _, p := range ff.FunctionPrefixes {
+		if strings.HasPrefix(f.Function, p) {
+			return true
+		}
+	}
+	for _, p := range ff.FunctionPatterns {
+		if strings.Contains(f.Function, p) {
+			return true
+		}
+	}
+	if len(ff.FilePrefixes) > 0 {
+		file := strings.ReplaceAll(f.File, "\\", "/")
+		for _, p := range ff.FilePrefixes {
+			if strings.HasPrefix(file, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FirstAppFrame returns the first frame that belongs to application code (see SetFrameFilters)
+func FirstAppFrame(frames []Frame) (Frame, bool) {
+	filters := frameFilters.Load()
+	for _, f := range frames {
+		if !filters.Skips(f) {
+			return f, true
+		}
+	}
+	return Frame{}, false
+}
+
+// ExtractErrorLocationWithDetails extracts the error location from a stack trace string,
+// filtering out internal runtime and middleware frames to find the actual application code location
+// Returns "file:line", the file and the line ("unknown location", "", 0 if no application frame is found)
+func ExtractErrorLocationWithDetails(stackTrace string) (string, string, int) {
+	return frameLocation(FirstAppFrame(ParseStack(stackTrace)))
+}
+
+// CallerErrorLocation is ExtractErrorLocationWithDetails for the current goroutine, using
+// runtime.CallersFrames instead of parsing a textual trace (e.g. from a deferred recover)
+func CallerErrorLocation() (string, string, int) {
+	return frameLocation(FirstAppFrame(CallerFrames(1)))
+}
+
+func frameLocation(f Frame, ok bool) (string, string, int) {
+	if !ok {
+		return "unknown location", "", 0
+	}
+	return f.Location(), f.File, f.Line
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+const goroutineTrace = `goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+github.com/org/app/handlers.(*Orders).Create(0xc000010000, {0x0, 0x0})
+	/home/dev/my app/handlers/orders.go:42 +0x1d
+created by github.com/org/app.Start in goroutine 7
+	C:\src\app\main.go:12 +0x25
+
+goroutine 9 [chan receive]:
+github.com/org/app.other()
+	/src/other.go:1 +0x1
+`
+
+func TestParseStack(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace string
+		want  []Frame
+	}{
+		{
+			name:  "goroutine trace",
+			trace: goroutineTrace,
+			want: []Frame{
+				{Function: "runtime/debug.Stack", File: "/usr/local/go/src/runtime/debug/stack.go", Line: 26},
+				{Function: "github.com/org/app/handlers.(*Orders).Create", File: "/home/dev/my app/handlers/orders.go", Line: 42},
+				{Function: "github.com/org/app.Start", File: `C:\src\app\main.go`, Line: 12},
+			},
+		},
+		{name: "empty", trace: "", want: nil},
+		{name: "file line without function", trace: "\t/src/a.go:1 +0x1\n", want: nil},
+		{
+			name:  "missing line number",
+			trace: "main.main()\n\t/src/main.go\n",
+			want:  []Frame{{Function: "main.main", File: "/src/main.go"}},
+		},
+		{
+			name:  "windows line endings",
+			trace: "main.main()\r\n\t/src/main.go:3 +0x1\r\n",
+			want:  []Frame{{Function: "main.main", File: "/src/main.go", Line: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseStack(tt.trace)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseStack() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("frame %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCallerFrames(t *testing.T) {
+	frames := CallerFrames(0)
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestCallerFrames") {
+		t.Fatalf("CallerFrames(0) = %+v, want the test function first", frames)
+	}
+	if frames := CallerFrames(1 << 10); frames != nil {
+		t.Fatalf("CallerFrames beyond the stack = %+v, want nil", frames)
+	}
+}
+
+func FuzzParseStack(f *testing.F) {
+	f.Add(goroutineTrace)
+	f.Add(string(debug.Stack()))
+	f.Add("panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/main.go:5 +0x1\n")
+	f.Add("created by main.init in goroutine 1\n\t:7\n")
+	f.Add("(\n\t:\n )\n\t+0x")
+
+	f.Fuzz(func(t *testing.T, trace string) {
+		for _, frame := range ParseStack(trace) {
+			if frame.Function == "" || frame.File == "" {
+				t.Fatalf("incomplete frame %+v", frame)
+			}
+			if frame.Line < 0 {
+				t.Fatalf("negative line in %+v", frame)
+			}
+			if strings.ContainsAny(frame.File, "\r\n") {
+				t.Fatalf("line break in file of %+v", frame)
+			}
+		}
+		_, _, _ = ExtractErrorLocationWithDetails(trace)
+	})
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// DefaultTraceIDFieldName is the default output field name for trace IDs
+const DefaultTraceIDFieldName = "log_trace_id"
+
+// traceIDKey is the immutable context key for trace IDs; the output field name is configured separately,
+// so renaming the field never breaks lookups of IDs already stored in contexts
+type traceIDKey struct{}
+
+var traceIDFieldName atomic.Value // string
+
+func init() {
+	traceIDFieldName.Store(DefaultTraceIDFieldName)
+}
+
+// WithTraceID returns a context carrying the trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored with WithTraceID, or "" if none
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// SetTraceIDFieldName sets the field name used for trace IDs in log records and Sentry tags
+// An empty name restores DefaultTraceIDFieldName
+func SetTraceIDFieldName(name string) {
+	if name == "" {
+		name = DefaultTraceIDFieldName
+	}
+	traceIDFieldName.Store(name)
+}
+
+// GetTraceIDFieldName returns the field name used for trace IDs
+func GetTraceIDFieldName() string {
+	return traceIDFieldName.Load().(string)
+}
+
+// NewTraceID generates a random 128-bit trace ID as 32 hex characters
+func NewTraceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,31 @@
+package core
+
+import "sync/atomic"
+
+// userIDHashKey holds the HMAC key for user IDs (nil disables hashing)
+var userIDHashKey atomic.Pointer[[]byte]
+
+// SetUserIDHashKey sets the HMAC key used by HashUserID; an empty key disables hashing
+func SetUserIDHashKey(key []byte) {
+	if len(key) == 0 {
+		userIDHashKey.Store(nil)
+		return
+	}
+	k := append([]byte(nil), key...)
+	userIDHashKey.Store(&k)
+}
+
+// UserIDHashingEnabled reports whether a user ID hash key is configured
+func UserIDHashingEnabled() bool {
+	return userIDHashKey.Load() != nil
+}
+
+// HashUserID returns the keyed hash of id (see Pseudonymize), or id unchanged when no key is configured
+// The hash is stable for a given key, so logs and Sentry events of the same user stay correlatable
+func HashUserID(id string) string {
+	key := userIDHashKey.Load()
+	if key == nil || id == "" {
+		return id
+	}
+	return Pseudonymize(*key, id)
+}
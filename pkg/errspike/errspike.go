@@ -0,0 +1,271 @@
+package errspike
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Config holds configuration for the error spike analyzer
+type Config struct {
+	// Logger for spike meta-events (if nil, uses middleware logger or slog.Default())
+	Logger *slog.Logger
+	// Window is the length of a counting window (default: 1 minute)
+	Window time.Duration
+	// Threshold is the minimum number of errors per window to report a spike (default: 10)
+	Threshold int
+	// Multiplier is how many times above its baseline a known fingerprint must be to spike (default: 3)
+	Multiplier float64
+	// Cooldown suppresses repeated reports for the same fingerprint (default: 10 minutes)
+	Cooldown time.Duration
+	// MaxFingerprints bounds memory; the quietest fingerprints are evicted first (default: 1000)
+	MaxFingerprints int
+	// CaptureToSentry also sends each spike to Sentry as a warning (requires Sentry to be enabled)
+	CaptureToSentry bool
+}
+
+// Spike describes a detected error spike
+type Spike struct {
+	Fingerprint string
+	Count       int           // Errors in the window that triggered the spike
+	Baseline    float64       // Average errors per window before the spike (0 for new fingerprints)
+	Window      time.Duration // Window length
+	New         bool          // True if the fingerprint was never seen before
+	Example     string        // Most recent error message for the fingerprint
+}
+
+// fingerprintStats holds per-fingerprint counters
+type fingerprintStats struct {
+	count        int
+	baseline     float64 // Exponentially weighted average of past window counts
+	windows      int     // Completed windows observed
+	lastReported time.Time
+	example      string
+}
+
+// baselineWeight is the weight of the newest window in the baseline average
+const baselineWeight = 0.3
+
+// Analyzer tracks error rates per fingerprint and reports spikes at the end of each window
+type Analyzer struct {
+	cfg   Config
+	mu    sync.Mutex
+	stats map[string]*fingerprintStats
+}
+
+// NewAnalyzer creates an analyzer with defaults applied; call Start to begin evaluating windows
+func NewAnalyzer(cfg Config) *Analyzer {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 10
+	}
+	if cfg.Multiplier <= 0 {
+		cfg.Multiplier = 3
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 10 * time.Minute
+	}
+	if cfg.MaxFingerprints <= 0 {
+		cfg.MaxFingerprints = 1000
+	}
+
+	return &Analyzer{
+		cfg:   cfg,
+		stats: make(map[string]*fingerprintStats, 64),
+	}
+}
+
+// Record counts one error occurrence for the fingerprint
+// message is kept as an example for the spike report
+func (a *Analyzer) Record(fingerprint, message string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.stats[fingerprint]
+	if !ok {
+		if len(a.stats) >= a.cfg.MaxFingerprints {
+			a.evictLocked()
+		}
+		s = &fingerprintStats{}
+		a.stats[fingerprint] = s
+	}
+	s.count++
+	s.example = message
+}
+
+// Start evaluates windows in the background until the returned stop function is called
+func (a *Analyzer) Start() (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(a.cfg.Window)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, spike := range a.Evaluate(core.Now()) {
+					a.report(spike)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// Evaluate closes the current window and returns the detected spikes without reporting them
+// Start calls it once per window
+func (a *Analyzer) Evaluate(now time.Time) []Spike {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var spikes []Spike
+	for fp, s := range a.stats {
+		isNew := s.windows == 0
+		spiking := s.count >= a.cfg.Threshold &&
+			(isNew || float64(s.count) >= a.cfg.Multiplier*s.baseline) &&
+			now.Sub(s.lastReported) >= a.cfg.Cooldown
+
+		if spiking {
+			s.lastReported = now
+			spikes = append(spikes, Spike{
+				Fingerprint: fp,
+				Count:       s.count,
+				Baseline:    s.baseline,
+				Window:      a.cfg.Window,
+				New:         isNew,
+				Example:     s.example,
+			})
+		}
+
+		if isNew {
+			s.baseline = float64(s.count)
+		} else {
+			s.baseline = baselineWeight*float64(s.count) + (1-baselineWeight)*s.baseline
+		}
+		s.windows++
+		s.count = 0
+	}
+
+	sort.Slice(spikes, func(i, j int) bool { return spikes[i].Count > spikes[j].Count })
+	return spikes
+}
+
+// evictLocked drops the fingerprint with the lowest activity to make room for a new one
+func (a *Analyzer) evictLocked() {
+	var victim string
+	lowest := -1.0
+	for fp, s := range a.stats {
+		activity := s.baseline + float64(s.count)
+		if lowest < 0 || activity < lowest {
+			victim, lowest = fp, activity
+		}
+	}
+	delete(a.stats, victim)
+}
+
+// report logs the spike and optionally captures it to Sentry
+func (a *Analyzer) report(spike Spike) {
+	log := a.cfg.Logger
+	if log == nil {
+		if log = config.Default().MiddlewareLogger(); log == nil {
+			log = slog.Default()
+		}
+	}
+
+	log.Warn("New error spike",
+		slog.String("fingerprint", spike.Fingerprint),
+		slog.Int("count", spike.Count),
+		slog.Float64("baseline", spike.Baseline),
+		slog.Duration("window", spike.Window),
+		slog.Bool("new_fingerprint", spike.New),
+		slog.String("example", spike.Example),
+	)
+
+	metrics.IncCounter("error_spikes_total", metrics.Labels{"new": fmt.Sprintf("%t", spike.New)})
+
+	if a.cfg.CaptureToSentry && config.Default().SentryEnabled() {
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelWarning)
+			scope.SetTag("error_spike", "true")
+			scope.SetTag("spike_fingerprint", spike.Fingerprint)
+			scope.SetFingerprint([]string{"error_spike", spike.Fingerprint})
+			scope.SetContext("error_spike", map[string]any{
+				"count":           spike.Count,
+				"baseline":        spike.Baseline,
+				"window":          spike.Window.String(),
+				"new_fingerprint": spike.New,
+				"example":         spike.Example,
+			})
+			sentry.CaptureMessage(fmt.Sprintf("Error spike: %s (%d in %s)", spike.Fingerprint, spike.Count, spike.Window))
+		})
+	}
+}
+
+var (
+	globalAnalyzer   *Analyzer
+	globalAnalyzerMu sync.RWMutex
+)
+
+// Start creates and starts the global analyzer fed by logbundle's error handling (replacing any previous one)
+//
+// Usage:
+//
+//	stop := errspike.Start(errspike.Config{Threshold: 20, CaptureToSentry: true})
+//	defer stop()
+func Start(cfg Config) (stop func()) {
+	a := NewAnalyzer(cfg)
+	stopAnalyzer := a.Start()
+
+	globalAnalyzerMu.Lock()
+	globalAnalyzer = a
+	globalAnalyzerMu.Unlock()
+
+	return func() {
+		stopAnalyzer()
+		globalAnalyzerMu.Lock()
+		if globalAnalyzer == a {
+			globalAnalyzer = nil
+		}
+		globalAnalyzerMu.Unlock()
+	}
+}
+
+// Record counts an error on the global analyzer (no-op if none is running)
+func Record(fingerprint, message string) {
+	globalAnalyzerMu.RLock()
+	a := globalAnalyzer
+	globalAnalyzerMu.RUnlock()
+
+	if a != nil {
+		a.Record(fingerprint, message)
+	}
+}
+
+// Fingerprint joins parts into a fingerprint, skipping empty ones
+func Fingerprint(parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "|")
+}
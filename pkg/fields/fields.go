@@ -0,0 +1,213 @@
+package fields
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Canonical attribute keys used across logbundle; the constructors below keep name and type consistent
+//
+// Usage:
+//
+//	log.Info("order created", fields.UserID(user.ID), fields.Route(c.Route().Path), fields.DurationMS(elapsed))
+const (
+	KeyUserID        = "user_id"
+	KeyRequestID     = "request_id"
+	KeyMethod        = "method"
+	KeyRoute         = "route"
+	KeyPath          = "path"
+	KeyURL           = "url"
+	KeyIP            = "ip"
+	KeyUserAgent     = "user_agent"
+	KeyHTTPStatus    = "status_code"
+	KeyDuration      = "duration"
+	KeyDurationMS    = "duration_ms"
+	KeySizeBytes     = "size_bytes"
+	KeyError         = "error"
+	KeyErrorType     = "error_type"
+	KeyErrorMessage  = "error_message"
+	KeyStackTrace    = "stack_trace"
+	KeySentryEventID = "sentry_event_id"
+	KeyService       = "service"
+	KeyOperation     = "operation"
+	KeyAttempt       = "attempt"
+	KeyReason        = "reason"
+	KeyEventType     = "event_type"
+	KeyAggregateID   = "aggregate_id"
+	KeyDestination   = "destination"
+	KeyRetryCount    = "retry_count"
+)
+
+// UserID returns the user_id attribute
+func UserID(id string) slog.Attr {
+	return slog.String(KeyUserID, id)
+}
+
+// HashedUserID returns the user_id attribute holding core.HashUserID(id), the value sent to Sentry
+// when user ID hashing is enabled (the raw ID when it is not)
+func HashedUserID(id string) slog.Attr {
+	return slog.String(KeyUserID, core.HashUserID(id))
+}
+
+// RequestID returns the request_id attribute
+func RequestID(id string) slog.Attr {
+	return slog.String(KeyRequestID, id)
+}
+
+// Method returns the HTTP method attribute
+func Method(method string) slog.Attr {
+	return slog.String(KeyMethod, method)
+}
+
+// Route returns the route pattern attribute (e.g. "/users/:id")
+func Route(route string) slog.Attr {
+	return slog.String(KeyRoute, route)
+}
+
+// Path returns the request path attribute
+func Path(path string) slog.Attr {
+	return slog.String(KeyPath, path)
+}
+
+// URL returns the full request URL attribute; sensitive query parameters are scrubbed (see core.ScrubURL)
+func URL(url string) slog.Attr {
+	return slog.String(KeyURL, core.ScrubURL(url))
+}
+
+// IP returns the client IP attribute
+func IP(ip string) slog.Attr {
+	return slog.String(KeyIP, ip)
+}
+
+// UserAgent returns the user_agent attribute
+func UserAgent(ua string) slog.Attr {
+	return slog.String(KeyUserAgent, ua)
+}
+
+// HTTPStatus returns the status_code attribute
+func HTTPStatus(status int) slog.Attr {
+	return slog.Int(KeyHTTPStatus, status)
+}
+
+// Duration returns the duration attribute
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration(KeyDuration, d)
+}
+
+// DurationMS returns the duration_ms attribute as fractional milliseconds
+func DurationMS(d time.Duration) slog.Attr {
+	return slog.Float64(KeyDurationMS, float64(d.Microseconds())/1000)
+}
+
+// SizeBytes returns the size_bytes attribute
+func SizeBytes(n int) slog.Attr {
+	return slog.Int(KeySizeBytes, n)
+}
+
+// Err returns the error attribute with the error message (an empty attribute for nil, which slog drops)
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String(KeyError, err.Error())
+}
+
+// ErrorType returns the error_type attribute (e.g. the lgerr type)
+func ErrorType(errType string) slog.Attr {
+	return slog.String(KeyErrorType, errType)
+}
+
+// ErrorMessage returns the error_message attribute
+func ErrorMessage(msg string) slog.Attr {
+	return slog.String(KeyErrorMessage, msg)
+}
+
+// GoType returns the Go type name of v under key (e.g. "wrapped_error_type")
+func GoType(key string, v any) slog.Attr {
+	return slog.String(key, fmt.Sprintf("%T", v))
+}
+
+// StackTrace returns the stack_trace attribute
+func StackTrace(trace string) slog.Attr {
+	return slog.String(KeyStackTrace, trace)
+}
+
+// SentryEventID returns the sentry_event_id attribute
+func SentryEventID(id string) slog.Attr {
+	return slog.String(KeySentryEventID, id)
+}
+
+// Service returns the service attribute (e.g. the external service of lgerr.External)
+func Service(name string) slog.Attr {
+	return slog.String(KeyService, name)
+}
+
+// Operation returns the operation attribute
+func Operation(name string) slog.Attr {
+	return slog.String(KeyOperation, name)
+}
+
+// Attempt returns the attempt number attribute
+func Attempt(n int) slog.Attr {
+	return slog.Int(KeyAttempt, n)
+}
+
+// Reason returns the reason attribute
+func Reason(reason string) slog.Attr {
+	return slog.String(KeyReason, reason)
+}
+
+// EventType returns the event_type attribute of a published event
+func EventType(eventType string) slog.Attr {
+	return slog.String(KeyEventType, eventType)
+}
+
+// AggregateID returns the aggregate_id attribute of a published event
+func AggregateID(id string) slog.Attr {
+	return slog.String(KeyAggregateID, id)
+}
+
+// Destination returns the destination (topic, queue or endpoint) attribute
+func Destination(destination string) slog.Attr {
+	return slog.String(KeyDestination, destination)
+}
+
+// RetryCount returns the retry_count attribute
+func RetryCount(n int) slog.Attr {
+	return slog.Int(KeyRetryCount, n)
+}
+
+// Schema returns the kinds of the canonical keys, ready for handler.AttrSchema.Fields
+// Add application keys to the returned map before use
+func Schema() map[string]slog.Kind {
+	return map[string]slog.Kind{
+		KeyUserID:        slog.KindString,
+		KeyRequestID:     slog.KindString,
+		KeyMethod:        slog.KindString,
+		KeyRoute:         slog.KindString,
+		KeyPath:          slog.KindString,
+		KeyURL:           slog.KindString,
+		KeyIP:            slog.KindString,
+		KeyUserAgent:     slog.KindString,
+		KeyHTTPStatus:    slog.KindInt64,
+		KeyDuration:      slog.KindDuration,
+		KeyDurationMS:    slog.KindFloat64,
+		KeySizeBytes:     slog.KindInt64,
+		KeyError:         slog.KindString,
+		KeyErrorType:     slog.KindString,
+		KeyErrorMessage:  slog.KindString,
+		KeyStackTrace:    slog.KindString,
+		KeySentryEventID: slog.KindString,
+		KeyService:       slog.KindString,
+		KeyOperation:     slog.KindString,
+		KeyAttempt:       slog.KindInt64,
+		KeyReason:        slog.KindString,
+		KeyEventType:     slog.KindString,
+		KeyAggregateID:   slog.KindString,
+		KeyDestination:   slog.KindString,
+		KeyRetryCount:    slog.KindInt64,
+	}
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// DeltaElidedKey lists the attributes a record omitted because they repeat the trace's previous value
+const DeltaElidedKey = "delta_elided"
+
+// DeltaOptions configures DeltaHandler
+type DeltaOptions struct {
+	// Keys limits elision to these attributes (e.g. "route", "user_id", "tenant"); empty means every
+	// non-group attribute. Grouped attributes are keyed "group.key"
+	Keys []string
+	// FullLevel is the level from which records keep all attributes, so errors read on their own
+	// (default: Warn)
+	FullLevel slog.Leveler
+	// MaxTraces bounds the traces tracked at once; the oldest is forgotten first (default: 10000)
+	MaxTraces int
+}
+
+// deltaState is shared by a DeltaHandler and the handlers derived from it
+type deltaState struct {
+	mu     sync.Mutex
+	traces map[string]map[string]slog.Value
+	order  []string // Trace IDs in first-seen order, for eviction
+	max    int
+}
+
+// DeltaHandler wraps a slog.Handler and omits attributes whose value equals the one last written for the
+// same trace (see core.WithTraceID): the first record of a trace carries route, user_id and tenant, later
+// records only what changed plus a delta_elided list of the omitted keys. Readers rebuild a record from the
+// earlier records of its trace. Records without a trace ID and records at or above FullLevel are written
+// in full. Attributes added with With are elided too, unless they were added inside a group
+//
+// Only scalar values (strings, numbers, bools, durations, times) are elided; LogValuers are passed on
+// unresolved, so redacting handlers must wrap the DeltaHandler rather than the other way round. The
+// trace state is updated only for records the wrapped handler wrote
+type DeltaHandler struct {
+	next   slog.Handler
+	opts   DeltaOptions
+	keys   map[string]bool // nil elides every key
+	state  *deltaState
+	held   []slog.Attr // With attributes kept back so they can be elided per record
+	prefix string      // Open groups, "group."
+}
+
+// NewDeltaHandler wraps next with per-trace attribute elision
+//
+// Usage:
+//
+//	h := handler.NewDeltaHandler(slog.NewJSONHandler(os.Stdout, nil), handler.DeltaOptions{
+//	    Keys: []string{"route", "user_id", "tenant"},
+//	})
+//	logger := slog.New(handler.NewTraceIDHandler(h))
+func NewDeltaHandler(next slog.Handler, opts DeltaOptions) *DeltaHandler {
+	if opts.FullLevel == nil {
+		opts.FullLevel = slog.LevelWarn
+	}
+	if opts.MaxTraces <= 0 {
+		opts.MaxTraces = 10000
+	}
+
+	h := &DeltaHandler{
+		next:  next,
+		opts:  opts,
+		state: &deltaState{traces: make(map[string]map[string]slog.Value), max: opts.MaxTraces},
+	}
+	if len(opts.Keys) > 0 {
+		h.keys = make(map[string]bool, len(opts.Keys))
+		for _, k := range opts.Keys {
+			h.keys[k] = true
+		}
+	}
+	return h
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *DeltaHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle elides the attributes repeated within the record's trace and passes the record on
+func (h *DeltaHandler) Handle(ctx context.Context, r slog.Record) error {
+	traceID := ""
+	if ctx != nil {
+		traceID = core.TraceIDFromContext(ctx)
+	}
+	if traceID == "" {
+		return h.next.Handle(ctx, h.withHeld(r))
+	}
+
+	full := r.Level >= h.opts.FullLevel.Level() || core.IsCritical(ctx)
+	traceKey := core.GetTraceIDFieldName()
+
+	if !handlerWrites(ctx, h.next, r) {
+		// Dropped downstream, so it must neither be elided against nor remembered
+		return h.next.Handle(ctx, h.withHeld(r))
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	var elided []string
+	var written []slog.Attr // Elidable attributes written in full, remembered after a successful write
+
+	h.state.mu.Lock()
+	last := h.state.traces[traceID]
+	visit := func(a slog.Attr) bool {
+		key := h.prefix + a.Key
+		if deltaComparable(a.Value) && a.Key != traceKey && (h.keys == nil || h.keys[key]) {
+			if prev, ok := last[key]; ok && !full && prev.Equal(a.Value) {
+				elided = append(elided, key)
+				return true
+			}
+			written = append(written, slog.Attr{Key: key, Value: a.Value})
+		}
+		out.AddAttrs(a)
+		return true
+	}
+	for _, a := range h.held {
+		visit(a)
+	}
+	r.Attrs(visit)
+	h.state.mu.Unlock()
+
+	if len(elided) > 0 {
+		out.AddAttrs(slog.Any(DeltaElidedKey, elided))
+	}
+	if err := h.next.Handle(ctx, out); err != nil {
+		return err
+	}
+
+	if len(written) > 0 {
+		h.state.mu.Lock()
+		last := h.state.trace(traceID)
+		for _, a := range written {
+			last[a.Key] = a.Value
+		}
+		h.state.mu.Unlock()
+	}
+	return nil
+}
+
+// deltaComparable reports whether a value may be elided: scalars only, since LogValuers must reach the
+// redacting handlers unresolved and arbitrary values may not be comparable
+func deltaComparable(v slog.Value) bool {
+	switch v.Kind() {
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool,
+		slog.KindDuration, slog.KindTime:
+		return true
+	}
+	return false
+}
+
+// withHeld returns r with the held With attributes in front of its own
+func (h *DeltaHandler) withHeld(r slog.Record) slog.Record {
+	if len(h.held) == 0 {
+		return r
+	}
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(h.held...)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(a)
+		return true
+	})
+	return out
+}
+
+// WithAttrs keeps top-level attributes back for per-record elision; inside a group they are passed on
+func (h *DeltaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	if h.prefix != "" {
+		clone.next = h.next.WithAttrs(attrs)
+		return &clone
+	}
+	clone.held = append(slices.Clone(h.held), attrs...)
+	return &clone
+}
+
+// WithGroup passes the held attributes on before opening the group
+func (h *DeltaHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	if len(h.held) > 0 {
+		clone.next = h.next.WithAttrs(h.held)
+		clone.held = nil
+	}
+	clone.next = clone.next.WithGroup(name)
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// trace returns the last values of a trace, tracking it if new (caller holds s.mu)
+func (s *deltaState) trace(traceID string) map[string]slog.Value {
+	if last, ok := s.traces[traceID]; ok {
+		return last
+	}
+	if len(s.traces) >= s.max {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		delete(s.traces, oldest)
+	}
+	last := make(map[string]slog.Value)
+	s.traces[traceID] = last
+	s.order = append(s.order, traceID)
+	return last
+}
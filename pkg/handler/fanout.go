@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// FanoutHandler sends every record to several handlers, e.g. one formatter per output
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// NewFanoutHandler creates a handler writing to all handlers
+func NewFanoutHandler(handlers ...slog.Handler) *FanoutHandler {
+	return &FanoutHandler{handlers: handlers}
+}
+
+// Enabled reports whether any handler handles the level
+func (h *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, next := range h.handlers {
+		if next.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a clone of the record to every enabled handler and joins their errors
+func (h *FanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, next := range h.handlers {
+		if !next.Enabled(ctx, r.Level) && !core.IsCritical(ctx) {
+			continue
+		}
+		if err := next.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writes reports whether any handler writes the record (see recordFilter)
+func (h *FanoutHandler) writes(ctx context.Context, r slog.Record) bool {
+	for _, next := range h.handlers {
+		if (next.Enabled(ctx, r.Level) || core.IsCritical(ctx)) && handlerWrites(ctx, next, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFilter is implemented by handlers that may drop records in Handle after Enabled passed
+// (per-module levels), so wrappers keeping per-record state can tell whether a record was written
+type recordFilter interface {
+	writes(ctx context.Context, r slog.Record) bool
+}
+
+// handlerWrites reports whether h writes the record, falling back to Enabled
+func handlerWrites(ctx context.Context, h slog.Handler, r slog.Record) bool {
+	if f, ok := h.(recordFilter); ok {
+		return f.writes(ctx, r)
+	}
+	return h.Enabled(ctx, r.Level) || core.IsCritical(ctx)
+}
+
+func (h *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, next := range h.handlers {
+		handlers[i] = next.WithAttrs(attrs)
+	}
+	return &FanoutHandler{handlers: handlers}
+}
+
+func (h *FanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, next := range h.handlers {
+		handlers[i] = next.WithGroup(name)
+	}
+	return &FanoutHandler{handlers: handlers}
+}
+
+// NewTeeHandler creates the formatting handler for a TeeWriter: one CustomHandler per distinct sink
+// format (see Sink.Format and Sink.Color), so each sink gets its own format while the enrichment
+// handlers wrapped around the result (trace ID, PII, secrets, schema) run once per record.
+// opts.Format is the format of sinks without an override
+//
+// Usage:
+//
+//	jsonFormat := handler.FormatJSON
+//	tee := handler.NewTeeWriter([]handler.Sink{
+//	    {Name: "stdout", Writer: os.Stdout, Color: true},
+//	    {Name: "file", Writer: logFile, Format: &jsonFormat},
+//	})
+//	h := handler.NewTraceIDHandler(handler.NewTeeHandler(tee, handler.HandlerOptions{Level: slog.LevelInfo}))
+func NewTeeHandler(t *TeeWriter, opts HandlerOptions) slog.Handler {
+	formats, writers := t.formatWriters(opts.Format)
+	if len(formats) <= 1 {
+		// All sinks share one format, which may be a sink override of opts.Format
+		if len(formats) == 1 {
+			opts.Format = formats[0]
+		}
+		return NewCustomHandlerWithOptions(t, opts)
+	}
+
+	handlers := make([]slog.Handler, len(formats))
+	for i, f := range formats {
+		o := opts
+		o.Format = f
+		handlers[i] = NewCustomHandlerWithOptions(writers[i], o)
+	}
+	return NewFanoutHandler(handlers...)
+}
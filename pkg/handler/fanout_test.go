@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTeeHandlerSingleSinkFormat(t *testing.T) {
+	jsonFormat := FormatJSON
+	tests := []struct {
+		name  string
+		sinks func(*bytes.Buffer) []Sink
+		want  string
+	}{
+		{"default format", func(buf *bytes.Buffer) []Sink {
+			return []Sink{{Name: "out", Writer: buf}}
+		}, "[INFO] hello k=1"},
+		{"sink override", func(buf *bytes.Buffer) []Sink {
+			return []Sink{{Name: "out", Writer: buf, Format: &jsonFormat}}
+		}, `"message":"hello"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tee := NewTeeWriter(tt.sinks(&buf))
+			slog.New(NewTeeHandler(tee, HandlerOptions{Level: slog.LevelInfo})).Info("hello", "k", 1)
+			if err := tee.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if out := buf.String(); !strings.Contains(out, tt.want) {
+				t.Fatalf("output %q does not contain %q", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+)
+
+// Format selects the output format of CustomHandler; each format is a versioned log schema
+type Format int
+
+const (
+	// FormatText is the "YYYY/MM/DD HH:MM:SS [LEVEL] message key=value" line (schema_version=1)
+	FormatText Format = iota
+	// FormatJSON is one ECS-style JSON object per line: @timestamp, log.level, message, log.origin
+	// and the record attributes (schema_version "2")
+	FormatJSON
+)
+
+// SchemaVersionKey is the attribute carrying the log schema version, added to every record so
+// downstream parsers can branch on the format while services migrate from text to JSON
+const SchemaVersionKey = "schema_version"
+
+// SchemaVersion returns the schema version written by the format
+func (f Format) SchemaVersion() string {
+	if f == FormatJSON {
+		return "2"
+	}
+	return "1"
+}
+
+// String returns the format name
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// ParseFormat parses a format name: "text" or "v1", "json", "ecs" or "v2"
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text", "v1", "1":
+		return FormatText, nil
+	case "json", "ecs", "v2", "2":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q", s)
+	}
+}
+
+// GetFormatFromEnv reads the format from an environment variable, defaulting to FormatText
+// Rolling out JSON is then a per-service config change, e.g. LOG_FORMAT=json
+func GetFormatFromEnv(key string) Format {
+	f, _ := ParseFormat(os.Getenv(key))
+	return f
+}
+
+// jsonOp replays a WithAttrs/WithGroup call on a JSON handler
+type jsonOp func(slog.Handler) slog.Handler
+
+// newECSHandler returns the JSON handler used by FormatJSON; level filtering is done by CustomHandler
+func newECSHandler(w io.Writer, addSource bool, base []slog.Attr, ops []jsonOp) slog.Handler {
+	var h slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{
+		AddSource:   addSource,
+		Level:       slog.Level(math.MinInt),
+		ReplaceAttr: ecsReplaceAttr,
+	})
+	h = h.WithAttrs(base)
+	for _, op := range ops {
+		h = op(h)
+	}
+	return h
+}
+
+// ecsReplaceAttr renames slog's built-in keys to their ECS names
+func ecsReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = "@timestamp"
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("log.level", strings.ToLower(level.String()))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group("log.origin",
+				slog.Group("file", slog.String("name", src.File), slog.Int("line", src.Line)),
+				slog.String("function", src.Function),
+			)
+		}
+	}
+	return a
+}
+
+// handleJSON writes a record in FormatJSON; critical records are rendered into a buffer and delivered
+// through SyncWriter when the writer supports it
+func (h *CustomHandler) handleJSON(ctx context.Context, r slog.Record, critical bool) error {
+	if sw, ok := h.writer.(SyncWriter); ok && critical {
+		var buf bytes.Buffer
+		if err := newECSHandler(&buf, h.addSource, h.jsonBase, h.jsonOps).Handle(ctx, r); err != nil {
+			return err
+		}
+		return sw.WriteSync(buf.Bytes())
+	}
+	return h.json.Handle(ctx, r)
+}
@@ -7,18 +7,44 @@ import (
 	"log/slog"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 )
 
+// SyncWriter is implemented by writers that can deliver a record synchronously and report the result
+// CustomHandler uses it for critical records (see core.WithCritical)
+type SyncWriter interface {
+	WriteSync(p []byte) error
+}
+
+// textSchemaVersion is appended to every FormatText record
+var textSchemaVersion = SchemaVersionKey + "=" + FormatText.SchemaVersion()
+
 // internalLog is used for logging within logbundle package (without source info for performance)
-var internalLog = slog.New(NewCustomHandler(os.Stdout, slog.LevelError, false))
+var internalLog = slog.New(NewTraceIDHandler(NewCustomHandler(os.Stdout, slog.LevelError, false)))
 
 // CustomHandler implements slog.Handler with custom formatting
-// Format: "YYYY/MM/DD HH:MM:SS [LEVEL] [file:line] message key=value..."
+// Format: "YYYY/MM/DD HH:MM:SS [LEVEL] [file:line] message key=value... schema_version=1",
+// or ECS-style JSON with HandlerOptions.Format = FormatJSON
 type CustomHandler struct {
-	writer    io.Writer  // Output destination (typically os.Stdout)
-	addSource bool       // Whether to include source file/line in output
-	level     slog.Level // Minimum level to log
+	writer          io.Writer  // Output destination (typically os.Stdout)
+	addSource       bool       // Whether to include source file/line in output
+	level           slog.Level // Minimum level to log
+	runtimeMetadata []string   // Pre-formatted host/process metadata appended to every record
+
+	json     slog.Handler // FormatJSON output (nil for FormatText)
+	jsonBase []slog.Attr  // Attributes added to every JSON record (schema version, runtime metadata)
+	jsonOps  []jsonOp     // WithAttrs/WithGroup calls applied to json, replayed for critical records
+}
+
+// HandlerOptions holds optional CustomHandler settings
+type HandlerOptions struct {
+	Level              slog.Level // Minimum level to log
+	AddSource          bool       // Whether to include source file/line in output
+	AddRuntimeMetadata bool       // Whether to append host.name, process.pid, go.version and app.version
+	Format             Format     // Output format (default FormatText)
 }
 
 func NewCustomHandler(w io.Writer, level slog.Level, addSource bool) *CustomHandler {
@@ -29,13 +55,75 @@ func NewCustomHandler(w io.Writer, level slog.Level, addSource bool) *CustomHand
 	}
 }
 
+// NewCustomHandlerWithOptions creates a CustomHandler with the given options
+func NewCustomHandlerWithOptions(w io.Writer, opts HandlerOptions) *CustomHandler {
+	h := NewCustomHandler(w, opts.Level, opts.AddSource)
+	if opts.AddRuntimeMetadata {
+		// Metadata never changes, so it is formatted once
+		for _, a := range core.RuntimeMetadataAttrs() {
+			h.runtimeMetadata = append(h.runtimeMetadata, fmt.Sprintf("%s=%s", a.Key, a.Value.String()))
+		}
+	}
+	if opts.Format == FormatJSON {
+		h.jsonBase = []slog.Attr{slog.String(SchemaVersionKey, FormatJSON.SchemaVersion())}
+		if opts.AddRuntimeMetadata {
+			h.jsonBase = append(h.jsonBase, core.RuntimeMetadataAttrs()...)
+		}
+		h.json = newECSHandler(w, opts.AddSource, h.jsonBase, nil)
+	}
+	return h
+}
+
+// Enabled reports whether the level is logged
+// The configured level is replaced while a core.SetLevelOverride is active.
+// Contexts marked with core.WithDebugScope enable every level for that request only.
+// With per-module overrides the final decision is made in Handle, once the record's source is known
 func (h *CustomHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.level
+	levels := core.Levels()
+	if level >= levels.EffectiveLevel(h.level) || core.IsDebugScope(ctx) {
+		return true
+	}
+	minLevel, ok := levels.MinModuleLevel()
+	return ok && level >= minLevel
+}
+
+// levelAllowed applies per-module level overrides to a record that passed Enabled
+func (h *CustomHandler) levelAllowed(ctx context.Context, r slog.Record) bool {
+	levels := core.Levels()
+	if r.Level >= levels.EffectiveLevel(h.level) {
+		// Overrides may also raise the level for noisy packages
+		if lvl, ok := levels.ModuleLevelForRecord(ctx, r.PC); ok && r.Level < lvl {
+			return core.IsDebugScope(ctx)
+		}
+		return true
+	}
+	if core.IsDebugScope(ctx) {
+		return true
+	}
+	lvl, ok := levels.ModuleLevelForRecord(ctx, r.PC)
+	return ok && r.Level >= lvl
+}
+
+// writes reports whether Handle writes the record (see recordFilter)
+func (h *CustomHandler) writes(ctx context.Context, r slog.Record) bool {
+	return core.IsCritical(ctx) || h.levelAllowed(ctx, r)
 }
 
 // Handle processes a log record and writes it to the output
 // This is the core slog.Handler method
 func (h *CustomHandler) Handle(ctx context.Context, r slog.Record) error {
+	critical := core.IsCritical(ctx)
+	if !critical && !h.levelAllowed(ctx, r) {
+		return nil
+	}
+	if !core.IsSystemClock() {
+		// slog stamps records with the wall clock; an injected clock makes timestamps deterministic
+		r.Time = core.Now()
+	}
+	if h.json != nil {
+		return h.handleJSON(ctx, r, critical)
+	}
+
 	const timestampFormat = "2006/01/02 15:04:05"
 	timestamp := r.Time.Format(timestampFormat)
 	level := fmt.Sprintf("[%s]", strings.ToUpper(r.Level.String()))
@@ -84,9 +172,11 @@ func (h *CustomHandler) Handle(ctx context.Context, r slog.Record) error {
 		if a.Key == "source" {
 			return true // Skip source attribute as it's already handled
 		}
-		attrs = append(attrs, fmt.Sprintf("%s=%s", a.Key, a.Value.String()))
+		attrs = appendTextAttr(attrs, "", a)
 		return true
 	})
+	attrs = append(attrs, h.runtimeMetadata...)
+	attrs = append(attrs, textSchemaVersion)
 
 	// Use strings.Builder for efficient concatenation
 	var builder strings.Builder
@@ -96,30 +186,60 @@ func (h *CustomHandler) Handle(ctx context.Context, r slog.Record) error {
 		builder.WriteString(strings.Join(attrs, " "))
 	}
 
+	if sw, ok := h.writer.(SyncWriter); ok && critical {
+		builder.WriteByte('\n')
+		return sw.WriteSync([]byte(builder.String()))
+	}
+
 	_, err := fmt.Fprintln(h.writer, builder.String())
 	return err
 }
 
+// appendTextAttr formats a as key=value, flattening groups into dotted keys (error_context.order_id=42)
+// like slog.TextHandler; empty groups are omitted and groups with an empty key are inlined
+func appendTextAttr(attrs []string, prefix string, a slog.Attr) []string {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, fmt.Sprintf("%s%s=%s", prefix, a.Key, a.Value.String()))
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		attrs = appendTextAttr(attrs, prefix, ga)
+	}
+	return attrs
+}
+
 func (h *CustomHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	// Create a new handler with the same configuration
-	// Note: This is a simplified implementation. For production use,
-	// consider implementing proper attribute chaining if needed.
-	return &CustomHandler{
-		writer:    h.writer,
-		level:     h.level,
-		addSource: h.addSource,
+	// Note: text output does not chain attributes (simplified implementation);
+	// JSON output delegates to slog's JSON handler, which does
+	if h.json == nil {
+		return h.clone()
 	}
+	return h.withJSONOp(func(j slog.Handler) slog.Handler { return j.WithAttrs(attrs) })
 }
 
 func (h *CustomHandler) WithGroup(name string) slog.Handler {
-	// Create a new handler with the same configuration
-	// Note: This is a simplified implementation. For production use,
-	// consider implementing proper group support if needed.
-	return &CustomHandler{
-		writer:    h.writer,
-		level:     h.level,
-		addSource: h.addSource,
+	// Note: text output does not support groups (simplified implementation)
+	if h.json == nil {
+		return h.clone()
 	}
+	return h.withJSONOp(func(j slog.Handler) slog.Handler { return j.WithGroup(name) })
+}
+
+// clone copies the handler configuration
+func (h *CustomHandler) clone() *CustomHandler {
+	c := *h
+	return &c
+}
+
+// withJSONOp returns a clone whose JSON handler has op applied
+func (h *CustomHandler) withJSONOp(op jsonOp) *CustomHandler {
+	c := h.clone()
+	c.json = op(h.json)
+	c.jsonOps = append(slices.Clip(h.jsonOps), op)
+	return c
 }
 
 // GetInternalLogger returns the internal logger used by logbundle (without source)
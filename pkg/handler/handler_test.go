@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTextHandlerFlattensGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{name: "group", attr: slog.Group("error_context", slog.Int("order_id", 42)), want: " error_context.order_id=42 "},
+		{name: "nested group", attr: slog.Group("a", slog.Group("b", slog.String("c", "d"))), want: " a.b.c=d "},
+		{name: "inline group", attr: slog.Group("", slog.String("k", "v")), want: " k=v "},
+		{name: "plain", attr: slog.String("k", "v"), want: " k=v "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(NewCustomHandler(&buf, slog.LevelInfo, false)).LogAttrs(context.Background(), slog.LevelInfo, "failed", tt.attr)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Fatalf("output %q does not contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Compression is the codec of an HTTPSink request body
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// HTTPSinkOptions configures an HTTPSink
+type HTTPSinkOptions struct {
+	// Name labels the sink metrics (default: "http")
+	Name string
+	// URL receives the batches as POST requests
+	URL string
+	// Headers are added to every request (e.g. authorization)
+	Headers map[string]string
+	// ContentType of the uncompressed batch (default: "application/x-ndjson")
+	ContentType string
+	// Client sends the requests (default: a client with a 10s timeout)
+	Client *http.Client
+	// Compression codec of the request body, announced in Content-Encoding (default: none)
+	Compression Compression
+	// Level is the codec level: 1-9 for gzip, 1-22 for zstd (default: the codec default)
+	Level int
+	// BatchBytes flushes the batch once it holds this many uncompressed bytes (default: 1MiB)
+	BatchBytes int
+	// FlushInterval flushes a partial batch (default: 1s)
+	FlushInterval time.Duration
+	// DeadLetter receives the uncompressed batches the intake rejected with a non-retryable status
+	// (4xx other than 408 and 429), e.g. a local file for later inspection (default: dropped)
+	DeadLetter io.Writer
+}
+
+// HTTPSink is an io.Writer batching records into compressed POST requests, for log collectors with an
+// HTTP intake. Use it as a TeeWriter sink writer so failed batches are retried and spilled per sink.
+// The compression ratio is exported as log_sink_compression_ratio{sink}, the volume as
+// log_sink_bytes_total{sink, stage="raw"|"sent"}. Requests are sent without holding the batch lock, so
+// writers keep appending while a batch is in flight. Batches rejected with a non-retryable status are
+// not retried: they go to DeadLetter or are dropped, counted in log_sink_dropped_total{sink, reason="rejected"}
+type HTTPSink struct {
+	opts HTTPSinkOptions
+	zenc *zstd.Encoder // nil unless CompressionZstd
+
+	mu     sync.Mutex // Guards batch
+	batch  []byte
+	sendMu sync.Mutex // Serializes requests to the intake
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewHTTPSink validates opts and starts the periodic flush; call Close to flush the last batch
+//
+// Usage:
+//
+//	sink, err := handler.NewHTTPSink(handler.HTTPSinkOptions{
+//	    Name:        "collector",
+//	    URL:         "https://logs.internal/ingest",
+//	    Compression: handler.CompressionZstd,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	tee := handler.NewTeeWriter([]handler.Sink{{Name: "collector", Writer: sink}})
+func NewHTTPSink(opts HTTPSinkOptions) (*HTTPSink, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("http sink: URL is required")
+	}
+	if opts.Name == "" {
+		opts.Name = "http"
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/x-ndjson"
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = 1 << 20
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+
+	s := &HTTPSink{opts: opts, done: make(chan struct{})}
+	switch opts.Compression {
+	case CompressionNone:
+	case CompressionGzip:
+		if opts.Level != 0 && (opts.Level < gzip.BestSpeed || opts.Level > gzip.BestCompression) {
+			return nil, fmt.Errorf("http sink: invalid gzip level %d", opts.Level)
+		}
+	case CompressionZstd:
+		encOpts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if opts.Level != 0 {
+			encOpts = append(encOpts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(opts.Level)))
+		}
+		enc, err := zstd.NewWriter(nil, encOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("http sink: %w", err)
+		}
+		s.zenc = enc
+	default:
+		return nil, fmt.Errorf("http sink: unknown compression %q", opts.Compression)
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Write adds a record to the batch and sends the batch once it is full. When sending fails the record
+// is removed again and the error returned, so the caller (e.g. TeeWriter) retries or spills that record
+// while the rest of the batch waits for the next attempt
+func (s *HTTPSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.batch = append(s.batch, p...)
+	if len(s.batch) < s.opts.BatchBytes {
+		s.mu.Unlock()
+		return len(p), nil
+	}
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+
+	if err := s.send(batch); err != nil {
+		s.requeue(batch[:len(batch)-len(p)])
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends the current batch
+func (s *HTTPSink) Flush() error {
+	s.mu.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+
+	if err := s.send(batch); err != nil {
+		s.requeue(batch)
+		return err
+	}
+	return nil
+}
+
+// Close stops the periodic flush and sends the last batch
+func (s *HTTPSink) Close() error {
+	s.once.Do(func() { close(s.done) })
+	err := s.Flush()
+	if s.zenc != nil {
+		s.zenc.Close()
+	}
+	return err
+}
+
+// run flushes partial batches every FlushInterval
+func (s *HTTPSink) run() {
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			// Failures are counted; the batch is kept for the next attempt
+			_ = s.Flush()
+		}
+	}
+}
+
+// requeue puts a batch that failed to send back in front of the records written meanwhile
+func (s *HTTPSink) requeue(batch []byte) {
+	if len(batch) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.batch = append(batch, s.batch...)
+	s.mu.Unlock()
+}
+
+// send compresses and posts a batch; only retryable failures are returned, a rejected batch is
+// dead-lettered or dropped
+func (s *HTTPSink) send(batch []byte) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	body, err := s.compress(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.opts.ContentType)
+	if s.opts.Compression != CompressionNone {
+		req.Header.Set("Content-Encoding", string(s.opts.Compression))
+	}
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		metrics.IncCounter("log_sink_flush_errors_total", metrics.Labels{"sink": s.opts.Name})
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+	case retryableStatus(resp.StatusCode):
+		metrics.IncCounter("log_sink_flush_errors_total", metrics.Labels{"sink": s.opts.Name})
+		return fmt.Errorf("http sink: unexpected status %d", resp.StatusCode)
+	default:
+		// Sending the same batch again would be rejected again and block every later record
+		metrics.IncCounter("log_sink_flush_errors_total", metrics.Labels{"sink": s.opts.Name})
+		metrics.IncCounter("log_sink_dropped_total", metrics.Labels{"sink": s.opts.Name, "reason": "rejected"})
+		if s.opts.DeadLetter != nil {
+			_, _ = s.opts.DeadLetter.Write(batch)
+		}
+		return nil
+	}
+
+	labels := metrics.Labels{"sink": s.opts.Name}
+	metrics.AddCounter("log_sink_bytes_total", metrics.Labels{"sink": s.opts.Name, "stage": "raw"}, float64(len(batch)))
+	metrics.AddCounter("log_sink_bytes_total", metrics.Labels{"sink": s.opts.Name, "stage": "sent"}, float64(len(body)))
+	metrics.SetGauge("log_sink_compression_ratio", labels, float64(len(batch))/float64(len(body)))
+	return nil
+}
+
+// retryableStatus reports whether a failed request may succeed when repeated
+func retryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// compress encodes data with the configured codec
+func (s *HTTPSink) compress(data []byte) ([]byte, error) {
+	switch s.opts.Compression {
+	case CompressionGzip:
+		level := s.opts.Level
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		return s.zenc.EncodeAll(data, nil), nil
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPSinkStatusHandling(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		wantErr        bool
+		wantDeadLetter bool
+	}{
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "rejected is dead-lettered", status: http.StatusRequestEntityTooLarge, wantDeadLetter: true},
+		{name: "bad request is dead-lettered", status: http.StatusBadRequest, wantDeadLetter: true},
+		{name: "throttled is retried", status: http.StatusTooManyRequests, wantErr: true},
+		{name: "server error is retried", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			var dead bytes.Buffer
+			sink, err := NewHTTPSink(HTTPSinkOptions{URL: srv.URL, BatchBytes: 1, FlushInterval: time.Hour, DeadLetter: &dead})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sink.Close()
+
+			_, err = sink.Write([]byte("record\n"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := dead.String() == "record\n"; got != tt.wantDeadLetter {
+				t.Fatalf("dead letter = %q, want dead-lettered %v", dead.String(), tt.wantDeadLetter)
+			}
+		})
+	}
+}
+
+func TestHTTPSinkWritesWhileSending(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-release
+	}))
+	defer srv.Close()
+
+	sink, err := NewHTTPSink(HTTPSinkOptions{URL: srv.URL, BatchBytes: 1 << 20, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = sink.Write([]byte("first\n"))
+	go func() { _ = sink.Flush() }()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = sink.Write([]byte("second\n"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Write blocked while a batch was in flight")
+	}
+	close(release)
+	_ = sink.Close()
+}
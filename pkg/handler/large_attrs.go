@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLargeAttrThreshold is the default size above which attribute values are compacted
+const DefaultLargeAttrThreshold = 8 << 10
+
+// BlobSink stores large attribute values outside the log stream and returns a reference to them
+type BlobSink interface {
+	Store(ctx context.Context, key string, data []byte) (ref string, err error)
+}
+
+// LargeAttrOptions configures LargeAttrHandler
+type LargeAttrOptions struct {
+	// Threshold is the value size in bytes above which an attribute is compacted (default: 8 KiB)
+	Threshold int
+	// Sink offloads large values; the log line keeps a reference. If nil (or the sink fails),
+	// values are gzip-compressed and base64-encoded inline instead
+	Sink BlobSink
+}
+
+// LargeAttrHandler wraps a slog.Handler and compacts oversized string attributes (stack traces,
+// payload dumps) so log lines stay under collector limits without losing data:
+//   - with a sink: key={ref=..., size=...}
+//   - without:     key={gzip_base64=..., size=...} (decode with base64 -d | gunzip)
+type LargeAttrHandler struct {
+	next slog.Handler
+	opts LargeAttrOptions
+}
+
+// NewLargeAttrHandler wraps next with large attribute compaction
+//
+// Usage:
+//
+//	h := handler.NewLargeAttrHandler(slog.NewJSONHandler(os.Stdout, nil), handler.LargeAttrOptions{
+//	    Threshold: 16 << 10,
+//	    Sink:      handler.NewFileBlobSink("/var/log/app/blobs"),
+//	})
+func NewLargeAttrHandler(next slog.Handler, opts LargeAttrOptions) *LargeAttrHandler {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultLargeAttrThreshold
+	}
+	return &LargeAttrHandler{next: next, opts: opts}
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *LargeAttrHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle compacts oversized attributes and passes the record on
+func (h *LargeAttrHandler) Handle(ctx context.Context, r slog.Record) error {
+	large := false
+	r.Attrs(func(a slog.Attr) bool {
+		if h.isLarge(a) {
+			large = true
+			return false
+		}
+		return true
+	})
+	if !large {
+		return h.next.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.compact(ctx, a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs compacts oversized attributes once and passes them on
+func (h *LargeAttrHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	compacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		compacted[i] = h.compact(context.Background(), a)
+	}
+	return &LargeAttrHandler{next: h.next.WithAttrs(compacted), opts: h.opts}
+}
+
+// WithGroup returns a LargeAttrHandler wrapping next.WithGroup
+func (h *LargeAttrHandler) WithGroup(name string) slog.Handler {
+	return &LargeAttrHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// isLarge reports whether the attribute (or any attribute of a group) exceeds the threshold
+func (h *LargeAttrHandler) isLarge(a slog.Attr) bool {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return len(v.String()) > h.opts.Threshold
+	case slog.KindGroup:
+		for _, ga := range v.Group() {
+			if h.isLarge(ga) {
+				return true
+			}
+		}
+	case slog.KindAny:
+		if b, ok := v.Any().([]byte); ok {
+			return len(b) > h.opts.Threshold
+		}
+	}
+	return false
+}
+
+// compact replaces an oversized value with a sink reference or its compressed form
+func (h *LargeAttrHandler) compact(ctx context.Context, a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+
+	var data []byte
+	switch v.Kind() {
+	case slog.KindString:
+		data = []byte(v.String())
+	case slog.KindGroup:
+		group := v.Group()
+		compacted := make([]any, len(group))
+		for i, ga := range group {
+			compacted[i] = h.compact(ctx, ga)
+		}
+		return slog.Group(a.Key, compacted...)
+	case slog.KindAny:
+		data, _ = v.Any().([]byte)
+	}
+	if len(data) <= h.opts.Threshold {
+		return a
+	}
+
+	if h.opts.Sink != nil {
+		if ref, err := h.opts.Sink.Store(ctx, a.Key, data); err == nil {
+			return slog.Group(a.Key, slog.String("ref", ref), slog.Int("size", len(data)))
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	return slog.Group(a.Key,
+		slog.String("gzip_base64", base64.StdEncoding.EncodeToString(buf.Bytes())),
+		slog.Int("size", len(data)),
+	)
+}
+
+// FileBlobSink stores blobs as files in a directory; the reference is the file name. Blobs hold request and
+// response bodies, so the directory and files are only accessible to the owner (0700 and 0600)
+type FileBlobSink struct {
+	dir string
+}
+
+// NewFileBlobSink creates a sink writing to dir (created on first use)
+func NewFileBlobSink(dir string) *FileBlobSink {
+	return &FileBlobSink{dir: dir}
+}
+
+// Store writes data to a new file named after the attribute key and a random ID
+func (s *FileBlobSink) Store(_ context.Context, key string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", err
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.blob", sanitizeBlobKey(key), hex.EncodeToString(id[:]))
+
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o600); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// sanitizeBlobKey keeps attribute keys safe for use in file names
+func sanitizeBlobKey(key string) string {
+	b := []byte(key)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 {
+		return "attr"
+	}
+	if len(b) > 64 {
+		b = b[:64]
+	}
+	return string(b)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBlobSinkIsPrivate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blobs")
+	ref, err := NewFileBlobSink(dir).Store(context.Background(), "request body", []byte(`{"card":"4242"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want os.FileMode
+	}{
+		{path: dir, want: 0o700},
+		{path: filepath.Join(dir, ref), want: 0o600},
+	}
+	for _, tt := range tests {
+		info, err := os.Stat(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != tt.want {
+			t.Fatalf("%s mode = %o, want %o", tt.path, perm, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+)
+
+// LevelRoute sends records from MinLevel up to the next route's MinLevel to Writer
+type LevelRoute struct {
+	MinLevel slog.Level
+	Writer   io.Writer
+}
+
+// StdStreamRoutes writes Warn and above to stderr and the rest to stdout, the split container
+// platforms use to tell error streams apart
+func StdStreamRoutes() []LevelRoute {
+	return []LevelRoute{
+		{MinLevel: slog.LevelDebug, Writer: os.Stdout},
+		{MinLevel: slog.LevelWarn, Writer: os.Stderr},
+	}
+}
+
+// levelHandler is the formatting handler of a route
+type levelHandler struct {
+	min slog.Level
+	h   slog.Handler
+}
+
+// LevelRouterHandler writes each record to the route of its level: the route with the highest MinLevel
+// not above the record level. Records below every route go to the lowest route
+type LevelRouterHandler struct {
+	routes []levelHandler // Sorted by descending MinLevel
+}
+
+// NewLevelRouterHandler creates one formatting handler per route, all sharing opts; routes writing to a
+// TeeWriter keep its per-sink formats (see NewTeeHandler). Without routes everything goes to stdout
+//
+// Usage:
+//
+//	h := handler.NewLevelRouterHandler(handler.StdStreamRoutes(), handler.HandlerOptions{Level: slog.LevelInfo})
+//	logger := slog.New(handler.NewTraceIDHandler(h))
+func NewLevelRouterHandler(routes []LevelRoute, opts HandlerOptions) *LevelRouterHandler {
+	if len(routes) == 0 {
+		routes = []LevelRoute{{MinLevel: slog.LevelDebug, Writer: os.Stdout}}
+	}
+
+	h := &LevelRouterHandler{routes: make([]levelHandler, len(routes))}
+	for i, r := range routes {
+		var next slog.Handler
+		if tee, ok := r.Writer.(*TeeWriter); ok {
+			next = NewTeeHandler(tee, opts)
+		} else {
+			next = NewCustomHandlerWithOptions(r.Writer, opts)
+		}
+		h.routes[i] = levelHandler{min: r.MinLevel, h: next}
+	}
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		return h.routes[i].min > h.routes[j].min
+	})
+	return h
+}
+
+// route returns the handler of a level
+func (h *LevelRouterHandler) route(level slog.Level) slog.Handler {
+	for _, r := range h.routes {
+		if level >= r.min {
+			return r.h
+		}
+	}
+	return h.routes[len(h.routes)-1].h
+}
+
+// Enabled reports whether the route of the level handles it
+func (h *LevelRouterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.route(level).Enabled(ctx, level)
+}
+
+// Handle passes the record to the route of its level
+func (h *LevelRouterHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.route(r.Level).Handle(ctx, r)
+}
+
+// writes reports whether the route of the record writes it (see recordFilter)
+func (h *LevelRouterHandler) writes(ctx context.Context, r slog.Record) bool {
+	return handlerWrites(ctx, h.route(r.Level), r)
+}
+
+func (h *LevelRouterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	routes := make([]levelHandler, len(h.routes))
+	for i, r := range h.routes {
+		routes[i] = levelHandler{min: r.min, h: r.h.WithAttrs(attrs)}
+	}
+	return &LevelRouterHandler{routes: routes}
+}
+
+func (h *LevelRouterHandler) WithGroup(name string) slog.Handler {
+	routes := make([]levelHandler, len(h.routes))
+	for i, r := range h.routes {
+		routes[i] = levelHandler{min: r.min, h: r.h.WithGroup(name)}
+	}
+	return &LevelRouterHandler{routes: routes}
+}
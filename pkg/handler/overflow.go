@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	overflowSegmentExt   = ".seg"
+	overflowSegmentBytes = 4 << 20
+)
+
+// errOverflowFull is returned when a non-critical record does not fit within the overflow size limit
+var errOverflowFull = errors.New("overflow full")
+
+// IsErrorRecord reports whether a formatted record has level Error or above, for CustomHandler's
+// text ("[ERROR]") and JSON ("log.level":"error") formats and slog's JSON/text handlers (level=ERROR)
+func IsErrorRecord(p []byte) bool {
+	for _, marker := range [][]byte{[]byte("[ERROR"), []byte(`"log.level":"error`), []byte(`"level":"ERROR`), []byte("level=ERROR")} {
+		if bytes.Contains(p, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// diskOverflow is an append-only queue of length-prefixed records in segment files
+// Producers append to the active segment; the single consumer drains sealed segments oldest first
+type diskOverflow struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex // Guards the active segment
+	cur     *os.File
+	curSize int64
+	seq     atomic.Uint64
+
+	size       atomic.Int64    // Bytes on disk, including drained parts of the oldest segment
+	readOffset int64           // Consumer position in the oldest segment
+	skipped    map[string]bool // Unreadable segments that could not be removed either (guarded by mu)
+}
+
+// newDiskOverflow opens dir, picking up segments left behind by a previous process
+func newDiskOverflow(dir string, maxBytes int64) (*diskOverflow, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("log overflow: %w", err)
+	}
+
+	o := &diskOverflow{dir: dir, maxBytes: maxBytes}
+	for _, name := range o.segments() {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			o.size.Add(info.Size())
+		}
+	}
+	return o, nil
+}
+
+// pending reports whether records are waiting on disk
+func (o *diskOverflow) pending() bool {
+	return o.size.Load() > 0
+}
+
+// append stores a record; records beyond maxBytes are refused unless critical
+func (o *diskOverflow) append(p []byte, critical bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	frame := int64(len(p)) + 4
+	if o.maxBytes > 0 && !critical && o.size.Load()+frame > o.maxBytes {
+		return errOverflowFull
+	}
+
+	if o.cur == nil || o.curSize+frame > overflowSegmentBytes {
+		if err := o.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(p)))
+	if _, err := o.cur.Write(append(header[:], p...)); err != nil {
+		return fmt.Errorf("log overflow: %w", err)
+	}
+	o.curSize += frame
+	o.size.Add(frame)
+	return nil
+}
+
+// rotateLocked closes the active segment and opens a new one (caller holds o.mu)
+func (o *diskOverflow) rotateLocked() error {
+	if o.cur != nil {
+		_ = o.cur.Close()
+		o.cur = nil
+	}
+
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), o.seq.Add(1)%1_000_000, overflowSegmentExt)
+	f, err := os.OpenFile(filepath.Join(o.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("log overflow: %w", err)
+	}
+	o.cur = f
+	o.curSize = 0
+	return nil
+}
+
+// drain writes spooled records oldest first, stopping at the first failed write
+// Delivery is at-least-once: records drained before a crash may be written again after a restart
+func (o *diskOverflow) drain(write func([]byte) error) error {
+	// Seal the active segment so producers continue in a new one while it is drained
+	o.mu.Lock()
+	if o.cur != nil {
+		_ = o.cur.Close()
+		o.cur = nil
+	}
+	segments := o.segmentsLocked()
+	o.mu.Unlock()
+
+	for _, name := range segments {
+		path := filepath.Join(o.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Left as is it would keep pending() true and every later record would spill to disk
+			o.skipSegment(name)
+			continue
+		}
+
+		for o.readOffset+4 <= int64(len(data)) {
+			n := int64(binary.BigEndian.Uint32(data[o.readOffset:]))
+			end := o.readOffset + 4 + n
+			if end > int64(len(data)) {
+				break // Truncated by a crash mid-write
+			}
+			if err := write(data[o.readOffset+4 : end]); err != nil {
+				return err
+			}
+			o.readOffset = end
+		}
+
+		_ = os.Remove(path)
+		o.size.Add(-int64(len(data)))
+		o.readOffset = 0
+	}
+	return nil
+}
+
+// skipSegment drops an unreadable segment from the queue: it is removed if possible, otherwise ignored
+// from now on, and the size is recounted from the remaining segments
+func (o *diskOverflow) skipSegment(name string) {
+	o.readOffset = 0
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := os.Remove(filepath.Join(o.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		if o.skipped == nil {
+			o.skipped = make(map[string]bool)
+		}
+		o.skipped[name] = true
+	}
+
+	var size int64
+	for _, name := range o.segmentsLocked() {
+		if info, err := os.Stat(filepath.Join(o.dir, name)); err == nil {
+			size += info.Size()
+		}
+	}
+	o.size.Store(size)
+}
+
+// close closes the active segment; spooled records stay on disk for the next process
+func (o *diskOverflow) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.cur != nil {
+		_ = o.cur.Close()
+		o.cur = nil
+	}
+}
+
+// segments returns segment names, oldest first
+func (o *diskOverflow) segments() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.segmentsLocked()
+}
+
+// segmentsLocked is segments for callers holding o.mu
+func (o *diskOverflow) segmentsLocked() []string {
+	entries, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), overflowSegmentExt) && !o.skipped[e.Name()] {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
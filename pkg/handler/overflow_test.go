@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverflowDrainSkipsUnreadableSegment(t *testing.T) {
+	dir := t.TempDir()
+	o, err := newDiskOverflow(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.append([]byte("lost"), false); err != nil {
+		t.Fatal(err)
+	}
+	o.close()
+
+	// Replace the segment with a dangling link, so reading it fails
+	segments := o.segments()
+	if len(segments) != 1 {
+		t.Fatalf("segments = %v, want one", segments)
+	}
+	path := filepath.Join(dir, segments[0])
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "missing"), path); err != nil {
+		t.Skip("symlinks unsupported:", err)
+	}
+
+	var drained [][]byte
+	if err := o.drain(func(p []byte) error {
+		drained = append(drained, p)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if o.pending() {
+		t.Fatalf("pending after skipping the unreadable segment (size %d)", o.size.Load())
+	}
+
+	if err := o.append([]byte("next"), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.drain(func(p []byte) error {
+		drained = append(drained, p)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(drained) != 1 || string(drained[0]) != "next" || o.pending() {
+		t.Fatalf("drained %q, pending %v; want only the later record", drained, o.pending())
+	}
+}
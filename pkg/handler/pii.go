@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// PIIMode controls how attributes tagged with core.PII are written
+type PIIMode int
+
+const (
+	// PIIKeep writes personal data unchanged
+	PIIKeep PIIMode = iota
+	// PIIPseudonymize replaces personal data with a salted, stable pseudonym
+	PIIPseudonymize
+	// PIIDrop removes personal data from records
+	PIIDrop
+)
+
+// String returns the mode name
+func (m PIIMode) String() string {
+	switch m {
+	case PIIPseudonymize:
+		return "pseudonymize"
+	case PIIDrop:
+		return "drop"
+	default:
+		return "keep"
+	}
+}
+
+// PIIOptions configures PIIHandler
+type PIIOptions struct {
+	// Mode applied when no EnvironmentModes entry matches (default PIIKeep)
+	Mode PIIMode
+	// EnvironmentModes overrides Mode per environment, e.g. {"production": PIIDrop, "staging": PIIPseudonymize}
+	EnvironmentModes map[string]PIIMode
+	// Environment name used for EnvironmentModes; if empty, read from APP_ENV, ENVIRONMENT or GO_ENV
+	// (see core.EnvironmentName)
+	Environment string
+	// Salt used for pseudonyms when TenantSalt is nil or returns nil
+	Salt []byte
+	// TenantSalt returns the salt of the tenant in ctx, so pseudonyms differ between tenants and
+	// deleting a tenant's salt erases the link between its pseudonyms and the original data
+	// Attributes bound with Logger.With have no context and use Salt
+	TenantSalt func(ctx context.Context) []byte
+	// Pseudonymize overrides the default pseudonym (core.Pseudonymize of the value's string form)
+	Pseudonymize func(ctx context.Context, key string, value slog.Value, salt []byte) slog.Value
+}
+
+// PIIHandler wraps a slog.Handler and keeps, pseudonymizes or drops attributes tagged with core.PII
+type PIIHandler struct {
+	next slog.Handler
+	opts PIIOptions
+	mode PIIMode
+}
+
+// NewPIIHandler wraps next with PII handling
+//
+// Usage:
+//
+//	h := handler.NewPIIHandler(slog.NewJSONHandler(os.Stdout, nil), handler.PIIOptions{
+//	    Mode:             handler.PIIPseudonymize,
+//	    EnvironmentModes: map[string]handler.PIIMode{"production": handler.PIIDrop},
+//	    Salt:             []byte(os.Getenv("PII_SALT")),
+//	})
+//	slog.New(h).Info("login", logbundle.PII("email", email))
+func NewPIIHandler(next slog.Handler, opts PIIOptions) *PIIHandler {
+	return &PIIHandler{next: next, opts: opts, mode: opts.resolveMode()}
+}
+
+// Mode returns the mode in effect for the configured environment
+func (h *PIIHandler) Mode() PIIMode {
+	return h.mode
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *PIIHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle applies the PII mode to tagged attributes and passes the record on
+func (h *PIIHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !recordHasPII(r) {
+		return h.next.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a, ok := h.apply(ctx, a); ok {
+			out.AddAttrs(a)
+		}
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs applies the PII mode to tagged attributes and passes them on
+func (h *PIIHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a, ok := h.apply(context.Background(), a); ok {
+			out = append(out, a)
+		}
+	}
+	return &PIIHandler{next: h.next.WithAttrs(out), opts: h.opts, mode: h.mode}
+}
+
+// WithGroup returns a PIIHandler wrapping next.WithGroup
+func (h *PIIHandler) WithGroup(name string) slog.Handler {
+	return &PIIHandler{next: h.next.WithGroup(name), opts: h.opts, mode: h.mode}
+}
+
+// apply unwraps, pseudonymizes or drops PII-tagged attributes (recursing into groups)
+// ok is false when the attribute is dropped
+func (h *PIIHandler) apply(ctx context.Context, a slog.Attr) (slog.Attr, bool) {
+	if p, isPII := core.AsPII(a.Value); isPII {
+		switch h.mode {
+		case PIIDrop:
+			return slog.Attr{}, false
+		case PIIPseudonymize:
+			return slog.Attr{Key: a.Key, Value: h.pseudonymize(ctx, a.Key, p.Value.Resolve())}, true
+		default:
+			return slog.Attr{Key: a.Key, Value: p.Value}, true
+		}
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return a, true
+	}
+
+	group := a.Value.Group()
+	out := make([]any, 0, len(group))
+	for _, ga := range group {
+		if ga, ok := h.apply(ctx, ga); ok {
+			out = append(out, ga)
+		}
+	}
+	return slog.Group(a.Key, out...), true
+}
+
+func (h *PIIHandler) pseudonymize(ctx context.Context, key string, v slog.Value) slog.Value {
+	salt := h.opts.Salt
+	if h.opts.TenantSalt != nil {
+		if s := h.opts.TenantSalt(ctx); s != nil {
+			salt = s
+		}
+	}
+
+	if h.opts.Pseudonymize != nil {
+		return h.opts.Pseudonymize(ctx, key, v, salt)
+	}
+	return slog.StringValue(core.Pseudonymize(salt, v.String()))
+}
+
+// resolveMode returns the EnvironmentModes entry for the current environment, or Mode
+func (o PIIOptions) resolveMode() PIIMode {
+	if len(o.EnvironmentModes) == 0 {
+		return o.Mode
+	}
+
+	env := o.Environment
+	if env == "" {
+		env = core.EnvironmentName()
+	}
+
+	for name, mode := range o.EnvironmentModes {
+		if strings.EqualFold(name, env) {
+			return mode
+		}
+	}
+	return o.Mode
+}
+
+// recordHasPII reports whether any attribute (including nested groups) is PII-tagged
+func recordHasPII(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = attrHasPII(a)
+		return !found
+	})
+	return found
+}
+
+func attrHasPII(a slog.Attr) bool {
+	if _, ok := core.AsPII(a.Value); ok {
+		return true
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			if attrHasPII(ga) {
+				return true
+			}
+		}
+	}
+	return false
+}
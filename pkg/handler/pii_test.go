@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+type tenantKey struct{}
+
+func TestPIIHandlerModes(t *testing.T) {
+	salt := []byte("salt")
+	pseudonym := core.Pseudonymize(salt, "ada@example.com")
+
+	tests := []struct {
+		mode    PIIMode
+		want    []string
+		notWant []string
+	}{
+		{mode: PIIKeep, want: []string{"email=ada@example.com", "user.email=ada@example.com"}},
+		{mode: PIIPseudonymize, want: []string{"email=" + pseudonym, "user.email=" + pseudonym}, notWant: []string{"ada@example.com"}},
+		{mode: PIIDrop, want: []string{"user.id=7"}, notWant: []string{"email=", "ada@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(NewPIIHandler(slog.NewTextHandler(&buf, nil), PIIOptions{Mode: tt.mode, Salt: salt}))
+			log.With(core.PII("email", "ada@example.com")).Info("login",
+				slog.Group("user", slog.Int("id", 7), core.PII("email", "ada@example.com")),
+			)
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Fatalf("output misses %q:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Fatalf("output contains %q:\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPIIHandlerEnvironmentModes(t *testing.T) {
+	modes := map[string]PIIMode{"production": PIIDrop, "Staging": PIIPseudonymize}
+	tests := []struct {
+		env     string
+		appEnv  string
+		goEnv   string
+		want    PIIMode
+		comment string
+	}{
+		{env: "production", want: PIIDrop},
+		{env: "staging", want: PIIPseudonymize, comment: "names match case-insensitively"},
+		{env: "development", want: PIIKeep, comment: "unlisted environment uses Mode"},
+		{appEnv: "production", want: PIIDrop, comment: "read from APP_ENV"},
+		{goEnv: "staging", want: PIIPseudonymize, comment: "read from GO_ENV"},
+		{want: PIIKeep, comment: "no environment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+tt.appEnv+tt.goEnv, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("ENVIRONMENT", "")
+			t.Setenv("GO_ENV", tt.goEnv)
+
+			h := NewPIIHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), PIIOptions{
+				Environment:      tt.env,
+				EnvironmentModes: modes,
+			})
+			if got := h.Mode(); got != tt.want {
+				t.Fatalf("Mode() = %v, want %v (%s)", got, tt.want, tt.comment)
+			}
+		})
+	}
+}
+
+func TestPIIHandlerTenantSalt(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewPIIHandler(slog.NewTextHandler(&buf, nil), PIIOptions{
+		Mode: PIIPseudonymize,
+		Salt: []byte("default"),
+		TenantSalt: func(ctx context.Context) []byte {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				return []byte(tenant)
+			}
+			return nil
+		},
+	}))
+
+	for _, tenant := range []string{"acme", "globex"} {
+		log.InfoContext(context.WithValue(context.Background(), tenantKey{}, tenant), "login", core.PII("email", "ada@example.com"))
+	}
+	log.Info("login", core.PII("email", "ada@example.com"))
+
+	out := buf.String()
+	for _, salt := range []string{"acme", "globex", "default"} {
+		if want := "email=" + core.Pseudonymize([]byte(salt), "ada@example.com"); !strings.Contains(out, want) {
+			t.Fatalf("output misses the pseudonym salted with %q:\n%s", salt, out)
+		}
+	}
+}
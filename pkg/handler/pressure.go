@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// pressureCallback is the overload callback set with SetPressureCallback
+type pressureCallback struct {
+	threshold  float64
+	fn         func(score float64, overloaded bool)
+	overloaded atomic.Bool
+}
+
+var (
+	pressureMu      sync.RWMutex
+	pressureWriters = make(map[*TeeWriter]struct{}) // Open TeeWriters
+	pressureHook    atomic.Pointer[pressureCallback]
+)
+
+// Pressure returns how saturated the TeeWriter's sinks are, from 0 (queues empty) to 1 (a queue is full
+// or records are waiting in the disk overflow): the fill ratio of its fullest sink queue
+func (t *TeeWriter) Pressure() float64 {
+	score := 0.0
+	for _, s := range t.sinks {
+		if s.overflow != nil && s.overflow.pending() {
+			return 1
+		}
+		if fill := float64(len(s.queue)) / float64(cap(s.queue)); fill > score {
+			score = fill
+		}
+	}
+	return score
+}
+
+// Pressure returns the highest pressure of the open TeeWriters (see TeeWriter.Pressure); 0 without any
+// It never blocks, so applications can check it before optional logging
+func Pressure() float64 {
+	pressureMu.RLock()
+	defer pressureMu.RUnlock()
+
+	score := 0.0
+	for t := range pressureWriters {
+		if p := t.Pressure(); p > score {
+			score = p
+		}
+	}
+	return score
+}
+
+// SetPressureCallback registers fn, called with overloaded=true when Pressure reaches threshold and with
+// overloaded=false once it falls below half of it. The state is checked on every TeeWriter write; fn runs
+// on the logging goroutine and must be quick (e.g. flip a flag). A nil fn removes the callback
+func SetPressureCallback(threshold float64, fn func(score float64, overloaded bool)) {
+	if fn == nil {
+		pressureHook.Store(nil)
+		return
+	}
+	pressureHook.Store(&pressureCallback{threshold: threshold, fn: fn})
+}
+
+// trackPressure registers an open TeeWriter
+func trackPressure(t *TeeWriter) {
+	pressureMu.Lock()
+	pressureWriters[t] = struct{}{}
+	pressureMu.Unlock()
+}
+
+// untrackPressure removes a closed TeeWriter
+func untrackPressure(t *TeeWriter) {
+	pressureMu.Lock()
+	delete(pressureWriters, t)
+	pressureMu.Unlock()
+}
+
+// notifyPressure calls the pressure callback when the overload state changes
+func notifyPressure() {
+	hook := pressureHook.Load()
+	if hook == nil {
+		return
+	}
+	score := Pressure()
+	switch {
+	case score >= hook.threshold:
+		if hook.overloaded.CompareAndSwap(false, true) {
+			hook.fn(score, true)
+		}
+	case score < hook.threshold/2:
+		if hook.overloaded.CompareAndSwap(true, false) {
+			hook.fn(score, false)
+		}
+	}
+}
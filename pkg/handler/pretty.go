@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// PrettyOptions configures PrettyPrint
+type PrettyOptions struct {
+	// Level skips records below it (nil prints all levels)
+	Level slog.Leveler
+	// TraceID keeps only records with this trace ID (core.GetTraceIDFieldName, "trace_id" or "trace.id")
+	TraceID string
+	// Route keeps only records with this route attribute (e.g. "/users/:id")
+	Route string
+	// Color renders levels, messages and keys with ANSI colors
+	Color bool
+}
+
+// filtering reports whether records are filtered by attribute
+func (o PrettyOptions) filtering() bool {
+	return o.TraceID != "" || o.Route != ""
+}
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiFaint  = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiGray   = "\033[90m"
+)
+
+// prettyField is a top-level attribute of a JSON record, in input order
+type prettyField struct {
+	key   string
+	value json.RawMessage
+}
+
+// PrettyPrint reads JSON log lines (FormatJSON, or slog's JSON handler) from r and writes them to w in
+// the console text format, optionally colorized and filtered. Lines that are not JSON objects are
+// copied unchanged unless a trace or route filter is set
+//
+// Usage:
+//
+//	kubectl logs -f deploy/api | logbundle-fmt --level warn --route /orders/:id
+func PrettyPrint(r io.Reader, w io.Writer, opts PrettyOptions) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if out, ok := PrettyLine(line, opts); ok {
+				if _, werr := w.Write(out); werr != nil {
+					return werr
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// PrettyLine renders a single JSON log line; ok is false when the line is filtered out
+func PrettyLine(line []byte, opts PrettyOptions) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 {
+		return nil, false
+	}
+
+	fields, err := parsePrettyFields(trimmed)
+	if err != nil {
+		if opts.filtering() {
+			return nil, false
+		}
+		return append(trimmed, '\n'), true
+	}
+
+	var (
+		timestamp, message, source string
+		level                      = slog.LevelInfo
+		traceID, route             string
+		attrs                      []prettyField
+	)
+	traceKey := core.GetTraceIDFieldName()
+
+	for _, f := range fields {
+		switch f.key {
+		case "@timestamp", slog.TimeKey:
+			timestamp = prettyTime(prettyString(f.value))
+		case "log.level", slog.LevelKey:
+			_ = level.UnmarshalText([]byte(prettyString(f.value)))
+		case "message", slog.MessageKey:
+			message = prettyString(f.value)
+		case "log.origin", slog.SourceKey:
+			source = prettySource(f.value)
+		case SchemaVersionKey:
+			// Only useful to log parsers
+		default:
+			switch f.key {
+			case traceKey, "trace_id", "trace.id":
+				traceID = prettyString(f.value)
+			case "route":
+				route = prettyString(f.value)
+			}
+			attrs = append(attrs, f)
+		}
+	}
+
+	if opts.Level != nil && level < opts.Level.Level() {
+		return nil, false
+	}
+	if opts.TraceID != "" && traceID != opts.TraceID {
+		return nil, false
+	}
+	if opts.Route != "" && route != opts.Route {
+		return nil, false
+	}
+
+	var b bytes.Buffer
+	if timestamp != "" {
+		b.WriteString(timestamp)
+		b.WriteByte(' ')
+	}
+	levelText := "[" + strings.ToUpper(level.String()) + "]"
+	if opts.Color {
+		levelText = levelColor(level) + levelText + ansiReset
+	}
+	b.WriteString(levelText)
+	if source != "" {
+		b.WriteString(" [" + source + "]")
+	}
+	b.WriteByte(' ')
+	if opts.Color {
+		b.WriteString(ansiBold + message + ansiReset)
+	} else {
+		b.WriteString(message)
+	}
+
+	for _, a := range attrs {
+		b.WriteByte(' ')
+		if opts.Color {
+			b.WriteString(ansiFaint + a.key + "=" + ansiReset)
+		} else {
+			b.WriteString(a.key + "=")
+		}
+		b.WriteString(prettyValue(a.value))
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), true
+}
+
+// parsePrettyFields decodes the top-level fields of a JSON object, keeping their order
+func parsePrettyFields(line []byte) ([]prettyField, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("not a JSON object")
+	}
+
+	var fields []prettyField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, prettyField{key: key, value: value})
+	}
+	return fields, nil
+}
+
+// prettyString returns a JSON string value, or the raw JSON for other values
+func prettyString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// prettyValue renders an attribute value: strings are unquoted unless they contain spaces
+// or quotes, other values keep their compact JSON form
+func prettyValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err == nil {
+		return compact.String()
+	}
+	return string(raw)
+}
+
+// prettyTime reformats an RFC 3339 timestamp to the text format's "2006/01/02 15:04:05"
+func prettyTime(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.Format("2006/01/02 15:04:05")
+}
+
+// prettySource renders the ECS log.origin or slog source object as "file:line"
+func prettySource(raw json.RawMessage) string {
+	var src struct {
+		File any `json:"file"`
+		Line int `json:"line"`
+	}
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return ""
+	}
+
+	switch file := src.File.(type) {
+	case string:
+		return file + ":" + strconv.Itoa(src.Line)
+	case map[string]any:
+		name, _ := file["name"].(string)
+		line, _ := file["line"].(float64)
+		return name + ":" + strconv.Itoa(int(line))
+	}
+	return ""
+}
+
+// levelColor returns the ANSI color of a level
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.LevelInfo:
+		return ansiCyan
+	default:
+		return ansiGray
+	}
+}
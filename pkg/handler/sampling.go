@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// SamplingOptions configures SamplingHandler
+type SamplingOptions struct {
+	// Rate is the fraction of records below KeepLevel that are written, from 0 to 1
+	Rate float64
+	// KeepLevel is the level from which every record is written (default: Warn)
+	KeepLevel slog.Leveler
+}
+
+// SamplingHandler wraps a slog.Handler and writes only a fraction of the records below KeepLevel.
+// Records carrying a trace ID (see core.WithTraceID) are sampled per trace, so a request is either
+// logged completely or not at all; other records are sampled individually. Critical records
+// (core.WithCritical) and debug-scoped requests (core.WithDebugScope) are always written. Dropped records
+// are counted in log_records_sampled_out_total{level}
+type SamplingHandler struct {
+	next      slog.Handler
+	threshold uint64 // Hashes below it are kept
+	keepAll   bool
+	keepLevel slog.Leveler
+}
+
+// NewSamplingHandler wraps next with sampling; a Rate of 1 or more writes everything, 0 or less drops every
+// record below KeepLevel
+//
+// Usage:
+//
+//	h := handler.NewSamplingHandler(handler.NewCustomHandler(os.Stdout, slog.LevelInfo, false),
+//	    handler.SamplingOptions{Rate: 0.1})
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	if opts.KeepLevel == nil {
+		opts.KeepLevel = slog.LevelWarn
+	}
+	h := &SamplingHandler{next: next, keepLevel: opts.KeepLevel}
+	switch {
+	case opts.Rate >= 1:
+		h.keepAll = true
+	case opts.Rate > 0:
+		h.threshold = uint64(opts.Rate * math.MaxUint64)
+	}
+	return h
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record on if it is sampled in
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.keep(ctx, r.Level) {
+		metrics.IncCounter("log_records_sampled_out_total", metrics.Labels{"level": strings.ToLower(r.Level.String())})
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// keep reports whether a record at level is written
+func (h *SamplingHandler) keep(ctx context.Context, level slog.Level) bool {
+	if h.keepAll || level >= h.keepLevel.Level() || core.IsCritical(ctx) || core.IsDebugScope(ctx) {
+		return true
+	}
+	if traceID := core.TraceIDFromContext(ctx); traceID != "" {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(traceID))
+		return mix64(hash.Sum64()) < h.threshold
+	}
+	return rand.Uint64() < h.threshold
+}
+
+// mix64 spreads FNV's low-bit differences over the whole word (the splitmix64 finalizer), since trace IDs
+// of sequential generators differ only in their last characters
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// WithAttrs returns a SamplingHandler over next.WithAttrs
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	return &clone
+}
+
+// WithGroup returns a SamplingHandler over next.WithGroup
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
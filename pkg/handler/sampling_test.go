@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		ctx   func(ctx context.Context) context.Context
+		level slog.Level
+		want  bool
+	}{
+		{name: "rate zero drops info", rate: 0, level: slog.LevelInfo, want: false},
+		{name: "rate one keeps info", rate: 1, level: slog.LevelInfo, want: true},
+		{name: "keep level always written", rate: 0, level: slog.LevelWarn, want: true},
+		{name: "critical bypasses sampling", rate: 0, level: slog.LevelInfo, ctx: core.WithCritical, want: true},
+		{name: "debug scope bypasses sampling", rate: 0, level: slog.LevelInfo, ctx: core.WithDebugScope, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewSamplingHandler(NewCustomHandler(&buf, slog.LevelDebug, false), SamplingOptions{Rate: tt.rate}))
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+			logger.Log(ctx, tt.level, "event")
+			if got := buf.Len() > 0; got != tt.want {
+				t.Fatalf("written = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingHandlerKeepsWholeTraces(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSamplingHandler(NewCustomHandler(&buf, slog.LevelDebug, false), SamplingOptions{Rate: 0.5}))
+
+	kept := 0
+	for i := range 200 {
+		buf.Reset()
+		ctx := core.WithTraceID(context.Background(), fmt.Sprintf("trace-%d", i))
+		for range 5 {
+			logger.InfoContext(ctx, "step")
+		}
+		switch n := strings.Count(buf.String(), "step"); n {
+		case 0:
+		case 5:
+			kept++
+		default:
+			t.Fatalf("trace-%d: %d of 5 records written, want all or none", i, n)
+		}
+	}
+	if kept < 50 || kept > 150 {
+		t.Fatalf("%d of 200 traces kept at rate 0.5", kept)
+	}
+}
+
+func BenchmarkSamplingHandler(b *testing.B) {
+	logger := slog.New(NewSamplingHandler(NewCustomHandler(&bytes.Buffer{}, slog.LevelInfo, false), SamplingOptions{Rate: 0.1}))
+	ctx := core.WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.InfoContext(ctx, "step")
+	}
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// AttrSchema describes the attribute keys a logger may emit
+// Nested groups are checked by their dotted path (e.g. "http.status_code"). Top-level keys added by
+// logbundle itself (the trace ID field and "critical") are always accepted
+type AttrSchema struct {
+	// Fields maps allowed keys to their expected kind; slog.KindAny accepts any type
+	Fields map[string]slog.Kind
+	// AllowUnknown accepts keys missing from Fields (they are still checked for snake_case)
+	AllowUnknown bool
+	// SnakeCase requires every key segment to be lower snake_case ("user_id", not "userId")
+	SnakeCase bool
+	// OnViolation receives each violation; use it to fail tests (t.Errorf) or count violations
+	// If nil, the first occurrence of each violation is logged at Warn through the wrapped handler
+	OnViolation func(SchemaViolation)
+}
+
+// SchemaViolation describes an attribute that does not match the schema
+type SchemaViolation struct {
+	Key     string
+	Reason  string // "unknown_key", "wrong_type" or "not_snake_case"
+	Message string // Message of the offending record
+	Got     slog.Kind
+	Want    slog.Kind
+}
+
+func (v SchemaViolation) String() string {
+	switch v.Reason {
+	case "wrong_type":
+		return fmt.Sprintf("attribute %q in %q: got %s, want %s", v.Key, v.Message, v.Got, v.Want)
+	default:
+		return fmt.Sprintf("attribute %q in %q: %s", v.Key, v.Message, v.Reason)
+	}
+}
+
+// SchemaHandler wraps a slog.Handler and checks attribute keys and types against an AttrSchema
+// Records are always passed on unchanged; violations are only reported
+type SchemaHandler struct {
+	next     slog.Handler
+	root     slog.Handler // Handler passed to NewSchemaHandler, used for violation reports outside any group
+	schema   *AttrSchema
+	prefix   string      // Dotted group prefix
+	attrs    []slog.Attr // Attributes from WithAttrs, already prefixed
+	reported *sync.Map   // Violations already logged by the default reporter
+}
+
+// NewSchemaHandler wraps next with attribute schema checks
+//
+// Usage:
+//
+//	schema := &handler.AttrSchema{
+//	    Fields:    map[string]slog.Kind{"user_id": slog.KindString, "duration": slog.KindDuration},
+//	    SnakeCase: true,
+//	}
+//	logger := slog.New(handler.NewSchemaHandler(slog.NewJSONHandler(os.Stdout, nil), schema))
+//
+// In tests, fail on violations instead of logging them:
+//
+//	schema.OnViolation = func(v handler.SchemaViolation) { t.Error(v) }
+func NewSchemaHandler(next slog.Handler, schema *AttrSchema) *SchemaHandler {
+	return &SchemaHandler{next: next, root: next, schema: schema, reported: &sync.Map{}}
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *SchemaHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle checks the record's attributes and passes the record on
+func (h *SchemaHandler) Handle(ctx context.Context, r slog.Record) error {
+	var violations []SchemaViolation
+	for _, a := range h.attrs {
+		violations = h.check(a, "", r.Message, violations)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		violations = h.check(a, h.prefix, r.Message, violations)
+		return true
+	})
+
+	if err := h.next.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	for _, v := range violations {
+		h.report(ctx, v)
+	}
+	return nil
+}
+
+// WithAttrs records the attributes for checking and passes them on
+func (h *SchemaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	clone.next = h.next.WithAttrs(attrs)
+	return &clone
+}
+
+// WithGroup extends the key prefix and passes the group on
+func (h *SchemaHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// check validates an attribute (recursing into groups) and appends any violations
+func (h *SchemaHandler) check(a slog.Attr, prefix, msg string, violations []SchemaViolation) []SchemaViolation {
+	if a.Key == "" {
+		if a.Value.Kind() != slog.KindGroup {
+			return violations
+		}
+		// Inline group: its attributes belong to the current level
+		for _, ga := range a.Value.Group() {
+			violations = h.check(ga, prefix, msg, violations)
+		}
+		return violations
+	}
+
+	key := prefix + a.Key
+	value := a.Value.Resolve()
+
+	if h.schema.SnakeCase && !isSnakeCase(a.Key) {
+		violations = append(violations, SchemaViolation{Key: key, Reason: "not_snake_case", Message: msg})
+	}
+
+	want, known := h.schema.Fields[key]
+	if !known && prefix == "" {
+		want, known = libraryKind(key)
+	}
+	if value.Kind() == slog.KindGroup && (!known || want == slog.KindGroup) {
+		for _, ga := range value.Group() {
+			violations = h.check(ga, key+".", msg, violations)
+		}
+		return violations
+	}
+
+	switch {
+	case !known && !h.schema.AllowUnknown:
+		violations = append(violations, SchemaViolation{Key: key, Reason: "unknown_key", Message: msg})
+	case known && want != slog.KindAny && !kindMatches(value, want):
+		violations = append(violations, SchemaViolation{
+			Key: key, Reason: "wrong_type", Message: msg, Got: value.Kind(), Want: want,
+		})
+	}
+	return violations
+}
+
+// report passes the violation to OnViolation or logs its first occurrence
+func (h *SchemaHandler) report(ctx context.Context, v SchemaViolation) {
+	if h.schema.OnViolation != nil {
+		h.schema.OnViolation(v)
+		return
+	}
+
+	if _, seen := h.reported.LoadOrStore(v.Key+"|"+v.Reason, struct{}{}); seen {
+		return
+	}
+	if !h.root.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	r := slog.NewRecord(core.Now(), slog.LevelWarn, "Log attribute schema violation", 0)
+	r.AddAttrs(
+		slog.String("attribute", v.Key),
+		slog.String("reason", v.Reason),
+		slog.String("log_message", v.Message),
+	)
+	if v.Reason == "wrong_type" {
+		r.AddAttrs(slog.String("got", v.Got.String()), slog.String("want", v.Want.String()))
+	}
+	_ = h.root.Handle(ctx, r)
+}
+
+// libraryKind returns the kind of a top-level key logbundle adds to records, so schemas need not list it
+func libraryKind(key string) (slog.Kind, bool) {
+	switch key {
+	case core.GetTraceIDFieldName():
+		return slog.KindString, true
+	case "critical":
+		return slog.KindBool, true
+	}
+	return slog.KindAny, false
+}
+
+// kindMatches reports whether the value has the wanted kind
+// Integer kinds are interchangeable, and KindAny values are accepted for KindAny only
+func kindMatches(v slog.Value, want slog.Kind) bool {
+	got := v.Kind()
+	if got == want {
+		return true
+	}
+	isInt := func(k slog.Kind) bool { return k == slog.KindInt64 || k == slog.KindUint64 }
+	return isInt(got) && isInt(want)
+}
+
+// isSnakeCase reports whether key is lower snake_case (digits allowed after the first character)
+func isSnakeCase(key string) bool {
+	if key == "" || key[0] == '_' || key[len(key)-1] == '_' || strings.Contains(key, "__") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c == '_':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// schemaLogger returns a logger checking schema and the violations it reported
+func schemaLogger(schema *AttrSchema) (*slog.Logger, *[]SchemaViolation) {
+	var violations []SchemaViolation
+	schema.OnViolation = func(v SchemaViolation) { violations = append(violations, v) }
+	h := NewSchemaHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil), schema)
+	return slog.New(NewTraceIDHandler(h)), &violations
+}
+
+func TestSchemaHandlerReportsViolations(t *testing.T) {
+	logger, violations := schemaLogger(&AttrSchema{
+		Fields: map[string]slog.Kind{
+			"user_id":          slog.KindString,
+			"count":            slog.KindInt64,
+			"http.status_code": slog.KindInt64,
+		},
+		SnakeCase: true,
+	})
+
+	logger.Info("ok", "user_id", "u1", "count", uint64(3), slog.Group("http", "status_code", 200))
+	if len(*violations) != 0 {
+		t.Fatalf("violations = %v, want none for a matching record", *violations)
+	}
+
+	logger.Info("bad", "user_id", 7, "extra", true, "userName", "ada")
+	logger.WithGroup("http").Info("grouped", "status_code", "200")
+
+	want := []SchemaViolation{
+		{Key: "user_id", Reason: "wrong_type", Message: "bad", Got: slog.KindInt64, Want: slog.KindString},
+		{Key: "extra", Reason: "unknown_key", Message: "bad"},
+		{Key: "userName", Reason: "not_snake_case", Message: "bad"},
+		{Key: "userName", Reason: "unknown_key", Message: "bad"},
+		{Key: "http.status_code", Reason: "wrong_type", Message: "grouped", Got: slog.KindString, Want: slog.KindInt64},
+	}
+	if len(*violations) != len(want) {
+		t.Fatalf("violations = %v, want %v", *violations, want)
+	}
+	for i, v := range *violations {
+		if v != want[i] {
+			t.Fatalf("violation %d = %+v, want %+v", i, v, want[i])
+		}
+	}
+}
+
+func TestSchemaHandlerAcceptsLibraryKeys(t *testing.T) {
+	logger, violations := schemaLogger(&AttrSchema{Fields: map[string]slog.Kind{}, SnakeCase: true})
+
+	ctx := core.WithTraceID(context.Background(), "trace-1")
+	logger.InfoContext(ctx, "audit", slog.Bool("critical", true))
+	if len(*violations) != 0 {
+		t.Fatalf("violations = %v, want the trace ID and critical keys accepted", *violations)
+	}
+
+	logger.Info("nested", slog.Group("job", slog.Bool("critical", true)))
+	if len(*violations) != 1 || (*violations)[0].Key != "job.critical" {
+		t.Fatalf("violations = %v, want library keys accepted at the top level only", *violations)
+	}
+}
+
+func TestSchemaHandlerLogsFirstViolation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSchemaHandler(slog.NewTextHandler(&buf, nil), &AttrSchema{}))
+
+	logger.Info("first", "extra", 1)
+	logger.Info("second", "extra", 2)
+
+	out := buf.String()
+	if n := strings.Count(out, "Log attribute schema violation"); n != 1 {
+		t.Fatalf("violation logged %d times, want once:\n%s", n, out)
+	}
+	if !strings.Contains(out, "attribute=extra reason=unknown_key log_message=first") {
+		t.Fatalf("violation report misses its attributes:\n%s", out)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// SecretsMaskedKey is the attribute added to records in which secrets were masked
+const SecretsMaskedKey = "secrets_masked"
+
+// SecretsHandler wraps a slog.Handler and masks likely secrets (see core.MaskSecrets) in the message
+// and string attributes. Records with masked values get a "secrets_masked" attribute listing the
+// detected kinds, and log_secrets_masked_total{kind} is incremented
+type SecretsHandler struct {
+	next slog.Handler
+}
+
+// NewSecretsHandler wraps next with secret detection
+//
+// Usage:
+//
+//	logger := slog.New(handler.NewSecretsHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.Info("calling API", "auth", "Bearer eyJhbGciOi...") // auth="****[bearer_token]" secrets_masked=[bearer_token]
+func NewSecretsHandler(next slog.Handler) *SecretsHandler {
+	if h, ok := next.(*SecretsHandler); ok {
+		return h
+	}
+	return &SecretsHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *SecretsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle masks secrets and passes the record on
+func (h *SecretsHandler) Handle(ctx context.Context, r slog.Record) error {
+	kinds := make(map[string]struct{})
+
+	msg, found := core.MaskSecrets(r.Message)
+	addKinds(kinds, found)
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, maskAttr(a, kinds))
+		return true
+	})
+
+	if len(kinds) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	out.AddAttrs(attrs...)
+
+	list := make([]string, 0, len(kinds))
+	for k := range kinds {
+		list = append(list, k)
+		metrics.IncCounter("log_secrets_masked_total", metrics.Labels{"kind": k})
+	}
+	slices.Sort(list)
+	out.AddAttrs(slog.String(SecretsMaskedKey, strings.Join(list, ",")))
+
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs masks secrets in the attributes and passes them on
+func (h *SecretsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kinds := make(map[string]struct{})
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = maskAttr(a, kinds)
+	}
+	return &SecretsHandler{next: h.next.WithAttrs(masked)}
+}
+
+// WithGroup returns a SecretsHandler wrapping next.WithGroup
+func (h *SecretsHandler) WithGroup(name string) slog.Handler {
+	return &SecretsHandler{next: h.next.WithGroup(name)}
+}
+
+// maskAttr masks secrets in string values (recursing into groups) and collects the detected kinds
+// Other values (errors, maps, structs) are checked in their text form and replaced by the masked text
+// only when it contains a secret, so clean values keep their type
+func maskAttr(a slog.Attr, kinds map[string]struct{}) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		if masked, found := core.MaskSecrets(v.String()); len(found) > 0 {
+			addKinds(kinds, found)
+			return slog.String(a.Key, masked)
+		}
+	case slog.KindAny:
+		if masked, found := core.MaskSecrets(anyText(v.Any())); len(found) > 0 {
+			addKinds(kinds, found)
+			return slog.String(a.Key, masked)
+		}
+	case slog.KindGroup:
+		group := v.Group()
+		masked := make([]any, len(group))
+		for i, ga := range group {
+			masked[i] = maskAttr(ga, kinds)
+		}
+		return slog.Group(a.Key, masked...)
+	}
+	return a
+}
+
+// anyText returns the text an any value is logged as: the error message, String() or fmt.Sprint
+func anyText(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func addKinds(kinds map[string]struct{}, found []string) {
+	for _, k := range found {
+		kinds[k] = struct{}{}
+	}
+}
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/chaos"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Sink is a named output of a TeeWriter
+type Sink struct {
+	Name   string
+	Writer io.Writer
+	// Format overrides the logger format for this sink (nil keeps it), e.g. JSON for a file or Loki
+	// sink next to text on stdout. Takes effect when the logger is built with NewTeeHandler
+	// (logbundle's builder does so for a TeeWriter output)
+	Format *Format
+	// Color writes colorized console lines (see PrettyLine) instead of the sink format, for stdout in development
+	Color bool
+}
+
+// TeeOptions configures a TeeWriter
+type TeeOptions struct {
+	// QueueSize is the number of pending records buffered per sink (default: 1024)
+	QueueSize int
+	// MaxRetries is the number of retries of a failed write before the record is dropped
+	// (default: 3; negative disables retries)
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, doubled on each retry (default: 100ms)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the retry delay (default: 5s)
+	MaxBackoff time.Duration
+	// OverflowDir enables disk-backed overflow: records that do not fit in a sink's queue or exhaust
+	// their retries are spilled to segment files in OverflowDir/<sink name> and drained once the sink
+	// recovers. Segments left by a previous process are drained on start
+	OverflowDir string
+	// OverflowMaxBytes bounds the overflow per sink; beyond it only critical records are spilled (default: 256MiB)
+	OverflowMaxBytes int64
+	// DrainInterval between attempts to drain the overflow (default: 1s)
+	DrainInterval time.Duration
+	// Critical reports records that are spilled even when the overflow is full (default: IsErrorRecord)
+	Critical func(p []byte) bool
+}
+
+// SinkStats holds the delivery counters of a sink
+type SinkStats struct {
+	Written  uint64 // Records written
+	Dropped  uint64 // Records dropped (queue full, retries exhausted or writer closed)
+	Failures uint64 // Failed write attempts
+	Spilled  uint64 // Records written to the disk overflow
+}
+
+// TeeWriter writes every record to several sinks, isolating their failures: each sink has its own
+// queue and goroutine, so a slow or failing sink (e.g. Loki down) never blocks or aborts the others
+// Failed writes are retried with exponential backoff on a copy of the buffer; records that cannot be
+// delivered are dropped and counted in log_sink_dropped_total{sink, reason}, unless TeeOptions.OverflowDir
+// is set: then they are spilled to disk and drained later, and Error-level records are never dropped
+// Record order across the overflow is not guaranteed
+type TeeWriter struct {
+	sinks  []*teeSink
+	mu     sync.RWMutex // Guards closed; never held while writing to a sink or calling back
+	closed bool
+	syncs  sync.WaitGroup // WriteSync calls in flight, awaited by Close
+}
+
+type teeSink struct {
+	name     string
+	w        io.Writer
+	format   *Format
+	color    bool
+	opts     TeeOptions
+	wmu      sync.Mutex // Serializes writes from the worker and WriteSync
+	queue    chan []byte
+	done     chan struct{}
+	overflow *diskOverflow // nil without OverflowDir
+	pending  atomic.Int64  // Records queued and not yet written, spilled or dropped
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failures atomic.Uint64
+	spilled  atomic.Uint64
+}
+
+// NewTeeWriter starts a TeeWriter for sinks; call Close on shutdown to flush pending records
+//
+// Usage:
+//
+//	tee := handler.NewTeeWriter([]handler.Sink{
+//	    {Name: "stdout", Writer: os.Stdout},
+//	    {Name: "loki", Writer: lokiWriter},
+//	})
+//	defer tee.Close()
+//	bundle := logbundle.NewBuilder().WithOutput(tee).Build()
+func NewTeeWriter(sinks []Sink, opts ...TeeOptions) *TeeWriter {
+	var o TeeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 1024
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 5 * time.Second
+	}
+	if o.OverflowMaxBytes <= 0 {
+		o.OverflowMaxBytes = 256 << 20
+	}
+	if o.DrainInterval <= 0 {
+		o.DrainInterval = time.Second
+	}
+	if o.Critical == nil {
+		o.Critical = IsErrorRecord
+	}
+
+	t := &TeeWriter{sinks: make([]*teeSink, 0, len(sinks))}
+	for _, s := range sinks {
+		ts := &teeSink{
+			name:   s.Name,
+			w:      s.Writer,
+			format: s.Format,
+			color:  s.Color,
+			opts:   o,
+			queue:  make(chan []byte, o.QueueSize),
+			done:   make(chan struct{}),
+		}
+		if o.OverflowDir != "" {
+			// Without a usable directory the sink falls back to dropping records
+			overflow, err := newDiskOverflow(filepath.Join(o.OverflowDir, s.Name), o.OverflowMaxBytes)
+			if err == nil {
+				ts.overflow = overflow
+			} else {
+				metrics.IncCounter("log_sink_overflow_errors_total", metrics.Labels{"sink": s.Name})
+			}
+		}
+		t.sinks = append(t.sinks, ts)
+		go ts.run()
+	}
+	trackPressure(t)
+	return t
+}
+
+// Write queues a copy of p for every sink and never fails; delivery errors are isolated per sink
+func (t *TeeWriter) Write(p []byte) (int, error) {
+	return t.write(p, t.sinks)
+}
+
+// write queues a copy of p for sinks
+func (t *TeeWriter) write(p []byte, sinks []*teeSink) (int, error) {
+	t.enqueue(p, sinks)
+	// Outside the lock, so a callback that logs or closes the writer cannot deadlock
+	notifyPressure()
+	return len(p), nil
+}
+
+// enqueue queues a copy of p for sinks without blocking
+func (t *TeeWriter) enqueue(p []byte, sinks []*teeSink) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	buf := append([]byte(nil), p...)
+	for _, s := range sinks {
+		if t.closed {
+			s.drop("closed")
+			continue
+		}
+		if chaos.SinkQueueFull(s.name) {
+			s.overflowOrDrop(buf, "queue_full")
+			continue
+		}
+		s.pending.Add(1)
+		select {
+		case s.queue <- buf:
+		default:
+			s.pending.Add(-1)
+			s.overflowOrDrop(buf, "queue_full")
+		}
+	}
+}
+
+// WriteSync writes p to every sink synchronously, retrying with backoff, and returns the failures
+// of sinks that could not take it (joined). Records that failed are still spilled when an overflow
+// is configured, so they may be delivered later: delivery is at-least-once
+// It implements SyncWriter and is used for critical records (see core.WithCritical)
+func (t *TeeWriter) WriteSync(p []byte) error {
+	return t.writeSync(p, t.sinks)
+}
+
+// writeSync writes p to sinks synchronously; the lock is only held to register the call, so retries
+// never block Write or a pending Close
+func (t *TeeWriter) writeSync(p []byte, sinks []*teeSink) error {
+	t.mu.RLock()
+	if t.closed {
+		t.mu.RUnlock()
+		return errors.New("tee writer closed")
+	}
+	t.syncs.Add(1)
+	t.mu.RUnlock()
+	defer t.syncs.Done()
+
+	var errs []error
+	for _, s := range sinks {
+		if err := s.deliver(p); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderFormat returns the format records for the sink are rendered in before queueing:
+// its override, JSON for colorized sinks (rendered by PrettyLine), or the logger format def
+func (s *teeSink) renderFormat(def Format) Format {
+	if s.color {
+		return FormatJSON
+	}
+	if s.format != nil {
+		return *s.format
+	}
+	return def
+}
+
+// teeFormatWriter queues records to the sinks of a single render format
+type teeFormatWriter struct {
+	t     *TeeWriter
+	sinks []*teeSink
+}
+
+func (w *teeFormatWriter) Write(p []byte) (int, error) {
+	return w.t.write(p, w.sinks)
+}
+
+// WriteSync implements SyncWriter for the sinks of the format
+func (w *teeFormatWriter) WriteSync(p []byte) error {
+	return w.t.writeSync(p, w.sinks)
+}
+
+// formatWriters groups the sinks by render format, in sink order; def is the logger format
+func (t *TeeWriter) formatWriters(def Format) (formats []Format, writers []io.Writer) {
+	byFormat := map[Format]*teeFormatWriter{}
+	for _, s := range t.sinks {
+		f := s.renderFormat(def)
+		w, ok := byFormat[f]
+		if !ok {
+			w = &teeFormatWriter{t: t}
+			byFormat[f] = w
+			formats = append(formats, f)
+			writers = append(writers, w)
+		}
+		w.sinks = append(w.sinks, s)
+	}
+	return formats, writers
+}
+
+// Stats returns the delivery counters per sink name
+func (t *TeeWriter) Stats() map[string]SinkStats {
+	stats := make(map[string]SinkStats, len(t.sinks))
+	for _, s := range t.sinks {
+		stats[s.name] = SinkStats{
+			Written:  s.written.Load(),
+			Dropped:  s.dropped.Load(),
+			Failures: s.failures.Load(),
+			Spilled:  s.spilled.Load(),
+		}
+	}
+	return stats
+}
+
+// Flush waits until the sink queues are empty: every record queued so far (and meanwhile) was written,
+// spilled or dropped. It returns ctx.Err() when ctx is done first, e.g. while a failing sink retries
+// Records in the disk overflow are not waited for; they drain once their sink recovers
+func (t *TeeWriter) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for !t.idle() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// idle reports whether no sink has queued records
+func (t *TeeWriter) idle() bool {
+	for _, s := range t.sinks {
+		if s.pending.Load() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Close stops accepting records and waits until the WriteSync calls in flight returned and every sink
+// has written, spilled or dropped its queue
+// Records written after Close are dropped; records left in the overflow are drained by the next process
+func (t *TeeWriter) Close() error {
+	t.mu.Lock()
+	wasClosed := t.closed
+	t.closed = true
+	t.mu.Unlock()
+
+	if !wasClosed {
+		// Writers check closed under the lock, so nothing is queued anymore; WriteSync calls in flight
+		// may still spill to the overflow, which the sink goroutine closes on exit
+		t.syncs.Wait()
+		for _, s := range t.sinks {
+			close(s.queue)
+		}
+	}
+	untrackPressure(t)
+
+	for _, s := range t.sinks {
+		<-s.done
+	}
+	return nil
+}
+
+// run writes queued records and drains the overflow
+func (s *teeSink) run() {
+	defer close(s.done)
+
+	var drainTick <-chan time.Time
+	if s.overflow != nil {
+		ticker := time.NewTicker(s.opts.DrainInterval)
+		defer ticker.Stop()
+		drainTick = ticker.C
+		s.drainOverflow()
+	}
+
+	for {
+		select {
+		case buf, ok := <-s.queue:
+			if !ok {
+				if s.overflow != nil {
+					s.drainOverflow()
+					s.overflow.close()
+				}
+				return
+			}
+			if s.overflow != nil && s.overflow.pending() {
+				// The sink is recovering; queue behind the records already on disk
+				s.overflowOrDrop(buf, "write_failed")
+			} else {
+				_ = s.deliver(buf)
+			}
+			s.pending.Add(-1)
+		case <-drainTick:
+			s.drainOverflow()
+		}
+	}
+}
+
+// deliver writes a record, retrying failures with exponential backoff; when retries are exhausted the
+// record is spilled or dropped and the last error returned
+func (s *teeSink) deliver(buf []byte) error {
+	backoff := s.opts.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.write(buf)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.opts.MaxRetries {
+			s.overflowOrDrop(buf, "write_failed")
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+	}
+}
+
+// write performs a single write and updates the counters
+func (s *teeSink) write(buf []byte) error {
+	if s.color {
+		// Overflow segments keep the JSON record; it is rendered on every write attempt
+		pretty, ok := PrettyLine(buf, PrettyOptions{Color: true})
+		if !ok {
+			return nil
+		}
+		buf = pretty
+	}
+
+	s.wmu.Lock()
+	err := chaos.SinkWrite(s.name)
+	if err == nil {
+		_, err = s.w.Write(buf)
+	}
+	s.wmu.Unlock()
+
+	if err != nil {
+		s.failures.Add(1)
+		metrics.IncCounter("log_sink_write_errors_total", metrics.Labels{"sink": s.name})
+		return err
+	}
+	s.written.Add(1)
+	return nil
+}
+
+// overflowOrDrop spills a record to disk, or drops it with reason when there is no overflow or it is full
+func (s *teeSink) overflowOrDrop(buf []byte, reason string) {
+	if s.overflow == nil {
+		s.drop(reason)
+		return
+	}
+
+	if err := s.overflow.append(buf, s.opts.Critical(buf)); err != nil {
+		if errors.Is(err, errOverflowFull) {
+			s.drop("overflow_full")
+		} else {
+			s.drop("overflow_failed")
+		}
+		return
+	}
+	s.spilled.Add(1)
+	metrics.IncCounter("log_sink_spilled_total", metrics.Labels{"sink": s.name})
+}
+
+// drainOverflow writes spooled records until the sink fails again
+func (s *teeSink) drainOverflow() {
+	if !s.overflow.pending() {
+		return
+	}
+	_ = s.overflow.drain(s.write)
+}
+
+func (s *teeSink) drop(reason string) {
+	s.dropped.Add(1)
+	metrics.IncCounter("log_sink_dropped_total", metrics.Labels{"sink": s.name, "reason": reason})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks writes until open is closed, then fails while failing is set
+type gatedWriter struct {
+	open    chan struct{}
+	failing atomic.Bool
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{open: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.open
+	if w.failing.Load() {
+		return 0, errors.New("sink down")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// within fails the test when fn does not return in time
+func within(t *testing.T, d time.Duration, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatalf("%s blocked for %v", what, d)
+	}
+}
+
+func TestTeeWriteSyncDoesNotBlockWritesDuringClose(t *testing.T) {
+	sink := newGatedWriter()
+	tee := NewTeeWriter([]Sink{{Name: "slow", Writer: sink}})
+
+	syncErr := make(chan error, 1)
+	go func() { syncErr <- tee.WriteSync([]byte("critical\n")) }()
+	time.Sleep(20 * time.Millisecond) // WriteSync is stuck in the sink
+
+	closed := make(chan struct{})
+	go func() {
+		_ = tee.Close()
+		close(closed)
+	}()
+	time.Sleep(20 * time.Millisecond) // Close waits for WriteSync
+
+	within(t, time.Second, "Write during a pending Close", func() {
+		_, _ = tee.Write([]byte("late\n"))
+	})
+	if got := tee.Stats()["slow"].Dropped; got != 1 {
+		t.Fatalf("dropped = %d, want the record written after Close", got)
+	}
+
+	close(sink.open)
+	if err := <-syncErr; err != nil {
+		t.Fatalf("WriteSync: %v", err)
+	}
+	<-closed
+	if sink.String() != "critical\n" {
+		t.Fatalf("sink = %q, want the critical record", sink.String())
+	}
+}
+
+func TestTeeWriteSyncReportsFailures(t *testing.T) {
+	sink := newGatedWriter()
+	close(sink.open)
+	sink.failing.Store(true)
+	tee := NewTeeWriter([]Sink{{Name: "down", Writer: sink}}, TeeOptions{MaxRetries: 2, InitialBackoff: time.Millisecond})
+	defer tee.Close()
+
+	if err := tee.WriteSync([]byte("critical\n")); err == nil {
+		t.Fatal("WriteSync succeeded with a failing sink")
+	}
+	if stats := tee.Stats()["down"]; stats.Failures != 3 || stats.Dropped != 1 {
+		t.Fatalf("stats = %+v, want 3 failures and 1 drop", stats)
+	}
+
+	_ = tee.Close()
+	if err := tee.WriteSync([]byte("after close\n")); err == nil {
+		t.Fatal("WriteSync succeeded after Close")
+	}
+}
+
+func TestTeePressureCallbackRunsOutsideLock(t *testing.T) {
+	sink := newGatedWriter()
+	tee := NewTeeWriter([]Sink{{Name: "stuck", Writer: sink}}, TeeOptions{QueueSize: 1})
+	defer tee.Close()
+	defer close(sink.open)
+
+	var calls atomic.Int32
+	SetPressureCallback(0.5, func(score float64, overloaded bool) {
+		if overloaded {
+			calls.Add(1)
+			// Close takes the lock first; it used to deadlock against the read lock held by Write
+			tee.mu.Lock()
+			defer tee.mu.Unlock()
+		}
+	})
+	t.Cleanup(func() { SetPressureCallback(0, nil) })
+
+	within(t, time.Second, "Write with a pressure callback taking the lock", func() {
+		for range 3 {
+			_, _ = tee.Write([]byte("record\n"))
+		}
+	})
+	if calls.Load() == 0 {
+		t.Fatal("pressure callback not called")
+	}
+}
+
+func TestTeeOverflowSpillsAndDrains(t *testing.T) {
+	sink := newGatedWriter()
+	close(sink.open)
+	sink.failing.Store(true)
+	tee := NewTeeWriter([]Sink{{Name: "flaky", Writer: sink}}, TeeOptions{
+		MaxRetries:    -1,
+		OverflowDir:   t.TempDir(),
+		DrainInterval: 10 * time.Millisecond,
+	})
+	defer tee.Close()
+
+	_, _ = tee.Write([]byte("spilled\n"))
+	waitFor(t, "spill", func() bool { return tee.Stats()["flaky"].Spilled == 1 })
+	if p := tee.Pressure(); p != 1 {
+		t.Fatalf("pressure = %v with a pending overflow, want 1", p)
+	}
+
+	sink.failing.Store(false)
+	waitFor(t, "drain", func() bool { return sink.String() == "spilled\n" })
+	if stats := tee.Stats()["flaky"]; stats.Dropped != 0 {
+		t.Fatalf("stats = %+v, want no drops", stats)
+	}
+}
+
+// waitFor polls cond until it holds or a second passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
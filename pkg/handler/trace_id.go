@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// TraceIDHandler wraps any slog.Handler and adds the context's trace ID (see core.WithTraceID)
+// to each record under the configured field name (default "log_trace_id")
+// The trace ID stays a top-level attribute under WithGroup: groups are kept here and applied to the
+// record's attributes, so the wrapped handler only ever sees the groups as attribute values
+type TraceIDHandler struct {
+	next   slog.Handler
+	groups []traceGroup // Groups opened with WithGroup, outermost first
+}
+
+// traceGroup is a group opened on a TraceIDHandler with the attributes added inside it
+type traceGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewTraceIDHandler wraps next with trace ID injection, so third-party handlers get the same correlation
+// field as logbundle's CustomHandler
+//
+// Usage:
+//
+//	logger := slog.New(handler.NewTraceIDHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(c.UserContext(), "charged") // {"msg":"charged","log_trace_id":"4bf92f35..."}
+func NewTraceIDHandler(next slog.Handler) *TraceIDHandler {
+	if h, ok := next.(*TraceIDHandler); ok {
+		return h
+	}
+	return &TraceIDHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles the level
+func (h *TraceIDHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the trace ID attribute and passes the record on
+func (h *TraceIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	traceID := core.TraceIDFromContext(ctx)
+	if len(h.groups) == 0 {
+		if traceID != "" {
+			r = r.Clone()
+			r.AddAttrs(slog.String(core.GetTraceIDFieldName(), traceID))
+		}
+		return h.next.Handle(ctx, r)
+	}
+
+	// Nest the record's attributes in the open groups, innermost first
+	inner := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		inner = append(inner, a)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		inner = []slog.Attr{{Key: g.name, Value: slog.GroupValue(append(slices.Clip(g.attrs), inner...)...)}}
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	if traceID != "" {
+		out.AddAttrs(slog.String(core.GetTraceIDFieldName(), traceID))
+	}
+	out.AddAttrs(inner...)
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs returns a TraceIDHandler wrapping next.WithAttrs, or adding attrs to the innermost open group
+func (h *TraceIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.groups) == 0 {
+		return &TraceIDHandler{next: h.next.WithAttrs(attrs)}
+	}
+	groups := slices.Clone(h.groups)
+	last := &groups[len(groups)-1]
+	last.attrs = append(slices.Clip(last.attrs), attrs...)
+	return &TraceIDHandler{next: h.next, groups: groups}
+}
+
+// WithGroup returns a TraceIDHandler with the group opened (see TraceIDHandler)
+func (h *TraceIDHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &TraceIDHandler{next: h.next, groups: append(slices.Clip(h.groups), traceGroup{name: name})}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+func TestTraceIDHandlerTopLevelUnderGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewTraceIDHandler(slog.NewJSONHandler(&buf, nil)))
+	ctx := core.WithTraceID(context.Background(), "trace-1")
+
+	logger.With("app", "api").WithGroup("req").With("method", "GET").WithGroup("db").
+		InfoContext(ctx, "query", "rows", 3)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got[core.GetTraceIDFieldName()] != "trace-1" {
+		t.Fatalf("trace ID not at the top level: %s", buf.String())
+	}
+	req, _ := got["req"].(map[string]any)
+	db, _ := req["db"].(map[string]any)
+	if got["app"] != "api" || req["method"] != "GET" || db["rows"] != float64(3) {
+		t.Fatalf("attributes not nested in their groups: %s", buf.String())
+	}
+}
+
+func TestTraceIDHandlerWithoutTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewTraceIDHandler(slog.NewJSONHandler(&buf, nil))).WithGroup("req").Info("m", "k", 1)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got[core.GetTraceIDFieldName()]; ok {
+		t.Fatalf("trace ID written without one in the context: %s", buf.String())
+	}
+	if req, _ := got["req"].(map[string]any); req["k"] != float64(1) {
+		t.Fatalf("record attribute not in its group: %s", buf.String())
+	}
+}
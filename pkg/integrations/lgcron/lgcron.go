@@ -0,0 +1,189 @@
+// Package lgcron wraps scheduled jobs (e.g. robfig/cron) with per-run trace IDs, duration logging,
+// overlapping run detection, panic recovery and Sentry check-ins. Its Job interface matches cron.Job,
+// so wrapped jobs are passed to cron directly without this package depending on a scheduler
+package lgcron
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Job is a scheduled job; cron.Job and cron.FuncJob satisfy it
+type Job interface {
+	Run()
+}
+
+// Config holds configuration for Wrap and WrapFunc
+type Config struct {
+	// Logger for run logs (if nil, uses the middleware logger)
+	Logger *slog.Logger
+	// Settings are isolated settings (e.g. from logbundle.LogBundle.Settings); nil uses the defaults
+	Settings *config.Settings
+	// MonitorSlug enables Sentry check-ins for the job's cron monitor when Sentry is enabled
+	MonitorSlug string
+	// MonitorConfig creates or updates the monitor with the first check-in of each run (optional)
+	MonitorConfig *sentry.MonitorConfig
+	// SkipOverlapping skips a run while the previous one is still running; by default it runs anyway
+	// Overlapping runs are logged at Warn and counted in cron_overlapping_runs_total{job} either way
+	SkipOverlapping bool
+}
+
+// job is a wrapped job
+type job struct {
+	name    string
+	fn      func(ctx context.Context) error
+	cfg     Config
+	running atomic.Int32
+}
+
+// Wrap instruments j; every run gets its own trace ID, and logs "Cron job finished" with its duration
+// (cron_job_duration_ms{job,status}). A panic is recovered and reported like a goroutine panic (see
+// lgreport.RecoverGoroutinePanic) instead of crashing the scheduler. Use WrapFunc to log with the run's
+// trace ID inside the job
+//
+// Usage:
+//
+//	c := cron.New()
+//	c.AddJob("@every 5m", lgcron.Wrap("sync_invoices", syncJob, lgcron.Config{MonitorSlug: "sync-invoices"}))
+//
+//	// or for every job of the scheduler
+//	c := cron.New(cron.WithChain(func(j cron.Job) cron.Job { return lgcron.Wrap("cron", j) }))
+func Wrap(name string, j Job, cfg ...Config) Job {
+	return WrapFunc(name, func(context.Context) error {
+		j.Run()
+		return nil
+	}, cfg...)
+}
+
+// WrapFunc instruments a job receiving the run context (trace ID, settings, Sentry hub) and returning
+// an error; errors are logged at Error and reported to Sentry like lgreport.HandleError
+//
+// Usage:
+//
+//	c.AddJob("0 3 * * *", lgcron.WrapFunc("cleanup", func(ctx context.Context) error {
+//	    log.InfoContext(ctx, "cleaning up") // ... log_trace_id=4bf92f35...
+//	    return store.Cleanup(ctx)
+//	}))
+func WrapFunc(name string, fn func(ctx context.Context) error, cfg ...Config) Job {
+	j := &job{name: name, fn: fn}
+	if len(cfg) > 0 {
+		j.cfg = cfg[0]
+	}
+	return j
+}
+
+// Run runs the job once
+func (j *job) Run() {
+	ctx := context.Background()
+	if j.cfg.Settings != nil {
+		ctx = config.WithSettings(ctx, j.cfg.Settings)
+	}
+	ctx = core.WithTraceID(ctx, core.NewTraceID())
+
+	if running := j.running.Add(1); running > 1 {
+		metrics.IncCounter("cron_overlapping_runs_total", metrics.Labels{"job": j.name})
+		logger.LogNoSourceCtx(ctx, j.logger(ctx), slog.LevelWarn, "Cron job overlapping run",
+			slog.String("job", j.name),
+			slog.Int("running", int(running-1)),
+			slog.Bool("skipped", j.cfg.SkipOverlapping),
+		)
+		if j.cfg.SkipOverlapping {
+			j.running.Add(-1)
+			return
+		}
+	}
+	defer j.running.Add(-1)
+
+	var hub *sentry.Hub
+	if config.FromContext(ctx).SentryEnabled() {
+		hub = sentry.CurrentHub().Clone()
+		hub.Scope().SetTag(core.GetTraceIDFieldName(), core.TraceIDFromContext(ctx))
+		hub.Scope().SetTag("cron_job", j.name)
+		ctx = sentry.SetHubOnContext(ctx, hub)
+	}
+
+	var checkInID *sentry.EventID
+	if hub != nil && j.cfg.MonitorSlug != "" {
+		checkInID = hub.CaptureCheckIn(&sentry.CheckIn{
+			MonitorSlug: j.cfg.MonitorSlug,
+			Status:      sentry.CheckInStatusInProgress,
+		}, j.cfg.MonitorConfig)
+	}
+
+	start := core.Now()
+	completed := false
+	var err error
+	// Runs after RecoverGoroutinePanic, so a panic leaves completed unset
+	defer func() {
+		j.finish(ctx, hub, checkInID, start, completed, err)
+	}()
+	defer lgreport.RecoverGoroutinePanic(ctx, "cron:"+j.name)
+
+	err = j.fn(ctx)
+	completed = true
+}
+
+// finish logs the run, records its duration and closes the check-in
+func (j *job) finish(ctx context.Context, hub *sentry.Hub, checkInID *sentry.EventID, start time.Time, completed bool, err error) {
+	duration := core.Since(start)
+
+	status := "ok"
+	switch {
+	case !completed:
+		// Already logged and reported by RecoverGoroutinePanic
+		status = "panic"
+	case err != nil:
+		status = "error"
+		lgreport.HandleError(ctx, lgerr.FromError(err))
+	}
+
+	metrics.Observe("cron_job_duration_ms", metrics.Labels{"job": j.name, "status": status},
+		float64(duration.Microseconds())/1000)
+
+	level := slog.LevelInfo
+	if status != "ok" {
+		level = slog.LevelError
+	}
+	logger.LogNoSourceCtx(ctx, j.logger(ctx), level, "Cron job finished",
+		slog.String("job", j.name),
+		slog.String("status", status),
+		fields.DurationMS(duration),
+	)
+
+	if checkInID != nil {
+		checkInStatus := sentry.CheckInStatusOK
+		if status != "ok" {
+			checkInStatus = sentry.CheckInStatusError
+		}
+		hub.CaptureCheckIn(&sentry.CheckIn{
+			ID:          *checkInID,
+			MonitorSlug: j.cfg.MonitorSlug,
+			Status:      checkInStatus,
+			Duration:    duration,
+		}, j.cfg.MonitorConfig)
+	}
+}
+
+// logger returns the configured logger, falling back to the middleware logger
+func (j *job) logger(ctx context.Context) *slog.Logger {
+	if j.cfg.Logger != nil {
+		return j.cfg.Logger
+	}
+	if log := config.FromContext(ctx).MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
@@ -0,0 +1,167 @@
+package lgdb
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/strutil"
+)
+
+// DefaultMaxSQLLen is the length SanitizeSQL caps statements to by default
+const DefaultMaxSQLLen = 500
+
+var (
+	// inList matches IN lists of placeholders
+	inList = regexp.MustCompile(`(?i)\bIN\s*\(\s*(?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))*\s*\)`)
+	// valueRows matches a placeholder tuple followed by more tuples (multi-row VALUES)
+	valueRows = regexp.MustCompile(`(\((?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))*\))(?:\s*,\s*\((?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))*\))+`)
+)
+
+// SanitizeSQL normalizes a statement for logs and Sentry: string, dollar-quoted and numeric literals
+// become ?, comments are removed, whitespace is collapsed, IN lists become "IN (...)" and multi-row
+// VALUES keep their first row. Placeholders ($1, ?, :name) and quoted identifiers are kept, so statements
+// differing only in data group together and no customer data is logged. The result is capped to maxLen
+// (default: DefaultMaxSQLLen)
+//
+// Strings follow standard SQL: a backslash is an ordinary character except in Postgres E'...' strings.
+// Tx logs its statements through SanitizeSQL; logbundle has no GORM or pgx dependency, so their loggers
+// (gorm logger.Interface, pgx tracelog) call it from their own hooks
+//
+// Usage:
+//
+//	log.Debug("query", slog.String("statement", lgdb.SanitizeSQL(query)))
+//	// SELECT * FROM users WHERE email = 'a@b.c' AND id IN (1, 2, 3)
+//	// -> SELECT * FROM users WHERE email = ? AND id IN (...)
+func SanitizeSQL(query string, maxLen ...int) string {
+	limit := DefaultMaxSQLLen
+	if len(maxLen) > 0 && maxLen[0] > 0 {
+		limit = maxLen[0]
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false
+	emit := func(s string) {
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			space = true
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+			space = true
+		case c == '\'':
+			i = skipQuoted(query, i, '\'', false)
+			emit("?")
+		case (c == 'E' || c == 'e') && i+1 < len(query) && query[i+1] == '\'':
+			// Escape string constant, where backslashes escape the next character
+			i = skipQuoted(query, i+1, '\'', true)
+			emit("?")
+		case c == '"' || c == '`':
+			end := skipQuoted(query, i, c, false)
+			emit(query[i:end])
+			i = end
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			emit(query[i:end])
+			i = end
+		case c == '$':
+			// Dollar-quoted string: $tag$ ... $tag$
+			tagEnd := strings.IndexByte(query[i+1:], '$')
+			if tagEnd < 0 || !isTag(query[i+1:i+1+tagEnd]) {
+				emit("$")
+				i++
+				continue
+			}
+			tag := query[i : i+tagEnd+2]
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+			emit("?")
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			end := i
+			for end < len(query) && (isIdent(query[end]) || query[end] == '.' ||
+				((query[end] == '+' || query[end] == '-') && (query[end-1] == 'e' || query[end-1] == 'E'))) {
+				end++
+			}
+			emit("?")
+			i = end
+		case isIdent(c):
+			end := i
+			for end < len(query) && isIdent(query[end]) {
+				end++
+			}
+			emit(query[i:end])
+			i = end
+		default:
+			emit(query[i : i+1])
+			i++
+		}
+	}
+
+	out := inList.ReplaceAllString(b.String(), "IN (...)")
+	out = valueRows.ReplaceAllString(out, "$1")
+	return strutil.Truncate(out, limit)
+}
+
+// skipQuoted returns the index after the quoted section starting at i; doubled quotes, and backslash
+// escapes when backslash is set, stay inside it
+func skipQuoted(s string, i int, quote byte, backslash bool) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if backslash {
+				j++
+			}
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'z') || c >= 0x80
+}
+
+// isTag reports whether s is a valid dollar-quote tag (possibly empty)
+func isTag(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdent(s[i]) || (i == 0 && isDigit(s[i])) {
+			return false
+		}
+	}
+	return true
+}
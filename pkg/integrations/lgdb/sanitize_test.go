@@ -0,0 +1,40 @@
+package lgdb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"string and IN list", "SELECT * FROM users WHERE email = 'a@b.c' AND id IN (1, 2, 3)", "SELECT * FROM users WHERE email = ? AND id IN (...)"},
+		{"trailing backslash in standard string", `INSERT INTO t VALUES ('C:\', 'secret-123-45-6789')`, "INSERT INTO t VALUES (?, ?)"},
+		{"doubled quote", "SELECT 'it''s', 'x'", "SELECT ?, ?"},
+		{"escape string", `SELECT E'it\'s', 'x'`, "SELECT ?, ?"},
+		{"lowercase escape string", `SELECT e'a\\', 'b'`, "SELECT ?, ?"},
+		{"identifier ending in e", "SELECT name FROM t WHERE type='x'", "SELECT name FROM t WHERE type=?"},
+		{"quoted identifier", `SELECT "Name" FROM t`, `SELECT "Name" FROM t`},
+		{"dollar quoted", "SELECT $tag$ secret $tag$, $1", "SELECT ?, $1"},
+		{"numbers", "SELECT 1.5e-3, 42", "SELECT ?, ?"},
+		{"comments", "SELECT 1 -- note\n/* block */ FROM t", "SELECT ? FROM t"},
+		{"multi-row values", "INSERT INTO t VALUES ($1, $2), ($3, $4)", "INSERT INTO t VALUES ($1, $2)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeSQL(tt.query); got != tt.want {
+				t.Fatalf("SanitizeSQL(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeSQLMaxLen(t *testing.T) {
+	got := SanitizeSQL("SELECT "+strings.Repeat("col, ", 100)+"x FROM t", 20)
+	if want := "SELECT col, col, col"; got != want {
+		t.Fatalf("SanitizeSQL capped to 20 = %q, want %q", got, want)
+	}
+}
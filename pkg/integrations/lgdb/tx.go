@@ -0,0 +1,235 @@
+// Package lgdb instruments database/sql transactions: rollback causes are logged as lgerr.Database
+// errors carrying the failing statement, durations are measured and each transaction gets a Sentry span
+package lgdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Options holds configuration for WithTx
+type Options struct {
+	// Name labels the transaction in logs, metrics and its span (default: "tx")
+	Name string
+	// TxOptions are passed to BeginTx (isolation level, read-only)
+	TxOptions *sql.TxOptions
+	// Logger for rollback and commit failures (if nil, uses the middleware logger)
+	Logger *slog.Logger
+}
+
+// Tx is the transaction passed to WithTx's function; it records the last statement so a rollback
+// cause can be attributed to it. Statements are logged sanitized (see SanitizeSQL) and their arguments
+// are never recorded
+type Tx struct {
+	*sql.Tx
+	mu        sync.Mutex
+	statement string
+}
+
+// WithTx runs fn in a transaction of db, committing when fn returns nil and rolling back when it returns
+// an error or panics (the panic is re-raised after the rollback). Errors returned by fn that are not
+// lgerr errors are translated with lgerr.FromError; untyped ones become lgerr.Database errors. The
+// rollback is logged at Warn with the error, the last statement and the duration; failures to begin or
+// commit are returned as lgerr.Database errors. Durations are exported as db_tx_duration_ms{tx,result}
+// With Sentry enabled and a span in ctx, the transaction runs in a "db.sql.transaction" child span
+// whose context fn receives
+//
+// Usage:
+//
+//	err := lgdb.WithTx(ctx, db, func(ctx context.Context, tx *lgdb.Tx) error {
+//	    if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from); err != nil {
+//	        return err
+//	    }
+//	    _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", amount, to)
+//	    return err
+//	}, lgdb.Options{Name: "transfer"})
+func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *Tx) error, opts ...Options) error {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Name == "" {
+		o.Name = "tx"
+	}
+
+	span := startTxSpan(ctx, o.Name)
+	if span != nil {
+		ctx = span.Context()
+	}
+	start := core.Now()
+	result := "commit"
+	defer func() {
+		metrics.Observe("db_tx_duration_ms", metrics.Labels{"tx": o.Name, "result": result},
+			float64(core.Since(start).Microseconds())/1000)
+		if span != nil {
+			span.Status = sentry.SpanStatusOK
+			if result != "commit" {
+				span.Status = sentry.SpanStatusInternalError
+			}
+			span.SetData("result", result)
+			span.Finish()
+		}
+	}()
+
+	sqlTx, err := db.BeginTx(ctx, o.TxOptions)
+	if err != nil {
+		result = "error"
+		return lgerr.Database("begin transaction failed", lgerr.WithContext("tx", o.Name)).Wrap(err)
+	}
+	tx := &Tx{Tx: sqlTx}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result = "rollback"
+			_ = sqlTx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(ctx, tx); err != nil {
+		result = "rollback"
+		return tx.rollback(ctx, o, err, core.Since(start))
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		result = "error"
+		lgErr := lgerr.Database("commit failed",
+			lgerr.WithContext("tx", o.Name),
+			lgerr.WithContext("statement", tx.lastStatement()),
+		).Wrap(err)
+		logger.LogNoSourceCtx(ctx, txLogger(ctx, o.Logger), slog.LevelError, "Transaction commit failed",
+			slog.String("tx", o.Name),
+			fields.Err(err),
+			fields.DurationMS(core.Since(start)),
+		)
+		return lgErr
+	}
+	return nil
+}
+
+// rollback rolls the transaction back after fn failed with cause, logs it and returns the typed cause
+func (tx *Tx) rollback(ctx context.Context, o Options, cause error, duration time.Duration) error {
+	statement := tx.lastStatement()
+	rollbackErr := tx.Tx.Rollback()
+
+	lgErr, typed := lgerr.AsError(cause)
+	if !typed {
+		lgErr = lgerr.FromError(cause)
+		if lgErr.Type() == lgerr.TypeInternal {
+			lgErr = lgerr.Database("transaction rolled back").Wrap(cause)
+		}
+		lgErr.WithContext("tx", o.Name)
+		if statement != "" {
+			lgErr.WithContext("statement", statement)
+		}
+	}
+
+	attrs := []any{
+		slog.String("tx", o.Name),
+		fields.ErrorType(string(lgErr.Type())),
+		fields.Err(cause),
+		fields.DurationMS(duration),
+	}
+	if statement != "" {
+		attrs = append(attrs, slog.String("statement", statement))
+	}
+	if rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+		attrs = append(attrs, slog.String("rollback_error", rollbackErr.Error()))
+	}
+	logger.LogNoSourceCtx(ctx, txLogger(ctx, o.Logger), slog.LevelWarn, "Transaction rolled back", attrs...)
+
+	if typed {
+		return cause
+	}
+	return lgErr
+}
+
+// ExecContext executes a statement in the transaction, recording it
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	tx.record(query)
+	return tx.Tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a query in the transaction, recording it
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	tx.record(query)
+	return tx.Tx.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a single-row query in the transaction, recording it
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	tx.record(query)
+	return tx.Tx.QueryRowContext(ctx, query, args...)
+}
+
+// Exec executes a statement in the transaction, recording it
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+// Query runs a query in the transaction, recording it
+func (tx *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow runs a single-row query in the transaction, recording it
+func (tx *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+// record remembers the last statement
+func (tx *Tx) record(query string) {
+	tx.mu.Lock()
+	tx.statement = query
+	tx.mu.Unlock()
+}
+
+// lastStatement returns the last statement, sanitized (see SanitizeSQL)
+func (tx *Tx) lastStatement() string {
+	tx.mu.Lock()
+	statement := tx.statement
+	tx.mu.Unlock()
+	if statement == "" {
+		return ""
+	}
+	return SanitizeSQL(statement)
+}
+
+// startTxSpan starts a child of the span in ctx, or returns nil without Sentry or a parent span
+func startTxSpan(ctx context.Context, name string) *sentry.Span {
+	if !config.FromContext(ctx).SentryEnabled() {
+		return nil
+	}
+	parent := sentry.SpanFromContext(ctx)
+	if parent == nil {
+		return nil
+	}
+	span := parent.StartChild("db.sql.transaction")
+	span.Description = name
+	return span
+}
+
+// txLogger returns the configured logger, falling back to the middleware logger
+func txLogger(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if log != nil {
+		return log
+	}
+	if log := config.FromContext(ctx).MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
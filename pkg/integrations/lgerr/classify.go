@@ -0,0 +1,265 @@
+package lgerr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// ClassifierFunc returns the type of errors it recognizes (ok is false for other errors)
+type ClassifierFunc func(err error) (errType ErrorType, ok bool)
+
+// namedClassifier is a registered classifier; the name becomes the first fingerprint part
+type namedClassifier struct {
+	name string
+	fn   ClassifierFunc
+}
+
+var (
+	classifiers     []namedClassifier
+	classifierMutex sync.RWMutex
+)
+
+// builtinClassifiers run after the registered classifiers
+var builtinClassifiers = []namedClassifier{
+	{"net", classifyNet},
+	{"sql", classifySQL},
+	{"grpc", classifyGRPC},
+	{"aws", classifyAWS},
+}
+
+// RegisterClassifier adds a classifier consulted by FromError for errors it does not translate itself,
+// before the built-in net, sql, gRPC and AWS SDK classifiers and the internal fallback
+// Classifiers run in registration order; registering an existing name replaces it
+//
+// Usage:
+//
+//	lgerr.RegisterClassifier("payments", func(err error) (lgerr.ErrorType, bool) {
+//	    if errors.Is(err, payments.ErrCardDeclined) {
+//	        return lgerr.TypeBadInput, true
+//	    }
+//	    return "", false
+//	})
+func RegisterClassifier(name string, fn ClassifierFunc) {
+	classifierMutex.Lock()
+	defer classifierMutex.Unlock()
+
+	for i, c := range classifiers {
+		if c.name == name {
+			classifiers[i].fn = fn
+			return
+		}
+	}
+	classifiers = append(classifiers, namedClassifier{name: name, fn: fn})
+}
+
+// ResetClassifiers removes all registered classifiers (built-in classifiers stay active)
+func ResetClassifiers() {
+	classifierMutex.Lock()
+	defer classifierMutex.Unlock()
+
+	classifiers = nil
+}
+
+// Classify returns the error type assigned by the registered and built-in classifiers
+func Classify(err error) (ErrorType, bool) {
+	errType, _, ok := classify(err)
+	return errType, ok
+}
+
+// classify runs the classifiers in order and returns the first match with the classifier name
+func classify(err error) (ErrorType, string, bool) {
+	classifierMutex.RLock()
+	registered := slices.Clone(classifiers)
+	classifierMutex.RUnlock()
+
+	for _, c := range append(registered, builtinClassifiers...) {
+		if errType, ok := c.fn(err); ok {
+			return errType, c.name, true
+		}
+	}
+	return "", "", false
+}
+
+// fromClassified builds the error for a classifier match
+func fromClassified(err error, errType ErrorType, classifier string) *Error {
+	e := New(err.Error())
+	e.errorType = errType
+	e.title = typeTitle(errType)
+	e.fingerprint = []string{classifier, string(errType)}
+	return e
+}
+
+// typeTitle returns the title used by the factory of errType, or the HTTP status text for custom types
+func typeTitle(errType ErrorType) string {
+	switch errType {
+	case TypeNotFound:
+		return "Resource Not Found"
+	case TypeValidation:
+		return "Validation Error"
+	case TypeDatabase:
+		return "Database Error"
+	case TypeBusy:
+		return "Service Unavailable"
+	case TypeForbidden:
+		return "Access Forbidden"
+	case TypeBadInput:
+		return "Bad Request"
+	case TypeUnauth:
+		return "Unauthorized"
+	case TypeConflict:
+		return "Resource Conflict"
+	case TypeExternal:
+		return "External Service Error"
+	case TypeTimeout:
+		return "Request Timeout"
+	}
+
+	if title := http.StatusText(getHTTPStatus(errType)); title != "" {
+		return title
+	}
+	return "Internal Server Error"
+}
+
+// classifyNet recognizes timeouts, DNS failures and refused or reset connections to other services
+func classifyNet(err error) (ErrorType, bool) {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return TypeTimeout, true
+	}
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	if errors.As(err, &dnsErr) || errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return TypeExternal, true
+	}
+	return "", false
+}
+
+// classifySQL recognizes closed connections and transactions and broken driver connections
+func classifySQL(err error) (ErrorType, bool) {
+	if errors.Is(err, sql.ErrConnDone) || errors.Is(err, sql.ErrTxDone) || errors.Is(err, driver.ErrBadConn) {
+		return TypeDatabase, true
+	}
+	return "", false
+}
+
+// grpcCodeTypes maps gRPC status codes (google.golang.org/grpc/codes) to error types
+var grpcCodeTypes = map[uint64]ErrorType{
+	2:  TypeExternal,  // Unknown
+	3:  TypeBadInput,  // InvalidArgument
+	4:  TypeTimeout,   // DeadlineExceeded
+	5:  TypeNotFound,  // NotFound
+	6:  TypeConflict,  // AlreadyExists
+	7:  TypeForbidden, // PermissionDenied
+	8:  TypeBusy,      // ResourceExhausted
+	9:  TypeBadInput,  // FailedPrecondition
+	10: TypeConflict,  // Aborted
+	11: TypeBadInput,  // OutOfRange
+	12: TypeExternal,  // Unimplemented
+	13: TypeExternal,  // Internal
+	14: TypeBusy,      // Unavailable
+	15: TypeExternal,  // DataLoss
+	16: TypeUnauth,    // Unauthenticated
+}
+
+// classifyGRPC recognizes gRPC status errors (anything with GRPCStatus().Code()) without importing grpc
+func classifyGRPC(err error) (ErrorType, bool) {
+	code, ok := grpcCode(err)
+	if !ok {
+		return "", false
+	}
+	errType, ok := grpcCodeTypes[code]
+	return errType, ok
+}
+
+// grpcCode walks the error tree for a GRPCStatus method and returns the status code
+func grpcCode(err error) (uint64, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	if m := reflect.ValueOf(err).MethodByName("GRPCStatus"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		if status := m.Call(nil)[0]; status.IsValid() && !(status.Kind() == reflect.Pointer && status.IsNil()) {
+			if codeFn := status.MethodByName("Code"); codeFn.IsValid() && codeFn.Type().NumIn() == 0 && codeFn.Type().NumOut() == 1 {
+				if code := codeFn.Call(nil)[0]; code.CanUint() {
+					return code.Uint(), true
+				}
+			}
+		}
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return grpcCode(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if code, ok := grpcCode(e); ok {
+				return code, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// awsCodeTypes maps common AWS API error codes to error types
+var awsCodeTypes = map[string]ErrorType{
+	"NoSuchKey":                              TypeNotFound,
+	"NoSuchBucket":                           TypeNotFound,
+	"NotFound":                               TypeNotFound,
+	"ResourceNotFoundException":              TypeNotFound,
+	"AccessDenied":                           TypeForbidden,
+	"AccessDeniedException":                  TypeForbidden,
+	"UnauthorizedOperation":                  TypeForbidden,
+	"ValidationException":                    TypeBadInput,
+	"InvalidParameterValue":                  TypeBadInput,
+	"InvalidParameterException":              TypeBadInput,
+	"ConditionalCheckFailedException":        TypeConflict,
+	"ConflictException":                      TypeConflict,
+	"ResourceInUseException":                 TypeConflict,
+	"Throttling":                             TypeBusy,
+	"ThrottlingException":                    TypeBusy,
+	"TooManyRequestsException":               TypeBusy,
+	"RequestLimitExceeded":                   TypeBusy,
+	"ProvisionedThroughputExceededException": TypeBusy,
+	"SlowDown":                               TypeBusy,
+	"ServiceUnavailable":                     TypeBusy,
+	"RequestTimeout":                         TypeTimeout,
+	"RequestTimeoutException":                TypeTimeout,
+}
+
+// classifyAWS recognizes AWS SDK API errors (v2 smithy.APIError and v1 awserr.Error) by error code;
+// unknown codes are external errors
+func classifyAWS(err error) (ErrorType, bool) {
+	var code string
+
+	var v2 interface {
+		ErrorCode() string
+		ErrorMessage() string
+	}
+	var v1 interface {
+		Code() string
+		Message() string
+		OrigErr() error
+	}
+	switch {
+	case errors.As(err, &v2):
+		code = v2.ErrorCode()
+	case errors.As(err, &v1):
+		code = v1.Code()
+	default:
+		return "", false
+	}
+
+	if errType, ok := awsCodeTypes[code]; ok {
+		return errType, true
+	}
+	return TypeExternal, true
+}
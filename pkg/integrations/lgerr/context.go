@@ -0,0 +1,121 @@
+package lgerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Get returns the context value of key
+func (e *Error) Get(key string) (any, bool) {
+	v, ok := e.context[key]
+	return v, ok
+}
+
+// GetString returns the context value of key as a string; strings, fmt.Stringers and byte slices
+// are accepted, other types report false
+//
+// Usage:
+//
+//	if orderID, ok := lgErr.GetString("order_id"); ok {
+//	    metrics.IncCounter("order_failures_total", metrics.Labels{"order": orderID})
+//	}
+func (e *Error) GetString(key string) (string, bool) {
+	switch v := e.context[key].(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// GetInt returns the context value of key as an int; any integer type, integral floats (e.g. values
+// decoded from JSON), json.Number and numeric strings are accepted
+func (e *Error) GetInt(key string) (int, bool) {
+	switch v := e.context[key].(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case uint64:
+		if v > math.MaxInt {
+			return 0, false
+		}
+		return int(v), true
+	case float32:
+		return intFromFloat(float64(v))
+	case float64:
+		return intFromFloat(v)
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// GetBool returns the context value of key as a bool; bools and strconv.ParseBool strings are accepted
+func (e *Error) GetBool(key string) (bool, bool) {
+	switch v := e.context[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
+// intFromFloat converts integral floats in the int range
+func intFromFloat(f float64) (int, bool) {
+	if f != math.Trunc(f) || f < math.MinInt || f > math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// ContextAttr renders the error context as an "error_context" group with sorted keys, so text logs
+// show error_context.order_id=42 instead of a Go map literal. Empty contexts render as an empty attr,
+// which slog omits
+func (e *Error) ContextAttr() slog.Attr {
+	if len(e.context) == 0 {
+		return slog.Attr{}
+	}
+
+	keys := make([]string, 0, len(e.context))
+	for k := range e.context {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.Any(k, e.context[k])
+	}
+	return slog.Group("error_context", attrs...)
+}
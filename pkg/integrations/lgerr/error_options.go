@@ -64,6 +64,12 @@ func WithIgnoreSentry() ErrorOption {
 	}
 }
 
+func WithFingerprint(parts ...string) ErrorOption {
+	return func(e *Error) {
+		e.fingerprint = parts
+	}
+}
+
 func WithValidationErr(field, message string, value ...any) ErrorOption {
 	return func(e *Error) {
 		if e.validationErrors == nil {
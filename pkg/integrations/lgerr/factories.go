@@ -1,6 +1,9 @@
 package lgerr
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 func NotFound(resource string, id any) *Error {
 	return NewWithOptions(
@@ -150,3 +153,21 @@ func Busy(message string, opts ...ErrorOption) *Error {
 	}
 	return err
 }
+
+func Aggregate(operation string, total int, errs []error, opts ...ErrorOption) *Error {
+	err := New(fmt.Sprintf("%s: %d of %d items failed", operation, len(errs), total))
+	err.errorType = TypeInternal
+	err.title = "Partial Failure"
+	err.detail = fmt.Sprintf("%d of %d items could not be processed", len(errs), total)
+	err.context = map[string]any{
+		"operation": operation,
+		"total":     total,
+		"failed":    len(errs),
+	}
+	err.wrapped = errors.Join(errs...)
+
+	for _, opt := range opts {
+		opt(err)
+	}
+	return err
+}
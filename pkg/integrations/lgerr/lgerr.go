@@ -53,6 +53,7 @@ type Error struct {
 	wrapped          error
 	ignoreSentry     bool
 	validationErrors []ValidationError
+	fingerprint      []string
 }
 
 var (
@@ -130,9 +131,14 @@ func getHTTPStatus(errType ErrorType) int {
 }
 
 func New(message string) *Error {
+	return newError(message, 3)
+}
+
+// newError creates an error whose stack trace starts skip frames up (as runtime.Callers counts them)
+func newError(message string, skip int) *Error {
 	const maxStackDepth = 32
 	var pcs [maxStackDepth]uintptr
-	n := runtime.Callers(2, pcs[:])
+	n := runtime.Callers(skip, pcs[:])
 
 	file := "unknown"
 	line := 0
@@ -200,6 +206,12 @@ func (e *Error) WithDetail(detail string) *Error {
 	return e
 }
 
+// WithFingerprint sets the Sentry grouping key; errors with the same fingerprint are grouped regardless of message
+func (e *Error) WithFingerprint(parts ...string) *Error {
+	e.fingerprint = parts
+	return e
+}
+
 func (e *Error) WithValidationError(field string, message string, value ...any) *Error {
 	if e.validationErrors == nil {
 		e.validationErrors = make([]ValidationError, 0, 4) // Pre-allocate for typical validation error count
@@ -270,6 +282,10 @@ func (e *Error) Detail() string {
 	return e.detail
 }
 
+func (e *Error) Fingerprint() []string {
+	return e.fingerprint
+}
+
 func (e *Error) ValidationErrors() []ValidationError {
 	return e.validationErrors
 }
@@ -0,0 +1,62 @@
+package lgerr
+
+import "errors"
+
+// AsError returns the first *Error in err's wrap chain
+//
+// Usage:
+//
+//	if lgErr, ok := lgerr.AsError(err); ok && lgErr.Type() == lgerr.TypeConflict {
+//	    return retryWithFreshVersion(ctx)
+//	}
+func AsError(err error) (*Error, bool) {
+	var lgErr *Error
+	ok := errors.As(err, &lgErr)
+	return lgErr, ok
+}
+
+// AsType returns the first error of type T in err's wrap chain, without declaring a target variable
+//
+// Usage:
+//
+//	if pgErr, ok := lgerr.AsType[*pgconn.PgError](err); ok {
+//	    log.Warn("constraint violated", "constraint", pgErr.ConstraintName)
+//	}
+func AsType[T error](err error) (T, bool) {
+	var target T
+	ok := errors.As(err, &target)
+	return target, ok
+}
+
+// IsType reports whether err's wrap chain contains an *Error of type errType
+//
+// Usage:
+//
+//	if lgerr.IsType(err, lgerr.TypeNotFound) {
+//	    return createDefault(ctx)
+//	}
+func IsType(err error, errType ErrorType) bool {
+	lgErr, ok := AsError(err)
+	return ok && lgErr.Type() == errType
+}
+
+// TypeOf returns the error type ErrorHandler would use for err (see FromError), or "" for nil
+func TypeOf(err error) ErrorType {
+	if err == nil {
+		return ""
+	}
+	return FromError(err).Type()
+}
+
+// CodeOf returns the HTTP status ErrorHandler would respond with for err (see FromError), or 0 for nil
+func CodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	return FromError(err).HTTPStatus()
+}
+
+// HasCode reports whether err maps to the HTTP status code (see CodeOf)
+func HasCode(err error, code int) bool {
+	return err != nil && CodeOf(err) == code
+}
@@ -0,0 +1,236 @@
+package lgerr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// fiberErrorPkg is the package of *fiber.Error, recognized without linking Fiber (see fiberError)
+const fiberErrorPkg = "github.com/gofiber/fiber/v2"
+
+// sqlStateError is implemented by Postgres driver errors (pgconn.PgError, pq.Error) and other
+// drivers reporting SQLSTATE codes
+type sqlStateError interface {
+	SQLState() string
+}
+
+// FromError converts well-known errors into typed errors with a stable title and Sentry fingerprint,
+// so handlers can return driver and framework errors directly:
+//   - *Error: returned as is
+//   - sql.ErrNoRows: not_found
+//   - SQLSTATE errors (pgconn, pq): conflict, bad_input, busy, timeout or database by code class
+//   - context.DeadlineExceeded: timeout
+//   - *fiber.Error: typed by HTTP status, keeping the status
+//   - validator.ValidationErrors: validation with one entry per field
+//
+// Other errors are typed by the classifiers (see RegisterClassifier) or become internal errors carrying
+// err's message; io.EOF from a database or network read is a server failure, see FromBodyError for
+// request bodies. The original error is always wrapped
+//
+// Usage:
+//
+//	user, err := repo.Find(ctx, id)
+//	if err != nil {
+//	    return lgerr.FromError(err).WithContext("user_id", id)
+//	}
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var lgErr *Error
+	if errors.As(err, &lgErr) {
+		return lgErr
+	}
+
+	var translated *Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		translated = NewWithOptions(
+			WithMessage("record not found"),
+			WithType(TypeNotFound),
+			WithTitle("Resource Not Found"),
+			WithDetail("The requested record does not exist"),
+			WithFingerprint("sql", "no_rows"),
+		)
+	case errors.Is(err, context.DeadlineExceeded):
+		translated = fromTimeout("context", "deadline_exceeded")
+	default:
+		translated = fromTypedError(err)
+	}
+
+	return translated.Wrap(err)
+}
+
+// FromBodyError is FromError for errors returned while reading or decoding the request body, where
+// io.EOF and io.ErrUnexpectedEOF mean an empty or truncated body and become bad_input errors
+//
+// Usage:
+//
+//	var req CreateOrderRequest
+//	if err := c.BodyParser(&req); err != nil {
+//	    return lgerr.FromBodyError(err)
+//	}
+func FromBodyError(err error) *Error {
+	var lgErr *Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &lgErr):
+		return lgErr
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return BadInput("truncated request body", WithFingerprint("io", "unexpected_eof")).Wrap(err)
+	case errors.Is(err, io.EOF):
+		return BadInput("empty request body", WithFingerprint("io", "eof")).Wrap(err)
+	}
+	return FromError(err)
+}
+
+// fromTypedError handles errors recognized by type or interface
+func fromTypedError(err error) *Error {
+	if code, message, ok := fiberError(err); ok {
+		return fromFiberError(code, message)
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return fromValidationErrors(validationErrs)
+	}
+
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) && stateErr.SQLState() != "" {
+		return fromSQLState(stateErr.SQLState())
+	}
+
+	if errType, classifier, ok := classify(err); ok {
+		return fromClassified(err, errType, classifier)
+	}
+
+	return Internal(err.Error())
+}
+
+// fromTimeout returns a timeout error with the given fingerprint
+func fromTimeout(fingerprint ...string) *Error {
+	err := New("operation timed out")
+	err.errorType = TypeTimeout
+	err.title = "Request Timeout"
+	err.detail = "The operation did not complete in time"
+	err.fingerprint = fingerprint
+	return err
+}
+
+// fiberError walks the error tree for a *fiber.Error and returns its status and message; the type is
+// matched by reflection so that code without Fiber (e.g. lgfasthttp) does not link it
+func fiberError(err error) (int, string, bool) {
+	if err == nil {
+		return 0, "", false
+	}
+
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Pointer && !v.IsNil() {
+		if t := v.Type().Elem(); t.Kind() == reflect.Struct && t.Name() == "Error" && t.PkgPath() == fiberErrorPkg {
+			code, message := v.Elem().FieldByName("Code"), v.Elem().FieldByName("Message")
+			if code.CanInt() && message.Kind() == reflect.String {
+				return int(code.Int()), message.String(), true
+			}
+		}
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return fiberError(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if code, message, ok := fiberError(e); ok {
+				return code, message, true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// fromFiberError types a fiber error by its HTTP status
+func fromFiberError(code int, message string) *Error {
+	errType := TypeInternal
+	switch {
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		errType = TypeBadInput
+	case code == http.StatusUnauthorized:
+		errType = TypeUnauth
+	case code == http.StatusForbidden:
+		errType = TypeForbidden
+	case code == http.StatusNotFound:
+		errType = TypeNotFound
+	case code == http.StatusConflict:
+		errType = TypeConflict
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		errType = TypeTimeout
+	case code == http.StatusServiceUnavailable || code == http.StatusTooManyRequests:
+		errType = TypeBusy
+	case code >= 400 && code < 500:
+		errType = TypeBadInput
+	}
+
+	title := http.StatusText(code)
+	if title == "" {
+		title = "Internal Server Error"
+	}
+
+	err := New(message)
+	err.errorType = errType
+	err.title = title
+	err.fingerprint = []string{"fiber", strconv.Itoa(code)}
+	return err.WithHTTPStatus(code)
+}
+
+// fromValidationErrors lists each failed field; values are omitted since they may be sensitive
+func fromValidationErrors(validationErrs validator.ValidationErrors) *Error {
+	fields := make([]ValidationError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		message := fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+		if fe.Param() != "" {
+			message = fmt.Sprintf("failed on the '%s=%s' rule", fe.Tag(), fe.Param())
+		}
+		fields = append(fields, ValidationError{Field: fe.Field(), Message: message})
+	}
+
+	return Validation("request validation failed",
+		WithDetail("One or more fields are invalid"),
+		WithValidationErrs(fields),
+		WithFingerprint("validation"),
+	)
+}
+
+// fromSQLState types a SQLSTATE error by code (see https://www.postgresql.org/docs/current/errcodes-appendix.html)
+func fromSQLState(code string) *Error {
+	var err *Error
+	switch {
+	case code == "23505":
+		err = Conflict("record", "a record with the same unique key already exists")
+	case code == "23503":
+		err = Conflict("record", "the record is referenced by or references a missing record")
+	case code == "23502", code == "23514", code == "23P01", strings.HasPrefix(code, "22"):
+		err = BadInput("the data violates a database constraint", WithTitle("Invalid Data"))
+	case code == "40001", code == "40P01":
+		err = Busy("transaction conflict, retry the request")
+	case code == "57014":
+		err = fromTimeout()
+		err.message = "database query canceled"
+	case strings.HasPrefix(code, "08"), strings.HasPrefix(code, "53"), code == "57P01", code == "57P03":
+		err = Busy("database unavailable")
+	default:
+		err = Database("database error")
+	}
+
+	// The code groups Sentry events but is not part of the context, which responses echo in "meta"
+	err.fingerprint = []string{"sql_state", code}
+	return err
+}
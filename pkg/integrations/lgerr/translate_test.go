@@ -0,0 +1,88 @@
+package lgerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type pgError struct{ code string }
+
+func (e *pgError) Error() string    { return "pg error " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestFromErrorKeepsUpstreamEOFInternal(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("redis: read reply: %w", io.EOF),
+		fmt.Errorf("upstream: %w", io.ErrUnexpectedEOF),
+	} {
+		if got := FromError(err); got.Type() != TypeInternal {
+			t.Errorf("FromError(%v) type = %s, want %s", err, got.Type(), TypeInternal)
+		}
+	}
+}
+
+func TestFromBodyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType ErrorType
+	}{
+		{"empty body", io.EOF, TypeBadInput},
+		{"truncated body", fmt.Errorf("decode: %w", io.ErrUnexpectedEOF), TypeBadInput},
+		{"other error", fmt.Errorf("boom"), TypeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromBodyError(tt.err); got.Type() != tt.wantType {
+				t.Fatalf("type = %s, want %s", got.Type(), tt.wantType)
+			}
+		})
+	}
+	if FromBodyError(nil) != nil {
+		t.Fatal("FromBodyError(nil) != nil")
+	}
+}
+
+func TestFromErrorSQLStateNotInContext(t *testing.T) {
+	got := FromError(&pgError{code: "23505"})
+	if got.Type() != TypeConflict {
+		t.Fatalf("type = %s, want %s", got.Type(), TypeConflict)
+	}
+	if _, ok := got.Context()["sql_state"]; ok {
+		t.Fatal("sql_state is in the context echoed to clients")
+	}
+	if fp := got.Fingerprint(); len(fp) != 2 || fp[1] != "23505" {
+		t.Fatalf("fingerprint = %v, want [sql_state 23505]", fp)
+	}
+}
+
+func TestFromErrorFiberError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantType   ErrorType
+		wantStatus int
+		wantTitle  string
+	}{
+		{"not found", fiber.ErrNotFound, TypeNotFound, fiber.StatusNotFound, "Not Found"},
+		{"wrapped", fmt.Errorf("route: %w", fiber.ErrMethodNotAllowed), TypeBadInput, fiber.StatusMethodNotAllowed, "Method Not Allowed"},
+		{"joined", errors.Join(errors.New("other"), fiber.NewError(fiber.StatusTooManyRequests, "slow down")), TypeBusy, fiber.StatusTooManyRequests, "Too Many Requests"},
+		{"server error", fiber.NewError(fiber.StatusBadGateway, "upstream"), TypeInternal, fiber.StatusBadGateway, "Bad Gateway"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromError(tt.err)
+			if got.Type() != tt.wantType || got.HTTPStatus() != tt.wantStatus || got.Title() != tt.wantTitle {
+				t.Fatalf("FromError = (%s, %d, %q), want (%s, %d, %q)",
+					got.Type(), got.HTTPStatus(), got.Title(), tt.wantType, tt.wantStatus, tt.wantTitle)
+			}
+			if fp := got.Fingerprint(); len(fp) != 2 || fp[0] != "fiber" {
+				t.Fatalf("fingerprint = %v, want [fiber <status>]", fp)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package lgerr
+
+import (
+	"runtime"
+)
+
+// StackTracer is implemented by errors carrying the program counters captured where they were created,
+// such as *Error; the wrap helpers keep the stack of the innermost StackTracer in a chain
+type StackTracer interface {
+	StackTrace() []uintptr
+}
+
+// WrapWithType wraps err in an error of errType with the factory title of that type. The stack trace and
+// location are taken from the innermost error in err's chain that captured one (see StackTracer), so
+// Sentry and the error logs point to where the failure originated rather than to the wrap site; without
+// such an error they are captured here. A nil err gives an unwrapped error
+//
+// Usage:
+//
+//	if err := repo.Save(ctx, order); err != nil {
+//	    return lgerr.WrapWithType(err, lgerr.TypeDatabase, "save order failed", lgerr.WithContext("order_id", order.ID))
+//	}
+func WrapWithType(err error, errType ErrorType, message string, opts ...ErrorOption) *Error {
+	return wrapWithType(err, errType, message, opts)
+}
+
+// WrapDatabase wraps err in a database error, keeping the original stack (see WrapWithType)
+func WrapDatabase(err error, message string, opts ...ErrorOption) *Error {
+	return wrapWithType(err, TypeDatabase, message, opts)
+}
+
+// WrapInternal wraps err in an internal error, keeping the original stack (see WrapWithType)
+func WrapInternal(err error, message string, opts ...ErrorOption) *Error {
+	return wrapWithType(err, TypeInternal, message, opts)
+}
+
+// WrapExternal wraps err in an external service error for service, keeping the original stack
+// (see WrapWithType)
+func WrapExternal(err error, service string, message string, opts ...ErrorOption) *Error {
+	return wrapWithType(err, TypeExternal, message, append([]ErrorOption{WithContext("service", service)}, opts...))
+}
+
+// wrapWithType is shared by the wrap helpers so the fallback stack starts at their caller
+func wrapWithType(err error, errType ErrorType, message string, opts []ErrorOption) *Error {
+	e := newError(message, 4)
+	e.errorType = errType
+	e.title = typeTitle(errType)
+	e.wrapped = err
+	preserveStack(e, err)
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// preserveStack replaces the stack and location of e with those of the innermost StackTracer in err's chain
+func preserveStack(e *Error, err error) {
+	var origin StackTracer
+	for current := err; current != nil; current = unwrapOne(current) {
+		if st, ok := current.(StackTracer); ok && len(st.StackTrace()) > 0 {
+			origin = st
+		}
+	}
+	if origin == nil {
+		return
+	}
+
+	e.stackTrace = origin.StackTrace()
+	if lgErr, ok := origin.(*Error); ok {
+		e.file, e.line = lgErr.file, lgErr.line
+		return
+	}
+	frame, _ := runtime.CallersFrames(e.stackTrace).Next()
+	if frame.PC != 0 {
+		e.file, e.line = frame.File, frame.Line
+	}
+}
+
+// unwrapOne follows Unwrap() error, or the first error of Unwrap() []error (e.g. errors.Join)
+func unwrapOne(err error) error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return u.Unwrap()
+	case interface{ Unwrap() []error }:
+		if errs := u.Unwrap(); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package lgfasthttp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// AccessLogConfig holds configuration for AccessLog
+type AccessLogConfig struct {
+	// Logger for the access log (if nil, uses the middleware logger)
+	Logger *slog.Logger
+	// Level of successful requests (default: Info); 4xx are logged at Warn and 5xx at Error
+	Level slog.Level
+	// Skip excludes requests from the access log (e.g. health checks)
+	Skip func(ctx *fasthttp.RequestCtx) bool
+}
+
+// AccessLog logs one record per request with method, path, status, duration, sizes and client IP,
+// after next returns. Place it inside TraceID so the record carries the trace ID
+//
+// Usage:
+//
+//	server := &fasthttp.Server{Handler: lgfasthttp.TraceID(lgfasthttp.AccessLog(handler, lgfasthttp.AccessLogConfig{
+//	    Skip: func(ctx *fasthttp.RequestCtx) bool { return string(ctx.Path()) == "/health" },
+//	}))}
+func AccessLog(next fasthttp.RequestHandler, cfg ...AccessLogConfig) fasthttp.RequestHandler {
+	var c AccessLogConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		if c.Skip != nil && c.Skip(ctx) {
+			next(ctx)
+			return
+		}
+
+		start := core.Now()
+		next(ctx)
+		duration := core.Since(start)
+
+		reqCtx := Context(ctx)
+		status := ctx.Response.StatusCode()
+		level := c.Level
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		logger.LogNoSourceCtx(reqCtx, accessLogger(reqCtx, c.Logger), level, "HTTP request",
+			fields.Method(string(ctx.Method())),
+			fields.Path(string(ctx.Path())),
+			fields.HTTPStatus(status),
+			fields.DurationMS(duration),
+			slog.Int("request_size", len(ctx.Request.Body())),
+			slog.Int("response_size", len(ctx.Response.Body())),
+			fields.IP(config.FromContext(reqCtx).ClientIP(ctx.RemoteIP().String())),
+		)
+	}
+}
+
+// accessLogger returns the configured logger, falling back to the middleware logger
+func accessLogger(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if log != nil {
+		return log
+	}
+	if log := config.FromContext(ctx).MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
@@ -0,0 +1,58 @@
+package lgfasthttp
+
+import (
+	"encoding/json"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+)
+
+// ErrorHandler is a fasthttp handler returning an error, handled by HandleErrors
+type ErrorHandler func(ctx *fasthttp.RequestCtx) error
+
+// HandleErrors turns an ErrorHandler into a fasthttp.RequestHandler: a returned error is handled by
+// WriteError, the way lgfiber.ErrorHandler handles errors of Fiber handlers
+//
+// Usage:
+//
+//	server := &fasthttp.Server{Handler: lgfasthttp.TraceID(lgfasthttp.HandleErrors(handle))}
+func HandleErrors(next ErrorHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if err := next(ctx); err != nil {
+			WriteError(ctx, err)
+		}
+	}
+}
+
+// WriteError logs err, reports it to Sentry when its status reaches the configured minimum (see
+// lgreport.HandleError) and writes the JSON error response. Errors other than *lgerr.Error are translated
+// with lgerr.FromError
+func WriteError(ctx *fasthttp.RequestCtx, err error) *sentry.EventID {
+	if err == nil {
+		return nil
+	}
+
+	return writeError(ctx, lgerr.FromError(err), false)
+}
+
+// writeError handles lgErr and replaces the response body; hideMeta keeps the error context out of it
+func writeError(ctx *fasthttp.RequestCtx, lgErr *lgerr.Error, hideMeta bool) *sentry.EventID {
+	eventID := lgreport.HandleError(Context(ctx), lgErr)
+
+	response := lgreport.ErrorResponse(lgErr)
+	if hideMeta {
+		response.Meta = nil
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		body = []byte(`{"title":"Internal Server Error"}`)
+	}
+	ctx.ResetBody()
+	ctx.SetStatusCode(lgErr.HTTPStatus())
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+	return eventID
+}
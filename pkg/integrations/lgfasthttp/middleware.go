@@ -0,0 +1,131 @@
+// Package lgfasthttp instruments raw fasthttp servers without Fiber: trace IDs, panic recovery, error
+// capture and an access log as fasthttp.RequestHandler wrappers, sharing lgfiber's error handling
+// (see lgreport)
+package lgfasthttp
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// contextKey stores the request context in the fasthttp user values
+type contextKey struct{}
+
+// Config holds configuration for Wrap and TraceID
+type Config struct {
+	// Header carrying an incoming trace ID and echoed in the response (default: "X-Request-ID")
+	Header string
+	// TrustIncoming reuses a valid incoming header value instead of generating a new ID (default: false)
+	TrustIncoming bool
+	// Generator creates new trace IDs (default: core.NewTraceID)
+	Generator func() string
+	// Settings are isolated settings (e.g. from logbundle.LogBundle.Settings); nil uses the defaults
+	Settings *config.Settings
+	// AccessLog configures the access log written by Wrap
+	AccessLog AccessLogConfig
+}
+
+// validTraceID limits accepted incoming IDs to safe, reasonably sized tokens
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._:-]{8,128}$`)
+
+// Context returns the request's logging context set by TraceID: it carries the trace ID, settings and
+// Sentry hub, and outlives the RequestCtx, so it is safe to pass to goroutines. Without TraceID it is
+// context.Background()
+//
+// Usage:
+//
+//	func handle(ctx *fasthttp.RequestCtx) error {
+//	    appLogger.InfoContext(lgfasthttp.Context(ctx), "processing") // ... log_trace_id=4bf92f35...
+//	}
+func Context(ctx *fasthttp.RequestCtx) context.Context {
+	if c, ok := ctx.UserValue(contextKey{}).(context.Context); ok {
+		return c
+	}
+	return context.Background()
+}
+
+// GetTraceID returns the request's trace ID set by TraceID, or "" if none
+func GetTraceID(ctx *fasthttp.RequestCtx) string {
+	return core.TraceIDFromContext(Context(ctx))
+}
+
+// TraceID assigns each request a trace ID, echoed in the response header and stored in the request
+// context (see Context). When Sentry is enabled the context also carries a per-request hub tagged
+// with the trace ID
+//
+// Usage:
+//
+//	server := &fasthttp.Server{Handler: lgfasthttp.TraceID(handler, lgfasthttp.Config{TrustIncoming: true})}
+func TraceID(next fasthttp.RequestHandler, cfg ...Config) fasthttp.RequestHandler {
+	c := newConfig(cfg)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		traceID := ""
+		if c.TrustIncoming {
+			if incoming := string(ctx.Request.Header.Peek(c.Header)); validTraceID.MatchString(incoming) {
+				traceID = incoming
+			}
+		}
+		if traceID == "" {
+			traceID = c.Generator()
+		}
+
+		reqCtx := context.Background()
+		if c.Settings != nil {
+			reqCtx = config.WithSettings(reqCtx, c.Settings)
+		}
+		reqCtx = core.WithTraceID(reqCtx, traceID)
+
+		if config.FromContext(reqCtx).SentryEnabled() {
+			hub := sentry.CurrentHub().Clone()
+			hub.Scope().SetTag(core.GetTraceIDFieldName(), traceID)
+			hub.Scope().SetContext("request", map[string]any{
+				"method": string(ctx.Method()),
+				"url":    core.ScrubURL(string(ctx.RequestURI())),
+			})
+			reqCtx = sentry.SetHubOnContext(reqCtx, hub)
+		}
+
+		ctx.SetUserValue(contextKey{}, reqCtx)
+		ctx.Response.Header.Set(c.Header, traceID)
+		next(ctx)
+	}
+}
+
+// Wrap instruments an error-returning handler with the full stack, outermost first: TraceID, AccessLog,
+// Recover and HandleErrors
+//
+// Usage:
+//
+//	server := &fasthttp.Server{Handler: lgfasthttp.Wrap(func(ctx *fasthttp.RequestCtx) error {
+//	    order, err := orders.Get(lgfasthttp.Context(ctx), string(ctx.QueryArgs().Peek("id")))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return writeJSON(ctx, order)
+//	})}
+func Wrap(next ErrorHandler, cfg ...Config) fasthttp.RequestHandler {
+	c := newConfig(cfg)
+	return TraceID(AccessLog(Recover(HandleErrors(next)), c.AccessLog), c)
+}
+
+// newConfig fills the defaults of the optional config
+func newConfig(cfg []Config) Config {
+	var c Config
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Header == "" {
+		c.Header = "X-Request-ID"
+	}
+	if c.Generator == nil {
+		c.Generator = core.NewTraceID
+	}
+	return c
+}
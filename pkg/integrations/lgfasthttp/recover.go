@@ -0,0 +1,61 @@
+package lgfasthttp
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Recover turns a panic in next into a 500 response handled like WriteError: logged with its location
+// and stack, reported to Sentry when enabled and counted in panics_recovered_total{source="fasthttp"}
+// The panic details are kept out of the response
+//
+// Usage:
+//
+//	server := &fasthttp.Server{Handler: lgfasthttp.TraceID(lgfasthttp.Recover(handler))}
+func Recover(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		defer func() {
+			if r := recover(); r != nil {
+				writeError(ctx, panicError(ctx, r), true)
+			}
+		}()
+		next(ctx)
+	}
+}
+
+// panicError builds the error of a recovered panic
+// It must run in the deferred function so the live stack still contains the panicking frames
+func panicError(ctx *fasthttp.RequestCtx, r any) *lgerr.Error {
+	stackTrace := string(debug.Stack())
+	errorLoc, _, _ := core.CallerErrorLocation()
+	if errorLoc == "" {
+		errorLoc, _, _ = core.ExtractErrorLocationWithDetails(stackTrace)
+	}
+
+	msg := fmt.Sprintf("panic: %v", r)
+	cause, isErr := r.(error)
+	if isErr {
+		// Error() renders the wrapped panic error after the message
+		msg = "panic"
+	}
+
+	lgErr := lgerr.Internal(msg,
+		lgerr.WithContext("panic_value", fmt.Sprintf("%v", r)),
+		lgerr.WithContext("error_location", errorLoc),
+		lgerr.WithContext("method", string(ctx.Method())),
+		lgerr.WithContext("path", string(ctx.Path())),
+		lgerr.WithFingerprint("fasthttp_panic", errorLoc),
+	)
+	if isErr {
+		lgErr.Wrap(cause)
+	}
+
+	metrics.IncCounter("panics_recovered_total", metrics.Labels{"source": "fasthttp"})
+	return lgErr
+}
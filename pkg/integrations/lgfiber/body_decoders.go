@@ -0,0 +1,140 @@
+package lgfiber
+
+import (
+	"encoding/xml"
+	"errors"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyDecoder decodes a request body into dto (a pointer to the DTO)
+// Implement it to accept additional formats, e.g. protobuf:
+//
+//	lgfiber.RegisterBodyDecoder("application/x-protobuf", lgfiber.BodyDecoderFunc(func(c *fiber.Ctx, dto any) error {
+//	    msg, ok := dto.(proto.Message)
+//	    if !ok {
+//	        return fmt.Errorf("%T is not a proto.Message", dto)
+//	    }
+//	    return proto.Unmarshal(c.Body(), msg)
+//	}))
+type BodyDecoder interface {
+	Decode(c *fiber.Ctx, dto any) error
+}
+
+// BodyDecoderFunc adapts a function to BodyDecoder
+type BodyDecoderFunc func(c *fiber.Ctx, dto any) error
+
+// Decode calls f(c, dto)
+func (f BodyDecoderFunc) Decode(c *fiber.Ctx, dto any) error {
+	return f(c, dto)
+}
+
+var (
+	// jsonBodyDecoder uses the app's fiber.Config.JSONDecoder (encoding/json unless the app replaces it)
+	jsonBodyDecoder = BodyDecoderFunc(func(c *fiber.Ctx, dto any) error {
+		return c.App().Config().JSONDecoder(c.Body(), dto)
+	})
+	xmlBodyDecoder = BodyDecoderFunc(func(c *fiber.Ctx, dto any) error {
+		return xml.Unmarshal(c.Body(), dto)
+	})
+	// formBodyDecoder uses fiber's form decoding (`form` struct tags)
+	formBodyDecoder = BodyDecoderFunc(func(c *fiber.Ctx, dto any) error {
+		return c.BodyParser(dto)
+	})
+
+	bodyDecoders = map[string]BodyDecoder{
+		fiber.MIMEApplicationJSON: jsonBodyDecoder,
+		fiber.MIMEApplicationXML:  xmlBodyDecoder,
+		fiber.MIMETextXML:         xmlBodyDecoder,
+		fiber.MIMEApplicationForm: formBodyDecoder,
+		fiber.MIMEMultipartForm:   formBodyDecoder,
+	}
+	bodyDecodersMutex sync.RWMutex
+)
+
+// RegisterBodyDecoder sets the decoder used by BodyValidationMiddleware for a media type
+// (e.g. "application/x-protobuf"); it replaces any existing decoder for the type
+func RegisterBodyDecoder(mediaType string, d BodyDecoder) {
+	bodyDecodersMutex.Lock()
+	bodyDecoders[strings.ToLower(mediaType)] = d
+	bodyDecodersMutex.Unlock()
+}
+
+// unsupportedMediaTypeError is returned when no decoder handles the request's Content-Type
+type unsupportedMediaTypeError struct {
+	mediaType string
+	supported []string
+}
+
+func (e *unsupportedMediaTypeError) Error() string {
+	return "unsupported Content-Type: " + e.mediaType
+}
+
+// findBodyDecoder returns the decoder for a media type, honoring structured syntax suffixes
+// such as application/problem+json and application/atom+xml
+func findBodyDecoder(mediaType string) (BodyDecoder, bool) {
+	bodyDecodersMutex.RLock()
+	defer bodyDecodersMutex.RUnlock()
+
+	if d, ok := bodyDecoders[mediaType]; ok {
+		return d, true
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return bodyDecoders[fiber.MIMEApplicationJSON], true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return bodyDecoders[fiber.MIMEApplicationXML], true
+	}
+	return nil, false
+}
+
+// supportedMediaTypes lists the registered media types, sorted
+func supportedMediaTypes() []string {
+	bodyDecodersMutex.RLock()
+	defer bodyDecodersMutex.RUnlock()
+
+	types := make([]string, 0, len(bodyDecoders))
+	for t := range bodyDecoders {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// requestMediaType returns the lowercased media type of the request without parameters
+func requestMediaType(c *fiber.Ctx) string {
+	contentType := c.Get(fiber.HeaderContentType)
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// negotiatedBodyParser decodes the body with the decoder registered for its Content-Type
+// Requests without a Content-Type keep fiber's BodyParser behavior (a 400 parse error, not a 415)
+func negotiatedBodyParser[T any](c *fiber.Ctx, dto *T) error {
+	mediaType := requestMediaType(c)
+	if mediaType == "" {
+		return c.BodyParser(dto)
+	}
+	d, ok := findBodyDecoder(mediaType)
+	if !ok {
+		return &unsupportedMediaTypeError{mediaType: mediaType, supported: supportedMediaTypes()}
+	}
+	return d.Decode(c, dto)
+}
+
+// isUnsupportedMediaType reports whether err is an unsupportedMediaTypeError
+func isUnsupportedMediaType(err error) (*unsupportedMediaTypeError, bool) {
+	var mediaErr *unsupportedMediaTypeError
+	ok := errors.As(err, &mediaErr)
+	return mediaErr, ok
+}
@@ -0,0 +1,63 @@
+package lgfiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type decoderDTO struct {
+	Name string `json:"name" xml:"name" form:"name"`
+}
+
+func TestBodyValidationContentTypes(t *testing.T) {
+	var customDecodes atomic.Int64
+	app := fiber.New(fiber.Config{
+		JSONDecoder: func(data []byte, v any) error {
+			customDecodes.Add(1)
+			return json.Unmarshal(data, v)
+		},
+	})
+	app.Post("/", BodyValidationMiddleware[decoderDTO](), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("body").(decoderDTO).Name)
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCustom  bool
+	}{
+		{name: "json uses app decoder", contentType: "application/json", body: `{"name":"a"}`, wantStatus: http.StatusOK, wantCustom: true},
+		{name: "json suffix uses app decoder", contentType: "application/problem+json", body: `{"name":"a"}`, wantStatus: http.StatusOK, wantCustom: true},
+		{name: "xml", contentType: "application/xml", body: `<decoderDTO><name>a</name></decoderDTO>`, wantStatus: http.StatusOK},
+		{name: "form", contentType: "application/x-www-form-urlencoded", body: `name=a`, wantStatus: http.StatusOK},
+		{name: "missing content type keeps parse error", body: `{"name":"a"}`, wantStatus: http.StatusBadRequest},
+		{name: "unsupported content type", contentType: "text/csv", body: `name`, wantStatus: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := customDecodes.Load()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if used := customDecodes.Load() > before; used != tt.wantCustom {
+				t.Fatalf("app JSONDecoder used = %v, want %v", used, tt.wantCustom)
+			}
+		})
+	}
+}
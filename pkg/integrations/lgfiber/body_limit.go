@@ -0,0 +1,69 @@
+package lgfiber
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// BodyLimitMiddleware creates a middleware that rejects request bodies larger than limit bytes
+// Oversized requests receive a structured 413 response (lgerr.BadInput) instead of fiber's plain
+// text body, are logged at Warn with route and size, and counted in "http_body_limit_rejected_total"
+//
+// fasthttp reads the whole body into memory before any middleware runs and fiber.Config.BodyLimit
+// (default: 4MB) rejects larger bodies with a plain text 413, so this middleware cannot save memory: it
+// gives smaller routes a tighter limit and a structured error. Keep fiber.Config.BodyLimit at the largest
+// limit a route really needs, since every route accepts bodies up to it:
+//
+//	app := fiber.New(fiber.Config{BodyLimit: 10 * 1024 * 1024})
+//	api := app.Group("/api", lgfiber.BodyLimitMiddleware(1024*1024))
+//	app.Post("/uploads", handler) // Up to fiber's 10MB
+//
+// The route label is the pattern of the route the request was headed for (e.g. /users/:id), also when
+// the middleware is mounted with app.Use
+func BodyLimitMiddleware(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Content-Length is checked first; the body is already buffered, but the handler never sees it
+		size := c.Request().Header.ContentLength()
+		if size < 0 {
+			// Chunked or unknown length - fall back to the received body
+			size = len(c.Body())
+		}
+
+		if size <= limit {
+			return c.Next()
+		}
+
+		route := matchedRoutePattern(c)
+		lgErr := lgerr.BadInput("request body too large",
+			lgerr.WithHTTPStatusOpt(fiber.StatusRequestEntityTooLarge),
+			lgerr.WithTitle("Payload Too Large"),
+			lgerr.WithDetail(fmt.Sprintf("Request body must not exceed %d bytes", limit)),
+			lgerr.WithContext("limit_bytes", limit),
+			lgerr.WithContext("size_bytes", size),
+		)
+
+		metrics.IncCounter("http_body_limit_rejected_total", metrics.Labels{
+			"method": c.Method(),
+			"route":  route,
+		})
+
+		logger.LogNoSourceCtx(c.UserContext(), getMiddlewareLogger(c.UserContext()), slog.LevelWarn, "Request body too large",
+			slog.String("method", c.Method()),
+			slog.String("route", route),
+			slog.String("url", core.ScrubURL(c.OriginalURL())),
+			slog.Int("size_bytes", size),
+			slog.Int("limit_bytes", limit),
+		)
+
+		c.Set(fiber.HeaderConnection, "close")
+		return c.Status(lgErr.HTTPStatus()).JSON(lgreport.ErrorResponse(lgErr))
+	}
+}
@@ -0,0 +1,88 @@
+package lgfiber
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+func TestBodyLimitMiddlewareLabelsRoutePattern(t *testing.T) {
+	registry := metrics.NewRegistry()
+	prev := metrics.GetRecorder()
+	metrics.SetRecorder(registry)
+	t.Cleanup(func() { metrics.SetRecorder(prev) })
+
+	var buf bytes.Buffer
+	settings := config.NewSettings()
+	settings.SetMiddlewareLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(config.WithSettings(c.UserContext(), settings))
+		return c.Next()
+	})
+	app.Use(BodyLimitMiddleware(8))
+	app.Post("/users/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{body: "small", status: fiber.StatusNoContent},
+		{body: strings.Repeat("x", 64), status: fiber.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/users/42", strings.NewReader(tt.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("body of %d bytes: status = %d, want %d", len(tt.body), resp.StatusCode, tt.status)
+		}
+	}
+
+	if out := buf.String(); !strings.Contains(out, "route=/users/:id") || !strings.Contains(out, "size_bytes=64") {
+		t.Fatalf("unexpected log:\n%s", out)
+	}
+	for _, s := range registry.Snapshot() {
+		if s.Name == "http_body_limit_rejected_total" {
+			if s.Labels["route"] != "/users/:id" || s.Value != 1 {
+				t.Fatalf("unexpected sample %+v", s)
+			}
+			return
+		}
+	}
+	t.Fatal("http_body_limit_rejected_total not recorded")
+}
+
+func TestRoutePatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "/", path: "/", want: true},
+		{pattern: "/users/:id", path: "/users/42", want: true},
+		{pattern: "/users/:id", path: "/users", want: false},
+		{pattern: "/users/:id", path: "/users/42/orders", want: false},
+		{pattern: "/users/:id?", path: "/users", want: true},
+		{pattern: "/Users", path: "/users/", want: true},
+		{pattern: "/files/*", path: "/files/a/b.txt", want: true},
+		{pattern: "/files/+", path: "/files", want: false},
+		{pattern: "/files/:name.:ext", path: "/files/a.txt", want: true},
+		{pattern: "/orders", path: "/refunds", want: false},
+	}
+	for _, tt := range tests {
+		if got := routePatternMatches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("routePatternMatches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
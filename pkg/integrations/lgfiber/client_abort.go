@@ -0,0 +1,66 @@
+package lgfiber
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"syscall"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// StatusClientClosedRequest is the non-standard status (popularized by nginx) used for
+// requests the client abandoned before the response was written
+const StatusClientClosedRequest = 499
+
+// IsClientAbort reports whether err means the client of c went away: a cancellation while the
+// request's own context was canceled, or a closed or reset connection to the client's address
+// The same errors from upstream calls (databases, HTTP clients) are real failures, not aborts
+// Client aborts are not server failures and are never sent to Sentry
+func IsClientAbort(c *fiber.Ctx, err error) bool {
+	if err == nil || c == nil {
+		return false
+	}
+	return lgreport.Canceled(c.UserContext(), err) || isClientConnError(c, err)
+}
+
+// isClientConnError reports whether err is a broken connection whose remote end is the client of c
+func isClientConnError(c *fiber.Ctx, err error) bool {
+	if !errors.Is(err, net.ErrClosed) &&
+		!errors.Is(err, syscall.EPIPE) &&
+		!errors.Is(err, syscall.ECONNRESET) &&
+		!errors.Is(err, syscall.ECONNABORTED) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Addr == nil {
+		return false
+	}
+	remote := c.Context().RemoteAddr()
+	return remote != nil && opErr.Addr.String() == remote.String()
+}
+
+// handleClientAbort logs an aborted request at Info level and sets the 499 status for access logs
+func handleClientAbort(c *fiber.Ctx, err error) error {
+	logger.LogNoSourceCtx(c.UserContext(), getMiddlewareLogger(c.UserContext()), slog.LevelInfo, "Client closed request",
+		slog.Int("status_code", StatusClientClosedRequest),
+		slog.String("url", core.ScrubURL(c.OriginalURL())),
+		slog.String("method", c.Method()),
+		slog.String("route", c.Route().Path),
+		slog.String("error", err.Error()),
+	)
+
+	metrics.IncCounter("http_client_aborts_total", metrics.Labels{
+		"method": c.Method(),
+		"route":  c.Route().Path,
+	})
+
+	// The client is gone, so there is nobody to send a body to
+	return c.SendStatus(StatusClientClosedRequest)
+}
@@ -0,0 +1,54 @@
+package lgfiber
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestIsClientAbort(t *testing.T) {
+	client := &net.TCPAddr{IP: net.IPv4(203, 0, 113, 7), Port: 51234}
+	upstream := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 5432}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	connErr := func(addr net.Addr, errno syscall.Errno) error {
+		return &net.OpError{Op: "write", Net: "tcp", Addr: addr, Err: os.NewSyscallError("write", errno)}
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		expect bool
+	}{
+		{"nil error", context.Background(), nil, false},
+		{"canceled request", canceled, fmt.Errorf("query: %w", context.Canceled), true},
+		{"canceled upstream call", context.Background(), fmt.Errorf("query: %w", context.Canceled), false},
+		{"reset by client", context.Background(), connErr(client, syscall.ECONNRESET), true},
+		{"broken pipe to client", context.Background(), connErr(client, syscall.EPIPE), true},
+		{"reset by upstream", context.Background(), fmt.Errorf("db: %w", connErr(upstream, syscall.ECONNRESET)), false},
+		{"closed without address", context.Background(), net.ErrClosed, false},
+	}
+
+	app := fiber.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fctx fasthttp.RequestCtx
+			fctx.Init(&fasthttp.Request{}, client, nil)
+			c := app.AcquireCtx(&fctx)
+			defer app.ReleaseCtx(c)
+			c.SetUserContext(tt.ctx)
+
+			if got := IsClientAbort(c, tt.err); got != tt.expect {
+				t.Fatalf("IsClientAbort(%v) = %v, want %v", tt.err, got, tt.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package lgfiber
+
+import (
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+// Middlewares returns the middlewares selected by m in registration order (Recover, Sentry, TraceID,
+// Breadcrumbs, Metrics, RequestLogger). All but Recover are bypassed for the paths in m's skip lists, in
+// addition to the shared path filter (see SetPathFilter), which is left unchanged
+//
+// Usage:
+//
+//	bundle, cfg, err := logbundle.InitFromConfigFile("/etc/app/logbundle.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer bundle.Close(context.Background())
+//	handlers, err := lgfiber.Middlewares(cfg.Fiber)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, h := range handlers {
+//	    app.Use(h)
+//	}
+func Middlewares(m config.FiberMiddlewares) ([]fiber.Handler, error) {
+	var skip *compiledPathFilter
+	if len(m.SkipPaths) > 0 || len(m.SkipPrefixes) > 0 {
+		f, err := PathFilter{Paths: m.SkipPaths, Prefixes: m.SkipPrefixes}.compile()
+		if err != nil {
+			return nil, err
+		}
+		skip = f
+	}
+	filtered := func(h fiber.Handler) fiber.Handler {
+		if skip == nil {
+			return h
+		}
+		return skipPaths(skip, h)
+	}
+
+	var handlers []fiber.Handler
+	if m.Recover {
+		handlers = append(handlers, RecoverMiddleware())
+	}
+	if m.Sentry {
+		handlers = append(handlers, filtered(SkipFiltered(sentryfiber.New(sentryfiber.Options{Repanic: true}))))
+	}
+	if m.TraceID {
+		handlers = append(handlers, filtered(TraceIDMiddleware(TraceIDConfig{TrustIncoming: m.TrustIncomingTraceID})))
+	}
+	if m.Breadcrumbs {
+		handlers = append(handlers, filtered(BreadcrumbsMiddleware()))
+	}
+	if m.Metrics {
+		handlers = append(handlers, filtered(MetricsMiddleware()))
+	}
+	if m.RequestLogger {
+		handlers = append(handlers, filtered(RequestLoggerMiddleware()))
+	}
+	return handlers, nil
+}
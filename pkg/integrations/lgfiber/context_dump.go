@@ -0,0 +1,55 @@
+package lgfiber
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// ContextDumpConfig holds configuration for ContextDumpMiddleware
+type ContextDumpConfig struct {
+	// Skip excludes requests from the dump, in addition to the shared path filter (see SetPathFilter)
+	Skip func(c *fiber.Ctx) bool
+}
+
+// ContextDumpMiddleware logs core.DumpContext of the request context at Debug, showing which
+// logbundle values (trace ID, record attributes, Sentry hub, span, baggage, settings, deadline) reach
+// the handlers. The Fiber context is passed along as "fiber_ctx", so the hub and span sentryfiber keeps
+// on it are found.
+// Nothing is computed unless the middleware logger has Debug enabled for the request, so it can stay
+// registered and be turned on per request with DebugScopeMiddleware. Register it after the middlewares
+// that populate the context
+//
+// Usage:
+//
+//	app.Use(lgfiber.TraceIDMiddleware())
+//	app.Use(lgfiber.DebugScopeMiddleware(debugCfg))
+//	app.Use(lgfiber.ContextDumpMiddleware())
+func ContextDumpMiddleware(cfg ...ContextDumpConfig) fiber.Handler {
+	var config ContextDumpConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+
+	return func(c *fiber.Ctx) error {
+		if (config.Skip != nil && config.Skip(c)) || IsFilteredPath(c) {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		log := getMiddlewareLogger(ctx)
+		if log.Enabled(ctx, slog.LevelDebug) {
+			logger.LogNoSourceCtx(ctx, log, slog.LevelDebug, "Request context",
+				slog.String("method", c.Method()),
+				slog.String("path", c.Path()),
+				slog.Any("context", core.DumpContext(context.WithValue(ctx, "fiber_ctx", c))),
+			)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,172 @@
+package lgfiber
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// CORSLoggingConfig holds configuration for CORS logging middleware
+type CORSLoggingConfig struct {
+	// Logger instance for blocked requests (if nil, uses middleware logger)
+	Logger *slog.Logger
+	// SentryTag adds a "cors_blocked" tag and breadcrumb to the request's Sentry scope
+	SentryTag bool
+}
+
+// CORSLoggingMiddleware logs requests that were blocked by the CORS policy
+// Register it BEFORE fiber's cors middleware so it can inspect the CORS response headers:
+//
+//	app.Use(lgfiber.CORSLoggingMiddleware())
+//	app.Use(cors.New(cors.Config{AllowOrigins: "https://app.example.com"}))
+//
+// Only cross-origin CORS requests are evaluated: same-origin requests (Origin matching the request's
+// scheme and host) and requests a browser marks as not using CORS (Sec-Fetch-Mode other than "cors",
+// e.g. form posts and navigations) are passed through. Such a request is considered blocked when the
+// response does not allow its origin, or a preflight asks for a method or headers the response does not allow
+func CORSLoggingMiddleware(cfg ...CORSLoggingConfig) fiber.Handler {
+	var c CORSLoggingConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		origin := ctx.Get(fiber.HeaderOrigin)
+		if !isCORSRequest(ctx, origin) {
+			return ctx.Next()
+		}
+
+		err := ctx.Next()
+
+		preflight := ctx.Method() == fiber.MethodOptions &&
+			ctx.Get(fiber.HeaderAccessControlRequestMethod) != ""
+
+		reason := corsBlockReason(ctx, origin, preflight)
+		if reason == "" {
+			return err
+		}
+
+		log := c.Logger
+		if log == nil {
+			log = getMiddlewareLogger(ctx.UserContext())
+		}
+
+		logger.LogNoSourceCtx(ctx.UserContext(), log, slog.LevelInfo, "Request blocked by CORS policy",
+			slog.String("origin", origin),
+			slog.String("reason", reason),
+			slog.Bool("preflight", preflight),
+			slog.String("method", ctx.Method()),
+			slog.String("requested_method", ctx.Get(fiber.HeaderAccessControlRequestMethod)),
+			slog.String("requested_headers", ctx.Get(fiber.HeaderAccessControlRequestHeaders)),
+			slog.String("path", ctx.Path()),
+			slog.String("route", ctx.Route().Path),
+		)
+
+		if c.SentryTag && config.FromContext(ctx.UserContext()).SentryEnabled() {
+			if hub := sentryfiber.GetHubFromContext(ctx); hub != nil {
+				hub.Scope().SetTag("cors_blocked", "true")
+				hub.AddBreadcrumb(&sentry.Breadcrumb{
+					Type:      "http",
+					Category:  "cors",
+					Message:   "Request blocked by CORS policy: " + reason,
+					Level:     sentry.LevelInfo,
+					Timestamp: core.Now(),
+					Data: map[string]any{
+						"origin":    origin,
+						"preflight": preflight,
+						"path":      ctx.Path(),
+					},
+				}, nil)
+			}
+		}
+
+		return err
+	}
+}
+
+// isCORSRequest reports whether the request is a cross-origin request subject to CORS
+func isCORSRequest(c *fiber.Ctx, origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if mode := c.Get("Sec-Fetch-Mode"); mode != "" && mode != "cors" {
+		return false
+	}
+	return !sameOrigin(origin, c.Protocol(), c.Hostname())
+}
+
+// sameOrigin reports whether origin is scheme://host, ignoring case and the default port of the scheme
+func sameOrigin(origin, scheme, host string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		// "null" and other opaque origins never match
+		return false
+	}
+	return strings.EqualFold(u.Scheme, scheme) &&
+		strings.EqualFold(stripDefaultPort(u.Host, u.Scheme), stripDefaultPort(host, scheme))
+}
+
+// stripDefaultPort removes :80 from http and :443 from https hosts
+func stripDefaultPort(host, scheme string) string {
+	switch {
+	case strings.EqualFold(scheme, "http"):
+		return strings.TrimSuffix(host, ":80")
+	case strings.EqualFold(scheme, "https"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+// corsBlockReason returns why the response would be rejected by the browser, or empty if allowed
+func corsBlockReason(c *fiber.Ctx, origin string, preflight bool) string {
+	resp := &c.Response().Header
+
+	allowOrigin := string(resp.Peek(fiber.HeaderAccessControlAllowOrigin))
+	if allowOrigin == "" {
+		return "origin not allowed"
+	}
+	if allowOrigin != "*" && allowOrigin != origin {
+		return "origin mismatch"
+	}
+
+	if !preflight {
+		return ""
+	}
+
+	if requested := c.Get(fiber.HeaderAccessControlRequestMethod); requested != "" {
+		allowed := string(resp.Peek(fiber.HeaderAccessControlAllowMethods))
+		if !headerListContains(allowed, requested) {
+			return "method not allowed"
+		}
+	}
+
+	if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+		allowed := string(resp.Peek(fiber.HeaderAccessControlAllowHeaders))
+		for _, h := range strings.Split(requested, ",") {
+			if h = strings.TrimSpace(h); h != "" && !headerListContains(allowed, h) {
+				return "header not allowed: " + h
+			}
+		}
+	}
+
+	return ""
+}
+
+// headerListContains reports whether a comma-separated header value contains item (case-insensitive)
+func headerListContains(list, item string) bool {
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.EqualFold(v, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package lgfiber
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCORSLoggingOnlyEvaluatesCrossOriginRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		origin    string
+		fetchMode string
+		blocked   bool
+	}{
+		{name: "no origin"},
+		{name: "same origin", origin: "http://example.com"},
+		{name: "same origin default port", origin: "http://EXAMPLE.com:80"},
+		{name: "form post", origin: "https://other.example", fetchMode: "navigate"},
+		{name: "cross origin", origin: "https://other.example", blocked: true},
+		{name: "cross origin fetch", origin: "https://other.example", fetchMode: "cors", blocked: true},
+		{name: "other scheme", origin: "https://example.com", blocked: true},
+		{name: "opaque origin", origin: "null", blocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			app := fiber.New()
+			app.Use(CORSLoggingMiddleware(CORSLoggingConfig{Logger: slog.New(slog.NewTextHandler(&buf, nil))}))
+			app.Post("/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/orders", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.fetchMode != "" {
+				req.Header.Set("Sec-Fetch-Mode", tt.fetchMode)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.Contains(buf.String(), "Request blocked by CORS policy"); got != tt.blocked {
+				t.Fatalf("blocked = %v, want %v:\n%s", got, tt.blocked, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package lgfiber
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+	"github.com/valyala/fasthttp/expvarhandler"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/breaker"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// underPrefix reports whether path is prefix itself or a path below it
+func underPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// levelRequest is the body of PUT {prefix}/level
+type levelRequest struct {
+	// Level is the temporary minimum level ("debug", "info", "warn", "error")
+	Level string `json:"level"`
+	// TTL of the override, e.g. "10m" (empty means until reset)
+	TTL string `json:"ttl"`
+	// ModuleLevels replaces the per-package overrides when set (see core.SetModuleLevels)
+	ModuleLevels *string `json:"module_levels"`
+}
+
+// MountDebug registers the debug surface under prefix, guarded by authFunc (a nil authFunc rejects all requests):
+//   - {prefix}/debug/pprof/...  runtime profiles (net/http/pprof)
+//   - {prefix}/vars             expvar variables
+//   - {prefix}/diagnostics      logging pipeline state as JSON
+//   - {prefix}/level            GET current levels, PUT {"level":"debug","ttl":"10m","module_levels":"..."},
+//     DELETE to reset the temporary override
+//
+// Rejected requests get 403 and are logged at Warn; level changes are logged at Warn with the new values
+//
+// Usage:
+//
+//	lgfiber.MountDebug(app, "/admin", func(c *fiber.Ctx) bool {
+//	    return subtle.ConstantTimeCompare([]byte(c.Get("X-Admin-Token")), adminToken) == 1
+//	})
+func MountDebug(app fiber.Router, prefix string, authFunc func(c *fiber.Ctx) bool) {
+	group := app.Group(prefix, func(c *fiber.Ctx) error {
+		// Group handlers match by string prefix, so /admin also sees /administrators
+		if !underPrefix(c.Path(), prefix) {
+			return c.Next()
+		}
+		if authFunc != nil && authFunc(c) {
+			return c.Next()
+		}
+
+		logger.LogNoSourceCtx(c.UserContext(), getMiddlewareLogger(c.UserContext()), slog.LevelWarn, "Debug endpoint access denied",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("ip", config.FromContext(c.UserContext()).ClientIP(c.IP())),
+		)
+		return lgerr.Forbidden("debug endpoints", "authorization required")
+	})
+
+	group.Use(pprof.New(pprof.Config{Prefix: prefix}))
+
+	group.Get("/vars", func(c *fiber.Ctx) error {
+		expvarhandler.ExpvarHandler(c.Context())
+		return nil
+	})
+
+	group.Get("/diagnostics", func(c *fiber.Ctx) error {
+		return c.JSON(debugDiagnostics(config.FromContext(c.UserContext())))
+	})
+
+	group.Get("/level", func(c *fiber.Ctx) error {
+		return c.JSON(debugLevels())
+	})
+
+	group.Put("/level", func(c *fiber.Ctx) error {
+		var req levelRequest
+		if err := c.BodyParser(&req); err != nil {
+			return lgerr.BadInput("invalid level request: " + err.Error())
+		}
+
+		if req.ModuleLevels != nil {
+			if err := core.SetModuleLevels(*req.ModuleLevels); err != nil {
+				return lgerr.BadInput(err.Error())
+			}
+		}
+		if req.Level != "" {
+			level, err := core.ParseLevel(req.Level)
+			if err != nil {
+				return lgerr.BadInput(err.Error())
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				if ttl, err = time.ParseDuration(req.TTL); err != nil {
+					return lgerr.BadInput("invalid ttl: " + err.Error())
+				}
+			}
+			core.SetLevelOverride(level, ttl)
+		}
+
+		state := debugLevels()
+		logger.LogNoSourceCtx(c.UserContext(), getMiddlewareLogger(c.UserContext()), slog.LevelWarn, "Log levels changed via debug endpoint",
+			slog.Any("levels", state),
+			slog.String("ip", config.FromContext(c.UserContext()).ClientIP(c.IP())),
+		)
+		return c.JSON(state)
+	})
+
+	group.Delete("/level", func(c *fiber.Ctx) error {
+		core.ResetLevelOverride()
+		logger.LogNoSourceCtx(c.UserContext(), getMiddlewareLogger(c.UserContext()), slog.LevelWarn, "Log level override reset via debug endpoint")
+		return c.JSON(debugLevels())
+	})
+}
+
+// debugLevels returns the active level settings
+func debugLevels() fiber.Map {
+	out := fiber.Map{"module_levels": core.GetModuleLevels()}
+	if level, ok := core.GetGlobalLevel(); ok {
+		out["global_level"] = level.String()
+	}
+	if level, expiresAt, ok := core.GetLevelOverride(); ok {
+		out["level_override"] = level.String()
+		if !expiresAt.IsZero() {
+			out["level_override_expires_at"] = expiresAt.Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+// debugDiagnostics returns the logging pipeline state (the JSON counterpart of logbundle.LogDiagnostics)
+func debugDiagnostics(s *config.Settings) fiber.Map {
+	out := fiber.Map{
+		"levels":                 debugLevels(),
+		"middleware_logger_set":  s.MiddlewareLogger() != nil,
+		"sentry_enabled":         s.SentryEnabled(),
+		"sentry_min_http_status": s.SentryMinHTTPStatus(),
+		"ip_anonymization":       s.IPAnonymization(),
+		"metric_series":          len(metrics.Snapshot()),
+		"goroutines":             runtime.NumGoroutine(),
+		"runtime":                core.RuntimeMetadataTags(),
+	}
+	if breakers := breaker.Summary(); len(breakers) > 0 {
+		out["circuit_breakers"] = breakers
+	}
+	return out
+}
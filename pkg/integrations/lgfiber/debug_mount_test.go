@@ -0,0 +1,43 @@
+package lgfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMountDebugPathBoundary(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		return c.SendStatus(fiber.ErrForbidden.Code)
+	}})
+	MountDebug(app, "/admin", func(c *fiber.Ctx) bool { return c.Get("X-Admin") == "yes" })
+	app.Get("/administrators", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	tests := []struct {
+		path   string
+		admin  bool
+		status int
+	}{
+		{path: "/administrators", status: http.StatusOK},
+		{path: "/admin/level", status: http.StatusForbidden},
+		{path: "/admin", status: http.StatusForbidden},
+		{path: "/admin/level", admin: true, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.admin {
+				req.Header.Set("X-Admin", "yes")
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("GET %s: status = %d, want %d", tt.path, resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package lgfiber
+
+import (
+	"log/slog"
+	"slices"
+
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// DebugScopeConfig holds the criteria for elevating a request to debug logging
+// A request is elevated when any configured criterion matches
+type DebugScopeConfig struct {
+	// UserIDs elevates requests from these users (requires UserID)
+	UserIDs []string
+	// UserID extracts the user ID from the request (e.g. from c.Locals set by auth middleware)
+	UserID func(c *fiber.Ctx) string
+	// TenantIDs elevates requests from these tenants (requires TenantID)
+	TenantIDs []string
+	// TenantID extracts the tenant ID from the request
+	TenantID func(c *fiber.Ctx) string
+	// TokenSecret enables elevation via a signed token (see core.GenerateDebugToken)
+	TokenSecret []byte
+	// TokenHeader carries the debug token (default: "X-Debug-Token")
+	TokenHeader string
+	// Match is a custom criterion
+	Match func(c *fiber.Ctx) bool
+}
+
+// DebugScopeMiddleware elevates matching requests to debug logging without changing the global level
+// Elevation is stored in the request context, so logs must use the context-aware methods
+// (log.DebugContext(c.UserContext(), ...)) to be affected
+//
+// Usage:
+//
+//	app.Use(authMiddleware)
+//	app.Use(lgfiber.DebugScopeMiddleware(lgfiber.DebugScopeConfig{
+//	    UserIDs:     []string{"user-42"},
+//	    UserID:      func(c *fiber.Ctx) string { id, _ := c.Locals("user_id").(string); return id },
+//	    TokenSecret: []byte(os.Getenv("DEBUG_TOKEN_SECRET")),
+//	}))
+func DebugScopeMiddleware(cfg DebugScopeConfig) fiber.Handler {
+	if cfg.TokenHeader == "" {
+		cfg.TokenHeader = "X-Debug-Token"
+	}
+
+	return func(c *fiber.Ctx) error {
+		reason := debugScopeReason(c, cfg)
+		if reason == "" {
+			return c.Next()
+		}
+
+		ctx := core.WithDebugScope(c.UserContext())
+		c.SetUserContext(ctx)
+
+		if config.FromContext(ctx).SentryEnabled() {
+			if hub := sentryfiber.GetHubFromContext(c); hub != nil {
+				hub.Scope().SetTag("debug_scope", reason)
+			}
+		}
+
+		logger.LogNoSourceCtx(ctx, getMiddlewareLogger(ctx), slog.LevelDebug, "Debug scope enabled for request",
+			slog.String("reason", reason),
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+		)
+
+		return c.Next()
+	}
+}
+
+// debugScopeReason returns which criterion matched, or empty if none
+func debugScopeReason(c *fiber.Ctx, cfg DebugScopeConfig) string {
+	if len(cfg.TokenSecret) > 0 {
+		if token := c.Get(cfg.TokenHeader); token != "" && core.VerifyDebugToken(cfg.TokenSecret, token) {
+			return "token"
+		}
+	}
+
+	if cfg.UserID != nil && len(cfg.UserIDs) > 0 {
+		if id := cfg.UserID(c); id != "" && slices.Contains(cfg.UserIDs, id) {
+			return "user"
+		}
+	}
+
+	if cfg.TenantID != nil && len(cfg.TenantIDs) > 0 {
+		if id := cfg.TenantID(c); id != "" && slices.Contains(cfg.TenantIDs, id) {
+			return "tenant"
+		}
+	}
+
+	if cfg.Match != nil && cfg.Match(c) {
+		return "match"
+	}
+
+	return ""
+}
@@ -0,0 +1,71 @@
+package lgfiber
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+func TestDebugScopeMiddlewareElevatesMatchingRequests(t *testing.T) {
+	secret := []byte("debug-secret")
+	var buf bytes.Buffer
+	appLog := slog.New(handler.NewCustomHandler(&buf, slog.LevelInfo, false))
+
+	app := fiber.New()
+	app.Use(DebugScopeMiddleware(DebugScopeConfig{
+		UserIDs:     []string{"user-42"},
+		UserID:      func(c *fiber.Ctx) string { return c.Get("X-User") },
+		TenantIDs:   []string{"acme"},
+		TenantID:    func(c *fiber.Ctx) string { return c.Get("X-Tenant") },
+		TokenSecret: secret,
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		appLog.DebugContext(c.UserContext(), "debug detail", "request", c.Get("X-Case"))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		elevated bool
+	}{
+		{name: "user", headers: map[string]string{"X-User": "user-42"}, elevated: true},
+		{name: "tenant", headers: map[string]string{"X-Tenant": "acme"}, elevated: true},
+		{name: "token", headers: map[string]string{"X-Debug-Token": core.GenerateDebugToken(secret, time.Minute)}, elevated: true},
+		{name: "other user", headers: map[string]string{"X-User": "user-7"}},
+		{name: "forged token", headers: map[string]string{"X-Debug-Token": core.GenerateDebugToken([]byte("guess"), time.Minute)}},
+		{name: "expired token", headers: map[string]string{"X-Debug-Token": core.GenerateDebugToken(secret, -time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Case", tt.name)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(buf.String(), "debug detail"); got != tt.elevated {
+				t.Fatalf("debug record written = %v, want %v:\n%s", got, tt.elevated, buf.String())
+			}
+		})
+	}
+
+	// The scope is per request: a plain context stays at the handler level
+	buf.Reset()
+	appLog.Debug("outside any request")
+	if buf.Len() != 0 {
+		t.Fatalf("debug record written without a scope:\n%s", buf.String())
+	}
+}
@@ -2,9 +2,9 @@ package lgfiber
 
 import (
 	"context"
-	"errors"
 
 	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
 	"github.com/getsentry/sentry-go"
 	sentryfiber "github.com/getsentry/sentry-go/fiber"
 	"github.com/gofiber/fiber/v2"
@@ -12,59 +12,45 @@ import (
 
 // ErrorHandler is the main Fiber error handler
 // Catches errors, logs them, and sends to Sentry if appropriate
+// Client aborts (see IsClientAbort) are logged at Info as 499 and never sent to Sentry, noise
+// (see SetNoiseConfig) at the noise level
 func ErrorHandler(c *fiber.Ctx, err error) error {
 	if err == nil {
 		return nil
 	}
 
-	// Try to extract lgerr.Error
-	var lgErr *lgerr.Error
-	if !errors.As(err, &lgErr) {
-		// Not an lgerr.Error - convert to lgerr.Internal for consistent handling
-		code := fiber.StatusInternalServerError
-		var fiberErr *fiber.Error
-		if errors.As(err, &fiberErr) {
-			code = fiberErr.Code
-		}
-
-		// Create lgerr.Error from generic error
-		lgErr = lgerr.Internal(err.Error()).
-			Wrap(err).
-			WithHTTPStatus(code)
-
-		// Map common HTTP status codes to appropriate error types
-		if code == fiber.StatusNotFound {
-			lgErr.WithType(lgerr.TypeNotFound).WithTitle("Not Found")
-		} else if code >= 500 {
-			lgErr.WithTitle("Internal Server Error")
-		} else if code >= 400 {
-			lgErr.WithTitle("Bad Request")
-		}
-
-		// Continue with normal lgerr.Error handling flow
+	if IsClientAbort(c, err) {
+		return handleClientAbort(c, err)
+	}
+
+	// Not an lgerr.Error: translate well-known errors (fiber, sql, validator, ...) for consistent handling
+	lgErr := lgerr.FromError(err)
+
+	// Scanner and browser probes (see SetNoiseConfig): quiet log, never Sentry
+	if level, ok := noiseLevel(c, lgErr.HTTPStatus()); ok {
+		logNoise(c, lgErr, level)
+		return c.Status(lgErr.HTTPStatus()).JSON(lgreport.ErrorResponse(lgErr))
 	}
 
 	// Handle lgerr.Error
 	var sentryEventID *sentry.EventID
+	req := reportRequest(c)
 
-	// Lightweight pre-check first
-	if shouldSendToSentryLazy(lgErr) {
-		// Only fetch hub if pre-check passed
-		hub := sentryfiber.GetHubFromContext(c)
-		if shouldSendToSentry(lgErr, hub) {
-			sentryEventID = captureToSentry(c.UserContext(), hub, lgErr, "error_handler", c)
-		}
+	// Lightweight pre-check first, the hub is only fetched when it passed
+	if lgreport.ShouldCapture(c.UserContext(), lgErr) {
+		sentryEventID = lgreport.Capture(c.UserContext(), sentryfiber.GetHubFromContext(c), lgErr, "error_handler", req)
 	}
 
 	// Log the error
-	logError(c.UserContext(), lgErr, sentryEventID, c)
+	lgreport.Log(c.UserContext(), lgErr, sentryEventID, req)
 
 	// Return error response
-	return c.Status(lgErr.HTTPStatus()).JSON(lgErr.ToErrorResponse())
+	return c.Status(lgErr.HTTPStatus()).JSON(lgreport.ErrorResponse(lgErr))
 }
 
 // HandleError manually handles an lgerr.Error with logging and Sentry reporting
 // Use this for explicit error handling in goroutines or background tasks
+// It is lgreport.HandleError, which code without Fiber should use
 //
 // Example usage in goroutine:
 //
@@ -76,22 +62,7 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 //	    }
 //	}()
 func HandleError(ctx context.Context, lgErr *lgerr.Error) *sentry.EventID {
-	if lgErr == nil {
-		return nil
-	}
-
-	hub := sentry.GetHubFromContext(ctx)
-	var sentryEventID *sentry.EventID
-
-	// Send to Sentry if appropriate
-	if shouldSendToSentry(lgErr, hub) {
-		sentryEventID = captureToSentry(ctx, hub, lgErr, "manual_handle", nil)
-	}
-
-	// Log the error
-	logError(ctx, lgErr, sentryEventID, nil)
-
-	return sentryEventID
+	return lgreport.HandleError(ctx, lgErr)
 }
 
 // HandleErrorWithFiber manually handles an lgerr.Error with full Fiber context
@@ -115,16 +86,16 @@ func HandleErrorWithFiber(c *fiber.Ctx, lgErr *lgerr.Error) *sentry.EventID {
 		return nil
 	}
 
-	hub := sentryfiber.GetHubFromContext(c)
 	var sentryEventID *sentry.EventID
+	req := reportRequest(c)
 
 	// Send to Sentry if appropriate with full Fiber context
-	if shouldSendToSentry(lgErr, hub) {
-		sentryEventID = captureToSentry(c.UserContext(), hub, lgErr, "manual_fiber_handle", c)
+	if lgreport.ShouldCapture(c.UserContext(), lgErr) {
+		sentryEventID = lgreport.Capture(c.UserContext(), sentryfiber.GetHubFromContext(c), lgErr, "manual_fiber_handle", req)
 	}
 
 	// Log the error with Fiber context
-	logError(c.UserContext(), lgErr, sentryEventID, c)
+	lgreport.Log(c.UserContext(), lgErr, sentryEventID, req)
 
 	return sentryEventID
 }
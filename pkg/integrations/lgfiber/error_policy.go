@@ -0,0 +1,40 @@
+package lgfiber
+
+import (
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+)
+
+// ErrorPolicy controls how ErrorHandler logs an error and what it returns to the client (see lgreport.ErrorPolicy)
+type ErrorPolicy = lgreport.ErrorPolicy
+
+// ErrorPolicies selects the ErrorPolicy of an error by lgerr type or HTTP status (see lgreport.ErrorPolicies)
+type ErrorPolicies = lgreport.ErrorPolicies
+
+// SetErrorPolicies sets the policies used by ErrorHandler, HandleError and HandleErrorWithFiber
+// It is lgreport.SetErrorPolicies: the policies are shared with the other integrations
+//
+// Usage:
+//
+//	lgfiber.SetErrorPolicies(lgfiber.ErrorPolicies{
+//	    ByType: map[lgerr.ErrorType]lgfiber.ErrorPolicy{
+//	        lgerr.TypeValidation: {Silent: true},
+//	        lgerr.TypeInternal:   {HideContext: true},
+//	    },
+//	    ByStatus: map[int]lgfiber.ErrorPolicy{
+//	        fiber.StatusNotFound: {Level: slog.LevelDebug},
+//	    },
+//	})
+func SetErrorPolicies(p ErrorPolicies) {
+	lgreport.SetErrorPolicies(p)
+}
+
+// ResetErrorPolicies restores the default logging and response behavior
+func ResetErrorPolicies() {
+	lgreport.ResetErrorPolicies()
+}
+
+// ErrorResponse returns the client response of lgErr under the configured policies (see lgreport.ErrorResponse)
+func ErrorResponse(lgErr *lgerr.Error) lgerr.ErrorResponse {
+	return lgreport.ErrorResponse(lgErr)
+}
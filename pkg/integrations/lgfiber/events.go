@@ -0,0 +1,266 @@
+package lgfiber
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Event is a domain event emitted by a handler with EmitEvent
+type Event struct {
+	Name       string         `json:"name"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Time       time.Time      `json:"time"`
+	TraceID    string         `json:"trace_id,omitempty"`
+	Method     string         `json:"method"`
+	Route      string         `json:"route"`
+}
+
+// EventSink delivers the events of one request (Kafka producer, analytics HTTP API, log stream)
+type EventSink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// EventSinkFunc adapts a function to EventSink
+//
+// Usage:
+//
+//	sink := lgfiber.EventSinkFunc(func(ctx context.Context, events []lgfiber.Event) error {
+//	    return producer.Produce(ctx, "analytics", events)
+//	})
+type EventSinkFunc func(ctx context.Context, events []Event) error
+
+// Send calls f
+func (f EventSinkFunc) Send(ctx context.Context, events []Event) error {
+	return f(ctx, events)
+}
+
+// LogEventSink writes every event as an Info record "Domain event" with its name and properties
+type LogEventSink struct {
+	logger *slog.Logger
+}
+
+// NewLogEventSink creates a sink logging to logger (nil uses the middleware logger)
+func NewLogEventSink(logger *slog.Logger) *LogEventSink {
+	return &LogEventSink{logger: logger}
+}
+
+// Send logs the events
+func (s *LogEventSink) Send(ctx context.Context, events []Event) error {
+	log := s.logger
+	if log == nil {
+		log = getMiddlewareLogger(ctx)
+	}
+	for _, e := range events {
+		log.InfoContext(ctx, "Domain event",
+			slog.String("event", e.Name),
+			slog.String("route", e.Route),
+			slog.Any("properties", e.Properties),
+		)
+	}
+	return nil
+}
+
+// HTTPEventSink posts the events of a request as one JSON array
+type HTTPEventSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPEventSink creates a sink posting to url (nil client uses a client with a 10s timeout)
+func NewHTTPEventSink(url string, client *http.Client) *HTTPEventSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPEventSink{url: url, client: client}
+}
+
+// Send posts the events; a non-2xx response is an error
+func (s *HTTPEventSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event sink: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EventsConfig holds configuration for EventsMiddleware
+type EventsConfig struct {
+	// Sink receives the events of each request (default: NewLogEventSink(nil))
+	Sink EventSink
+	// DiscardOnError drops the events of requests that returned an error or a 5xx status
+	DiscardOnError bool
+	// Async hands the events to a background worker after the response instead of delaying it
+	Async bool
+	// QueueSize bounds the requests waiting for the Async worker; when it is full their events are
+	// dropped and counted in events_emitted_total{result="dropped"} (default: 1000)
+	QueueSize int
+	// MaxEvents bounds the events collected per request; later ones are dropped (default: 100)
+	MaxEvents int
+}
+
+// eventBatch is the events of one request waiting for the Async worker
+type eventBatch struct {
+	ctx    context.Context
+	events []Event
+}
+
+// eventCollector holds the events of one request
+type eventCollector struct {
+	mu      sync.Mutex
+	events  []Event
+	dropped int
+	max     int
+}
+
+// EventsMiddleware collects the domain events emitted with EmitEvent during a request and flushes
+// them to the sink once the handler chain returns, each carrying the trace ID, method and route,
+// so product analytics need no extra SDK in handlers. Sink failures are logged at Warn and counted
+// in events_flush_failures_total
+//
+// Usage:
+//
+//	app.Use(lgfiber.TraceIDMiddleware())
+//	app.Use(lgfiber.EventsMiddleware(lgfiber.EventsConfig{
+//	    Sink:  lgfiber.NewHTTPEventSink("https://analytics.internal/v1/events", nil),
+//	    Async: true,
+//	}))
+func EventsMiddleware(cfg ...EventsConfig) fiber.Handler {
+	var config EventsConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.Sink == nil {
+		config.Sink = NewLogEventSink(nil)
+	}
+	if config.MaxEvents <= 0 {
+		config.MaxEvents = 100
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+
+	var queue chan eventBatch
+	if config.Async {
+		// A single worker, so a slow sink backs up the queue rather than piling up goroutines
+		queue = make(chan eventBatch, config.QueueSize)
+		go func() {
+			for batch := range queue {
+				flushEvents(batch.ctx, config.Sink, batch.events)
+			}
+		}()
+	}
+
+	return func(c *fiber.Ctx) error {
+		collector := &eventCollector{max: config.MaxEvents}
+		c.Locals("event_collector", collector)
+
+		err := c.Next()
+
+		collector.mu.Lock()
+		events, dropped := collector.events, collector.dropped
+		collector.events = nil
+		collector.mu.Unlock()
+
+		if len(events) == 0 {
+			return err
+		}
+		if config.DiscardOnError && (err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError) {
+			return err
+		}
+
+		ctx := c.UserContext()
+		if dropped > 0 {
+			getMiddlewareLogger(ctx).WarnContext(ctx, "Domain events dropped",
+				slog.Int("dropped", dropped),
+				slog.Int("max_events", config.MaxEvents),
+			)
+		}
+
+		if !config.Async {
+			flushEvents(ctx, config.Sink, events)
+			return err
+		}
+
+		select {
+		// The request context ends with the response
+		case queue <- eventBatch{ctx: context.WithoutCancel(ctx), events: events}:
+		default:
+			metrics.AddCounter("events_emitted_total", metrics.Labels{"result": "dropped"}, float64(len(events)))
+		}
+		return err
+	}
+}
+
+// flushEvents sends events to sink and reports failures
+func flushEvents(ctx context.Context, sink EventSink, events []Event) {
+	err := sink.Send(ctx, events)
+	result := "success"
+	if err != nil {
+		result = "failure"
+		metrics.IncCounter("events_flush_failures_total", nil)
+		getMiddlewareLogger(ctx).WarnContext(ctx, "Failed to flush domain events",
+			slog.Int("events", len(events)),
+			slog.String("error", err.Error()),
+		)
+	}
+	metrics.AddCounter("events_emitted_total", metrics.Labels{"result": result}, float64(len(events)))
+}
+
+// EmitEvent records a domain event for the current request, flushed by EventsMiddleware at request end
+// Returns false when EventsMiddleware is not registered for the route (the event is discarded)
+// Events may be sent after the request, so props must not hold strings from c (c.Params, c.Get, ...)
+// without utils.CopyString, as fasthttp reuses their memory for the next request
+//
+// Usage:
+//
+//	lgfiber.EmitEvent(c, "order_placed", map[string]any{"order_id": order.ID, "total": order.Total})
+func EmitEvent(c *fiber.Ctx, name string, props map[string]any) bool {
+	collector, ok := c.Locals("event_collector").(*eventCollector)
+	if !ok {
+		return false
+	}
+
+	event := Event{
+		Name:       name,
+		Properties: props,
+		Time:       core.Now(),
+		TraceID:    GetTraceID(c),
+		Method:     utils.CopyString(c.Method()),
+		Route:      c.Route().Path,
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.events) >= collector.max {
+		collector.dropped++
+		return false
+	}
+	collector.events = append(collector.events, event)
+	return true
+}
@@ -0,0 +1,82 @@
+package lgfiber
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+func TestEventsMiddlewareAsyncQueueBounded(t *testing.T) {
+	registry := metrics.NewRegistry()
+	previous := metrics.GetRecorder()
+	metrics.SetRecorder(registry)
+	defer metrics.SetRecorder(previous)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	sink := EventSinkFunc(func(context.Context, []Event) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})
+
+	app := fiber.New()
+	app.Use(EventsMiddleware(EventsConfig{Sink: sink, Async: true, QueueSize: 1}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		EmitEvent(c, "order_placed", nil)
+		return nil
+	})
+	request := func() {
+		if _, err := app.Test(httptest.NewRequest("GET", "/", nil), -1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request()
+	select {
+	case <-started: // The worker is busy with the first request
+	case <-time.After(time.Second):
+		t.Fatal("events not flushed")
+	}
+	request() // Queued
+	request() // Queue full: dropped
+
+	var dropped float64
+	for _, s := range registry.Snapshot() {
+		if s.Name == "events_emitted_total" && s.Labels["result"] == "dropped" {
+			dropped = s.Value
+		}
+	}
+	if dropped != 1 {
+		t.Fatalf("dropped %v events, want 1", dropped)
+	}
+}
+
+func TestEmitEventRequestFields(t *testing.T) {
+	var events []Event
+	app := fiber.New()
+	app.Use(EventsMiddleware(EventsConfig{Sink: EventSinkFunc(func(_ context.Context, e []Event) error {
+		events = e
+		return nil
+	})}))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		EmitEvent(c, "order_placed", nil)
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest("POST", "/orders", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Method != "POST" || events[0].Route != "/orders" {
+		t.Fatalf("events = %+v", events)
+	}
+}
@@ -0,0 +1,174 @@
+package lgfiber
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgsentry"
+)
+
+// FeedbackConfig holds configuration for the user feedback handler
+type FeedbackConfig struct {
+	// Logger instance for forwarding failures (if nil, uses middleware logger)
+	Logger *slog.Logger
+	// RateLimit is the maximum number of reports per client IP per RateWindow (default: 5)
+	RateLimit int
+	// RateWindow is the rate limiting window (default: 1 minute)
+	RateWindow time.Duration
+	// MaxCommentLength limits the comments field (default: 5000)
+	MaxCommentLength int
+}
+
+// feedbackRequest is the payload accepted by FeedbackHandler
+type feedbackRequest struct {
+	EventID  string `json:"event_id" validate:"required,len=32,hexadecimal"`
+	Name     string `json:"name" validate:"max=128"`
+	Email    string `json:"email" validate:"omitempty,email,max=254"`
+	Comments string `json:"comments" validate:"required"`
+}
+
+// FeedbackHandler returns a handler that forwards "report this error" submissions to Sentry's user feedback API
+// The payload references the event ID returned with the error (see the sentry_event_id log field):
+//
+//	{"event_id": "fc6d8c0c43fc4630ad850ee518f1b9d0", "name": "Jane", "email": "jane@example.com", "comments": "Clicked save"}
+//
+// Responds 204 on success, 422 for invalid payloads, 429 when the client exceeds the rate limit and
+// 503 when Sentry is not enabled
+//
+// Usage:
+//
+//	app.Post("/api/feedback", lgfiber.FeedbackHandler())
+func FeedbackHandler(cfg ...FeedbackConfig) fiber.Handler {
+	var config FeedbackConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = 5
+	}
+	if config.RateWindow <= 0 {
+		config.RateWindow = time.Minute
+	}
+	if config.MaxCommentLength <= 0 {
+		config.MaxCommentLength = 5000
+	}
+
+	limiter := newFeedbackLimiter(config.RateLimit, config.RateWindow)
+
+	return func(c *fiber.Ctx) error {
+		if !limiter.allow(c.IP(), core.Now()) {
+			return c.Status(http.StatusTooManyRequests).JSON(lgerr.ErrorResponse{
+				Title:  "Too Many Requests",
+				Detail: "Too many feedback reports, please try again later",
+			})
+		}
+
+		var req feedbackRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(lgerr.ErrorResponse{
+				Title:  "Invalid Request Format",
+				Detail: "Failed to parse request: " + err.Error(),
+			})
+		}
+
+		// Accept UUID formatting of event IDs
+		req.EventID = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(req.EventID), "-", ""))
+		req.Comments = strings.TrimSpace(req.Comments)
+
+		var validationErrors []lgerr.ValidationError
+		if err := getDefaultValidator().Struct(req); err != nil {
+			validationErrors = parseValidationErrors(err, req)
+		}
+		if len(req.Comments) > config.MaxCommentLength {
+			validationErrors = append(validationErrors, lgerr.ValidationError{
+				Field:   "comments",
+				Message: "comments is too long",
+			})
+		}
+		if len(validationErrors) > 0 {
+			return c.Status(http.StatusUnprocessableEntity).JSON(lgerr.ErrorResponse{
+				Title:  "Invalid Feedback",
+				Errors: validationErrors,
+			})
+		}
+
+		err := lgsentry.CaptureUserFeedback(c.UserContext(), lgsentry.UserFeedback{
+			EventID:  req.EventID,
+			Name:     req.Name,
+			Email:    req.Email,
+			Comments: req.Comments,
+		})
+		if errors.Is(err, lgsentry.ErrFeedbackUnavailable) {
+			return lgerr.Busy("user feedback is not available").IgnoreSentry()
+		}
+		if err != nil {
+			log := config.Logger
+			if log == nil {
+				log = getMiddlewareLogger(c.UserContext())
+			}
+			logger.LogNoSourceCtx(c.UserContext(), log, slog.LevelWarn, "Failed to forward user feedback",
+				slog.String("event_id", req.EventID),
+				slog.String("error", err.Error()),
+			)
+			return lgerr.External("sentry", "failed to forward feedback").Wrap(err).IgnoreSentry()
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	}
+}
+
+// feedbackLimiter is a fixed-window per-key rate limiter
+type feedbackLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*feedbackWindow
+}
+
+type feedbackWindow struct {
+	start time.Time
+	count int
+}
+
+func newFeedbackLimiter(limit int, window time.Duration) *feedbackLimiter {
+	return &feedbackLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*feedbackWindow, 64),
+	}
+}
+
+// allow reports whether key may submit another report
+func (l *feedbackLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		// Drop expired windows occasionally to stay bounded
+		if len(l.windows) >= 10000 {
+			for k, old := range l.windows {
+				if now.Sub(old.start) >= l.window {
+					delete(l.windows, k)
+				}
+			}
+		}
+		l.windows[key] = &feedbackWindow{start: now, count: 1}
+		return true
+	}
+
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
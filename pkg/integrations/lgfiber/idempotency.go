@@ -0,0 +1,200 @@
+package lgfiber
+
+import (
+	"container/list"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/internal/strutil"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// IdempotencyStore records idempotency keys and reports whether a key was already seen
+// Implement this interface to share state between instances (e.g. Redis)
+type IdempotencyStore interface {
+	// Seen records the key and returns the time it was first seen and whether it is a duplicate
+	// within the given window
+	Seen(key string, window time.Duration) (firstSeen time.Time, duplicate bool)
+}
+
+// IdempotencyConfig holds configuration for idempotency middleware
+type IdempotencyConfig struct {
+	// Logger instance for duplicate logging (if nil, uses middleware logger)
+	Logger *slog.Logger
+	// Store used to detect duplicates (if nil, uses an in-memory store)
+	Store IdempotencyStore
+	// Header name carrying the idempotency key (default: "Idempotency-Key")
+	Header string
+	// Window in which a repeated key is considered a duplicate (default: 24h)
+	Window time.Duration
+	// Scope returns the namespace of the request's keys, so a key reused on another endpoint is not a
+	// duplicate (default: method and path, e.g. "POST /orders"); return "" to share keys across routes
+	Scope func(c *fiber.Ctx) string
+}
+
+// sentryTagMaxLength is the longest tag value Sentry accepts, and so the longest idempotency key kept
+const sentryTagMaxLength = 200
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore suitable for single-instance deployments
+// When full, the least recently seen key is evicted
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	keys    map[string]*list.Element
+	order   *list.List // Front is most recently seen
+	maxSize int
+}
+
+type idempotencyEntry struct {
+	key       string
+	firstSeen time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store holding at most maxSize keys
+// If maxSize <= 0, defaults to 10000
+func NewMemoryIdempotencyStore(maxSize int) *MemoryIdempotencyStore {
+	if maxSize <= 0 {
+		maxSize = 10000
+	}
+	return &MemoryIdempotencyStore{
+		keys:    make(map[string]*list.Element, 64),
+		order:   list.New(),
+		maxSize: maxSize,
+	}
+}
+
+// Seen implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Seen(key string, window time.Duration) (time.Time, bool) {
+	now := core.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		entry := el.Value.(*idempotencyEntry)
+		if now.Sub(entry.firstSeen) < window {
+			return entry.firstSeen, true
+		}
+		// Expired: the key starts a new window
+		entry.firstSeen = now
+		return now, false
+	}
+
+	for s.order.Len() >= s.maxSize {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(*idempotencyEntry).key)
+	}
+
+	s.keys[key] = s.order.PushFront(&idempotencyEntry{key: key, firstSeen: now})
+	return now, false
+}
+
+// IdempotencyMiddleware tags requests carrying an idempotency key and detects duplicates
+// The key is stored in c.Locals("idempotency_key") and added as a Sentry tag, so replayed requests (e.g.
+// webhook retries) are easy to identify in logs and Sentry events. Keys longer than Sentry's 200 character
+// tag limit are shortened to a prefix and a hash of the whole key, which is what is stored, tagged and
+// logged. Keys are scoped per method and path by default (see IdempotencyConfig.Scope)
+//
+// Usage:
+//
+//	app.Post("/webhooks/stripe", lgfiber.IdempotencyMiddleware(), handler)
+//
+//	// With a shared store and custom window
+//	app.Use(lgfiber.IdempotencyMiddleware(lgfiber.IdempotencyConfig{
+//	    Store:  redisStore,
+//	    Window: time.Hour,
+//	}))
+func IdempotencyMiddleware(cfg ...IdempotencyConfig) fiber.Handler {
+	var c IdempotencyConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Header == "" {
+		c.Header = "Idempotency-Key"
+	}
+	if c.Window <= 0 {
+		c.Window = 24 * time.Hour
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryIdempotencyStore(0)
+	}
+	if c.Scope == nil {
+		c.Scope = func(ctx *fiber.Ctx) string {
+			return ctx.Method() + " " + ctx.Path()
+		}
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		header := ctx.Get(c.Header)
+		if header == "" {
+			return ctx.Next()
+		}
+
+		// Fiber reuses the header buffer, so the key is copied
+		key := capIdempotencyKey(strings.Clone(header))
+		storeKey := key
+		if scope := c.Scope(ctx); scope != "" {
+			storeKey = scope + "\x00" + key
+		}
+		firstSeen, duplicate := c.Store.Seen(storeKey, c.Window)
+		ctx.Locals("idempotency_key", key)
+		ctx.Locals("idempotency_duplicate", duplicate)
+
+		if config.FromContext(ctx.UserContext()).SentryEnabled() {
+			if hub := sentryfiber.GetHubFromContext(ctx); hub != nil {
+				hub.Scope().SetTag("idempotency_key", key)
+				if duplicate {
+					hub.Scope().SetTag("idempotency_duplicate", "true")
+					hub.Scope().SetContext("idempotency", map[string]any{
+						"key":        key,
+						"first_seen": firstSeen,
+					})
+				}
+			}
+		}
+
+		if duplicate {
+			log := c.Logger
+			if log == nil {
+				log = getMiddlewareLogger(ctx.UserContext())
+			}
+			logger.LogNoSourceCtx(ctx.UserContext(), log, slog.LevelInfo, "Duplicate idempotency key",
+				slog.String("idempotency_key", key),
+				slog.Time("first_seen", firstSeen),
+				slog.String("method", ctx.Method()),
+				slog.String("route", matchedRoutePattern(ctx)),
+			)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// capIdempotencyKey shortens a key longer than sentryTagMaxLength to a prefix and a hash of the whole key,
+// so distinct long keys stay distinct
+func capIdempotencyKey(key string) string {
+	if utf8.RuneCountInString(key) <= sentryTagMaxLength {
+		return key
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := strconv.FormatUint(h.Sum64(), 16)
+	return strutil.Truncate(key, sentryTagMaxLength-len(sum)-1) + "~" + sum
+}
+
+// IsDuplicateRequest reports whether IdempotencyMiddleware marked the current request as a duplicate
+func IsDuplicateRequest(c *fiber.Ctx) bool {
+	duplicate, _ := c.Locals("idempotency_duplicate").(bool)
+	return duplicate
+}
@@ -0,0 +1,106 @@
+package lgfiber
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+func TestMemoryIdempotencyStoreEvictsLeastRecentlySeen(t *testing.T) {
+	store := NewMemoryIdempotencyStore(2)
+	store.Seen("a", time.Hour)
+	store.Seen("b", time.Hour)
+	store.Seen("a", time.Hour) // "b" is now the least recently seen
+	store.Seen("c", time.Hour)
+
+	tests := []struct {
+		key       string
+		duplicate bool
+	}{
+		{key: "a", duplicate: true},
+		{key: "c", duplicate: true},
+		{key: "b", duplicate: false},
+	}
+	for _, tt := range tests {
+		if _, duplicate := store.Seen(tt.key, time.Hour); duplicate != tt.duplicate {
+			t.Fatalf("Seen(%q) duplicate = %v, want %v", tt.key, duplicate, tt.duplicate)
+		}
+	}
+}
+
+func TestIdempotencyMiddlewareScopesKeysPerRoute(t *testing.T) {
+	hub := sentry.NewHub(nil, sentry.NewScope())
+	settings := config.NewSettings()
+	settings.SetSentryEnabled(true)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(config.WithSettings(c.UserContext(), settings))
+		sentryfiber.SetHubOnContext(c, hub)
+		return c.Next()
+	})
+	var buf bytes.Buffer
+	app.Use(IdempotencyMiddleware(IdempotencyConfig{Logger: slog.New(slog.NewTextHandler(&buf, nil))}))
+	handler := func(c *fiber.Ctx) error {
+		if IsDuplicateRequest(c) {
+			return c.SendStatus(fiber.StatusConflict)
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	}
+	app.Post("/orders", handler)
+	app.Post("/refunds", handler)
+
+	longKey := strings.Repeat("k", 300)
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{path: "/orders", status: fiber.StatusCreated},
+		{path: "/refunds", status: fiber.StatusCreated},
+		{path: "/orders", status: fiber.StatusConflict},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		req.Header.Set("Idempotency-Key", longKey)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("POST %s: status = %d, want %d", tt.path, resp.StatusCode, tt.status)
+		}
+	}
+
+	event := hub.Scope().ApplyToEvent(sentry.NewEvent(), nil, nil)
+	tag := event.Tags["idempotency_key"]
+	if len(tag) != sentryTagMaxLength {
+		t.Fatalf("idempotency_key tag has %d characters, want %d", len(tag), sentryTagMaxLength)
+	}
+	if key := event.Contexts["idempotency"]["key"]; key != tag {
+		t.Fatalf("idempotency context key = %v, want the tagged key", key)
+	}
+	if !strings.Contains(buf.String(), "route=/orders") {
+		t.Fatalf("duplicate not logged with the route pattern:\n%s", buf.String())
+	}
+}
+
+func TestCapIdempotencyKeyKeepsLongKeysDistinct(t *testing.T) {
+	a := capIdempotencyKey(strings.Repeat("k", 250) + "a")
+	b := capIdempotencyKey(strings.Repeat("k", 250) + "b")
+	if a == b {
+		t.Fatalf("keys differing after the cap collide: %q", a)
+	}
+	if key := "short-key"; capIdempotencyKey(key) != key {
+		t.Fatalf("short key changed to %q", capIdempotencyKey(key))
+	}
+}
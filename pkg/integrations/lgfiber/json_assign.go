@@ -0,0 +1,332 @@
+package lgfiber
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	// jsonFieldsCache maps a struct type to its JSON fields (lowercased names) for assignJSON
+	jsonFieldsCache = newTypeCache[map[string]jsonField](typeCacheMaxSize)
+)
+
+// jsonField locates a struct field decoded from a JSON property
+type jsonField struct {
+	index  []int
+	quoted bool // ",string" option
+}
+
+// assignJSON stores a value decoded into `any` (with json.Decoder.UseNumber) in v following the rules of
+// encoding/json, so a body parsed once can also fill a typed value. Types with custom decoding
+// (json.Unmarshaler, encoding.TextUnmarshaler) decode their own subtree. Like json.Unmarshal, it keeps
+// going after a type mismatch and returns the first one. Keys differing only in case resolve in sorted
+// rather than document order, since the generic value no longer has one
+func assignJSON(raw any, v reflect.Value) error {
+	if raw == nil {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assignJSON(raw, v.Elem())
+	}
+	if v.CanAddr() {
+		switch u := v.Addr().Interface().(type) {
+		case json.Unmarshaler:
+			data, err := json.Marshal(raw)
+			if err != nil {
+				return err
+			}
+			return u.UnmarshalJSON(data)
+		case encoding.TextUnmarshaler:
+			if s, ok := raw.(string); ok {
+				return u.UnmarshalText([]byte(s))
+			}
+		}
+	}
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		v.Set(reflect.ValueOf(plainJSON(raw)))
+		return nil
+	}
+
+	switch value := raw.(type) {
+	case map[string]any:
+		return assignJSONObject(value, v)
+	case []any:
+		return assignJSONArray(value, v)
+	case json.Number:
+		return assignJSONNumber(value, v)
+	case string:
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(value)
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			b, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+		default:
+			return jsonMismatch("string", v)
+		}
+	case bool:
+		if v.Kind() != reflect.Bool {
+			return jsonMismatch("bool", v)
+		}
+		v.SetBool(value)
+	}
+	return nil
+}
+
+// assignJSONObject fills a struct or map from a JSON object
+func assignJSONObject(obj map[string]any, v reflect.Value) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var first error
+	keep := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := getJSONFields(v.Type())
+		for _, k := range keys {
+			f, ok := fields[strings.ToLower(k)]
+			if !ok {
+				continue
+			}
+			fv, ok := jsonFieldByIndex(v, f.index)
+			if !ok {
+				continue
+			}
+			if s, isString := obj[k].(string); f.quoted && isString {
+				if err := json.Unmarshal([]byte(s), fv.Addr().Interface()); err != nil {
+					keep(err)
+				}
+				continue
+			}
+			if err := assignJSON(obj[k], fv); err != nil {
+				keep(err)
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(obj)))
+		}
+		for _, k := range keys {
+			key, err := jsonMapKey(k, v.Type().Key())
+			if err != nil {
+				keep(err)
+				continue
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := assignJSON(obj[k], elem); err != nil {
+				keep(err)
+			}
+			v.SetMapIndex(key, elem)
+		}
+	default:
+		return jsonMismatch("object", v)
+	}
+	return first
+}
+
+// assignJSONArray fills a slice or array from a JSON array
+func assignJSONArray(arr []any, v reflect.Value) error {
+	var first error
+	switch v.Kind() {
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+		for i, item := range arr {
+			if err := assignJSON(item, s.Index(i)); err != nil && first == nil {
+				first = err
+			}
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if i >= len(arr) {
+				v.Index(i).SetZero()
+				continue
+			}
+			if err := assignJSON(arr[i], v.Index(i)); err != nil && first == nil {
+				first = err
+			}
+		}
+	default:
+		return jsonMismatch("array", v)
+	}
+	return first
+}
+
+// assignJSONNumber stores a JSON number in a numeric value, rejecting fractions and overflows
+func assignJSONNumber(num json.Number, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(num), 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return jsonMismatch("number "+string(num), v)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(string(num), 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return jsonMismatch("number "+string(num), v)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(num), v.Type().Bits())
+		if err != nil {
+			return jsonMismatch("number "+string(num), v)
+		}
+		v.SetFloat(f)
+	default:
+		return jsonMismatch("number", v)
+	}
+	return nil
+}
+
+// jsonMapKey converts an object key to a map key of type t (strings, integers or encoding.TextUnmarshaler)
+func jsonMapKey(k string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		key := reflect.New(t)
+		if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k)); err != nil {
+			return reflect.Value{}, err
+		}
+		return key.Elem(), nil
+	}
+
+	key := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(k)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(k, 10, 64)
+		if err != nil || key.OverflowInt(n) {
+			return reflect.Value{}, jsonMismatch("number "+k, key)
+		}
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || key.OverflowUint(n) {
+			return reflect.Value{}, jsonMismatch("number "+k, key)
+		}
+		key.SetUint(n)
+	default:
+		return reflect.Value{}, jsonMismatch("object key", key)
+	}
+	return key, nil
+}
+
+// jsonFieldByIndex returns the field at index, allocating nil embedded pointers on the way; false when an
+// embedded pointer cannot be set (unexported), as encoding/json skips those too
+func jsonFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// getJSONFields returns the JSON fields of a struct type, including promoted embedded fields
+func getJSONFields(t reflect.Type) map[string]jsonField {
+	if cached, ok := jsonFieldsCache.Load(t); ok {
+		return cached
+	}
+
+	fields := make(map[string]jsonField, t.NumField())
+	collectJSONFieldIndexes(t, nil, fields)
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectJSONFieldIndexes mirrors collectJSONFields with index paths; shallower fields win, as in encoding/json
+func collectJSONFieldIndexes(t reflect.Type, prefix []int, fields map[string]jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		index := append(append([]int(nil), prefix...), i)
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectJSONFieldIndexes(ft, index, fields)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := strings.ToLower(name)
+		if existing, ok := fields[key]; ok && len(existing.index) <= len(index) {
+			continue
+		}
+		fields[key] = jsonField{index: index, quoted: strings.Contains(opts, "string")}
+	}
+}
+
+// plainJSON replaces json.Number with float64, the type json.Unmarshal stores in interface values
+func plainJSON(raw any) any {
+	switch value := raw.(type) {
+	case json.Number:
+		f, _ := value.Float64()
+		return f
+	case map[string]any:
+		out := make(map[string]any, len(value))
+		for k, item := range value {
+			out[k] = plainJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(value))
+		for i, item := range value {
+			out[i] = plainJSON(item)
+		}
+		return out
+	}
+	return raw
+}
+
+// jsonMismatch describes a JSON value that does not fit the Go type of v
+func jsonMismatch(what string, v reflect.Value) error {
+	return fmt.Errorf("cannot unmarshal %s into Go value of type %s", what, v.Type())
+}
@@ -0,0 +1,139 @@
+package lgfiber
+
+import (
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// MetricsConfig holds configuration for MetricsMiddleware
+type MetricsConfig struct {
+	// ErrorMinStatus is the lowest status counted as an error (default: 500)
+	ErrorMinStatus int
+	// StatusClass labels statuses by class ("2xx", "4xx", ...) to reduce cardinality
+	StatusClass bool
+	// Skip excludes requests from metrics, in addition to the shared path filter (see SetPathFilter)
+	Skip func(c *fiber.Ctx) bool
+	// LargeRequestBytes logs a Warn for request bodies above it (0 disables)
+	LargeRequestBytes int
+	// LargeResponseBytes logs a Warn for response bodies above it (0 disables)
+	LargeResponseBytes int
+}
+
+// inFlightRequests counts requests currently handled by MetricsMiddleware
+var inFlightRequests atomic.Int64
+
+// MetricsMiddleware records RED metrics (rate, errors, duration) for every request:
+//   - http_requests_total{method, route, status}
+//   - http_request_errors_total{method, route, status} for statuses >= ErrorMinStatus
+//     (client aborts are labeled 499 and not counted as errors)
+//   - http_request_duration_ms{method, route, status} histogram
+//   - http_requests_in_flight gauge
+//   - http_request_size_bytes{method, route} and http_response_size_bytes{method, route, status} histograms
+//   - http_large_payloads_total{method, route, direction} for bodies above LargeRequestBytes or
+//     LargeResponseBytes, each also logged at Warn
+//
+// Routes are labeled with their pattern (c.Route().Path), never the raw path, to keep cardinality bounded.
+// Register it early so the duration covers the whole chain:
+//
+//	app.Use(lgfiber.MetricsMiddleware(lgfiber.MetricsConfig{
+//	    Skip: func(c *fiber.Ctx) bool { return c.Path() == "/health" },
+//	}))
+func MetricsMiddleware(cfg ...MetricsConfig) fiber.Handler {
+	var config MetricsConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.ErrorMinStatus <= 0 {
+		config.ErrorMinStatus = fiber.StatusInternalServerError
+	}
+
+	return func(c *fiber.Ctx) error {
+		if (config.Skip != nil && config.Skip(c)) || IsFilteredPath(c) {
+			return c.Next()
+		}
+
+		start := core.Now()
+		metrics.SetGauge("http_requests_in_flight", nil, float64(inFlightRequests.Add(1)))
+		defer func() {
+			metrics.SetGauge("http_requests_in_flight", nil, float64(inFlightRequests.Add(-1)))
+		}()
+
+		requestSize := payloadSize(c.Request().Header.ContentLength(), len(c.Request().Body()))
+		largeRequest := config.LargeRequestBytes > 0 && requestSize > config.LargeRequestBytes
+		if largeRequest {
+			// Logged before the handler runs, so the payload is on record even if handling it goes wrong
+			logLargePayload(c, "request", requestSize, config.LargeRequestBytes)
+		}
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		switch {
+		case err != nil && IsClientAbort(c, err):
+			status = StatusClientClosedRequest
+		case err != nil:
+			// The error handler has not written the response yet
+			status = statusFromError(err)
+		}
+
+		statusLabel := strconv.Itoa(status)
+		if config.StatusClass {
+			statusLabel = strconv.Itoa(status/100) + "xx"
+		}
+
+		labels := metrics.Labels{
+			"method": c.Method(),
+			"route":  c.Route().Path,
+			"status": statusLabel,
+		}
+
+		metrics.IncCounter("http_requests_total", labels)
+		if status >= config.ErrorMinStatus && status != StatusClientClosedRequest {
+			metrics.IncCounter("http_request_errors_total", labels)
+		}
+		metrics.Observe("http_request_duration_ms", labels, float64(core.Since(start).Microseconds())/1000)
+
+		// The route is only known once the chain has matched it
+		sizeLabels := metrics.Labels{"method": c.Method(), "route": c.Route().Path}
+		metrics.Observe("http_request_size_bytes", sizeLabels, float64(requestSize))
+		if largeRequest {
+			metrics.IncCounter("http_large_payloads_total", metrics.Labels{"method": c.Method(), "route": c.Route().Path, "direction": "request"})
+		}
+
+		responseSize := payloadSize(c.Response().Header.ContentLength(), len(c.Response().Body()))
+		metrics.Observe("http_response_size_bytes", labels, float64(responseSize))
+		if config.LargeResponseBytes > 0 && responseSize > config.LargeResponseBytes {
+			metrics.IncCounter("http_large_payloads_total", metrics.Labels{"method": c.Method(), "route": c.Route().Path, "direction": "response"})
+			logLargePayload(c, "response", responseSize, config.LargeResponseBytes)
+		}
+
+		return err
+	}
+}
+
+// payloadSize returns the body size: the Content-Length when declared (streamed bodies), else the buffered length
+func payloadSize(contentLength, buffered int) int {
+	return max(contentLength, buffered)
+}
+
+// logLargePayload logs a body above its threshold
+func logLargePayload(c *fiber.Ctx, direction string, size, threshold int) {
+	ctx := c.UserContext()
+	logger.LogNoSourceCtx(ctx, getMiddlewareLogger(ctx), slog.LevelWarn, "Large "+direction+" payload",
+		fields.Method(c.Method()),
+		fields.URL(c.OriginalURL()),
+		slog.Int("size_bytes", size),
+		slog.Int("threshold_bytes", threshold),
+		slog.String("ip", config.FromContext(ctx).ClientIP(c.IP())),
+		slog.String("user_agent", c.Get(fiber.HeaderUserAgent)),
+	)
+}
@@ -1,23 +1,24 @@
 package lgfiber
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/getsentry/sentry-go"
 	sentryfiber "github.com/getsentry/sentry-go/fiber"
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
-	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
 )
 
 func BreadcrumbsMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Skip breadcrumbs if Sentry disabled to avoid allocations
-		if !config.IsSentryEnabled() {
+		if !config.FromContext(c.UserContext()).SentryEnabled() || IsFilteredPath(c) {
 			return c.Next()
 		}
 
@@ -26,7 +27,7 @@ func BreadcrumbsMiddleware() fiber.Handler {
 			return c.Next()
 		}
 
-		startTime := time.Now()
+		startTime := core.Now()
 
 		// Add request start breadcrumb
 		hub.AddBreadcrumb(&sentry.Breadcrumb{
@@ -36,18 +37,18 @@ func BreadcrumbsMiddleware() fiber.Handler {
 			Level:     sentry.LevelInfo,
 			Timestamp: startTime,
 			Data: map[string]any{
-				"url":    c.OriginalURL(),
+				"url":    core.ScrubURL(c.OriginalURL()),
 				"method": c.Method(),
 				"path":   c.Path(),
 				"route":  c.Route().Path,
-				"ip":     c.IP(),
+				"ip":     config.FromContext(c.UserContext()).ClientIP(c.IP()),
 			},
 		}, nil)
 
 		err := c.Next()
 
 		// Add request end breadcrumb
-		duration := time.Since(startTime)
+		duration := core.Since(startTime)
 		statusCode := c.Response().StatusCode()
 
 		breadcrumbLevel := sentry.LevelInfo
@@ -62,7 +63,7 @@ func BreadcrumbsMiddleware() fiber.Handler {
 			Category:  "request.end",
 			Message:   fmt.Sprintf("%s %s - %d", c.Method(), c.Path(), statusCode),
 			Level:     breadcrumbLevel,
-			Timestamp: time.Now(),
+			Timestamp: core.Now(),
 			Data: map[string]any{
 				"status_code":   statusCode,
 				"duration_ms":   duration.Milliseconds(),
@@ -78,15 +79,11 @@ func RecoverMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		defer func() {
 			if r := recover(); r != nil {
-				// Use middleware logger if configured, otherwise fall back to internal logger
-				log := config.GetMiddlewareLogger()
-				if log == nil {
-					log = handler.GetInternalLogger()
-				}
+				log := getMiddlewareLogger(c.UserContext())
 
 				log.Error("Panic recovered",
 					slog.String("panic", fmt.Sprintf("%v", r)),
-					slog.String("url", c.OriginalURL()),
+					slog.String("url", core.ScrubURL(c.OriginalURL())),
 					slog.String("method", c.Method()),
 				)
 
@@ -121,7 +118,7 @@ func AddBreadcrumb(c *fiber.Ctx, category, message string, level sentry.Level, d
 		Category:  category,
 		Message:   message,
 		Level:     level,
-		Timestamp: time.Now(),
+		Timestamp: core.Now(),
 		Data:      data,
 	}, nil)
 }
@@ -143,3 +140,24 @@ func SetContext(c *fiber.Ctx, key string, value map[string]any) {
 	}
 	hub.Scope().SetContext(key, value)
 }
+
+// getMiddlewareLogger returns the middleware logger of the context's settings if configured,
+// otherwise the internal logger
+func getMiddlewareLogger(ctx context.Context) *slog.Logger {
+	return lgreport.Logger(ctx)
+}
+
+// SettingsMiddleware attaches isolated settings (e.g. from logbundle.LogBundle.Settings) to each request,
+// so logbundle middlewares and the error handler use its logger and Sentry options instead of the globals
+// Register it before other logbundle middlewares
+//
+// Usage:
+//
+//	bundle := logbundle.NewBuilder().WithLevel(slog.LevelInfo).Build()
+//	app.Use(lgfiber.SettingsMiddleware(bundle.Settings()))
+func SettingsMiddleware(s *config.Settings) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(config.WithSettings(c.UserContext(), s))
+		return c.Next()
+	}
+}
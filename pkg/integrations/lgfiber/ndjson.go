@@ -0,0 +1,212 @@
+package lgfiber
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// NDJSONConfig holds configuration for NDJSON stream validation
+type NDJSONConfig struct {
+	// Logger for progress and summary logs (if nil, uses validation logger or middleware logger)
+	Logger *slog.Logger
+	// Validator instance (if nil, uses default validator)
+	Validator *validator.Validate
+	// MaxLineBytes is the maximum size of a single record (default: 1 MiB)
+	MaxLineBytes int
+	// MaxRecords rejects streams with more records (0 means unlimited)
+	MaxRecords int
+	// MaxErrors is the number of invalid records reported in detail; later ones are only counted (default: 100)
+	MaxErrors int
+	// ProgressEvery logs progress after this many records (default: 10000, negative disables)
+	ProgressEvery int
+}
+
+// NDJSONRecordError describes an invalid record by its zero-based index in the stream
+type NDJSONRecordError struct {
+	Index  int
+	Errors []lgerr.ValidationError
+}
+
+// NDJSONSummary is the outcome of processing a stream
+type NDJSONSummary struct {
+	Records int                 // Non-empty lines read
+	Valid   int                 // Records passed to the handler
+	Invalid int                 // Records that failed decoding or validation
+	Errors  []NDJSONRecordError // Details of the first MaxErrors invalid records
+}
+
+// ValidateNDJSON reads a newline-delimited JSON request body record by record, validating each one like
+// BodyValidationMiddleware (defaults, `validate` tags) and calling fn for every valid record
+// Invalid records are skipped and collected by index. If any record was invalid, the returned error is an
+// lgerr validation error (422) whose field errors are prefixed with the record index ("[12].email").
+// An error returned by fn stops processing and is returned as is
+// Works with fiber's StreamRequestBody, so large imports are not buffered in memory
+//
+// Usage:
+//
+//	app.Post("/import", func(c *fiber.Ctx) error {
+//	    summary, err := lgfiber.ValidateNDJSON(c, func(i int, u UserRecord) error {
+//	        return store.Insert(c.UserContext(), u)
+//	    })
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return c.JSON(fiber.Map{"imported": summary.Valid})
+//	})
+func ValidateNDJSON[T any](c *fiber.Ctx, fn func(index int, record T) error, cfg ...NDJSONConfig) (NDJSONSummary, error) {
+	var config NDJSONConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.Validator == nil {
+		config.Validator = getDefaultValidator()
+	}
+	if config.MaxLineBytes <= 0 {
+		config.MaxLineBytes = 1 << 20
+	}
+	if config.MaxErrors <= 0 {
+		config.MaxErrors = 100
+	}
+	if config.ProgressEvery == 0 {
+		config.ProgressEvery = 10000
+	}
+	if config.Logger == nil {
+		if config.Logger = GetValidationLogger(); config.Logger == nil {
+			config.Logger = getMiddlewareLogger(c.UserContext())
+		}
+	}
+	// A broken `default` tag is a server bug, not an invalid record (RegisterDTO reports it at startup)
+	if err := checkDefaults(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return NDJSONSummary{}, lgerr.Internal("invalid DTO defaults").Wrap(err)
+	}
+
+	var body io.Reader = c.Request().BodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, min(config.MaxLineBytes, 64<<10)), config.MaxLineBytes)
+
+	var summary NDJSONSummary
+	var failures []validationFailure
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		index := summary.Records
+		summary.Records++
+		if config.MaxRecords > 0 && summary.Records > config.MaxRecords {
+			return summary, lgerr.BadInput(fmt.Sprintf("stream exceeds the limit of %d records", config.MaxRecords))
+		}
+
+		var record T
+		recordErrors := decodeNDJSONRecord(line, &record, config.Validator)
+		if len(recordErrors) > 0 {
+			summary.Invalid++
+			for _, ve := range recordErrors {
+				failures = append(failures, validationFailure{field: ve.Field, tag: "invalid"})
+			}
+			if len(summary.Errors) < config.MaxErrors {
+				summary.Errors = append(summary.Errors, NDJSONRecordError{Index: index, Errors: recordErrors})
+			}
+		} else {
+			summary.Valid++
+			if err := fn(index, record); err != nil {
+				return summary, err
+			}
+		}
+
+		if config.ProgressEvery > 0 && summary.Records%config.ProgressEvery == 0 {
+			logger.LogNoSourceCtx(c.UserContext(), config.Logger, slog.LevelInfo, "NDJSON stream progress",
+				slog.String("route", c.Route().Path),
+				slog.Int("records", summary.Records),
+				slog.Int("invalid", summary.Invalid),
+			)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return summary, lgerr.BadInput(fmt.Sprintf("record %d exceeds %d bytes", summary.Records, config.MaxLineBytes))
+		}
+		return summary, err
+	}
+
+	recordValidationFailures(c, "ndjson", failures)
+
+	logger.LogNoSourceCtx(c.UserContext(), config.Logger, slog.LevelInfo, "NDJSON stream processed",
+		slog.String("route", c.Route().Path),
+		slog.Int("records", summary.Records),
+		slog.Int("valid", summary.Valid),
+		slog.Int("invalid", summary.Invalid),
+	)
+
+	if summary.Invalid == 0 {
+		return summary, nil
+	}
+	return summary, summary.Err()
+}
+
+// Err returns the aggregated validation error for the invalid records, or nil if all were valid
+func (s NDJSONSummary) Err() error {
+	if s.Invalid == 0 {
+		return nil
+	}
+
+	var errs []lgerr.ValidationError
+	for _, re := range s.Errors {
+		prefix := "[" + strconv.Itoa(re.Index) + "]"
+		for _, ve := range re.Errors {
+			if ve.Field != "" {
+				ve.Field = prefix + "." + ve.Field
+			} else {
+				ve.Field = prefix
+			}
+			errs = append(errs, ve)
+		}
+	}
+
+	return lgerr.Validation(fmt.Sprintf("%d of %d records are invalid", s.Invalid, s.Records),
+		lgerr.WithValidationErrs(errs),
+		lgerr.WithHTTPStatusOpt(fiber.StatusUnprocessableEntity),
+		lgerr.WithContext("records", s.Records),
+		lgerr.WithContext("invalid_records", s.Invalid),
+	)
+}
+
+// decodeNDJSONRecord decodes and validates one record, returning its field errors
+func decodeNDJSONRecord[T any](line []byte, record *T, v *validator.Validate) []lgerr.ValidationError {
+	if err := json.Unmarshal(line, record); err != nil {
+		if typeErrors := parseBodyTypeError(err); len(typeErrors) > 0 {
+			return typeErrors
+		}
+		return []lgerr.ValidationError{{Message: "Invalid JSON: " + err.Error()}}
+	}
+
+	if err := applyDefaults(record); err != nil {
+		return []lgerr.ValidationError{{Message: err.Error()}}
+	}
+
+	if err := v.Struct(*record); err != nil {
+		if errs := parseValidationErrors(err, *record); len(errs) > 0 {
+			return errs
+		}
+	}
+	return nil
+}
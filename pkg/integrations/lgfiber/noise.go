@@ -0,0 +1,86 @@
+package lgfiber
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// NoiseConfig marks error responses on paths probed by scanners and browsers (favicons, /.well-known/,
+// /wp-admin) as noise: ErrorHandler logs them at Level instead of Warn and never sends them to Sentry
+type NoiseConfig struct {
+	// Paths selects the noisy paths
+	Paths PathFilter
+	// Statuses are the noisy response statuses (default: 404)
+	Statuses []int
+	// Level of the noise logs (default: Debug)
+	Level slog.Leveler
+}
+
+type compiledNoise struct {
+	filter   *compiledPathFilter
+	statuses []int
+	level    slog.Level
+}
+
+var noiseConfig atomic.Pointer[compiledNoise]
+
+// SetNoiseConfig sets the noise paths used by ErrorHandler; matching errors are counted in
+// http_noise_responses_total{status}. Returns an error for an invalid glob or pattern
+//
+// Usage:
+//
+//	err := lgfiber.SetNoiseConfig(lgfiber.NoiseConfig{
+//	    Paths: lgfiber.PathFilter{
+//	        Paths:    []string{"/favicon.ico", "/robots.txt"},
+//	        Prefixes: []string{"/.well-known/", "/wp-"},
+//	    },
+//	})
+func SetNoiseConfig(cfg NoiseConfig) error {
+	filter, err := cfg.Paths.compile()
+	if err != nil {
+		return err
+	}
+	n := &compiledNoise{filter: filter, statuses: slices.Clone(cfg.Statuses), level: slog.LevelDebug}
+	if len(n.statuses) == 0 {
+		n.statuses = []int{fiber.StatusNotFound}
+	}
+	if cfg.Level != nil {
+		n.level = cfg.Level.Level()
+	}
+	noiseConfig.Store(n)
+	return nil
+}
+
+// ResetNoiseConfig removes the noise paths
+func ResetNoiseConfig() {
+	noiseConfig.Store(nil)
+}
+
+// noiseLevel returns the log level of a noisy error response and counts it
+func noiseLevel(c *fiber.Ctx, status int) (slog.Level, bool) {
+	n := noiseConfig.Load()
+	if n == nil || c == nil || !slices.Contains(n.statuses, status) || !n.filter.match(c.Path()) {
+		return 0, false
+	}
+	metrics.IncCounter("http_noise_responses_total", metrics.Labels{"status": strconv.Itoa(status)})
+	return n.level, true
+}
+
+// logNoise logs a noisy error response with the request line only
+func logNoise(c *fiber.Ctx, lgErr *lgerr.Error, level slog.Level) {
+	ctx := c.UserContext()
+	logger.LogNoSourceCtx(ctx, getMiddlewareLogger(ctx), level, "Noise request",
+		fields.HTTPStatus(lgErr.HTTPStatus()),
+		fields.Method(c.Method()),
+		fields.URL(c.OriginalURL()),
+	)
+}
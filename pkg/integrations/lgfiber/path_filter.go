@@ -0,0 +1,148 @@
+package lgfiber
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PathFilter selects request paths that logbundle middlewares ignore entirely (health checks, metrics
+// scrapes, static assets): no trace ID, breadcrumbs, request log block, metrics or context dump
+type PathFilter struct {
+	// Paths are matched exactly (e.g. "/health")
+	Paths []string
+	// Prefixes match path prefixes (e.g. "/static/")
+	Prefixes []string
+	// Globs are path.Match patterns, where * does not cross "/" (e.g. "/assets/*.js")
+	Globs []string
+	// Patterns are regular expressions (e.g. `^/internal/.+/ping$`)
+	Patterns []string
+}
+
+// compiledPathFilter is a validated PathFilter
+type compiledPathFilter struct {
+	paths    map[string]struct{}
+	prefixes []string
+	globs    []string
+	patterns []*regexp.Regexp
+}
+
+// pathFilter is the shared filter; nil matches nothing
+var pathFilter atomic.Pointer[compiledPathFilter]
+
+// SetPathFilter sets the filter shared by BreadcrumbsMiddleware, TraceIDMiddleware, RequestLoggerMiddleware,
+// MetricsMiddleware and ContextDumpMiddleware; wrap other middlewares (e.g. the sentryfiber handler that
+// starts performance transactions, or custom context enrichment) with SkipFiltered. Returns an error for
+// an invalid glob or pattern, leaving the previous filter in place. An empty filter matches nothing
+//
+// Usage:
+//
+//	err := lgfiber.SetPathFilter(lgfiber.PathFilter{
+//	    Paths:    []string{"/health", "/metrics"},
+//	    Prefixes: []string{"/static/"},
+//	    Globs:    []string{"/*.ico"},
+//	})
+func SetPathFilter(f PathFilter) error {
+	c, err := f.compile()
+	if err != nil {
+		return err
+	}
+	pathFilter.Store(c)
+	return nil
+}
+
+// compile validates the filter
+func (f PathFilter) compile() (*compiledPathFilter, error) {
+	c := &compiledPathFilter{
+		paths:    make(map[string]struct{}, len(f.Paths)),
+		prefixes: append([]string(nil), f.Prefixes...),
+	}
+	for _, p := range f.Paths {
+		c.paths[p] = struct{}{}
+	}
+	for _, g := range f.Globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("path filter glob %q: %w", g, err)
+		}
+		c.globs = append(c.globs, g)
+	}
+	for _, p := range f.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("path filter pattern %q: %w", p, err)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	return c, nil
+}
+
+// ResetPathFilter removes the shared filter
+func ResetPathFilter() {
+	pathFilter.Store(nil)
+}
+
+// Match reports whether the filter selects p, or an error for an invalid glob or pattern
+// The filter is compiled on every call; middlewares match request paths against the compiled shared filter
+func (f PathFilter) Match(p string) (bool, error) {
+	c, err := f.compile()
+	if err != nil {
+		return false, err
+	}
+	return c.match(p), nil
+}
+
+func (c *compiledPathFilter) match(p string) bool {
+	if _, ok := c.paths[p]; ok {
+		return true
+	}
+	for _, prefix := range c.prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	for _, g := range c.globs {
+		if ok, _ := path.Match(g, p); ok {
+			return true
+		}
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFilteredPath reports whether the request path is selected by the shared filter (see SetPathFilter)
+func IsFilteredPath(c *fiber.Ctx) bool {
+	f := pathFilter.Load()
+	return f != nil && f.match(c.Path())
+}
+
+// SkipFiltered wraps a middleware so it is bypassed for paths selected by the shared filter
+//
+// Usage:
+//
+//	app.Use(lgfiber.SkipFiltered(sentryfiber.New(sentryfiber.Options{Repanic: true})))
+func SkipFiltered(h fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if IsFilteredPath(c) {
+			return c.Next()
+		}
+		return h(c)
+	}
+}
+
+// skipPaths wraps a middleware so it is bypassed for paths selected by f
+func skipPaths(f *compiledPathFilter, h fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if f.match(c.Path()) {
+			return c.Next()
+		}
+		return h(c)
+	}
+}
@@ -0,0 +1,75 @@
+package lgfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+func TestPathFilterMatch(t *testing.T) {
+	f := PathFilter{
+		Paths:    []string{"/health"},
+		Prefixes: []string{"/static/"},
+		Globs:    []string{"/*.ico"},
+		Patterns: []string{`^/internal/.+/ping$`},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/health", want: true},
+		{path: "/static/app.js", want: true},
+		{path: "/favicon.ico", want: true},
+		{path: "/internal/db/ping", want: true},
+		{path: "/orders", want: false},
+		{path: "/img/favicon.ico", want: false},
+	}
+	for _, tt := range tests {
+		got, err := f.Match(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if _, err := (PathFilter{Patterns: []string{"("}}).Match("/health"); err == nil {
+		t.Fatal("Match accepted an invalid pattern")
+	}
+}
+
+func TestMiddlewaresSkipPathsWithoutSharedFilter(t *testing.T) {
+	ResetPathFilter()
+	t.Cleanup(ResetPathFilter)
+
+	handlers, err := Middlewares(config.FiberMiddlewares{TraceID: true, SkipPaths: []string{"/health"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pathFilter.Load() != nil {
+		t.Fatal("Middlewares changed the shared path filter")
+	}
+
+	app := fiber.New()
+	for _, h := range handlers {
+		app.Use(h)
+	}
+	app.Get("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for path, traced := range map[string]bool{"/health": false, "/orders": true} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Header.Get("X-Request-ID") != ""; got != traced {
+			t.Fatalf("GET %s traced = %v, want %v", path, got, traced)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package lgfiber
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+// TracedJSON is c.JSON inside a "serialize.json" child span of the request transaction, so large
+// payload marshaling shows up in the transaction waterfall (span data: response size in bytes)
+// Without Sentry or an active transaction it is plain c.JSON
+//
+// Usage:
+//
+//	return lgfiber.TracedJSON(c, report)
+func TracedJSON(c *fiber.Ctx, data any, ctype ...string) error {
+	span := startRenderSpan(c, "serialize.json", "JSON response")
+	if span == nil {
+		return c.JSON(data, ctype...)
+	}
+
+	err := c.JSON(data, ctype...)
+	finishRenderSpan(span, c, err)
+	return err
+}
+
+// TracedRender is c.Render inside a "template.render" child span named after the template
+// Without Sentry or an active transaction it is plain c.Render
+//
+// Usage:
+//
+//	return lgfiber.TracedRender(c, "dashboard", fiber.Map{"Items": items}, "layouts/main")
+func TracedRender(c *fiber.Ctx, name string, bind any, layouts ...string) error {
+	span := startRenderSpan(c, "template.render", name)
+	if span == nil {
+		return c.Render(name, bind, layouts...)
+	}
+
+	span.SetData("template", name)
+	if len(layouts) > 0 {
+		span.SetData("layouts", layouts)
+	}
+
+	err := c.Render(name, bind, layouts...)
+	finishRenderSpan(span, c, err)
+	return err
+}
+
+// startRenderSpan starts a child of the request span, or returns nil when there is none
+func startRenderSpan(c *fiber.Ctx, operation, description string) *sentry.Span {
+	if !config.FromContext(c.UserContext()).SentryEnabled() {
+		return nil
+	}
+	parent := requestSpan(c.UserContext(), c)
+	if parent == nil {
+		return nil
+	}
+
+	span := parent.StartChild(operation)
+	span.Description = description
+	return span
+}
+
+// finishRenderSpan records the rendered size and outcome and finishes the span
+func finishRenderSpan(span *sentry.Span, c *fiber.Ctx, err error) {
+	span.SetData("response_size", len(c.Response().Body()))
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		span.SetData("error", err.Error())
+	} else {
+		span.Status = sentry.SpanStatusOK
+	}
+	span.Finish()
+}
@@ -0,0 +1,195 @@
+package lgfiber
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// RequestLoggerConfig holds configuration for request-scoped buffered logging
+type RequestLoggerConfig struct {
+	// Logger whose handler chain writes the block, so formatting, redaction (secrets, PII) and sinks
+	// apply as for any other record (default: the middleware logger)
+	Logger *slog.Logger
+	// MinStatus emits the block only for responses with at least this status; 0 emits every request
+	// Errors returned to the error handler count with their lgerr/fiber status (500 otherwise), and a
+	// panic passing through the middleware counts as 500
+	MinStatus int
+	// Level is the minimum level buffered (zero value: Info); buffered records are written even when
+	// Logger's own level is higher, which is what keeps debug detail for failing requests
+	Level slog.Level
+	// MaxRecords bounds the records buffered per request; later ones are dropped and counted in the
+	// block's records_dropped (default: 1000)
+	MaxRecords int
+}
+
+// bufferedRecord is a record captured by the request logger with the handler and context to write it with
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// requestBuffer is shared by a request's logger and its derived loggers
+type requestBuffer struct {
+	mu      sync.Mutex
+	records []bufferedRecord
+	max     int
+	dropped int
+}
+
+// bufferHandler is a slog.Handler appending records to a requestBuffer, keeping the handler of the
+// target chain (with this logger's attributes and groups applied) to write them with at the end
+type bufferHandler struct {
+	buf   *requestBuffer
+	level slog.Level
+	next  slog.Handler
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level || core.IsDebugScope(ctx)
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.buf.mu.Lock()
+	defer h.buf.mu.Unlock()
+	if len(h.buf.records) >= h.buf.max {
+		h.buf.dropped++
+		return nil
+	}
+	h.buf.records = append(h.buf.records, bufferedRecord{ctx: ctx, handler: h.next, record: r.Clone()})
+	return nil
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &bufferHandler{buf: h.buf, level: h.level, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &bufferHandler{buf: h.buf, level: h.level, next: h.next.WithGroup(name)}
+}
+
+// requestBlockMu keeps the records of one block together in the output
+var requestBlockMu sync.Mutex
+
+// RequestLoggerMiddleware gives each request a logger (see GetRequestLogger) whose records are buffered and
+// written through Logger's handler chain as one block when the request ends: a "Request" record with the
+// method, path, status and duration, then the buffered records, so a request's story is not interleaved
+// with other requests. With MinStatus the block is only written for failing requests, which keeps debug
+// detail for errors without paying for it on every success. The block is also written when a panic
+// passes through the middleware
+//
+// Usage:
+//
+//	app.Use(lgfiber.RequestLoggerMiddleware(lgfiber.RequestLoggerConfig{MinStatus: 500}))
+//
+//	func handler(c *fiber.Ctx) error {
+//	    log := lgfiber.GetRequestLogger(c)
+//	    log.Debug("loaded cart", "items", len(cart.Items))
+//	}
+func RequestLoggerMiddleware(cfg ...RequestLoggerConfig) fiber.Handler {
+	var config RequestLoggerConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.MaxRecords <= 0 {
+		config.MaxRecords = 1000
+	}
+
+	return func(c *fiber.Ctx) (err error) {
+		if IsFilteredPath(c) {
+			return c.Next()
+		}
+
+		log := config.Logger
+		if log == nil {
+			log = getMiddlewareLogger(c.UserContext())
+		}
+
+		start := core.Now()
+		buf := &requestBuffer{max: config.MaxRecords}
+		c.Locals("request_logger", slog.New(&bufferHandler{buf: buf, level: config.Level, next: log.Handler()}))
+
+		defer func() {
+			if r := recover(); r != nil {
+				flushRequestBlock(c, log, buf, fiber.StatusInternalServerError, start, config.MinStatus)
+				panic(r)
+			}
+		}()
+
+		err = c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = statusFromError(err)
+		}
+		flushRequestBlock(c, log, buf, status, start, config.MinStatus)
+		return err
+	}
+}
+
+// flushRequestBlock writes the block of a request with at least minStatus through log's handler chain
+// The records bypass the chain's level (see RequestLoggerConfig.Level) through a debug scope
+func flushRequestBlock(c *fiber.Ctx, log *slog.Logger, buf *requestBuffer, status int, start time.Time, minStatus int) {
+	if status < minStatus {
+		return
+	}
+
+	buf.mu.Lock()
+	records, dropped := buf.records, buf.dropped
+	buf.records = nil
+	buf.mu.Unlock()
+
+	ctx := core.WithDebugScope(c.UserContext())
+	summary := slog.NewRecord(core.Now(), slog.LevelInfo, "Request", 0)
+	summary.AddAttrs(
+		slog.String("method", c.Method()),
+		slog.String("path", core.ScrubURL(c.OriginalURL())),
+		slog.String("route", c.Route().Path),
+		slog.Int("status", status),
+		slog.Duration("duration", core.Since(start)),
+		slog.Int("records", len(records)),
+	)
+	if dropped > 0 {
+		summary.AddAttrs(slog.Int("records_dropped", dropped))
+	}
+
+	requestBlockMu.Lock()
+	defer requestBlockMu.Unlock()
+	_ = log.Handler().Handle(ctx, summary)
+	for _, r := range records {
+		_ = r.handler.Handle(core.WithDebugScope(r.ctx), r.record)
+	}
+}
+
+// GetRequestLogger returns the request's buffered logger, or the middleware logger if
+// RequestLoggerMiddleware is not installed
+func GetRequestLogger(c *fiber.Ctx) *slog.Logger {
+	if log, ok := c.Locals("request_logger").(*slog.Logger); ok && log != nil {
+		return log
+	}
+	return getMiddlewareLogger(c.UserContext())
+}
+
+// statusFromError returns the response status the error handler will use for err
+func statusFromError(err error) int {
+	var lgErr *lgerr.Error
+	if errors.As(err, &lgErr) {
+		return lgErr.HTTPStatus()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
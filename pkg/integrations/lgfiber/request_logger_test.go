@@ -0,0 +1,95 @@
+package lgfiber
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+func newRequestLoggerApp(t *testing.T, cfg RequestLoggerConfig, h fiber.Handler) (*fiber.App, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	chain := handler.NewPIIHandler(
+		handler.NewCustomHandlerWithOptions(&buf, handler.HandlerOptions{Level: slog.LevelWarn, Format: handler.FormatJSON}),
+		handler.PIIOptions{Mode: handler.PIIDrop},
+	)
+	cfg.Logger = slog.New(chain)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if recover() != nil {
+				err = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}()
+		return c.Next()
+	})
+	app.Use(RequestLoggerMiddleware(cfg))
+	app.Get("/", h)
+	return app, &buf
+}
+
+func TestRequestLoggerUsesHandlerChain(t *testing.T) {
+	app, buf := newRequestLoggerApp(t, RequestLoggerConfig{Level: slog.LevelDebug}, func(c *fiber.Ctx) error {
+		GetRequestLogger(c).WithGroup("cart").Debug("loaded cart",
+			slog.Int("items", 3),
+			slog.Any("error", errors.New("stale price")),
+			core.PII("email", "alice@example.com"),
+		)
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{`"message":"Request"`, `"cart":{`, `"items":3`, `"error":"stale price"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "alice@example.com") {
+		t.Errorf("PII bypassed the handler chain:\n%s", out)
+	}
+}
+
+func TestRequestLoggerMaxRecords(t *testing.T) {
+	app, buf := newRequestLoggerApp(t, RequestLoggerConfig{MaxRecords: 2}, func(c *fiber.Ctx) error {
+		for range 5 {
+			GetRequestLogger(c).Info("step")
+		}
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), `"message":"step"`); n != 2 {
+		t.Fatalf("%d records written, want 2:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"records_dropped":3`) {
+		t.Fatalf("dropped records not counted:\n%s", buf.String())
+	}
+}
+
+func TestRequestLoggerFlushesOnPanic(t *testing.T) {
+	app, buf := newRequestLoggerApp(t, RequestLoggerConfig{MinStatus: 500}, func(c *fiber.Ctx) error {
+		GetRequestLogger(c).Info("before panic")
+		panic("boom")
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "before panic") || !strings.Contains(buf.String(), `"status":500`) {
+		t.Fatalf("block lost on panic:\n%s", buf.String())
+	}
+}
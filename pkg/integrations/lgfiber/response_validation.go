@@ -0,0 +1,187 @@
+package lgfiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// ResponseValidationConfig holds configuration for response validation middleware
+type ResponseValidationConfig struct {
+	// Logger instance for contract violations (if nil, uses validation logger or middleware logger)
+	Logger *slog.Logger
+	// Validator instance (if nil, uses default validator)
+	Validator *validator.Validate
+	// Environment name; the middleware only runs in development, test and staging environments
+	// (development, dev, local, test, testing, ci, staging, stage, qa) and is disabled for any other name,
+	// so a typo or a missing variable never enables it in production
+	// If empty, read from APP_ENV, ENVIRONMENT or GO_ENV
+	Environment string
+}
+
+// ResponseValidationMiddleware checks successful JSON responses against the DTO type T and logs contract
+// violations as a diff: "+field" for properties not in T, "-field" for required properties missing from
+// the response and "~field" for values failing `validate` rules. Responses are never modified
+//
+// The middleware is a no-op outside development, test and staging environments, so it can stay registered
+// everywhere:
+//
+//	app.Get("/users/:id", lgfiber.ResponseValidationMiddleware[UserResponse](), handler)
+func ResponseValidationMiddleware[T any](cfg ...ResponseValidationConfig) fiber.Handler {
+	var c ResponseValidationConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if isProductionEnvironment(c.Environment) {
+		return func(ctx *fiber.Ctx) error {
+			return ctx.Next()
+		}
+	}
+
+	if c.Validator == nil {
+		c.Validator = getDefaultValidator()
+	}
+
+	dtoType := reflect.TypeOf((*T)(nil)).Elem()
+
+	return func(ctx *fiber.Ctx) error {
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+
+		status := ctx.Response().StatusCode()
+		if status < 200 || status >= 300 {
+			return nil
+		}
+		contentType := strings.ToLower(string(ctx.Response().Header.ContentType()))
+		if !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		diff := responseContractDiff[T](ctx.Response().Body(), dtoType, c.Validator)
+		if len(diff) == 0 {
+			return nil
+		}
+
+		log := c.Logger
+		if log == nil {
+			log = GetValidationLogger()
+		}
+		if log == nil {
+			log = getMiddlewareLogger(ctx.UserContext())
+		}
+
+		logger.LogNoSourceCtx(ctx.UserContext(), log, slog.LevelWarn, "Response contract violation",
+			slog.String("method", ctx.Method()),
+			slog.String("route", ctx.Route().Path),
+			slog.Int("status_code", status),
+			slog.String("dto", dtoType.String()),
+			slog.Any("diff", diff),
+		)
+
+		return nil
+	}
+}
+
+// responseContractDiff compares a JSON response body with the DTO type and returns the violations
+func responseContractDiff[T any](body []byte, dtoType reflect.Type, v *validator.Validate) []string {
+	// The body is decoded once; the typed value is filled from the generic one
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return []string{"!body: invalid JSON: " + err.Error()}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return []string{"!body: invalid JSON: trailing data after the value"}
+	}
+
+	var diff []string
+	for _, field := range findUnknownFields(raw, dtoType, "") {
+		diff = append(diff, "+"+field)
+	}
+
+	if obj, ok := raw.(map[string]any); ok {
+		diff = append(diff, missingRequiredFields(obj, dtoType)...)
+	}
+
+	var dto T
+	if err := assignJSON(raw, reflect.ValueOf(&dto).Elem()); err != nil {
+		return append(diff, "!body: "+err.Error())
+	}
+
+	if dtoType.Kind() == reflect.Struct {
+		if err := v.Struct(dto); err != nil {
+			for _, ve := range parseValidationErrors(err, dto) {
+				diff = append(diff, "~"+ve.Field+": "+ve.Message)
+			}
+		}
+	}
+
+	return diff
+}
+
+// missingRequiredFields returns "-field" entries for top-level non-optional fields absent from the response
+// Fields are optional when they are pointers or tagged with omitempty
+func missingRequiredFields(obj map[string]any, t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	present := make(map[string]struct{}, len(obj))
+	for k := range obj {
+		present[strings.ToLower(k)] = struct{}{}
+	}
+
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous || field.Type.Kind() == reflect.Ptr {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero") {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if _, ok := present[strings.ToLower(name)]; !ok {
+			missing = append(missing, "-"+name)
+		}
+	}
+
+	sort.Strings(missing)
+	return missing
+}
+
+// isProductionEnvironment reports whether env (or the environment variables when empty) may be production;
+// only known development, test and staging names are not, so unset and unknown names fail closed
+func isProductionEnvironment(env string) bool {
+	if env == "" {
+		env = core.EnvironmentName()
+	}
+
+	switch strings.ToLower(env) {
+	case "development", "dev", "local", "test", "testing", "ci", "staging", "stage", "qa":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,190 @@
+package lgfiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIsProductionEnvironment(t *testing.T) {
+	tests := []struct {
+		env  string
+		want bool
+	}{
+		{env: "", want: true},
+		{env: "production", want: true},
+		{env: "prd", want: true},
+		{env: "live", want: true},
+		{env: "development", want: false},
+		{env: "Staging", want: false},
+		{env: "test", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			for _, key := range []string{"APP_ENV", "ENVIRONMENT", "GO_ENV"} {
+				t.Setenv(key, "")
+			}
+			if got := isProductionEnvironment(tt.env); got != tt.want {
+				t.Fatalf("isProductionEnvironment(%q) = %v, want %v", tt.env, got, tt.want)
+			}
+		})
+	}
+}
+
+type contractAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type contractResponse struct {
+	ID      int             `json:"id"`
+	Name    string          `json:"name" validate:"required"`
+	Address contractAddress `json:"address"`
+}
+
+func TestResponseValidationMiddlewareReportsDiff(t *testing.T) {
+	var buf bytes.Buffer
+	app := fiber.New()
+	app.Get("/user", ResponseValidationMiddleware[contractResponse](ResponseValidationConfig{
+		Logger:      slog.New(slog.NewTextHandler(&buf, nil)),
+		Environment: "development",
+	}), func(c *fiber.Ctx) error {
+		return c.JSON(map[string]any{"id": 7, "name": "", "address": map[string]any{"city": "Oslo", "zip": "0150"}})
+	})
+
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/user", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Response contract violation", "+address.zip", "~"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("log misses %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestResponseContractDiffRejectsTrailingData(t *testing.T) {
+	dtoType := reflect.TypeOf(contractResponse{})
+	diff := responseContractDiff[contractResponse]([]byte(`{"id":1,"name":"a","address":{"city":"b"}} {}`), dtoType, getDefaultValidator())
+	if len(diff) != 1 || !strings.HasPrefix(diff[0], "!body: invalid JSON") {
+		t.Fatalf("diff = %v, want an invalid JSON entry", diff)
+	}
+}
+
+type assignInner struct {
+	Flag bool `json:"flag"`
+}
+
+type assignTarget struct {
+	*assignInner
+	Int     int               `json:"int"`
+	Small   uint8             `json:"small"`
+	Float   float32           `json:"float"`
+	Text    string            `json:"text"`
+	Bytes   []byte            `json:"bytes"`
+	Ptr     *int              `json:"ptr"`
+	List    []string          `json:"list"`
+	Pair    [2]int            `json:"pair"`
+	Counts  map[string]int    `json:"counts"`
+	ByID    map[int]string    `json:"by_id"`
+	Any     any               `json:"any"`
+	At      time.Time         `json:"at"`
+	Quoted  int64             `json:"quoted,string"`
+	Nested  []assignInner     `json:"nested"`
+	Renamed string            `json:"other_name"`
+	Skipped string            `json:"-"`
+	Raw     json.RawMessage   `json:"raw"`
+	Deep    map[string][]bool `json:"deep"`
+}
+
+func FuzzAssignJSONMatchesUnmarshal(f *testing.F) {
+	f.Add(`{"int":-3,"small":255,"float":1.5,"text":"a","bytes":"aGk=","ptr":4,"list":["x"],"pair":[1,2,3]}`)
+	f.Add(`{"counts":{"a":1},"by_id":{"7":"seven"},"any":{"n":[1,2.5,null,true]},"at":"2026-01-02T03:04:05Z"}`)
+	f.Add(`{"quoted":"42","nested":[{"flag":true}],"flag":true,"OTHER_NAME":"r","Skipped":"s","raw":{"k":[1]}}`)
+	f.Add(`{"ptr":null,"list":null,"deep":{"a":[true,false]},"small":256}`)
+	f.Add(`{"int":1.5,"text":3}`)
+	f.Add(`[1,2]`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		var want assignTarget
+		if err := json.Unmarshal([]byte(body), &want); err != nil {
+			return
+		}
+
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+		var raw any
+		if err := dec.Decode(&raw); err != nil {
+			t.Fatalf("Decode failed where Unmarshal succeeded: %v", err)
+		}
+		if hasFoldedDuplicateKeys(raw) {
+			// Keys differing only in case resolve in sorted rather than document order
+			return
+		}
+
+		var got assignTarget
+		if err := assignJSON(raw, reflect.ValueOf(&got).Elem()); err != nil {
+			t.Fatalf("assignJSON failed where Unmarshal succeeded: %v", err)
+		}
+		// json.RawMessage is re-encoded from the generic value, so compare it semantically
+		if !jsonEqual(got.Raw, want.Raw) {
+			t.Fatalf("raw = %s, want %s", got.Raw, want.Raw)
+		}
+		got.Raw, want.Raw = nil, nil
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("assignJSON = %+v\nUnmarshal  = %+v", got, want)
+		}
+	})
+}
+
+func BenchmarkResponseContractDiff(b *testing.B) {
+	body := []byte(`{"id":7,"name":"Ada","address":{"city":"Oslo","zip":"0150"}}`)
+	dtoType := reflect.TypeOf(contractResponse{})
+	v := getDefaultValidator()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = responseContractDiff[contractResponse](body, dtoType, v)
+	}
+}
+
+// hasFoldedDuplicateKeys reports whether an object in raw has keys that are equal ignoring case
+func hasFoldedDuplicateKeys(raw any) bool {
+	switch value := raw.(type) {
+	case map[string]any:
+		seen := make(map[string]bool, len(value))
+		for k, item := range value {
+			if seen[strings.ToLower(k)] || hasFoldedDuplicateKeys(item) {
+				return true
+			}
+			seen[strings.ToLower(k)] = true
+		}
+	case []any:
+		for _, item := range value {
+			if hasFoldedDuplicateKeys(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonEqual reports whether two JSON documents decode to the same value
+func jsonEqual(a, b []byte) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,226 @@
+package lgfiber
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Name     string   `json:"name,omitempty"`
+	Handlers []string `json:"handlers"` // Route middlewares then the handler, as "package.Function"
+}
+
+// String renders the route as "GET /users/:id [auth.Required handlers.GetUser]"
+func (r RouteInfo) String() string {
+	return r.Method + " " + r.Path + " [" + strings.Join(r.Handlers, " ") + "]"
+}
+
+// RouteConflict is a pair of routes matching the same requests
+type RouteConflict struct {
+	Method string
+	// Paths in registration order: the first route serves the requests, the second is unreachable. They
+	// are equal for duplicates and differ only in parameter names otherwise
+	Paths []string
+}
+
+// RouteLogConfig holds configuration for LogRoutes
+type RouteLogConfig struct {
+	// Logger for the route table (if nil, uses the middleware logger)
+	Logger *slog.Logger
+	// Level of the route table log (default: Info); conflicts are always logged at Warn
+	Level slog.Level
+	// IncludeHead lists the HEAD routes fiber adds for every GET route
+	IncludeHead bool
+}
+
+// LogRoutes logs the final route table and warns on duplicate or conflicting routes when the app starts
+// listening, so deployment diffs of the API surface are visible in logs. Conflicts are routes of the same
+// method whose paths are equal, or differ only in parameter names (/users/:id and /users/:name): the
+// later registration is unreachable. Fiber merges consecutive registrations of the same route into one
+// handler chain, which the table shows as extra handlers rather than as a duplicate
+//
+// Usage:
+//
+//	app := fiber.New()
+//	// register routes...
+//	lgfiber.LogRoutes(app)
+//	app.Listen(":8080")
+func LogRoutes(app *fiber.App, cfg ...RouteLogConfig) {
+	var config RouteLogConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+
+	app.Hooks().OnListen(func(fiber.ListenData) error {
+		log := config.Logger
+		if log == nil {
+			log = getMiddlewareLogger(context.Background())
+		}
+
+		routes := RouteTable(app, config.IncludeHead)
+		table := make([]string, len(routes))
+		for i, r := range routes {
+			table[i] = r.String()
+		}
+		logger.LogNoSource(log, config.Level, "Route table",
+			slog.Int("routes", len(routes)),
+			slog.Any("table", table),
+		)
+
+		for _, conflict := range RouteConflicts(app) {
+			msg := "Conflicting routes"
+			if conflict.Paths[0] == conflict.Paths[1] {
+				msg = "Duplicate route"
+			}
+			logger.LogNoSource(log, slog.LevelWarn, msg,
+				slog.String("method", conflict.Method),
+				slog.Any("paths", conflict.Paths),
+			)
+		}
+		return nil
+	})
+}
+
+// RouteTable returns the routes of app sorted by path and method, without middleware-only (Use) routes
+// HEAD routes are skipped unless includeHead is set
+func RouteTable(app *fiber.App, includeHead bool) []RouteInfo {
+	var routes []RouteInfo
+	for _, r := range registeredRoutes(app, includeHead) {
+		handlers := make([]string, len(r.Handlers))
+		for i, h := range r.Handlers {
+			handlers[i] = handlerName(h)
+		}
+		routes = append(routes, RouteInfo{Method: r.Method, Path: r.Path, Name: r.Name, Handlers: handlers})
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// RouteConflicts returns the pairs of routes of app matching the same requests. Routes are compared in
+// registration order (the order of app.Stack()), so the second path of each pair is the unreachable one;
+// the HEAD routes fiber adds for GET routes are skipped
+func RouteConflicts(app *fiber.App) []RouteConflict {
+	type routeKey struct {
+		method string
+		shape  string
+	}
+
+	routes := registeredRoutes(app, false)
+	first := make(map[routeKey]string, len(routes))
+	var conflicts []RouteConflict
+	for _, r := range routes {
+		key := routeKey{r.Method, routeShape(r.Path)}
+		if prev, ok := first[key]; ok {
+			conflicts = append(conflicts, RouteConflict{Method: r.Method, Paths: []string{prev, r.Path}})
+			continue
+		}
+		first[key] = r.Path
+	}
+	return conflicts
+}
+
+// registeredRoutes returns the routes of app without middleware-only (Use) routes, per method in
+// registration order: GetRoutes walks app.Stack(), which fiber keeps in that order
+func registeredRoutes(app *fiber.App, includeHead bool) []fiber.Route {
+	var routes []fiber.Route
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == fiber.MethodHead && !includeHead {
+			continue
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// matchedRoutePattern returns the pattern of the route that will serve the request, for middleware that
+// runs before routing has matched it (in app.Use middleware c.Route() is the middleware's own route).
+// It approximates fiber's matcher and returns "unmatched" when no route fits
+func matchedRoutePattern(c *fiber.Ctx) string {
+	for _, r := range c.App().GetRoutes(true) {
+		if r.Method == c.Method() && routePatternMatches(r.Path, c.Path()) {
+			return r.Path
+		}
+	}
+	return "unmatched"
+}
+
+// routePatternMatches reports whether path matches a fiber route pattern: ":name" matches one segment,
+// ":name?" an optional one and "*" or "+" the rest of the path
+func routePatternMatches(pattern, path string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathSegs) == 1 && pathSegs[0] == "" {
+		pathSegs = nil
+	}
+	if len(patternSegs) == 1 && patternSegs[0] == "" {
+		patternSegs = nil
+	}
+
+	j := 0
+	for _, seg := range patternSegs {
+		switch {
+		case strings.HasPrefix(seg, "*"):
+			return true
+		case strings.HasPrefix(seg, "+"):
+			return j < len(pathSegs)
+		case strings.HasPrefix(seg, ":") && strings.HasSuffix(seg, "?"):
+			if j < len(pathSegs) {
+				j++
+			}
+		case strings.ContainsAny(seg, ":*"):
+			// A parameter inside a segment ("file.:ext", ":from-:to") matches any one segment
+			if j >= len(pathSegs) {
+				return false
+			}
+			j++
+		default:
+			if j >= len(pathSegs) || !strings.EqualFold(seg, pathSegs[j]) {
+				return false
+			}
+			j++
+		}
+	}
+	return j == len(pathSegs)
+}
+
+// routeShape replaces parameter names so paths matching the same requests compare equal
+func routeShape(p string) string {
+	segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			if strings.HasSuffix(s, "?") {
+				segments[i] = ":?"
+			} else {
+				segments[i] = ":"
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// handlerName returns "package.Function" for a handler
+func handlerName(h fiber.Handler) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return path.Base(fn.Name())
+}
@@ -0,0 +1,27 @@
+package lgfiber
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRouteConflictsUseRegistrationOrder(t *testing.T) {
+	ok := func(c *fiber.Ctx) error { return nil }
+	app := fiber.New()
+	app.Use(ok)
+	app.Get("/users/:name", ok)
+	app.Get("/users/:id", ok)
+	app.Post("/orders", ok)
+	app.Post("/refunds", ok)
+	app.Post("/orders", ok)
+
+	want := []RouteConflict{
+		{Method: fiber.MethodGet, Paths: []string{"/users/:name", "/users/:id"}},
+		{Method: fiber.MethodPost, Paths: []string{"/orders", "/orders"}},
+	}
+	if got := RouteConflicts(app); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RouteConflicts = %+v, want %+v", got, want)
+	}
+}
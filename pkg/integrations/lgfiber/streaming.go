@@ -0,0 +1,260 @@
+package lgfiber
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// StreamConfig holds configuration for long-lived connection instrumentation
+type StreamConfig struct {
+	// Logger instance (if nil, uses middleware logger)
+	Logger *slog.Logger
+	// Kind labels logs and metrics, e.g. "websocket" or "sse" (default: "websocket")
+	Kind string
+	// MessageSampleRate is the fraction of messages logged at Debug level (0 disables per-message logs)
+	MessageSampleRate float64
+	// Breadcrumbs adds a Sentry breadcrumb per message (Sentry keeps only the most recent ones)
+	Breadcrumbs bool
+	// Hub of the connection's request, which gets the breadcrumbs (default: the hub bound to the
+	// context; StreamUpgradeMiddleware uses the request hub of sentryfiber). Without one no breadcrumbs
+	// are added, since the shared current hub would mix them into other requests' events
+	Hub *sentry.Hub
+}
+
+// StreamConn instruments a single long-lived connection: message logging with sampling, per-message
+// Sentry breadcrumbs, panic recovery in message handlers and a connection duration histogram
+// (stream_connection_duration_seconds{kind, route}). It is safe for concurrent use
+type StreamConn struct {
+	ctx      context.Context
+	cfg      StreamConfig
+	route    string
+	start    time.Time
+	received atomic.Int64
+	sent     atomic.Int64
+	closed   sync.Once
+}
+
+// NewStreamConn starts instrumenting a connection; call Close when the connection ends
+// Use it directly for streams that are not upgraded through StreamUpgradeMiddleware (e.g. SSE)
+func NewStreamConn(ctx context.Context, route string, cfg ...StreamConfig) *StreamConn {
+	var c StreamConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Kind == "" {
+		c.Kind = "websocket"
+	}
+	if c.Logger == nil {
+		c.Logger = getMiddlewareLogger(ctx)
+	}
+	if c.Hub == nil {
+		c.Hub = sentry.GetHubFromContext(ctx)
+	}
+
+	metrics.IncCounter("stream_connections_total", metrics.Labels{"kind": c.Kind, "route": route})
+
+	return &StreamConn{ctx: ctx, cfg: c, route: route, start: core.Now()}
+}
+
+// StreamUpgradeMiddleware logs websocket upgrade requests and their outcome, and stores a StreamConn
+// in the "stream_conn" local, which websocket libraries expose to the connection handler
+//
+// Usage:
+//
+//	app.Get("/ws", lgfiber.StreamUpgradeMiddleware(), websocket.New(func(conn *websocket.Conn) {
+//	    sc := conn.Locals("stream_conn").(*lgfiber.StreamConn)
+//	    defer sc.Close(nil)
+//	    for {
+//	        _, msg, err := conn.ReadMessage()
+//	        if err != nil {
+//	            sc.Close(err)
+//	            return
+//	        }
+//	        sc.Received(len(msg))
+//	        _ = sc.Handle("chat.message", func() error { return handleMessage(msg) })
+//	    }
+//	}))
+func StreamUpgradeMiddleware(cfg ...StreamConfig) fiber.Handler {
+	var streamConfig StreamConfig
+	if len(cfg) > 0 {
+		streamConfig = cfg[0]
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !isWebSocketUpgrade(c) {
+			return c.Next()
+		}
+
+		connConfig := streamConfig
+		if connConfig.Hub == nil {
+			connConfig.Hub = sentryfiber.GetHubFromContext(c)
+		}
+		sc := NewStreamConn(c.UserContext(), c.Route().Path, connConfig)
+		c.Locals("stream_conn", sc)
+
+		logger.LogNoSourceCtx(c.UserContext(), sc.cfg.Logger, slog.LevelInfo, "Stream upgrade requested",
+			slog.String("kind", sc.cfg.Kind),
+			slog.String("route", sc.route),
+			slog.String("path", c.Path()),
+			slog.String("ip", config.FromContext(c.UserContext()).ClientIP(c.IP())),
+			slog.String("origin", c.Get(fiber.HeaderOrigin)),
+			slog.String("protocol", c.Get(fiber.HeaderSecWebSocketProtocol)),
+		)
+
+		err := c.Next()
+
+		if status := c.Response().StatusCode(); err != nil || status != fiber.StatusSwitchingProtocols {
+			attrs := []any{
+				slog.String("kind", sc.cfg.Kind),
+				slog.String("route", sc.route),
+				slog.Int("status_code", status),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogNoSourceCtx(c.UserContext(), sc.cfg.Logger, slog.LevelWarn, "Stream upgrade rejected", attrs...)
+			metrics.IncCounter("stream_upgrade_rejected_total", metrics.Labels{"kind": sc.cfg.Kind, "route": sc.route})
+		}
+
+		return err
+	}
+}
+
+// Received records an incoming message of size bytes
+func (s *StreamConn) Received(size int, attrs ...any) {
+	s.received.Add(1)
+	s.message("in", size, attrs)
+}
+
+// Sent records an outgoing message of size bytes
+func (s *StreamConn) Sent(size int, attrs ...any) {
+	s.sent.Add(1)
+	s.message("out", size, attrs)
+}
+
+func (s *StreamConn) message(direction string, size int, attrs []any) {
+	metrics.IncCounter("stream_messages_total", metrics.Labels{
+		"kind":      s.cfg.Kind,
+		"route":     s.route,
+		"direction": direction,
+	})
+
+	if s.cfg.MessageSampleRate > 0 && (s.cfg.MessageSampleRate >= 1 || rand.Float64() < s.cfg.MessageSampleRate) {
+		logger.LogNoSourceCtx(s.ctx, s.cfg.Logger, slog.LevelDebug, "Stream message",
+			append([]any{
+				slog.String("kind", s.cfg.Kind),
+				slog.String("route", s.route),
+				slog.String("direction", direction),
+				slog.Int("size", size),
+			}, attrs...)...,
+		)
+	}
+
+	if s.cfg.Breadcrumbs && s.cfg.Hub != nil && config.FromContext(s.ctx).SentryEnabled() {
+		s.cfg.Hub.AddBreadcrumb(&sentry.Breadcrumb{
+			Type:      "default",
+			Category:  s.cfg.Kind + ".message",
+			Message:   fmt.Sprintf("%s message (%d bytes)", direction, size),
+			Level:     sentry.LevelInfo,
+			Timestamp: core.Now(),
+			Data: map[string]any{
+				"route":     s.route,
+				"direction": direction,
+				"size":      size,
+			},
+		}, nil)
+	}
+}
+
+// Handle runs a message handler, recovering panics so a single bad message does not kill the connection
+// A recovered panic is logged, sent to Sentry and returned as an error
+func (s *StreamConn) Handle(name string, fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		info := lgreport.RecoverPanic(s.ctx, r, s.hub(), func(scope *sentry.Scope, info *lgreport.PanicInfo) {
+			scope.SetLevel(sentry.LevelError)
+			scope.SetTag("error_source", "stream_panic_recovery")
+			scope.SetTag("stream_kind", s.cfg.Kind)
+			scope.SetTag("stream_handler", name)
+			scope.SetTag("route", s.route)
+			scope.SetFingerprint([]string{"stream_panic", s.route, name, info.ErrorLocation})
+		})
+
+		s.cfg.Logger.ErrorContext(s.ctx, "Panic in stream message handler", append([]any{
+			slog.String("kind", s.cfg.Kind),
+			slog.String("route", s.route),
+			slog.String("handler", name),
+		}, info.LogFields()...)...)
+
+		err = fmt.Errorf("panic in stream message handler %s: %v", name, r)
+	}()
+
+	return fn()
+}
+
+// Close records the end of the connection: its duration, message counts and the closing error (if any)
+// Only the first call has an effect
+func (s *StreamConn) Close(err error) {
+	s.closed.Do(func() {
+		duration := core.Since(s.start)
+		metrics.Observe("stream_connection_duration_seconds", metrics.Labels{
+			"kind":  s.cfg.Kind,
+			"route": s.route,
+		}, duration.Seconds())
+
+		level := slog.LevelInfo
+		attrs := []any{
+			slog.String("kind", s.cfg.Kind),
+			slog.String("route", s.route),
+			slog.Duration("duration", duration),
+			slog.Int64("messages_received", s.received.Load()),
+			slog.Int64("messages_sent", s.sent.Load()),
+		}
+		if err != nil {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogNoSourceCtx(s.ctx, s.cfg.Logger, level, "Stream connection closed", attrs...)
+	})
+}
+
+// Context returns the context the connection was opened with
+func (s *StreamConn) Context() context.Context {
+	return s.ctx
+}
+
+// hub returns the connection's hub for panic reports, or a clone of the current hub so the panic's
+// scope does not touch the shared one
+func (s *StreamConn) hub() *sentry.Hub {
+	if s.cfg.Hub != nil {
+		return s.cfg.Hub
+	}
+	return sentry.CurrentHub().Clone()
+}
+
+// isWebSocketUpgrade reports whether the request asks for a websocket upgrade
+func isWebSocketUpgrade(c *fiber.Ctx) bool {
+	return strings.Contains(strings.ToLower(c.Get(fiber.HeaderConnection)), "upgrade") &&
+		strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket")
+}
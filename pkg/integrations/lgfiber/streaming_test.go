@@ -0,0 +1,34 @@
+package lgfiber
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+// breadcrumbCount returns the number of breadcrumbs an event captured on hub would carry
+func breadcrumbCount(hub *sentry.Hub) int {
+	return len(hub.Scope().ApplyToEvent(sentry.NewEvent(), nil, nil).Breadcrumbs)
+}
+
+func TestStreamBreadcrumbsUseConnectionHub(t *testing.T) {
+	settings := config.NewSettings()
+	settings.SetSentryEnabled(true)
+	ctx := config.WithSettings(context.Background(), settings)
+
+	hub := sentry.NewHub(nil, sentry.NewScope())
+	global := breadcrumbCount(sentry.CurrentHub())
+
+	NewStreamConn(ctx, "/ws", StreamConfig{Breadcrumbs: true, Hub: hub}).Received(3)
+	if n := breadcrumbCount(hub); n != 1 {
+		t.Fatalf("connection hub has %d breadcrumbs, want 1", n)
+	}
+
+	NewStreamConn(ctx, "/ws", StreamConfig{Breadcrumbs: true}).Received(3)
+	if n := breadcrumbCount(sentry.CurrentHub()); n != global {
+		t.Fatalf("breadcrumb added to the current hub without a request hub")
+	}
+}
@@ -0,0 +1,101 @@
+package lgfiber
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// TimeoutMiddleware enforces a deadline on the rest of the handler chain
+// The request's user context gets the deadline, so database and HTTP calls made with c.UserContext()
+// are canceled when it passes. Once the deadline is exceeded the handler's error (typically a cascade of
+// "context deadline exceeded" failures) is replaced by a single lgerr.Timeout carrying the route and
+// duration, so logs and Sentry get one well-grouped event per route; a handler that returned no error
+// and already wrote a response keeps it. Additional errors that should count as a timeout can be passed
+// in timeoutErrors
+//
+// Fiber handlers run on the connection goroutine, so the handler is not interrupted: it must honor
+// c.UserContext() for the deadline to take effect
+//
+// Usage:
+//
+//	app.Get("/reports/:id", lgfiber.TimeoutMiddleware(5*time.Second), reportHandler)
+func TimeoutMiddleware(d time.Duration, timeoutErrors ...error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		parent := c.UserContext()
+		ctx, cancel := context.WithTimeout(parent, d)
+		defer cancel()
+
+		c.SetUserContext(ctx)
+		start := core.Now()
+		err := c.Next()
+		restoreParentContext(c, ctx, parent)
+
+		timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || !responseWritten(c))
+		if !timedOut && !isTimeoutError(err, timeoutErrors) {
+			return err
+		}
+
+		route := c.Route().Path
+		lgErr := lgerr.Timeout(c.Method()+" "+route, d.String(),
+			lgerr.WithContext("route", route),
+			lgerr.WithContext("method", c.Method()),
+			lgerr.WithContext("elapsed", core.Since(start).String()),
+		)
+		if err != nil {
+			lgErr.Wrap(err)
+		}
+		return lgErr
+	}
+}
+
+// restoreParentContext removes the deadline of ctx from the user context of c once the chain returned
+// Values the handlers attached on top of ctx are kept; only the deadline and cancellation go back to
+// those of parent
+func restoreParentContext(c *fiber.Ctx, ctx, parent context.Context) {
+	current := c.UserContext()
+	if current == ctx {
+		c.SetUserContext(parent)
+		return
+	}
+	c.SetUserContext(parentDeadlineContext{Context: parent, values: current})
+}
+
+// parentDeadlineContext takes its deadline and cancellation from the embedded parent and its values
+// from the context the handlers built under the timeout
+type parentDeadlineContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c parentDeadlineContext) Value(key any) any {
+	return c.values.Value(key)
+}
+
+// responseWritten reports whether the handler produced a response: a body, a body stream or a
+// status other than the default 200
+func responseWritten(c *fiber.Ctx) bool {
+	resp := c.Response()
+	return len(resp.Body()) > 0 || resp.IsBodyStream() || resp.StatusCode() != fiber.StatusOK
+}
+
+// isTimeoutError reports whether err is a deadline error or one of the configured timeout errors
+func isTimeoutError(err error, timeoutErrors []error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	for _, target := range timeoutErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
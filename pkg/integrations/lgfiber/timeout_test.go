@@ -0,0 +1,70 @@
+package lgfiber
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type timeoutTestKey struct{}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	const deadline = 10 * time.Millisecond
+	wait := func(c *fiber.Ctx) { <-c.UserContext().Done() }
+
+	tests := []struct {
+		name    string
+		handler fiber.Handler
+		status  int
+	}{
+		{"fast handler", func(c *fiber.Ctx) error { return c.SendString("ok") }, fiber.StatusOK},
+		{"late response kept", func(c *fiber.Ctx) error { wait(c); return c.SendString("ok") }, fiber.StatusOK},
+		{"late error", func(c *fiber.Ctx) error { wait(c); return c.UserContext().Err() }, fiber.StatusGatewayTimeout},
+		{"late empty response", func(c *fiber.Ctx) error { wait(c); return nil }, fiber.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+				return c.SendStatus(statusFromError(err))
+			}})
+			app.Get("/", TimeoutMiddleware(deadline), tt.handler)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareKeepsDownstreamValues(t *testing.T) {
+	app := fiber.New()
+	var value any
+	var ctxErr error
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		value = c.UserContext().Value(timeoutTestKey{})
+		ctxErr = c.UserContext().Err()
+		return err
+	})
+	app.Get("/", TimeoutMiddleware(time.Minute), func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), timeoutTestKey{}, "set downstream"))
+		return c.SendString("ok")
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil), -1); err != nil {
+		t.Fatal(err)
+	}
+	if value != "set downstream" {
+		t.Fatalf("downstream value = %v after the timeout middleware", value)
+	}
+	if ctxErr != nil {
+		t.Fatalf("restored context is done: %v", ctxErr)
+	}
+}
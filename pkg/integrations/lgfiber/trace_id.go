@@ -0,0 +1,85 @@
+package lgfiber
+
+import (
+	"regexp"
+
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// TraceIDConfig holds configuration for trace ID middleware
+type TraceIDConfig struct {
+	// Header carrying an incoming trace ID and echoed in the response (default: "X-Request-ID")
+	Header string
+	// TrustIncoming reuses a valid incoming header value instead of generating a new ID (default: false)
+	TrustIncoming bool
+	// Generator creates new trace IDs (default: core.NewTraceID)
+	Generator func() string
+}
+
+// validTraceID limits accepted incoming IDs to safe, reasonably sized tokens
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._:-]{8,128}$`)
+
+// TraceIDMiddleware assigns each request a trace ID stored in the request context under a typed key
+// Logs written with c.UserContext() include it as the configured field (see logbundle.SetTraceIDFieldName),
+// and the same field is set as a Sentry tag, so log lines and Sentry events correlate
+//
+// Usage:
+//
+//	app.Use(lgfiber.TraceIDMiddleware(lgfiber.TraceIDConfig{TrustIncoming: true}))
+//
+//	func handler(c *fiber.Ctx) error {
+//	    appLogger.InfoContext(c.UserContext(), "processing") // ... log_trace_id=4bf92f35...
+//	}
+func TraceIDMiddleware(cfg ...TraceIDConfig) fiber.Handler {
+	var c TraceIDConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Header == "" {
+		c.Header = fiber.HeaderXRequestID
+	}
+	if c.Generator == nil {
+		c.Generator = core.NewTraceID
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if IsFilteredPath(ctx) {
+			return ctx.Next()
+		}
+
+		traceID := ""
+		if c.TrustIncoming {
+			if incoming := ctx.Get(c.Header); validTraceID.MatchString(incoming) {
+				// Get aliases the request buffer, which fasthttp reuses after the request; the ID outlives
+				// it in the context, locals and Sentry tags
+				traceID = utils.CopyString(incoming)
+			}
+		}
+		if traceID == "" {
+			traceID = c.Generator()
+		}
+
+		userCtx := core.WithTraceID(ctx.UserContext(), traceID)
+		ctx.SetUserContext(userCtx)
+		ctx.Locals("trace_id", traceID)
+		ctx.Set(c.Header, traceID)
+
+		if config.FromContext(userCtx).SentryEnabled() {
+			if hub := sentryfiber.GetHubFromContext(ctx); hub != nil {
+				hub.Scope().SetTag(core.GetTraceIDFieldName(), traceID)
+			}
+		}
+
+		return ctx.Next()
+	}
+}
+
+// GetTraceID returns the request's trace ID set by TraceIDMiddleware, or "" if none
+func GetTraceID(c *fiber.Ctx) string {
+	return core.TraceIDFromContext(c.UserContext())
+}
@@ -0,0 +1,30 @@
+package lgfiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestTraceIDMiddlewareCopiesIncomingID(t *testing.T) {
+	const incoming = "0123456789abcdef0123456789abcdef"
+	var traceID string
+	app := fiber.New()
+	app.Use(TraceIDMiddleware(TraceIDConfig{TrustIncoming: true}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		traceID = GetTraceID(c)
+		return nil
+	})
+
+	var fctx fasthttp.RequestCtx
+	fctx.Request.SetRequestURI("/")
+	fctx.Request.Header.Set(fiber.HeaderXRequestID, incoming)
+	app.Handler()(&fctx)
+
+	// fasthttp reuses the header buffer for the next request on the connection
+	fctx.Request.Header.Set(fiber.HeaderXRequestID, "ffffffffffffffffffffffffffffffff")
+	if traceID != incoming {
+		t.Fatalf("trace ID changed to %q when the request buffer was reused", traceID)
+	}
+}
@@ -0,0 +1,90 @@
+package lgfiber
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+const (
+	// typeCacheShards spreads lock contention across independent shards
+	typeCacheShards = 16
+	// typeCacheMaxSize bounds each reflection cache; the least recently used types are evicted first
+	typeCacheMaxSize = 1024
+)
+
+// typeCache is a concurrency-safe, size-bounded LRU cache of per-type reflection results
+// Values must be treated as immutable once stored
+type typeCache[V any] struct {
+	shards [typeCacheShards]typeCacheShard[V]
+}
+
+type typeCacheShard[V any] struct {
+	mu    sync.Mutex
+	items map[reflect.Type]*list.Element
+	order *list.List // Front is most recently used
+	max   int
+}
+
+type typeCacheEntry[V any] struct {
+	key   reflect.Type
+	value V
+}
+
+func newTypeCache[V any](maxSize int) *typeCache[V] {
+	perShard := max(maxSize/typeCacheShards, 1)
+	c := &typeCache[V]{}
+	for i := range c.shards {
+		c.shards[i].items = make(map[reflect.Type]*list.Element)
+		c.shards[i].order = list.New()
+		c.shards[i].max = perShard
+	}
+	return c
+}
+
+func (c *typeCache[V]) shard(t reflect.Type) *typeCacheShard[V] {
+	// Hash the type name; types with equal names just share a shard
+	var h uint32 = 2166136261
+	name := t.String()
+	for i := 0; i < len(name); i++ {
+		h = (h ^ uint32(name[i])) * 16777619
+	}
+	return &c.shards[h%typeCacheShards]
+}
+
+// Load returns the cached value for t
+func (c *typeCache[V]) Load(t reflect.Type) (V, bool) {
+	s := c.shard(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[t]; ok {
+		s.order.MoveToFront(el)
+		return el.Value.(*typeCacheEntry[V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Store caches value for t, evicting the least recently used entry of the shard when full
+func (c *typeCache[V]) Store(t reflect.Type, value V) {
+	s := c.shard(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[t]; ok {
+		el.Value.(*typeCacheEntry[V]).value = value
+		s.order.MoveToFront(el)
+		return
+	}
+
+	s.items[t] = s.order.PushFront(&typeCacheEntry[V]{key: t, value: value})
+	if s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*typeCacheEntry[V]).key)
+	}
+}
+
+// fieldNameCache maps a struct type to its Go field name -> JSON name table
+var fieldNameCache = newTypeCache[map[string]string](typeCacheMaxSize)
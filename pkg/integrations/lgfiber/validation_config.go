@@ -2,7 +2,6 @@ package lgfiber
 
 import (
 	"log/slog"
-	"reflect"
 	"sync"
 
 	"github.com/go-playground/validator/v10"
@@ -20,40 +19,135 @@ type ValidationConfig struct {
 	Title string
 	// Detail for validation error response (optional)
 	Detail string
+	// DisallowUnknownFields rejects JSON bodies containing properties not defined in the DTO
+	// Unknown properties are reported as validation errors (body validation only)
+	DisallowUnknownFields bool
 }
 
-var (
-	defaultValidator     *validator.Validate
-	defaultValidatorOnce sync.Once
+// validationSource identifies the request part a global validation config applies to
+type validationSource int
 
-	defaultBodyConfig    ValidationConfig
-	defaultQueryConfig   ValidationConfig
-	defaultParamsConfig  ValidationConfig
-	defaultHeadersConfig ValidationConfig
-	defaultGlobalLogger  *slog.Logger
-	configMutex          sync.RWMutex
-
-	fieldNameCache      = make(map[reflect.Type]map[string]string, 64) // Pre-allocate with reasonable capacity
-	fieldNameCacheMutex sync.RWMutex
-	cacheMaxSize        = 1000 // Maximum cache entries to prevent unbounded growth
+const (
+	sourceBody validationSource = iota
+	sourceQuery
+	sourceParams
+	sourceHeaders
+	sourceCount
 )
 
+// validationStore is the single home of the global validation settings
+// Middlewares are usually created while routes are registered, possibly from several goroutines,
+// so every read and write goes through the store's lock
+type validationStore struct {
+	mu        sync.RWMutex
+	validator *validator.Validate // Created on first use unless set via SetDefaultValidator
+	logger    *slog.Logger
+	configs   [sourceCount]ValidationConfig
+}
+
+var validationSettings = newValidationStore()
+
+func newValidationStore() *validationStore {
+	return &validationStore{configs: defaultValidationConfigs()}
+}
+
+// defaultValidationConfigs returns the built-in config of every validation source
+func defaultValidationConfigs() [sourceCount]ValidationConfig {
+	return [sourceCount]ValidationConfig{
+		sourceBody: {
+			LocalsKey: "body",
+			Title:     "Validation Error",
+			Detail:    "Please check your request body",
+		},
+		sourceQuery: {
+			LocalsKey: "query",
+			Title:     "Invalid Query Parameters",
+			Detail:    "Please check your query parameters",
+		},
+		sourceParams: {
+			LocalsKey: "params",
+			Title:     "Invalid Route Parameters",
+			Detail:    "Please check your route parameters",
+		},
+		sourceHeaders: {
+			LocalsKey: "headers",
+			Title:     "Invalid Request Headers",
+			Detail:    "Please check your request headers",
+		},
+	}
+}
+
+// getValidator returns the default validator, creating it with the common rules on first use
+func (s *validationStore) getValidator() *validator.Validate {
+	s.mu.RLock()
+	v := s.validator
+	s.mu.RUnlock()
+	if v != nil {
+		return v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.validator == nil {
+		s.validator = validator.New()
+		// Built-in rules use fixed, valid tags - registration cannot fail
+		_ = RegisterCommonValidations(s.validator)
+	}
+	return s.validator
+}
+
+// get returns a copy of the source's config
+func (s *validationStore) get(source validationSource) ValidationConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.configs[source]
+}
+
+// middlewareConfig returns the source's config with the global validation logger as logger fallback
+func (s *validationStore) middlewareConfig(source validationSource) ValidationConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := s.configs[source]
+	if c.Logger == nil {
+		c.Logger = s.logger
+	}
+	return c
+}
+
+// update merges the set fields of config into the source's config
+// LocalsKey and Detail keep their defaults
+func (s *validationStore) update(source validationSource, config ValidationConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &s.configs[source]
+	if config.Logger != nil {
+		c.Logger = config.Logger
+	}
+	if config.Validator != nil {
+		c.Validator = config.Validator
+	}
+	if config.Title != "" {
+		c.Title = config.Title
+	}
+	if config.DisallowUnknownFields && source == sourceBody {
+		c.DisallowUnknownFields = true
+	}
+}
+
 // getDefaultValidator returns the default validator instance (lazy initialization)
 func getDefaultValidator() *validator.Validate {
-	defaultValidatorOnce.Do(func() {
-		defaultValidator = validator.New()
-	})
-	return defaultValidator
+	return validationSettings.getValidator()
 }
 
 // SetDefaultValidator sets a custom default validator instance
 // Call this at application startup to use a custom validator with additional rules
 func SetDefaultValidator(v *validator.Validate) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	if v != nil {
-		defaultValidator = v
+	if v == nil {
+		return
 	}
+	validationSettings.mu.Lock()
+	validationSettings.validator = v
+	validationSettings.mu.Unlock()
 }
 
 // GetDefaultValidator returns the current default validator instance
@@ -61,163 +155,68 @@ func GetDefaultValidator() *validator.Validate {
 	return getDefaultValidator()
 }
 
-// init initializes all default validation configs at package load time
-func init() {
-	defaultBodyConfig = ValidationConfig{
-		LocalsKey: "body",
-		Title:     "Validation Error",
-		Detail:    "Please check your request body",
-	}
-	defaultQueryConfig = ValidationConfig{
-		LocalsKey: "query",
-		Title:     "Invalid Query Parameters",
-		Detail:    "Please check your query parameters",
-	}
-	defaultParamsConfig = ValidationConfig{
-		LocalsKey: "params",
-		Title:     "Invalid Route Parameters",
-		Detail:    "Please check your route parameters",
-	}
-	defaultHeadersConfig = ValidationConfig{
-		LocalsKey: "headers",
-		Title:     "Invalid Request Headers",
-		Detail:    "Please check your request headers",
-	}
-}
-
 // SetValidationLogger sets the global logger for all validation middlewares
 // Call this at application startup to configure logging for validation errors
 func SetValidationLogger(logger *slog.Logger) {
-	configMutex.Lock()
-	defaultGlobalLogger = logger
-	configMutex.Unlock()
+	validationSettings.mu.Lock()
+	validationSettings.logger = logger
+	validationSettings.mu.Unlock()
 }
 
 // GetValidationLogger returns the global validation logger
 func GetValidationLogger() *slog.Logger {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
-	return defaultGlobalLogger
+	validationSettings.mu.RLock()
+	defer validationSettings.mu.RUnlock()
+	return validationSettings.logger
 }
 
 // SetBodyValidationConfig sets the global configuration for body validation middleware
 func SetBodyValidationConfig(config ValidationConfig) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	// Keep LocalsKey and Detail as defaults if not explicitly set
-	if config.Logger != nil {
-		defaultBodyConfig.Logger = config.Logger
-	}
-	if config.Validator != nil {
-		defaultBodyConfig.Validator = config.Validator
-	}
-	if config.Title != "" {
-		defaultBodyConfig.Title = config.Title
-	}
+	validationSettings.update(sourceBody, config)
 }
 
 // GetBodyValidationConfig returns a copy of the global body validation config
 func GetBodyValidationConfig() ValidationConfig {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
-	return defaultBodyConfig
+	return validationSettings.get(sourceBody)
 }
 
 // SetQueryValidationConfig sets the global configuration for query validation middleware
 func SetQueryValidationConfig(config ValidationConfig) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	// Keep LocalsKey and Detail as defaults if not explicitly set
-	if config.Logger != nil {
-		defaultQueryConfig.Logger = config.Logger
-	}
-	if config.Validator != nil {
-		defaultQueryConfig.Validator = config.Validator
-	}
-	if config.Title != "" {
-		defaultQueryConfig.Title = config.Title
-	}
+	validationSettings.update(sourceQuery, config)
 }
 
 // GetQueryValidationConfig returns a copy of the global query validation config
 func GetQueryValidationConfig() ValidationConfig {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
-	return defaultQueryConfig
+	return validationSettings.get(sourceQuery)
 }
 
 // SetParamsValidationConfig sets the global configuration for params validation middleware
 func SetParamsValidationConfig(config ValidationConfig) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	// Keep LocalsKey and Detail as defaults if not explicitly set
-	if config.Logger != nil {
-		defaultParamsConfig.Logger = config.Logger
-	}
-	if config.Validator != nil {
-		defaultParamsConfig.Validator = config.Validator
-	}
-	if config.Title != "" {
-		defaultParamsConfig.Title = config.Title
-	}
+	validationSettings.update(sourceParams, config)
 }
 
 // GetParamsValidationConfig returns a copy of the global params validation config
 func GetParamsValidationConfig() ValidationConfig {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
-	return defaultParamsConfig
+	return validationSettings.get(sourceParams)
 }
 
 // SetHeadersValidationConfig sets the global configuration for headers validation middleware
 func SetHeadersValidationConfig(config ValidationConfig) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	// Keep LocalsKey and Detail as defaults if not explicitly set
-	if config.Logger != nil {
-		defaultHeadersConfig.Logger = config.Logger
-	}
-	if config.Validator != nil {
-		defaultHeadersConfig.Validator = config.Validator
-	}
-	if config.Title != "" {
-		defaultHeadersConfig.Title = config.Title
-	}
+	validationSettings.update(sourceHeaders, config)
 }
 
 // GetHeadersValidationConfig returns a copy of the global headers validation config
 func GetHeadersValidationConfig() ValidationConfig {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
-	return defaultHeadersConfig
+	return validationSettings.get(sourceHeaders)
 }
 
-// ResetValidationConfigs resets all validation configs to their defaults
+// ResetValidationConfigs resets all validation configs, the validation logger, the default validator
+// and the span data toggle. The default validator is recreated (with the common rules) on next use
 func ResetValidationConfigs() {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	defaultGlobalLogger = nil
-	defaultValidator = nil
-
-	// Re-initialize to defaults
-	defaultBodyConfig = ValidationConfig{
-		LocalsKey: "body",
-		Title:     "Validation Error",
-		Detail:    "Please check your request body",
-	}
-	defaultQueryConfig = ValidationConfig{
-		LocalsKey: "query",
-		Title:     "Invalid Query Parameters",
-		Detail:    "Please check your query parameters",
-	}
-	defaultParamsConfig = ValidationConfig{
-		LocalsKey: "params",
-		Title:     "Invalid Route Parameters",
-		Detail:    "Please check your route parameters",
-	}
-	defaultHeadersConfig = ValidationConfig{
-		LocalsKey: "headers",
-		Title:     "Invalid Request Headers",
-		Detail:    "Please check your request headers",
-	}
+	validationSpanData.Store(false)
+	validationSettings.mu.Lock()
+	defer validationSettings.mu.Unlock()
+	validationSettings.logger = nil
+	validationSettings.validator = nil
+	validationSettings.configs = defaultValidationConfigs()
 }
@@ -0,0 +1,231 @@
+package lgfiber
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// defaultsPlanCache maps a struct type to the fields that carry defaults (nil if none)
+	defaultsPlanCache = newTypeCache[[]defaultField](typeCacheMaxSize)
+
+	// defaultsCheckCache maps a struct type to the result of checkDefaults
+	defaultsCheckCache = newTypeCache[error](typeCacheMaxSize)
+)
+
+// defaultField describes a struct field with a `default:"..."` tag or nested defaults
+type defaultField struct {
+	index  int
+	value  string
+	nested bool
+}
+
+// applyDefaults sets `default:"..."` tag values on zero-valued fields of the DTO
+// Values for slices are comma-separated. Nested structs (and pointers to them) are processed recursively
+//
+// Note: a field explicitly set to its zero value (e.g. ?limit=0) is indistinguishable from a missing one
+// and receives the default. In particular a bool with `default:"true"` can never be set to false by the
+// client; use a pointer field (e.g. *bool with `default:"true"`), which only gets the default when nil
+func applyDefaults(dto any) error {
+	v := reflect.ValueOf(dto)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	return applyStructDefaults(v.Elem())
+}
+
+func applyStructDefaults(v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, f := range getDefaultsPlan(v.Type()) {
+		field := v.Field(f.index)
+
+		if f.nested {
+			target := field
+			if target.Kind() == reflect.Ptr {
+				if target.IsNil() {
+					continue
+				}
+				target = target.Elem()
+			}
+			if err := applyStructDefaults(target); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.IsZero() {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+
+		if err := setDefaultValue(field, f.value); err != nil {
+			return fmt.Errorf("invalid default %q for field %s: %w", f.value, v.Type().Field(f.index).Name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkDefaults parses every `default` tag of t, including those of nested structs behind nil pointers
+// that applyDefaults only reaches once they are set, so a broken tag fails when the DTO is registered or
+// its middleware is built rather than as a 500 on some request
+func checkDefaults(t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := defaultsCheckCache.Load(t); ok {
+		return cached
+	}
+
+	err := checkStructDefaults(t, make(map[reflect.Type]bool))
+	defaultsCheckCache.Store(t, err)
+	return err
+}
+
+func checkStructDefaults(t reflect.Type, visiting map[reflect.Type]bool) error {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if value, ok := field.Tag.Lookup("default"); ok {
+			if err := setDefaultValue(reflect.New(ft).Elem(), value); err != nil {
+				return fmt.Errorf("invalid default %q for field %s.%s: %w", value, t.Name(), field.Name, err)
+			}
+			continue
+		}
+
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			if err := checkStructDefaults(ft, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getDefaultsPlan returns (and caches) the fields of t that need default processing
+func getDefaultsPlan(t reflect.Type) []defaultField {
+	if cached, ok := defaultsPlanCache.Load(t); ok {
+		return cached
+	}
+
+	plan := buildDefaultsPlan(t, make(map[reflect.Type]bool))
+	defaultsPlanCache.Store(t, plan)
+	return plan
+}
+
+// buildDefaultsPlan computes the plan of t; visiting holds the types being built up the recursion so
+// self-referencing types terminate. Only complete plans are cached (by getDefaultsPlan), so concurrent
+// requests never see a partial one
+func buildDefaultsPlan(t reflect.Type, visiting map[reflect.Type]bool) []defaultField {
+	if cached, ok := defaultsPlanCache.Load(t); ok {
+		return cached
+	}
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var plan []defaultField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if value, ok := field.Tag.Lookup("default"); ok {
+			plan = append(plan, defaultField{index: i, value: value})
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) && len(buildDefaultsPlan(ft, visiting)) > 0 {
+			plan = append(plan, defaultField{index: i, nested: true})
+		}
+	}
+	return plan
+}
+
+// setDefaultValue parses raw into field according to its kind
+func setDefaultValue(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setDefaultValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported kind %s", field.Kind())
+	}
+	return nil
+}
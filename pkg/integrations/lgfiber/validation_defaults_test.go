@@ -0,0 +1,114 @@
+package lgfiber
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type defaultsNode struct {
+	Name string `default:"node"`
+	Next *defaultsNode
+}
+
+type defaultsDTO struct {
+	Limit   int      `default:"20"`
+	Active  *bool    `default:"true"`
+	Visible bool     `default:"true"`
+	Tags    []string `default:"a, b"`
+	Node    defaultsNode
+}
+
+func TestApplyDefaults(t *testing.T) {
+	no := false
+	tests := []struct {
+		name  string
+		dto   defaultsDTO
+		check func(t *testing.T, dto defaultsDTO)
+	}{
+		{
+			name: "zero values get defaults",
+			check: func(t *testing.T, dto defaultsDTO) {
+				if dto.Limit != 20 || dto.Active == nil || !*dto.Active || !dto.Visible || len(dto.Tags) != 2 || dto.Tags[1] != "b" {
+					t.Fatalf("defaults not applied: %+v", dto)
+				}
+				if dto.Node.Name != "node" {
+					t.Fatalf("nested default not applied: %+v", dto.Node)
+				}
+			},
+		},
+		{
+			name: "explicit false pointer is kept",
+			dto:  defaultsDTO{Active: &no},
+			check: func(t *testing.T, dto defaultsDTO) {
+				if *dto.Active {
+					t.Fatal("explicit false replaced by the default")
+				}
+			},
+		},
+		{
+			name: "set values are kept",
+			dto:  defaultsDTO{Limit: 5},
+			check: func(t *testing.T, dto defaultsDTO) {
+				if dto.Limit != 5 {
+					t.Fatalf("Limit = %d, want 5", dto.Limit)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := tt.dto
+			if err := applyDefaults(&dto); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, dto)
+		})
+	}
+}
+
+func TestDefaultsPlanConcurrentFirstUse(t *testing.T) {
+	type fresh struct {
+		Limit int `default:"20"`
+		Node  *defaultsNode
+	}
+
+	var wg sync.WaitGroup
+	for range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dto fresh
+			if err := applyDefaults(&dto); err != nil {
+				t.Error(err)
+				return
+			}
+			if dto.Limit != 20 {
+				t.Errorf("Limit = %d, want 20 (a partial plan was used)", dto.Limit)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type brokenDefaultsInner struct {
+	Limit int `default:"ten"`
+}
+
+type brokenDefaultsDTO struct {
+	Name  string `default:"x"`
+	Inner *brokenDefaultsInner
+}
+
+func TestBrokenDefaultsFailAtStartup(t *testing.T) {
+	if err := RegisterDTO[brokenDefaultsDTO](); err == nil || !strings.Contains(err.Error(), "brokenDefaultsInner.Limit") {
+		t.Fatalf("RegisterDTO err = %v, want the nested field with the broken default", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("BodyValidationMiddleware accepted a broken default tag")
+		}
+	}()
+	BodyValidationMiddleware[brokenDefaultsDTO]()
+}
@@ -0,0 +1,194 @@
+package lgfiber
+
+import (
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// FileRule describes validation rules for files uploaded under a multipart form field
+type FileRule struct {
+	// Field is the multipart form field name
+	Field string
+	// Required rejects requests without at least one file in the field
+	Required bool
+	// MaxFiles limits the number of files in the field (0 = unlimited)
+	MaxFiles int
+	// MaxSize limits the size of each file in bytes (0 = unlimited)
+	MaxSize int64
+	// AllowedTypes lists allowed MIME types, sniffed from file content (e.g. "image/png", "image/*")
+	// Empty means any type is allowed
+	AllowedTypes []string
+}
+
+// UploadedFile holds metadata about a validated uploaded file
+type UploadedFile struct {
+	Field        string
+	Filename     string
+	Size         int64
+	ContentType  string // Sniffed from the file content
+	DeclaredType string // Content-Type sent by the client
+	Header       *multipart.FileHeader
+}
+
+// GetUploadedFiles returns the files validated by FormDataValidationMiddleware, keyed by form field
+func GetUploadedFiles(c *fiber.Ctx) map[string][]UploadedFile {
+	files, _ := c.Locals("files").(map[string][]UploadedFile)
+	return files
+}
+
+// fileValidationMiddleware validates uploaded files against rules before calling next
+func fileValidationMiddleware(config ValidationConfig, rules []FileRule, next fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		files, validationErrors, err := validateUploadedFiles(c, rules)
+		if err != nil {
+			if config.Logger != nil {
+				logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelWarn, "Failed to parse multipart form",
+					"error", err.Error(),
+					"parser", config.LocalsKey,
+				)
+			}
+
+			return c.Status(http.StatusBadRequest).JSON(lgerr.ErrorResponse{
+				Title:  "Invalid Request Format",
+				Detail: "Failed to parse multipart form: " + err.Error(),
+			})
+		}
+
+		if len(validationErrors) > 0 {
+			recordValidationFailures(c, config.LocalsKey, failuresFromErrors(validationErrors, "file", nil))
+
+			if config.Logger != nil {
+				logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelDebug, "File validation failed",
+					"errors_count", len(validationErrors),
+					"parser", config.LocalsKey,
+				)
+			}
+
+			return c.Status(http.StatusUnprocessableEntity).JSON(lgerr.ErrorResponse{
+				Title:  config.Title,
+				Detail: config.Detail,
+				Errors: validationErrors,
+			})
+		}
+
+		c.Locals("files", files)
+		return next(c)
+	}
+}
+
+// validateUploadedFiles checks multipart files against rules and returns their metadata
+// Validation failures are returned as lgerr.ValidationError entries, parse failures as error
+func validateUploadedFiles(c *fiber.Ctx, rules []FileRule) (map[string][]UploadedFile, []lgerr.ValidationError, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := make(map[string][]UploadedFile, len(rules))
+	var validationErrors []lgerr.ValidationError
+
+	for _, rule := range rules {
+		headers := form.File[rule.Field]
+
+		if len(headers) == 0 {
+			if rule.Required {
+				validationErrors = append(validationErrors, lgerr.ValidationError{
+					Field:   rule.Field,
+					Message: "This field is required",
+				})
+			}
+			continue
+		}
+
+		if rule.MaxFiles > 0 && len(headers) > rule.MaxFiles {
+			validationErrors = append(validationErrors, lgerr.ValidationError{
+				Field:   rule.Field,
+				Message: fmt.Sprintf("Too many files (max: %d)", rule.MaxFiles),
+				Value:   len(headers),
+			})
+			continue
+		}
+
+		uploaded := make([]UploadedFile, 0, len(headers))
+		for _, fh := range headers {
+			if rule.MaxSize > 0 && fh.Size > rule.MaxSize {
+				validationErrors = append(validationErrors, lgerr.ValidationError{
+					Field:   rule.Field,
+					Message: fmt.Sprintf("File is too large (max: %d bytes)", rule.MaxSize),
+					Value:   fh.Filename,
+				})
+				continue
+			}
+
+			contentType, err := sniffContentType(fh)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if len(rule.AllowedTypes) > 0 && !mimeTypeAllowed(contentType, rule.AllowedTypes) {
+				validationErrors = append(validationErrors, lgerr.ValidationError{
+					Field:   rule.Field,
+					Message: "File type " + contentType + " is not allowed (allowed: " + strings.Join(rule.AllowedTypes, ", ") + ")",
+					Value:   fh.Filename,
+				})
+				continue
+			}
+
+			uploaded = append(uploaded, UploadedFile{
+				Field:        rule.Field,
+				Filename:     fh.Filename,
+				Size:         fh.Size,
+				ContentType:  contentType,
+				DeclaredType: fh.Header.Get(fiber.HeaderContentType),
+				Header:       fh,
+			})
+		}
+
+		files[rule.Field] = uploaded
+	}
+
+	return files, validationErrors, nil
+}
+
+// sniffContentType detects the MIME type from the first 512 bytes of the file
+func sniffContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var buf [512]byte
+	n, err := f.Read(buf[:])
+	if err != nil && n == 0 && fh.Size > 0 {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(buf[:n])
+	// Strip parameters such as "; charset=utf-8"
+	if idx := strings.IndexByte(contentType, ';'); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	return contentType, nil
+}
+
+// mimeTypeAllowed reports whether contentType matches one of the allowed patterns ("image/*" supported)
+func mimeTypeAllowed(contentType string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == contentType || pattern == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package lgfiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+type uploadForm struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type uploadPart struct {
+	field, filename, declared, content string
+}
+
+// multipartRequest builds a POST with the json_data field and the given files
+func multipartRequest(t *testing.T, files ...uploadPart) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("json_data", `{"name":"ada"}`); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		h.Set("Content-Type", f.declared)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = part.Write([]byte(f.content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/avatars", &body)
+	req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+	return req
+}
+
+func TestFormDataValidationMiddlewareValidatesFiles(t *testing.T) {
+	app := fiber.New()
+	app.Post("/avatars", FormDataValidationMiddleware[uploadForm]("",
+		FileRule{Field: "avatar", Required: true, MaxFiles: 1, MaxSize: 64, AllowedTypes: []string{"image/*"}},
+	), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	png := uploadPart{field: "avatar", filename: "a.png", declared: "image/png", content: pngHeader}
+	tests := []struct {
+		name    string
+		files   []uploadPart
+		status  int
+		message string
+	}{
+		{name: "valid", files: []uploadPart{png}, status: fiber.StatusOK},
+		{name: "missing", status: fiber.StatusUnprocessableEntity, message: "required"},
+		{name: "too many", files: []uploadPart{png, png}, status: fiber.StatusUnprocessableEntity, message: "Too many files"},
+		{
+			name:    "too large",
+			files:   []uploadPart{{field: "avatar", filename: "big.png", declared: "image/png", content: pngHeader + strings.Repeat("x", 100)}},
+			status:  fiber.StatusUnprocessableEntity,
+			message: "too large",
+		},
+		{
+			name:    "declared type is not trusted",
+			files:   []uploadPart{{field: "avatar", filename: "a.png", declared: "image/png", content: "#!/bin/sh\necho hi\n"}},
+			status:  fiber.StatusUnprocessableEntity,
+			message: "text/plain is not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(multipartRequest(t, tt.files...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.message == "" {
+				return
+			}
+
+			var body lgerr.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Field != "avatar" || !strings.Contains(body.Errors[0].Message, tt.message) {
+				t.Fatalf("errors = %+v, want one avatar error mentioning %q", body.Errors, tt.message)
+			}
+		})
+	}
+}
+
+func TestUploadedFileMetadata(t *testing.T) {
+	app := fiber.New()
+	app.Post("/avatars", FormDataValidationMiddleware[uploadForm]("", FileRule{Field: "avatar"}), func(c *fiber.Ctx) error {
+		f := GetUploadedFiles(c)["avatar"][0]
+		return c.SendString(f.Filename + "|" + f.ContentType + "|" + f.DeclaredType)
+	})
+
+	resp, err := app.Test(multipartRequest(t, uploadPart{field: "avatar", filename: "a.bin", declared: "application/octet-stream", content: pngHeader}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	_, _ = out.ReadFrom(resp.Body)
+	if got, want := out.String(), "a.bin|image/png|application/octet-stream"; got != want {
+		t.Fatalf("metadata = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,266 @@
+package lgfiber
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// validationFailure identifies a single failed rule on a request field
+type validationFailure struct {
+	field string
+	tag   string
+}
+
+type validationSummaryKey struct {
+	method string
+	route  string
+	field  string
+	tag    string
+}
+
+var (
+	// validationSummaryCounts aggregates failures between summary logs (nil when summary is disabled)
+	validationSummaryCounts map[validationSummaryKey]int
+	validationSummaryMutex  sync.Mutex
+)
+
+// recordValidationFailures emits validation failure metrics for the current request:
+//   - validation_failures_total{method, route, parser}
+//   - validation_field_failures_total{route, field, tag}, with undeclared fields as "other"
+//
+// and records them on the Sentry span when enabled (see SetValidationSpanData)
+func recordValidationFailures(c *fiber.Ctx, parser string, failures []validationFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	method := c.Method()
+	route := c.Route().Path
+
+	metrics.IncCounter("validation_failures_total", metrics.Labels{
+		"method": method,
+		"route":  route,
+		"parser": parser,
+	})
+
+	for _, f := range failures {
+		metrics.IncCounter("validation_field_failures_total", metrics.Labels{
+			"route": route,
+			"field": f.field,
+			"tag":   f.tag,
+		})
+	}
+
+	validationSummaryMutex.Lock()
+	if validationSummaryCounts != nil {
+		for _, f := range failures {
+			validationSummaryCounts[validationSummaryKey{method, route, f.field, f.tag}]++
+		}
+	}
+	validationSummaryMutex.Unlock()
+
+	recordValidationSpan(c, parser, failures)
+}
+
+// otherField replaces field names not declared on the DTO in metric labels and summaries
+const otherField = "other"
+
+// declaredFieldsCache maps a struct type to the field names it declares (see declaredFieldNames)
+var declaredFieldsCache = newTypeCache[map[string]bool](typeCacheMaxSize)
+
+// metricFieldName returns field if every dotted segment names a field declared on dto (or on its nested
+// structs), otherwise "other": parse errors name fields taken from the request (unknown JSON keys, form
+// keys), and labels must stay bounded by the code. A nil dto keeps field (e.g. file rule names)
+func metricFieldName(dto any, field string) string {
+	if dto == nil {
+		return field
+	}
+	t := reflect.TypeOf(dto)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return otherField
+	}
+
+	names, ok := declaredFieldsCache.Load(t)
+	if !ok {
+		names = make(map[string]bool)
+		collectDeclaredFieldNames(t, names, 0)
+		declaredFieldsCache.Store(t, names)
+	}
+	for _, part := range strings.Split(field, ".") {
+		part, _, _ = strings.Cut(part, "[")
+		if !names[part] {
+			return otherField
+		}
+	}
+	return field
+}
+
+// declaredFieldTags are the struct tags fiber's parsers and the JSON decoder match request keys against
+var declaredFieldTags = []string{"json", "query", "form", "params", "reqHeader", "cookie", "xml"}
+
+// collectDeclaredFieldNames adds the Go names (as is and lowercased) and tag names of t's fields and of
+// the structs nested in them
+func collectDeclaredFieldNames(t reflect.Type, names map[string]bool, depth int) {
+	const maxDepth = 8
+	if depth > maxDepth {
+		return
+	}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+		if !field.Anonymous {
+			names[field.Name] = true
+			names[strings.ToLower(field.Name)] = true
+			for _, tag := range declaredFieldTags {
+				if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+					names[name] = true
+				}
+			}
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != t {
+			collectDeclaredFieldNames(ft, names, depth+1)
+		}
+	}
+}
+
+// validatorFailures extracts field/tag pairs from validator errors using the same field naming as responses
+func validatorFailures(err error, dto any) []validationFailure {
+	validatorErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	failures := make([]validationFailure, 0, len(validatorErrs))
+	for _, fieldErr := range validatorErrs {
+		fieldName := getJSONFieldName(dto, fieldErr.Field())
+		if fieldName == "" {
+			fieldName = strings.ToLower(fieldErr.Field())
+		}
+		failures = append(failures, validationFailure{field: metricFieldName(dto, fieldName), tag: fieldErr.Tag()})
+	}
+	return failures
+}
+
+// failuresFromErrors builds failures for validation errors that share a single tag (e.g. "type", "file")
+// Field names not declared on dto are reported as "other" (see metricFieldName)
+func failuresFromErrors(validationErrors []lgerr.ValidationError, tag string, dto any) []validationFailure {
+	failures := make([]validationFailure, 0, len(validationErrors))
+	for _, ve := range validationErrors {
+		failures = append(failures, validationFailure{field: metricFieldName(dto, ve.Field), tag: tag})
+	}
+	return failures
+}
+
+// StartValidationSummary periodically logs the most frequent validation failures since the previous summary
+// Returns a function that stops the summary. Only one summary can run at a time; starting a new one
+// replaces the previous counters
+//
+// Usage:
+//
+//	stop := lgfiber.StartValidationSummary(appLogger, 10*time.Minute, 20)
+//	defer stop()
+func StartValidationSummary(log *slog.Logger, interval time.Duration, topN int) (stop func()) {
+	if log == nil {
+		log = getMiddlewareLogger(context.Background())
+	}
+	if topN <= 0 {
+		topN = 10
+	}
+
+	validationSummaryMutex.Lock()
+	validationSummaryCounts = make(map[validationSummaryKey]int, 64)
+	validationSummaryMutex.Unlock()
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logValidationSummary(log, interval, topN)
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() {
+			close(done)
+			validationSummaryMutex.Lock()
+			validationSummaryCounts = nil
+			validationSummaryMutex.Unlock()
+		})
+	}
+}
+
+// logValidationSummary logs and resets the aggregated validation failure counters
+func logValidationSummary(log *slog.Logger, interval time.Duration, topN int) {
+	validationSummaryMutex.Lock()
+	counts := validationSummaryCounts
+	if counts != nil {
+		validationSummaryCounts = make(map[validationSummaryKey]int, len(counts))
+	}
+	validationSummaryMutex.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	type entry struct {
+		key   validationSummaryKey
+		count int
+	}
+
+	total := 0
+	entries := make([]entry, 0, len(counts))
+	for k, n := range counts {
+		entries = append(entries, entry{k, n})
+		total += n
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Compare(b.count, a.count)
+	})
+
+	if len(entries) > topN {
+		entries = entries[:topN]
+	}
+
+	top := make([]string, 0, len(entries))
+	for _, e := range entries {
+		top = append(top, fmt.Sprintf("%s %s %s:%s=%d", e.key.method, e.key.route, e.key.field, e.key.tag, e.count))
+	}
+
+	log.Info("Validation failure summary",
+		slog.Duration("interval", interval),
+		slog.Int("total_failures", total),
+		slog.Int("distinct_failures", len(counts)),
+		slog.Any("top_failures", top),
+	)
+}
@@ -0,0 +1,38 @@
+package lgfiber
+
+import "testing"
+
+type metricAddress struct {
+	City string `json:"city"`
+}
+
+type metricDTO struct {
+	Email     string          `json:"email"`
+	Page      int             `query:"page"`
+	Address   metricAddress   `json:"address"`
+	Shipments []metricAddress `json:"shipments"`
+}
+
+func TestMetricFieldName(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"email", "email"},
+		{"page", "page"},
+		{"Email", "Email"},
+		{"address.city", "address.city"},
+		{"shipments[2].city", "shipments[2].city"},
+		{"injected_key_81723", otherField},
+		{"address.injected", otherField},
+		{"", otherField},
+	}
+	for _, tt := range tests {
+		if got := metricFieldName(&metricDTO{}, tt.field); got != tt.want {
+			t.Errorf("metricFieldName(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+	if got := metricFieldName(nil, "avatar"); got != "avatar" {
+		t.Errorf("metricFieldName without DTO = %q, want the field", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
 	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
@@ -11,6 +13,7 @@ import (
 )
 
 // genericValidationMiddleware creates a validation middleware for any parser function
+// It panics when a `default` tag of T cannot be parsed, so the mistake surfaces at route registration
 func genericValidationMiddleware[T any](
 	parserFunc func(*fiber.Ctx, *T) error,
 	config ValidationConfig,
@@ -22,12 +25,47 @@ func genericValidationMiddleware[T any](
 	if config.Title == "" {
 		config.Title = "Validation Error"
 	}
+	if err := checkDefaults(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		panic("lgfiber: " + err.Error())
+	}
 
 	return func(c *fiber.Ctx) error {
 		var dto T
 
 		// Parse the request
 		if err := parserFunc(c, &dto); err != nil {
+			if mediaErr, ok := isUnsupportedMediaType(err); ok {
+				if config.Logger != nil {
+					logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelDebug, "Unsupported request media type",
+						"content_type", mediaErr.mediaType,
+						"parser", config.LocalsKey,
+					)
+				}
+
+				return c.Status(http.StatusUnsupportedMediaType).JSON(lgerr.ErrorResponse{
+					Title:  "Unsupported Media Type",
+					Detail: mediaErr.Error() + " (supported: " + strings.Join(mediaErr.supported, ", ") + ")",
+				})
+			}
+
+			// Type coercion failures (e.g. "abc" into an int field) and unknown fields are reported per field
+			if parseErrors, tag := parseParserErrors(err); len(parseErrors) > 0 {
+				recordValidationFailures(c, config.LocalsKey, failuresFromErrors(parseErrors, tag, &dto))
+
+				if config.Logger != nil {
+					logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelDebug, "Request parameters rejected",
+						"errors_count", len(parseErrors),
+						"parser", config.LocalsKey,
+					)
+				}
+
+				return c.Status(http.StatusUnprocessableEntity).JSON(lgerr.ErrorResponse{
+					Title:  config.Title,
+					Detail: config.Detail,
+					Errors: parseErrors,
+				})
+			}
+
 			if config.Logger != nil {
 				logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelWarn, "Failed to parse request",
 					"error", err.Error(),
@@ -41,11 +79,18 @@ func genericValidationMiddleware[T any](
 			})
 		}
 
+		// Apply `default:"..."` tags before validation
+		if err := applyDefaults(&dto); err != nil {
+			return lgerr.Internal("failed to apply DTO defaults").Wrap(err)
+		}
+
 		// Validate the parsed data
 		if err := config.Validator.Struct(dto); err != nil {
 			validationErrors := parseValidationErrors(err, dto)
 
 			if len(validationErrors) > 0 {
+				recordValidationFailures(c, config.LocalsKey, validatorFailures(err, dto))
+
 				if config.Logger != nil {
 					logger.LogWithSourceCtx(c.UserContext(), config.Logger, slog.LevelDebug, "Validation failed",
 						"errors_count", len(validationErrors),
@@ -96,29 +141,15 @@ func genericValidationMiddleware[T any](
 //	    // Use validated body...
 //	}
 func BodyValidationMiddleware[T any]() fiber.Handler {
-	// Capture global config pointers once at middleware creation (avoid struct copy)
-	configMutex.RLock()
-	logger := defaultBodyConfig.Logger
-	validator := defaultBodyConfig.Validator
-	title := defaultBodyConfig.Title
-	detail := defaultBodyConfig.Detail
-	if defaultGlobalLogger != nil && logger == nil {
-		logger = defaultGlobalLogger
-	}
-	configMutex.RUnlock()
-
-	config := ValidationConfig{
-		Logger:    logger,
-		Validator: validator,
-		LocalsKey: "body",
-		Title:     title,
-		Detail:    detail,
+	// Capture the global config once at middleware creation
+	config := validationSettings.middlewareConfig(sourceBody)
+
+	parser := negotiatedBodyParser[T]
+	if config.DisallowUnknownFields {
+		parser = strictBodyParser[T]
 	}
 
-	return genericValidationMiddleware(
-		func(ctx *fiber.Ctx, dto *T) error { return ctx.BodyParser(dto) },
-		config,
-	)
+	return genericValidationMiddleware(parser, config)
 }
 
 // QueryValidationMiddleware creates a middleware that validates query parameters
@@ -145,24 +176,8 @@ func BodyValidationMiddleware[T any]() fiber.Handler {
 //	    // Use validated query...
 //	}
 func QueryValidationMiddleware[T any]() fiber.Handler {
-	// Capture global config pointers once at middleware creation (avoid struct copy)
-	configMutex.RLock()
-	logger := defaultQueryConfig.Logger
-	validator := defaultQueryConfig.Validator
-	title := defaultQueryConfig.Title
-	detail := defaultQueryConfig.Detail
-	if defaultGlobalLogger != nil && logger == nil {
-		logger = defaultGlobalLogger
-	}
-	configMutex.RUnlock()
-
-	config := ValidationConfig{
-		Logger:    logger,
-		Validator: validator,
-		LocalsKey: "query",
-		Title:     title,
-		Detail:    detail,
-	}
+	// Capture the global config once at middleware creation
+	config := validationSettings.middlewareConfig(sourceQuery)
 
 	return genericValidationMiddleware(
 		func(ctx *fiber.Ctx, dto *T) error { return ctx.QueryParser(dto) },
@@ -193,24 +208,8 @@ func QueryValidationMiddleware[T any]() fiber.Handler {
 //	    // Use validated params...
 //	}
 func ParamsValidationMiddleware[T any]() fiber.Handler {
-	// Capture global config pointers once at middleware creation (avoid struct copy)
-	configMutex.RLock()
-	logger := defaultParamsConfig.Logger
-	validator := defaultParamsConfig.Validator
-	title := defaultParamsConfig.Title
-	detail := defaultParamsConfig.Detail
-	if defaultGlobalLogger != nil && logger == nil {
-		logger = defaultGlobalLogger
-	}
-	configMutex.RUnlock()
-
-	config := ValidationConfig{
-		Logger:    logger,
-		Validator: validator,
-		LocalsKey: "params",
-		Title:     title,
-		Detail:    detail,
-	}
+	// Capture the global config once at middleware creation
+	config := validationSettings.middlewareConfig(sourceParams)
 
 	return genericValidationMiddleware(
 		func(ctx *fiber.Ctx, dto *T) error { return ctx.ParamsParser(dto) },
@@ -242,24 +241,8 @@ func ParamsValidationMiddleware[T any]() fiber.Handler {
 //	    // Use validated headers...
 //	}
 func HeadersValidationMiddleware[T any]() fiber.Handler {
-	// Capture global config pointers once at middleware creation (avoid struct copy)
-	configMutex.RLock()
-	logger := defaultHeadersConfig.Logger
-	validator := defaultHeadersConfig.Validator
-	title := defaultHeadersConfig.Title
-	detail := defaultHeadersConfig.Detail
-	if defaultGlobalLogger != nil && logger == nil {
-		logger = defaultGlobalLogger
-	}
-	configMutex.RUnlock()
-
-	config := ValidationConfig{
-		Logger:    logger,
-		Validator: validator,
-		LocalsKey: "headers",
-		Title:     title,
-		Detail:    detail,
-	}
+	// Capture the global config once at middleware creation
+	config := validationSettings.middlewareConfig(sourceHeaders)
 
 	return genericValidationMiddleware(
 		func(ctx *fiber.Ctx, dto *T) error { return ctx.ReqHeaderParser(dto) },
@@ -294,32 +277,29 @@ func HeadersValidationMiddleware[T any]() fiber.Handler {
 //	    body := c.Locals("form_data").(CreateUserRequest)
 //	    // Use validated body...
 //	}
-func FormDataValidationMiddleware[T any](formFieldName string) fiber.Handler {
+//
+// Uploaded files can be validated with file rules; their metadata is available via GetUploadedFiles():
+//
+//	app.Post("/avatars", lgfiber.FormDataValidationMiddleware[CreateUserRequest]("",
+//	    lgfiber.FileRule{Field: "avatar", Required: true, MaxFiles: 1, MaxSize: 5 << 20, AllowedTypes: []string{"image/*"}},
+//	), handler)
+//
+//	func handler(c *fiber.Ctx) error {
+//	    avatar := lgfiber.GetUploadedFiles(c)["avatar"][0]
+//	    // Use avatar.Header.Open()...
+//	}
+func FormDataValidationMiddleware[T any](formFieldName string, fileRules ...FileRule) fiber.Handler {
 	fieldName := "json_data"
 	if formFieldName != "" {
 		fieldName = formFieldName
 	}
 
-	// Capture global config pointers once at middleware creation (avoid struct copy)
-	configMutex.RLock()
-	logger := defaultBodyConfig.Logger
-	validator := defaultBodyConfig.Validator
-	title := defaultBodyConfig.Title
-	detail := defaultBodyConfig.Detail
-	if defaultGlobalLogger != nil && logger == nil {
-		logger = defaultGlobalLogger
-	}
-	configMutex.RUnlock()
-
-	config := ValidationConfig{
-		Logger:    logger,
-		Validator: validator,
-		LocalsKey: "form_data",
-		Title:     title,
-		Detail:    detail,
-	}
+	// Capture the global body config once at middleware creation
+	config := validationSettings.middlewareConfig(sourceBody)
+	config.LocalsKey = "form_data"
+	config.DisallowUnknownFields = false
 
-	return genericValidationMiddleware(
+	next := genericValidationMiddleware(
 		func(ctx *fiber.Ctx, dto *T) error {
 			// Get form value
 			bodyStr := ctx.FormValue(fieldName)
@@ -336,4 +316,10 @@ func FormDataValidationMiddleware[T any](formFieldName string) fiber.Handler {
 		},
 		config,
 	)
+
+	if len(fileRules) == 0 {
+		return next
+	}
+
+	return fileValidationMiddleware(config, fileRules, next)
 }
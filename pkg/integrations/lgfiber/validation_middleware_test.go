@@ -0,0 +1,140 @@
+package lgfiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+type listQuery struct {
+	Limit  int  `query:"limit"`
+	Active bool `query:"active"`
+}
+
+type itemParams struct {
+	ID int `params:"id"`
+}
+
+// validationErrors sends req to app and returns the status and the field errors of the response
+func validationErrors(t *testing.T, app *fiber.App, req *http.Request) (int, []lgerr.ValidationError) {
+	t.Helper()
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body lgerr.ErrorResponse
+	if resp.StatusCode != fiber.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode, body.Errors
+}
+
+func TestCoercionErrorsArePerField(t *testing.T) {
+	app := fiber.New()
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/items", QueryValidationMiddleware[listQuery](), ok)
+	app.Get("/items/:id", ParamsValidationMiddleware[itemParams](), ok)
+
+	tests := []struct {
+		name   string
+		target string
+		fields []string
+	}{
+		{name: "valid query", target: "/items?limit=10&active=true"},
+		{name: "query", target: "/items?limit=abc&active=maybe", fields: []string{"active", "limit"}},
+		{name: "params", target: "/items/abc", fields: []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, errs := validationErrors(t, app, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if len(tt.fields) == 0 {
+				if status != fiber.StatusOK {
+					t.Fatalf("status = %d, want 200", status)
+				}
+				return
+			}
+			if status != fiber.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want 422", status)
+			}
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("errors = %+v, want fields %v", errs, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if errs[i].Field != field || errs[i].Message == "" {
+					t.Fatalf("error %d = %+v, want field %q with a message", i, errs[i], field)
+				}
+			}
+		})
+	}
+}
+
+type strictAddress struct {
+	City string `json:"city"`
+}
+
+type strictOrder struct {
+	Name  string          `json:"name" validate:"required"`
+	Items []strictAddress `json:"items"`
+	Ship  *strictAddress  `json:"ship"`
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	SetBodyValidationConfig(ValidationConfig{DisallowUnknownFields: true})
+	t.Cleanup(ResetValidationConfigs)
+
+	app := fiber.New()
+	app.Post("/", BodyValidationMiddleware[strictOrder](), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{name: "known fields in any case", body: `{"NAME":"a","items":[{"city":"Oslo"}],"ship":{"city":"Bergen"}}`},
+		{name: "top level", body: `{"name":"a","nmae":"b"}`, fields: []string{"nmae"}},
+		{name: "nested", body: `{"name":"a","items":[{"city":"Oslo"},{"zip":"0150"}],"ship":{"cty":"x"}}`, fields: []string{"items[1].zip", "ship.cty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			status, errs := validationErrors(t, app, req)
+			if len(tt.fields) == 0 {
+				if status != fiber.StatusOK {
+					t.Fatalf("status = %d, want 200 (errors: %+v)", status, errs)
+				}
+				return
+			}
+			if status != fiber.StatusUnprocessableEntity || len(errs) != len(tt.fields) {
+				t.Fatalf("status = %d, errors = %+v, want 422 for %v", status, errs, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if errs[i].Field != field || errs[i].Message != "Unknown field" {
+					t.Fatalf("error %d = %+v, want unknown field %q", i, errs[i], field)
+				}
+			}
+		})
+	}
+
+	// Without the option, unknown properties are ignored
+	ResetValidationConfigs()
+	lenient := fiber.New()
+	lenient.Post("/", BodyValidationMiddleware[strictOrder](), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"a","nmae":"b"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if status, errs := validationErrors(t, lenient, req); status != fiber.StatusOK {
+		t.Fatalf("status = %d, errors = %+v, want unknown fields ignored by default", status, errs)
+	}
+}
@@ -0,0 +1,80 @@
+package lgfiber
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterDTO warms the reflection caches for the DTO type T at startup, so the first request does not pay
+// for them, and fails fast on broken struct tags: unknown `validate` rules and unparsable `default` values
+// The default validator is used unless v is given
+//
+// Usage:
+//
+//	func main() {
+//	    if err := lgfiber.RegisterDTO[CreateUserRequest](); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    app.Post("/users", lgfiber.BodyValidationMiddleware[CreateUserRequest](), handler)
+//	}
+func RegisterDTO[T any](v ...*validator.Validate) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("register DTO %s: not a struct", t)
+	}
+
+	validate := getDefaultValidator()
+	if len(v) > 0 && v[0] != nil {
+		validate = v[0]
+	}
+
+	// JSON field names used in validation error responses and metrics
+	if _, ok := fieldNameCache.Load(t); !ok {
+		fieldNameCache.Store(t, collectJSONFieldNames(t))
+	}
+
+	// Known fields used by strict body parsing and response validation
+	getKnownJSONFields(t)
+
+	// Default values: every `default` tag is parsed, and the plan applied per request is cached
+	if err := checkDefaults(t); err != nil {
+		return fmt.Errorf("register DTO %s: %w", t, err)
+	}
+	getDefaultsPlan(t)
+
+	// Validator metadata: the validator parses and caches the struct's tags on first use
+	if err := warmValidator(validate, reflect.New(t).Interface()); err != nil {
+		return fmt.Errorf("register DTO %s: %w", t, err)
+	}
+
+	return nil
+}
+
+// MustRegisterDTO is like RegisterDTO but panics on error
+func MustRegisterDTO[T any](v ...*validator.Validate) {
+	if err := RegisterDTO[T](v...); err != nil {
+		panic(err)
+	}
+}
+
+// warmValidator validates a zero value so the validator caches the struct metadata
+// Failing rules are expected for a zero value; only broken tags (which make the validator panic) are errors
+func warmValidator(v *validator.Validate, dto any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid validate tag: %v", r)
+		}
+	}()
+
+	var invalid *validator.InvalidValidationError
+	if verr := v.Struct(dto); errors.As(verr, &invalid) {
+		return verr
+	}
+	return nil
+}
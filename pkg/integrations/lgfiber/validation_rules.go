@@ -0,0 +1,138 @@
+package lgfiber
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	validationMessages      = make(map[string]string, 16)
+	validationMessagesMutex sync.RWMutex
+
+	slugRegex  = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	phoneRegex = regexp.MustCompile(`^\+?[0-9\s\-().]{7,24}$`)
+	ibanRegex  = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// RegisterValidation registers a custom validation rule on the default validator together with
+// its human-readable message. The message may contain "{param}" which is replaced with the tag
+// parameter (e.g. "max=10" -> "10")
+//
+// Usage:
+//
+//	lgfiber.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+//	    return fl.Field().Int()%2 == 0
+//	}, "Value must be even")
+func RegisterValidation(tag string, fn validator.Func, message string) error {
+	if err := getDefaultValidator().RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("register validation %q: %w", tag, err)
+	}
+	RegisterValidationMessage(tag, message)
+	return nil
+}
+
+// RegisterValidationMessage sets the human-readable message returned for a validation tag
+// Use it to override built-in messages or to describe rules registered directly on a validator
+func RegisterValidationMessage(tag string, message string) {
+	validationMessagesMutex.Lock()
+	validationMessages[tag] = message
+	validationMessagesMutex.Unlock()
+}
+
+// RegisterEnum registers a validation tag that only accepts the given string values
+//
+// Usage:
+//
+//	lgfiber.RegisterEnum("order_status", "pending", "paid", "shipped")
+//
+//	type UpdateOrder struct {
+//	    Status string `json:"status" validate:"required,order_status"`
+//	}
+func RegisterEnum(tag string, values ...string) error {
+	allowed := slices.Clone(values)
+	return RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return slices.Contains(allowed, fl.Field().String())
+	}, "Value must be one of: "+strings.Join(allowed, ", "))
+}
+
+// RegisterCommonValidations registers the built-in logbundle rules on a validator:
+//   - phone: international phone number (digits, spaces, dashes, parentheses, optional leading +)
+//   - slug: lowercase alphanumeric words separated by single dashes
+//   - iban: IBAN with a valid checksum
+//
+// The default validator has these rules registered automatically; call this for validators
+// passed to SetDefaultValidator or ValidationConfig.Validator
+func RegisterCommonValidations(v *validator.Validate) error {
+	rules := map[string]validator.Func{
+		"phone": isPhone,
+		"slug":  isSlug,
+		"iban":  isIBAN,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("register validation %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// getCustomValidationMessage returns the registered message for a tag, if any
+func getCustomValidationMessage(fieldErr validator.FieldError) (string, bool) {
+	validationMessagesMutex.RLock()
+	message, ok := validationMessages[fieldErr.Tag()]
+	validationMessagesMutex.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return strings.ReplaceAll(message, "{param}", fieldErr.Param()), true
+}
+
+func isPhone(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if !phoneRegex.MatchString(value) {
+		return false
+	}
+
+	digits := 0
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+func isSlug(fl validator.FieldLevel) bool {
+	return slugRegex.MatchString(fl.Field().String())
+}
+
+func isIBAN(fl validator.FieldLevel) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(fl.Field().String(), " ", ""))
+	if !ibanRegex.MatchString(iban) {
+		return false
+	}
+
+	// Move the first four characters to the end and convert letters to numbers (A=10 ... Z=35)
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	numeric.Grow(len(rearranged) * 2)
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&numeric, "%d", r-'A'+10)
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
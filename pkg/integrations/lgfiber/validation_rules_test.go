@@ -0,0 +1,101 @@
+package lgfiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+func TestCommonValidations(t *testing.T) {
+	v := validator.New()
+	if err := RegisterCommonValidations(v); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tag   string
+		value string
+		valid bool
+	}{
+		{tag: "phone", value: "+47 (22) 12-34-56", valid: true},
+		{tag: "phone", value: "12345", valid: false},
+		{tag: "phone", value: "+1234567890123456", valid: false},
+		{tag: "slug", value: "summer-sale-2026", valid: true},
+		{tag: "slug", value: "Summer--Sale", valid: false},
+		{tag: "iban", value: "GB82 WEST 1234 5698 7654 32", valid: true},
+		{tag: "iban", value: "GB82WEST12345698765433", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.value, func(t *testing.T) {
+			err := v.Var(tt.value, tt.tag)
+			if (err == nil) != tt.valid {
+				t.Fatalf("Var(%q, %q) err = %v, want valid %v", tt.value, tt.tag, err, tt.valid)
+			}
+		})
+	}
+}
+
+type customRuleDTO struct {
+	Count  int    `json:"count" validate:"lgtest_even"`
+	Status string `json:"status" validate:"lgtest_status"`
+	Name   string `json:"name" validate:"max=3"`
+}
+
+func TestRegisteredValidationMessages(t *testing.T) {
+	err := RegisterValidation("lgtest_even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}, "Value must be even")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEnum("lgtest_status", "open", "closed"); err != nil {
+		t.Fatal(err)
+	}
+	RegisterValidationMessage("max", "At most {param} characters")
+	t.Cleanup(func() {
+		validationMessagesMutex.Lock()
+		delete(validationMessages, "max")
+		validationMessagesMutex.Unlock()
+	})
+
+	app := fiber.New()
+	app.Post("/", BodyValidationMiddleware[customRuleDTO](), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"count":3,"status":"lost","name":"abcd"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", resp.StatusCode)
+	}
+
+	var body lgerr.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	messages := map[string]string{}
+	for _, e := range body.Errors {
+		messages[e.Field] = e.Message
+	}
+	want := map[string]string{
+		"count":  "Value must be even",
+		"status": "Value must be one of: open, closed",
+		"name":   "At most 3 characters",
+	}
+	for field, msg := range want {
+		if messages[field] != msg {
+			t.Fatalf("%s message = %q, want %q (all: %v)", field, messages[field], msg, messages)
+		}
+	}
+}
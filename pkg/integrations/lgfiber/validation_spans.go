@@ -0,0 +1,67 @@
+package lgfiber
+
+import (
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// validationSpanData enables recording validation failures on the request span (see SetValidationSpanData)
+var validationSpanData atomic.Bool
+
+// SetValidationSpanData records validation failures of the validation middlewares on the current Sentry
+// span and as a breadcrumb, so traces of 422-heavy endpoints show why requests short-circuited.
+// Only field names and rule tags are recorded, never the rejected values. Disabled by default
+//
+// Usage:
+//
+//	lgfiber.SetValidationSpanData(true)
+func SetValidationSpanData(enabled bool) {
+	validationSpanData.Store(enabled)
+}
+
+// recordValidationSpan adds the failed fields and rules to the request span and hub breadcrumbs
+func recordValidationSpan(c *fiber.Ctx, parser string, failures []validationFailure) {
+	if !validationSpanData.Load() {
+		return
+	}
+	ctx := c.UserContext()
+	if !config.FromContext(ctx).SentryEnabled() {
+		return
+	}
+
+	fields := make([]string, 0, len(failures))
+	rules := make([]string, 0, len(failures))
+	for _, f := range failures {
+		fields = append(fields, f.field)
+		rules = append(rules, f.field+":"+f.tag)
+	}
+
+	if span := requestSpan(ctx, c); span != nil {
+		span.SetData("validation.parser", parser)
+		span.SetData("validation.failed_fields", fields)
+		span.SetData("validation.failed_rules", rules)
+		span.SetTag("validation_failed", "true")
+	}
+
+	if hub := sentryfiber.GetHubFromContext(c); hub != nil {
+		hub.AddBreadcrumb(&sentry.Breadcrumb{
+			Type:      "info",
+			Category:  "validation",
+			Message:   "Validation failed",
+			Level:     sentry.LevelWarning,
+			Timestamp: core.Now(),
+			Data: map[string]any{
+				"parser": parser,
+				"route":  c.Route().Path,
+				"fields": fields,
+				"rules":  rules,
+			},
+		}, nil)
+	}
+}
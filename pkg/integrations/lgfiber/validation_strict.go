@@ -0,0 +1,158 @@
+package lgfiber
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+	// knownFieldsCache maps a struct type to its JSON field names (lowercased) and field types
+	knownFieldsCache = newTypeCache[map[string]reflect.Type](typeCacheMaxSize)
+)
+
+// unknownFieldsError is returned by the strict body parser when the payload has unexpected properties
+type unknownFieldsError struct {
+	fields []string
+}
+
+func (e *unknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.fields, ", ")
+}
+
+// validationErrors converts the unknown fields into validation errors
+func (e *unknownFieldsError) validationErrors() []lgerr.ValidationError {
+	validationErrors := make([]lgerr.ValidationError, 0, len(e.fields))
+	for _, field := range e.fields {
+		validationErrors = append(validationErrors, lgerr.ValidationError{
+			Field:   field,
+			Message: "Unknown field",
+		})
+	}
+	return validationErrors
+}
+
+// strictBodyParser parses JSON bodies rejecting properties that don't exist in the DTO, then decodes them
+// with the app's fiber.Config.JSONDecoder
+// Non-JSON bodies are decoded by the decoder registered for their Content-Type
+func strictBodyParser[T any](c *fiber.Ctx, dto *T) error {
+	if mediaType := requestMediaType(c); mediaType != fiber.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return negotiatedBodyParser(c, dto)
+	}
+
+	body := c.Body()
+
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+
+	if unknown := findUnknownFields(raw, reflect.TypeOf(dto).Elem(), ""); len(unknown) > 0 {
+		return &unknownFieldsError{fields: unknown}
+	}
+
+	return c.App().Config().JSONDecoder(body, dto)
+}
+
+// findUnknownFields walks a decoded JSON value and returns the paths of properties
+// that have no matching field in t (matching is case-insensitive, like encoding/json)
+func findUnknownFields(value any, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Types with custom decoding define their own shape
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	var unknown []string
+
+	switch v := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			known := getKnownJSONFields(t)
+
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				fieldType, ok := known[strings.ToLower(k)]
+				if !ok {
+					unknown = append(unknown, prefix+k)
+					continue
+				}
+				unknown = append(unknown, findUnknownFields(v[k], fieldType, prefix+k+".")...)
+			}
+		case reflect.Map:
+			for k, item := range v {
+				unknown = append(unknown, findUnknownFields(item, t.Elem(), prefix+k+".")...)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			base := strings.TrimSuffix(prefix, ".")
+			for i, item := range v {
+				unknown = append(unknown, findUnknownFields(item, t.Elem(), base+"["+strconv.Itoa(i)+"].")...)
+			}
+		}
+	}
+
+	return unknown
+}
+
+// getKnownJSONFields returns the JSON field names of a struct type, including promoted embedded fields
+func getKnownJSONFields(t reflect.Type) map[string]reflect.Type {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached
+	}
+
+	known := make(map[string]reflect.Type, t.NumField())
+	collectJSONFields(t, known)
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+func collectJSONFields(t reflect.Type, known map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name are flattened by encoding/json
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectJSONFields(ft, known)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = field.Type
+	}
+}
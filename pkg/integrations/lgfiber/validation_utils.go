@@ -1,7 +1,10 @@
 package lgfiber
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
@@ -43,47 +46,37 @@ func getJSONFieldName(dto any, fieldName string) string {
 		return ""
 	}
 
-	fieldNameCacheMutex.RLock()
-	if typeCache, exists := fieldNameCache[t]; exists {
-		if jsonName, found := typeCache[fieldName]; found {
-			fieldNameCacheMutex.RUnlock()
-			return jsonName
-		}
-	}
-	fieldNameCacheMutex.RUnlock()
-
-	field, found := t.FieldByName(fieldName)
-	if !found {
-		return ""
-	}
-
-	jsonTag := field.Tag.Get("json")
-	if jsonTag == "" {
-		return ""
+	names, ok := fieldNameCache.Load(t)
+	if !ok {
+		names = collectJSONFieldNames(t)
+		fieldNameCache.Store(t, names)
 	}
+	return names[fieldName]
+}
 
-	parts := strings.Split(jsonTag, ",")
-	if parts[0] == "-" {
-		return ""
-	}
-
-	jsonName := parts[0]
-
-	fieldNameCacheMutex.Lock()
-	// Prevent unbounded cache growth - only cache if under limit
-	if len(fieldNameCache) < cacheMaxSize {
-		if fieldNameCache[t] == nil {
-			fieldNameCache[t] = make(map[string]string)
+// collectJSONFieldNames maps the Go names of a struct's visible fields (including promoted ones) to their
+// JSON names. Fields without a json tag or tagged "-" are omitted
+func collectJSONFieldNames(t reflect.Type) map[string]string {
+	names := make(map[string]string, t.NumField())
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			continue
 		}
-		fieldNameCache[t][fieldName] = jsonName
+		names[field.Name] = jsonName
 	}
-	fieldNameCacheMutex.Unlock()
-
-	return jsonName
+	return names
 }
 
 // getValidationMessage returns a human-readable error message for the validation tag
 func getValidationMessage(fieldErr validator.FieldError) string {
+	if message, ok := getCustomValidationMessage(fieldErr); ok {
+		return message
+	}
+
 	switch fieldErr.Tag() {
 	case "required":
 		return "This field is required"
@@ -115,7 +108,122 @@ func getValidationMessage(fieldErr validator.FieldError) string {
 		return "Only numeric characters allowed"
 	case "oneof":
 		return "Value must be one of: " + fieldErr.Param()
+	case "phone", "e164":
+		return "Invalid phone number format"
+	case "slug":
+		return "Only lowercase letters, digits and single dashes allowed"
+	case "iban":
+		return "Invalid IBAN"
+	case "timezone":
+		return "Invalid time zone"
 	default:
 		return "Validation failed: " + fieldErr.Tag()
 	}
 }
+
+// parseParserErrors converts parser failures that can be attributed to specific fields into validation errors
+// The returned tag ("unknown" or "type") classifies the failure for metrics
+func parseParserErrors(err error) ([]lgerr.ValidationError, string) {
+	var unknownErr *unknownFieldsError
+	if errors.As(err, &unknownErr) {
+		return unknownErr.validationErrors(), "unknown"
+	}
+	if bodyErrors := parseBodyTypeError(err); len(bodyErrors) > 0 {
+		return bodyErrors, "type"
+	}
+	return parseCoercionErrors(err), "type"
+}
+
+// parseBodyTypeError converts a JSON type mismatch (e.g. a string for an int field) into a field error
+// Returns nil for other errors
+func parseBodyTypeError(err error) []lgerr.ValidationError {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return nil
+	}
+	return []lgerr.ValidationError{{
+		Field:   typeErr.Field,
+		Message: "Invalid value, expected " + describeKind(typeErr.Type),
+	}}
+}
+
+// parseCoercionErrors extracts per-field type conversion errors from fiber's Query/Params/Header parsers
+// Fiber wraps its internal schema.MultiError (map[string]error of ConversionError/EmptyFieldError),
+// so the errors are inspected via reflection. Returns nil if err is not a coercion error
+func parseCoercionErrors(err error) []lgerr.ValidationError {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		v := reflect.ValueOf(e)
+		if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			continue
+		}
+
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		validationErrors := make([]lgerr.ValidationError, 0, len(keys))
+		for _, key := range keys {
+			fieldErr, ok := v.MapIndex(reflect.ValueOf(key)).Interface().(error)
+			if !ok {
+				continue
+			}
+			validationErrors = append(validationErrors, coercionValidationError(key, fieldErr))
+		}
+		return validationErrors
+	}
+
+	return nil
+}
+
+// coercionValidationError converts a single schema decoding error into a ValidationError
+func coercionValidationError(key string, err error) lgerr.ValidationError {
+	ve := lgerr.ValidationError{
+		Field:   key,
+		Message: "Invalid value",
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Struct {
+		return ve
+	}
+
+	if f := v.FieldByName("Key"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+		ve.Field = f.String()
+	}
+
+	switch v.Type().Name() {
+	case "EmptyFieldError":
+		ve.Message = "This field is required"
+	case "UnknownKeyError":
+		ve.Message = "Unknown parameter"
+	case "ConversionError":
+		if t, ok := v.FieldByName("Type").Interface().(reflect.Type); ok && t != nil {
+			ve.Message = "Invalid value, expected " + describeKind(t)
+		}
+	}
+
+	return ve
+}
+
+// describeKind returns a client-friendly name for the expected type of a parameter
+func describeKind(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,243 @@
+package lgfiber
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+)
+
+// WebhookScheme defines how a webhook signature header is encoded
+type WebhookScheme string
+
+const (
+	// SchemeHMACSHA256 expects a hex-encoded HMAC-SHA256 of the body, optionally prefixed with "sha256="
+	SchemeHMACSHA256 WebhookScheme = "hmac-sha256"
+	// SchemeGitHub expects "sha256=<hex>" computed over the body; the legacy "sha1=<hex>" is only
+	// accepted from the legacy X-Hub-Signature header
+	SchemeGitHub WebhookScheme = "github"
+	// SchemeStripe expects "t=<unix>,v1=<hex>[,v1=<hex>]" computed over "<t>.<body>"
+	SchemeStripe WebhookScheme = "stripe"
+)
+
+// WebhookConfig holds configuration for webhook signature validation middleware
+type WebhookConfig struct {
+	// Provider name used in logs and Sentry tags (e.g. "github", "stripe")
+	Provider string
+	// Secret shared with the provider
+	Secret []byte
+	// Scheme defines the signature format (default: SchemeHMACSHA256)
+	Scheme WebhookScheme
+	// SignatureHeader is the header carrying the signature
+	SignatureHeader string
+	// IDHeader is the header carrying the delivery ID (optional)
+	IDHeader string
+	// Tolerance is the maximum age of a timestamped signature (Stripe only, default: 5m)
+	Tolerance time.Duration
+}
+
+// legacyGitHubSignatureHeader is the only header SchemeGitHub accepts SHA-1 signatures from
+const legacyGitHubSignatureHeader = "X-Hub-Signature"
+
+// GitHubWebhook returns a WebhookConfig for GitHub webhooks
+func GitHubWebhook(secret string) WebhookConfig {
+	return WebhookConfig{
+		Provider:        "github",
+		Secret:          []byte(secret),
+		Scheme:          SchemeGitHub,
+		SignatureHeader: "X-Hub-Signature-256",
+		IDHeader:        "X-GitHub-Delivery",
+	}
+}
+
+// StripeWebhook returns a WebhookConfig for Stripe webhooks
+func StripeWebhook(secret string) WebhookConfig {
+	return WebhookConfig{
+		Provider:        "stripe",
+		Secret:          []byte(secret),
+		Scheme:          SchemeStripe,
+		SignatureHeader: "Stripe-Signature",
+		Tolerance:       5 * time.Minute,
+	}
+}
+
+// WebhookValidationMiddleware creates a middleware that verifies HMAC webhook signatures
+// Failed verifications are logged as lgerr.Unauthorized and reported to Sentry as warnings
+// tagged with the provider and delivery ID. Secrets and signatures are never logged
+// Panics when cfg.Secret is empty: anyone can compute an HMAC with an empty key, so an unset secret
+// (e.g. a missing environment variable) must fail at startup instead of accepting forged deliveries
+//
+// Usage:
+//
+//	app.Post("/webhooks/github",
+//	    lgfiber.WebhookValidationMiddleware(lgfiber.GitHubWebhook(os.Getenv("GITHUB_WEBHOOK_SECRET"))),
+//	    handler,
+//	)
+func WebhookValidationMiddleware(cfg WebhookConfig) fiber.Handler {
+	if len(cfg.Secret) == 0 {
+		panic("lgfiber: WebhookValidationMiddleware requires a non-empty secret (provider " + strconv.Quote(cfg.Provider) + ")")
+	}
+	if cfg.Scheme == "" {
+		cfg.Scheme = SchemeHMACSHA256
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = string(cfg.Scheme)
+	}
+	if cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = "X-Signature"
+	}
+	if cfg.Scheme == SchemeStripe && cfg.Tolerance <= 0 {
+		cfg.Tolerance = 5 * time.Minute
+	}
+
+	return func(c *fiber.Ctx) error {
+		var deliveryID string
+		if cfg.IDHeader != "" {
+			deliveryID = c.Get(cfg.IDHeader)
+		}
+
+		signature := c.Get(cfg.SignatureHeader)
+		var reason string
+		if signature == "" {
+			reason = "missing signature header"
+		} else {
+			reason = verifyWebhookSignature(cfg, signature, c.Body())
+		}
+
+		if reason == "" {
+			return c.Next()
+		}
+
+		lgErr := lgerr.Unauthorized("invalid webhook signature",
+			lgerr.WithDetail("Webhook signature verification failed"),
+			lgerr.WithContext("provider", cfg.Provider),
+			lgerr.WithContext("reason", reason),
+		)
+		if deliveryID != "" {
+			lgErr.WithContext("webhook_id", deliveryID)
+		}
+
+		var sentryEventID *sentry.EventID
+		if config.FromContext(c.UserContext()).SentryEnabled() && !lgErr.ShouldIgnoreSentry() {
+			if hub := sentryfiber.GetHubFromContext(c); hub != nil {
+				hub.WithScope(func(scope *sentry.Scope) {
+					lgreport.LinkSpan(scope, c.UserContext(), reportRequest(c))
+					scope.SetTags(core.SentryScopeTags(c.UserContext()))
+					scope.SetLevel(sentry.LevelWarning)
+					scope.SetTag("error_source", "webhook_validation")
+					scope.SetTag("webhook_provider", cfg.Provider)
+					if deliveryID != "" {
+						scope.SetTag("webhook_id", deliveryID)
+					}
+					scope.SetContext("webhook", map[string]any{
+						"provider": cfg.Provider,
+						"reason":   reason,
+						"route":    c.Route().Path,
+					})
+					scope.SetFingerprint([]string{"webhook_validation", cfg.Provider, reason})
+					sentryEventID = hub.CaptureMessage("Webhook signature verification failed: " + cfg.Provider)
+				})
+			}
+		}
+
+		lgreport.Log(c.UserContext(), lgErr, sentryEventID, reportRequest(c))
+
+		return c.Status(lgErr.HTTPStatus()).JSON(lgreport.ErrorResponse(lgErr))
+	}
+}
+
+// verifyWebhookSignature returns an empty string if the signature is valid, otherwise a sanitized reason
+func verifyWebhookSignature(cfg WebhookConfig, signature string, body []byte) string {
+	switch cfg.Scheme {
+	case SchemeStripe:
+		return verifyStripeSignature(cfg, signature, body)
+	case SchemeGitHub:
+		algo, sig, ok := strings.Cut(signature, "=")
+		if !ok {
+			return "malformed signature header"
+		}
+		switch algo {
+		case "sha256":
+			return compareHMAC(sha256.New, cfg.Secret, body, sig)
+		case "sha1":
+			if !strings.EqualFold(cfg.SignatureHeader, legacyGitHubSignatureHeader) {
+				return "unsupported signature algorithm"
+			}
+			return compareHMAC(sha1.New, cfg.Secret, body, sig)
+		default:
+			return "unsupported signature algorithm"
+		}
+	default:
+		return compareHMAC(sha256.New, cfg.Secret, body, strings.TrimPrefix(signature, "sha256="))
+	}
+}
+
+// verifyStripeSignature validates a Stripe-style "t=...,v1=..." signature header
+func verifyStripeSignature(cfg WebhookConfig, header string, body []byte) string {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return "malformed signature header"
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "malformed signature timestamp"
+	}
+	if age := core.Since(time.Unix(ts, 0)); age > cfg.Tolerance || age < -cfg.Tolerance {
+		return "signature timestamp outside tolerance"
+	}
+
+	payload := make([]byte, 0, len(timestamp)+1+len(body))
+	payload = append(payload, timestamp...)
+	payload = append(payload, '.')
+	payload = append(payload, body...)
+
+	for _, sig := range signatures {
+		if compareHMAC(sha256.New, cfg.Secret, payload, sig) == "" {
+			return ""
+		}
+	}
+	return "signature mismatch"
+}
+
+// compareHMAC computes the HMAC of payload and compares it in constant time with the hex signature
+func compareHMAC(h func() hash.Hash, secret, payload []byte, signature string) string {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return "malformed signature encoding"
+	}
+
+	mac := hmac.New(h, secret)
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return "signature mismatch"
+	}
+	return ""
+}
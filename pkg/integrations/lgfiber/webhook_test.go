@@ -0,0 +1,168 @@
+package lgfiber
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+func sign(h func() hash.Hash, secret, body string) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookValidationRequiresSecret(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("empty secret accepted")
+		}
+	}()
+	WebhookValidationMiddleware(GitHubWebhook(""))
+}
+
+func TestWebhookValidationGitHub(t *testing.T) {
+	const secret, body = "s3cret", `{"action":"opened"}`
+	legacy := GitHubWebhook(secret)
+	legacy.SignatureHeader = "X-Hub-Signature"
+
+	tests := []struct {
+		name   string
+		cfg    WebhookConfig
+		header string
+		value  string
+		want   int
+	}{
+		{"sha256", GitHubWebhook(secret), "X-Hub-Signature-256", "sha256=" + sign(sha256.New, secret, body), fiber.StatusNoContent},
+		{"sha256 mismatch", GitHubWebhook(secret), "X-Hub-Signature-256", "sha256=" + sign(sha256.New, "other", body), fiber.StatusUnauthorized},
+		{"sha1 in sha256 header", GitHubWebhook(secret), "X-Hub-Signature-256", "sha1=" + sign(sha1.New, secret, body), fiber.StatusUnauthorized},
+		{"sha1 in legacy header", legacy, "X-Hub-Signature", "sha1=" + sign(sha1.New, secret, body), fiber.StatusNoContent},
+		{"missing", GitHubWebhook(secret), "X-Other", "x", fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/hook", WebhookValidationMiddleware(tt.cfg), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPost, "/hook", strings.NewReader(body))
+			req.Header.Set(tt.header, tt.value)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+// fixedClock reports a constant time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestVerifyStripeSignature(t *testing.T) {
+	const secret, body = "whsec_test", `{"type":"charge.succeeded"}`
+	now := time.Unix(1_700_000_000, 0)
+	core.SetClock(fixedClock(now))
+	t.Cleanup(func() { core.SetClock(nil) })
+
+	stamp := func(at time.Time) string { return strconv.FormatInt(at.Unix(), 10) }
+	ts := stamp(now)
+	valid := sign(sha256.New, secret, ts+"."+body)
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"valid", "t=" + ts + ",v1=" + valid, ""},
+		{"spaces and unknown parts", " t=" + ts + " , v0=abc , v1=" + valid, ""},
+		{"second v1 matches", "t=" + ts + ",v1=" + sign(sha256.New, "rotated", ts+"."+body) + ",v1=" + valid, ""},
+		{"signed without timestamp", "t=" + ts + ",v1=" + sign(sha256.New, secret, body), "signature mismatch"},
+		{"other timestamp", "t=" + stamp(now.Add(-time.Second)) + ",v1=" + valid, "signature mismatch"},
+		{"missing timestamp", "v1=" + valid, "malformed signature header"},
+		{"missing v1", "t=" + ts + ",v0=" + valid, "malformed signature header"},
+		{"non-numeric timestamp", "t=soon,v1=" + valid, "malformed signature timestamp"},
+		{"non-hex signature", "t=" + ts + ",v1=zz", "signature mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyWebhookSignature(StripeWebhook(secret), tt.header, []byte(body)); got != tt.want {
+				t.Fatalf("verifyWebhookSignature(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyStripeSignatureTolerance(t *testing.T) {
+	const secret, body = "whsec_test", `{}`
+	now := time.Unix(1_700_000_000, 0)
+	core.SetClock(fixedClock(now))
+	t.Cleanup(func() { core.SetClock(nil) })
+
+	cfg := StripeWebhook(secret)
+	cfg.Tolerance = time.Minute
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, ""},
+		{-time.Minute, ""},
+		{time.Minute, ""},
+		{-time.Minute - time.Second, "signature timestamp outside tolerance"},
+		{time.Minute + time.Second, "signature timestamp outside tolerance"},
+	}
+	for _, tt := range tests {
+		ts := strconv.FormatInt(now.Add(tt.offset).Unix(), 10)
+		header := "t=" + ts + ",v1=" + sign(sha256.New, secret, ts+"."+body)
+		if got := verifyWebhookSignature(cfg, header, []byte(body)); got != tt.want {
+			t.Fatalf("signature %v from now = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookValidationStripe(t *testing.T) {
+	const secret, body = "whsec_test", `{"type":"charge.succeeded"}`
+	app := fiber.New()
+	// Tolerance left zero: the middleware applies the 5 minute default
+	app.Post("/hook", WebhookValidationMiddleware(WebhookConfig{
+		Secret:          []byte(secret),
+		Scheme:          SchemeStripe,
+		SignatureHeader: "Stripe-Signature",
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, tt := range []struct {
+		age  time.Duration
+		want int
+	}{
+		{4 * time.Minute, fiber.StatusNoContent},
+		{6 * time.Minute, fiber.StatusUnauthorized},
+	} {
+		ts := strconv.FormatInt(time.Now().Add(-tt.age).Unix(), 10)
+		req := httptest.NewRequest(fiber.MethodPost, "/hook", strings.NewReader(body))
+		req.Header.Set("Stripe-Signature", "t="+ts+",v1="+sign(sha256.New, secret, ts+"."+body))
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Fatalf("signature %v old: status %d, want %d", tt.age, resp.StatusCode, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package lgreport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/strutil"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// RecoverGoroutinePanic recovers from panics in goroutines and logs them with full context
+// This function should be used as: defer RecoverGoroutinePanic(ctx, "goroutineName")
+// The Sentry hub of ctx is used, falling back to the current hub
+// It captures panic details, logs them, and sends to Sentry if enabled
+func RecoverGoroutinePanic(ctx context.Context, goroutineName string) {
+	if r := recover(); r != nil {
+		ReportGoroutinePanic(ctx, goroutineName, r)
+	}
+}
+
+// ReportGoroutinePanic logs and reports the value r recovered from a panic in a goroutine, like
+// RecoverGoroutinePanic; for deferred functions that call recover() themselves
+func ReportGoroutinePanic(ctx context.Context, goroutineName string, r any) {
+	// Get hub from context, fallback to current
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	info := RecoverPanic(ctx, r, hub, func(scope *sentry.Scope, info *PanicInfo) {
+		scope.SetLevel(sentry.LevelFatal)
+		scope.SetTag("error_source", "goroutine_panic_recovery")
+		scope.SetTag("goroutine_name", goroutineName)
+		scope.SetTag("handled", "false")
+
+		scope.SetContext("goroutine_details", map[string]any{
+			"goroutine_name": goroutineName,
+		})
+
+		scope.SetFingerprint([]string{
+			"goroutine_panic",
+			goroutineName,
+			fmt.Sprintf("%v", r),
+			info.ErrorLocation,
+		})
+
+		hub.AddBreadcrumb(&sentry.Breadcrumb{
+			Type:     "error",
+			Category: "goroutine_panic",
+			Message:  fmt.Sprintf("Panic in goroutine '%s': %v", goroutineName, r),
+			Level:    sentry.LevelFatal,
+			Data: map[string]any{
+				"recovered_value": fmt.Sprintf("%v", r),
+				"goroutine_name":  goroutineName,
+				"location":        info.ErrorLocation,
+			},
+		}, nil)
+	})
+
+	logFields := append([]any{
+		slog.String("goroutine_name", goroutineName),
+	}, info.LogFields()...)
+
+	Logger(ctx).ErrorContext(ctx, "Unhandled panic in goroutine", logFields...)
+}
+
+// PanicInfo describes a recovered panic
+type PanicInfo struct {
+	Value         any
+	StackTrace    string
+	ErrorLocation string
+	File          string
+	Line          int
+	// SentryEventID is nil when the panic was not sent to Sentry
+	SentryEventID *sentry.EventID
+}
+
+// RecoverPanic reports the recovered value r to Sentry through hub (when enabled) and returns its
+// details for logging; enrichScope adds the caller's tags, level and fingerprint before the capture
+// Call it from a deferred function right after recover(), so the stack still shows the panic site
+func RecoverPanic(ctx context.Context, r any, hub *sentry.Hub, enrichScope func(*sentry.Scope, *PanicInfo)) *PanicInfo {
+	stackTrace := string(debug.Stack())
+	// Walk the live stack first; the textual trace is only parsed when no application frame is found
+	errorLoc, file, line := core.CallerErrorLocation()
+	if file == "" {
+		errorLoc, file, line = core.ExtractErrorLocationWithDetails(stackTrace)
+	}
+
+	info := &PanicInfo{
+		Value:         r,
+		StackTrace:    stackTrace,
+		ErrorLocation: errorLoc,
+		File:          file,
+		Line:          line,
+	}
+
+	if config.FromContext(ctx).SentryEnabled() && hub != nil {
+		hub.WithScope(func(scope *sentry.Scope) {
+			LinkSpan(scope, ctx, nil)
+			scope.SetTags(core.SentryScopeTags(ctx))
+			scope.SetTag("panic_recovered", "true")
+			scope.SetContext("panic_details", map[string]any{
+				"recovered_value": fmt.Sprintf("%v", r),
+				"stack_trace":     strutil.Truncate(stackTrace, 5000),
+				"error_location":  errorLoc,
+			})
+
+			if file != "" && line > 0 {
+				scope.SetTag("panic_file", file)
+				scope.SetTag("panic_line", fmt.Sprintf("%d", line))
+				scope.SetContext("source", map[string]any{
+					"file": file,
+					"line": line,
+				})
+			}
+
+			enrichScope(scope, info)
+			info.SentryEventID = hub.CaptureException(fmt.Errorf("panic: %v", r))
+		})
+	}
+
+	return info
+}
+
+// LogFields returns the log attributes of the panic
+func (pi *PanicInfo) LogFields() []any {
+	fields := []any{
+		slog.Any("panic_value", pi.Value),
+		slog.String("error_location", pi.ErrorLocation),
+		slog.String("stack_trace", strutil.Truncate(pi.StackTrace, 5000)),
+	}
+
+	if pi.SentryEventID != nil {
+		fields = append(fields, slog.String("sentry_event_id", string(*pi.SentryEventID)))
+	}
+
+	if pi.File != "" && pi.Line > 0 {
+		fields = append(fields, slog.Any("source", slog.Source{
+			File: pi.File,
+			Line: pi.Line,
+		}))
+	}
+
+	return fields
+}
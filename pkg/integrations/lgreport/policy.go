@@ -0,0 +1,104 @@
+package lgreport
+
+import (
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// ErrorPolicy controls how an error is logged and what is returned to the client
+type ErrorPolicy struct {
+	// Level overrides the log level (default: Error for 5xx, Warn for 4xx, Info otherwise)
+	Level slog.Leveler
+	// Silent skips logging (e.g. for expected validation errors); Sentry reporting is unaffected
+	Silent bool
+	// StackTrace logs the stack trace below 500 (it is always logged for 5xx)
+	StackTrace bool
+	// HideContext omits the error context from the response "meta" (it is still logged)
+	HideContext bool
+}
+
+// ErrorPolicies selects the ErrorPolicy of an error by lgerr type or HTTP status
+// A type policy takes precedence over a status policy; errors matching neither use the defaults
+type ErrorPolicies struct {
+	ByType   map[lgerr.ErrorType]ErrorPolicy
+	ByStatus map[int]ErrorPolicy
+}
+
+var (
+	errorPolicies   ErrorPolicies
+	errorPoliciesMu sync.RWMutex
+)
+
+// SetErrorPolicies sets the policies used by HandleError, ErrorResponse and the error handlers of the
+// HTTP integrations (lgfiber, lgfasthttp)
+//
+// Usage:
+//
+//	lgreport.SetErrorPolicies(lgreport.ErrorPolicies{
+//	    ByType: map[lgerr.ErrorType]lgreport.ErrorPolicy{
+//	        lgerr.TypeValidation: {Silent: true},
+//	        lgerr.TypeInternal:   {HideContext: true},
+//	    },
+//	    ByStatus: map[int]lgreport.ErrorPolicy{
+//	        http.StatusNotFound: {Level: slog.LevelDebug},
+//	    },
+//	})
+func SetErrorPolicies(p ErrorPolicies) {
+	errorPoliciesMu.Lock()
+	defer errorPoliciesMu.Unlock()
+
+	errorPolicies = ErrorPolicies{
+		ByType:   maps.Clone(p.ByType),
+		ByStatus: maps.Clone(p.ByStatus),
+	}
+}
+
+// ResetErrorPolicies restores the default logging and response behavior
+func ResetErrorPolicies() {
+	SetErrorPolicies(ErrorPolicies{})
+}
+
+// PolicyFor returns the policy of lgErr
+func PolicyFor(lgErr *lgerr.Error) ErrorPolicy {
+	errorPoliciesMu.RLock()
+	defer errorPoliciesMu.RUnlock()
+
+	if p, ok := errorPolicies.ByType[lgErr.Type()]; ok {
+		return p
+	}
+	if p, ok := errorPolicies.ByStatus[lgErr.HTTPStatus()]; ok {
+		return p
+	}
+	return ErrorPolicy{}
+}
+
+// LogLevel returns the level an error with statusCode is logged at
+func (p ErrorPolicy) LogLevel(statusCode int) slog.Level {
+	switch {
+	case p.Level != nil:
+		return p.Level.Level()
+	case statusCode >= 500:
+		return slog.LevelError
+	case statusCode >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Response returns the response body of lgErr under the policy
+func (p ErrorPolicy) Response(lgErr *lgerr.Error) lgerr.ErrorResponse {
+	response := lgErr.ToErrorResponse()
+	if p.HideContext {
+		response.Meta = nil
+	}
+	return response
+}
+
+// ErrorResponse returns the client response of lgErr under the configured policies
+func ErrorResponse(lgErr *lgerr.Error) lgerr.ErrorResponse {
+	return PolicyFor(lgErr).Response(lgErr)
+}
@@ -0,0 +1,301 @@
+// Package lgreport logs and reports lgerr errors and recovered panics to Sentry without depending on an
+// HTTP framework. lgfiber, lgfasthttp, lgcron and lgtemporal share it, so errors look the same in logs
+// and Sentry whichever integration handled them
+package lgreport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/errspike"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// Request describes the request an error occurred in, for integrations that handle HTTP errors
+type Request struct {
+	URL    string
+	Method string
+	// Route is the matched route pattern; it groups error spikes (see errspike)
+	Route string
+	// Span is the request transaction, used when ctx carries no span
+	Span *sentry.Span
+}
+
+// HandleError manually handles an lgerr.Error with logging and Sentry reporting
+// Use this for explicit error handling in goroutines or background tasks
+//
+// Example usage in goroutine:
+//
+//	go func() {
+//	    err := performBackgroundTask()
+//	    if err != nil {
+//	        lgErr := lgerr.Internal("background task failed").Wrap(err)
+//	        lgreport.HandleError(ctx, lgErr)
+//	    }
+//	}()
+func HandleError(ctx context.Context, lgErr *lgerr.Error) *sentry.EventID {
+	if lgErr == nil {
+		return nil
+	}
+
+	var sentryEventID *sentry.EventID
+	if ShouldCapture(ctx, lgErr) {
+		sentryEventID = Capture(ctx, sentry.GetHubFromContext(ctx), lgErr, "manual_handle", nil)
+	}
+
+	Log(ctx, lgErr, sentryEventID, nil)
+
+	return sentryEventID
+}
+
+// ShouldCapture reports whether lgErr is sent to Sentry: Sentry is enabled, the error does not ignore
+// Sentry, was not caused by the caller canceling ctx and its status reaches the configured minimum
+// It needs no hub, so callers can skip looking one up for most errors
+func ShouldCapture(ctx context.Context, lgErr *lgerr.Error) bool {
+	settings := config.FromContext(ctx)
+
+	if !settings.SentryEnabled() {
+		return false
+	}
+
+	if lgErr.ShouldIgnoreSentry() {
+		return false
+	}
+
+	// The caller's context was canceled (e.g. the client went away); nothing failed on our side
+	if Canceled(ctx, lgErr) {
+		return false
+	}
+
+	// If minStatus is 0, send all errors
+	minStatus := settings.SentryMinHTTPStatus()
+	if minStatus == 0 {
+		return true
+	}
+
+	return lgErr.HTTPStatus() >= minStatus
+}
+
+// Canceled reports whether err is a cancellation and ctx itself was canceled, so it came from the
+// caller rather than from a call that was canceled on its own
+func Canceled(ctx context.Context, err error) bool {
+	return ctx != nil && errors.Is(err, context.Canceled) && errors.Is(ctx.Err(), context.Canceled)
+}
+
+// Capture captures an lgerr.Error to Sentry with full context; source becomes the error_source tag
+// req is nil outside a request
+func Capture(ctx context.Context, hub *sentry.Hub, lgErr *lgerr.Error, source string, req *Request) *sentry.EventID {
+	if hub == nil {
+		return nil
+	}
+
+	var eventID *sentry.EventID
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		LinkSpan(scope, ctx, req)
+
+		// Context tags first so the tags below take precedence
+		scope.SetTags(core.SentryScopeTags(ctx))
+
+		// Set basic tags
+		scope.SetLevel(sentry.LevelError)
+		scope.SetTag("error_source", source)
+		scope.SetTag("error_type", string(lgErr.Type()))
+		scope.SetTag("status_code", fmt.Sprintf("%d", lgErr.HTTPStatus()))
+
+		// Add error context
+		if errCtx := lgErr.Context(); len(errCtx) > 0 {
+			scope.SetContext("error_context", errCtx)
+		}
+
+		// Add source location if available
+		if lgErr.File() != "" && lgErr.Line() > 0 {
+			scope.SetTag("error_file", lgErr.File())
+			scope.SetTag("error_line", fmt.Sprintf("%d", lgErr.Line()))
+			scope.SetContext("source", map[string]any{
+				"file": lgErr.File(),
+				"line": lgErr.Line(),
+			})
+		}
+
+		// Set fingerprint for grouping; an explicit fingerprint replaces the message so that
+		// translated errors (see lgerr.FromError) group by cause rather than by embedded values
+		if fp := lgErr.Fingerprint(); len(fp) > 0 {
+			scope.SetFingerprint(append([]string{source, string(lgErr.Type())}, fp...))
+		} else {
+			scope.SetFingerprint([]string{
+				source,
+				string(lgErr.Type()),
+				lgErr.Message(),
+			})
+		}
+
+		// Build Sentry exception
+		event := sentry.NewEvent()
+		event.Level = sentry.LevelError
+		event.Message = lgErr.Message()
+
+		exception := sentry.Exception{
+			Type:  fmt.Sprintf("lgerr.%s", lgErr.Type()),
+			Value: lgErr.Error(),
+			Mechanism: &sentry.Mechanism{
+				Type:    "lgerr_handler",
+				Handled: func() *bool { b := true; return &b }(),
+			},
+		}
+
+		// Add stack trace if available
+		if stackTrace := lgErr.StackTrace(); len(stackTrace) > 0 {
+			exception.Stacktrace = buildStacktrace(stackTrace)
+		}
+
+		// Add wrapped error info
+		if wrapped := lgErr.Wrapped(); wrapped != nil {
+			if exception.Mechanism.Data == nil {
+				exception.Mechanism.Data = make(map[string]any)
+			}
+			exception.Mechanism.Data["wrapped_error"] = wrapped.Error()
+			exception.Mechanism.Data["wrapped_error_type"] = fmt.Sprintf("%T", wrapped)
+		}
+
+		event.Exception = []sentry.Exception{exception}
+		eventID = hub.CaptureEvent(event)
+	})
+
+	return eventID
+}
+
+// LinkSpan sets the active span on the scope so the event carries its trace_id/span_id and Sentry shows
+// it in the request transaction's trace view: the span of ctx, else the span of req
+// The hub scope only holds the transaction when EnableTracing is set, so it is looked up explicitly
+func LinkSpan(scope *sentry.Scope, ctx context.Context, req *Request) {
+	if span := sentry.SpanFromContext(ctx); span != nil {
+		scope.SetSpan(span)
+	} else if req != nil && req.Span != nil {
+		scope.SetSpan(req.Span)
+	}
+}
+
+// Log logs an error with appropriate level and context (see SetErrorPolicies); req is nil outside a
+// request
+func Log(ctx context.Context, lgErr *lgerr.Error, sentryEventID *sentry.EventID, req *Request) {
+	statusCode := lgErr.HTTPStatus()
+	policy := PolicyFor(lgErr)
+
+	// Build log fields
+	logFields := []any{
+		fields.HTTPStatus(statusCode),
+		fields.ErrorType(string(lgErr.Type())),
+		fields.ErrorMessage(lgErr.Message()),
+	}
+
+	// Feed the error spike analyzer (no-op unless errspike.Start was called)
+	route := ""
+	if req != nil {
+		route = req.Route
+	}
+	errspike.Record(errspike.Fingerprint(string(lgErr.Type()), route, lgErr.Message()), lgErr.Error())
+
+	if policy.Silent {
+		return
+	}
+
+	// Add request info if available
+	if req != nil {
+		logFields = append(logFields,
+			fields.URL(req.URL),
+			fields.Method(req.Method),
+			fields.Route(req.Route),
+		)
+	}
+
+	// Add error context
+	if len(lgErr.Context()) > 0 {
+		logFields = append(logFields, lgErr.ContextAttr())
+	}
+
+	// Add source location
+	if lgErr.File() != "" && lgErr.Line() > 0 {
+		logFields = append(logFields, slog.Any("source", slog.Source{
+			File: lgErr.File(),
+			Line: lgErr.Line(),
+		}))
+	}
+
+	// Add Sentry event ID if captured
+	if sentryEventID != nil {
+		logFields = append(logFields, fields.SentryEventID(string(*sentryEventID)))
+	}
+
+	// Add wrapped error
+	if wrapped := lgErr.Wrapped(); wrapped != nil {
+		logFields = append(logFields,
+			slog.String("wrapped_error", wrapped.Error()),
+			fields.GoType("wrapped_error_type", wrapped),
+		)
+	}
+
+	// Add stack trace for server errors
+	if statusCode >= 500 || policy.StackTrace {
+		if stackTrace := lgErr.FormatStackTrace(); stackTrace != "" {
+			logFields = append(logFields, fields.StackTrace(stackTrace))
+		}
+	}
+
+	// Log with appropriate level
+	msg := "Error handled"
+	if statusCode >= 500 {
+		msg = "Server error"
+	} else if statusCode >= 400 {
+		msg = "Client error"
+	}
+	Logger(ctx).Log(ctx, policy.LogLevel(statusCode), msg, logFields...)
+}
+
+// Logger returns the middleware logger of the context's settings if configured, otherwise the internal
+// logger
+func Logger(ctx context.Context) *slog.Logger {
+	if log := config.FromContext(ctx).MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
+
+// buildStacktrace converts runtime stack trace to Sentry format
+func buildStacktrace(pcs []uintptr) *sentry.Stacktrace {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	sentryFrames := make([]sentry.Frame, 0, len(pcs)) // Pre-allocate with exact capacity
+
+	for {
+		frame, more := frames.Next()
+		sentryFrames = append(sentryFrames, sentry.Frame{
+			Filename: frame.File,
+			Function: frame.Function,
+			Lineno:   frame.Line,
+			AbsPath:  frame.File,
+		})
+		if !more {
+			break
+		}
+	}
+
+	// Reverse frames in-place (Sentry expects bottom-up)
+	for i, j := 0, len(sentryFrames)-1; i < j; i, j = i+1, j-1 {
+		sentryFrames[i], sentryFrames[j] = sentryFrames[j], sentryFrames[i]
+	}
+
+	return &sentry.Stacktrace{Frames: sentryFrames}
+}
@@ -0,0 +1,80 @@
+package lgreport
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// logContext returns a context whose middleware logger writes to the returned buffer
+func logContext() (context.Context, *bytes.Buffer) {
+	var buf bytes.Buffer
+	settings := config.NewSettings()
+	settings.SetMiddlewareLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	return config.WithSettings(context.Background(), settings), &buf
+}
+
+func TestHandleErrorAppliesPolicies(t *testing.T) {
+	t.Cleanup(ResetErrorPolicies)
+	SetErrorPolicies(ErrorPolicies{
+		ByType: map[lgerr.ErrorType]ErrorPolicy{lgerr.TypeValidation: {Silent: true}},
+		ByStatus: map[int]ErrorPolicy{
+			404: {Level: slog.LevelDebug, HideContext: true},
+		},
+	})
+
+	ctx, buf := logContext()
+	HandleError(ctx, lgerr.Validation("bad payload"))
+	if buf.Len() != 0 {
+		t.Fatalf("silent error was logged:\n%s", buf)
+	}
+
+	HandleError(ctx, lgerr.Database("query failed"))
+	if out := buf.String(); !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "Server error") {
+		t.Fatalf("server error not logged at Error:\n%s", out)
+	}
+
+	notFound := lgerr.NotFound("user", 7)
+	if resp := ErrorResponse(notFound); resp.Meta != nil {
+		t.Fatalf("meta = %v, want it hidden by the status policy", resp.Meta)
+	}
+	if level := PolicyFor(notFound).LogLevel(notFound.HTTPStatus()); level != slog.LevelDebug {
+		t.Fatalf("level = %v, want %v", level, slog.LevelDebug)
+	}
+}
+
+func TestShouldCaptureSkipsCallerCancellation(t *testing.T) {
+	settings := config.NewSettings()
+	settings.SetSentryEnabled(true)
+	ctx, cancel := context.WithCancel(config.WithSettings(context.Background(), settings))
+
+	lgErr := lgerr.FromError(context.Canceled)
+	if !ShouldCapture(ctx, lgErr) {
+		t.Fatal("cancellation of another call is not captured")
+	}
+	cancel()
+	if ShouldCapture(ctx, lgErr) {
+		t.Fatal("cancellation by the caller is captured")
+	}
+}
+
+func TestRecoverGoroutinePanicLogs(t *testing.T) {
+	ctx, buf := logContext()
+
+	func() {
+		defer RecoverGoroutinePanic(ctx, "worker")
+		panic("boom")
+	}()
+
+	out := buf.String()
+	for _, want := range []string{"Unhandled panic in goroutine", "goroutine_name=worker", "panic_value=boom"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("log misses %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package lgsentry
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// AttributeMetricsConfig selects numeric log attributes to emit as metrics
+type AttributeMetricsConfig struct {
+	// Attributes are the attribute keys to emit (e.g. "duration_ms", "queue_depth"); attributes inside
+	// groups are named by their dotted path (e.g. "db.duration_ms")
+	Attributes []string
+	// Service is added as the "service" tag/label
+	Service string
+	// RouteAttribute is the attribute whose value becomes the "route" tag/label (default: "route"), named
+	// like Attributes
+	RouteAttribute string
+	// Routes are the route values used as the "route" metric label (e.g. "/users/:id"); other values are
+	// labeled "other", so raw paths cannot create a series each. Without Routes or a route attribute the
+	// label is left empty. The span data always carries the attribute value
+	Routes []string
+}
+
+// otherRoute labels metrics whose route is not in AttributeMetricsConfig.Routes
+const otherRoute = "other"
+
+// attributeMetricsHandler wraps a slog.Handler and emits selected numeric attributes as metrics
+type attributeMetricsHandler struct {
+	next   slog.Handler
+	cfg    AttributeMetricsConfig
+	attrs  []slog.Attr // Attributes added with WithAttrs, qualified with their groups, for route lookups
+	prefix string      // Groups opened with WithGroup, as "a.b."
+}
+
+// NewAttributeMetricsHandler wraps next so selected numeric attributes of every record are emitted:
+//   - as data on the active Sentry span from the record's context ("<attr>", plus "route" and "service"),
+//     which Sentry can chart and alert on without Prometheus
+//   - as histograms "log_attribute_<attr>" on the logbundle metrics recorder (dots in grouped names become
+//     underscores), labeled by route (see AttributeMetricsConfig.Routes) and service
+//
+// The record itself is passed to next unchanged. The pinned Sentry SDK has no standalone metrics API,
+// so records logged outside a traced request only reach the metrics recorder
+//
+// Usage:
+//
+//	h := lgsentry.NewAttributeMetricsHandler(baseLogger.Handler(), lgsentry.AttributeMetricsConfig{
+//	    Attributes: []string{"duration_ms", "queue_depth"},
+//	    Service:    "billing",
+//	    Routes:     []string{"/invoices", "/invoices/:id"},
+//	})
+//	appLogger := slog.New(h)
+func NewAttributeMetricsHandler(next slog.Handler, cfg AttributeMetricsConfig) slog.Handler {
+	if cfg.RouteAttribute == "" {
+		cfg.RouteAttribute = "route"
+	}
+	return &attributeMetricsHandler{next: next, cfg: cfg}
+}
+
+func (h *attributeMetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *attributeMetricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	route := ""
+	values := make(map[string]float64, len(h.cfg.Attributes))
+
+	var collect func(prefix string, a slog.Attr)
+	collect = func(prefix string, a slog.Attr) {
+		key := prefix + a.Key
+		a.Value = a.Value.Resolve()
+		switch {
+		case a.Value.Kind() == slog.KindGroup:
+			if a.Key != "" {
+				prefix = key + "."
+			}
+			for _, ga := range a.Value.Group() {
+				collect(prefix, ga)
+			}
+		case key == h.cfg.RouteAttribute:
+			route = a.Value.String()
+		case slices.Contains(h.cfg.Attributes, key):
+			if v, ok := numericValue(a.Value); ok {
+				values[key] = v
+			}
+		}
+	}
+	for _, a := range h.attrs {
+		collect("", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		collect(h.prefix, a)
+		return true
+	})
+
+	if len(values) > 0 {
+		h.emit(ctx, route, values)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *attributeMetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	qualified := slices.Clip(h.attrs)
+	for _, a := range attrs {
+		if h.prefix != "" {
+			a = slog.Attr{Key: h.prefix + a.Key, Value: a.Value}
+		}
+		qualified = append(qualified, a)
+	}
+	return &attributeMetricsHandler{
+		next:   h.next.WithAttrs(attrs),
+		cfg:    h.cfg,
+		attrs:  qualified,
+		prefix: h.prefix,
+	}
+}
+
+func (h *attributeMetricsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &attributeMetricsHandler{
+		next:   h.next.WithGroup(name),
+		cfg:    h.cfg,
+		attrs:  h.attrs,
+		prefix: h.prefix + name + ".",
+	}
+}
+
+func (h *attributeMetricsHandler) emit(ctx context.Context, route string, values map[string]float64) {
+	labels := metrics.Labels{"route": h.routeLabel(route), "service": h.cfg.Service}
+	for name, v := range values {
+		metrics.Observe("log_attribute_"+strings.ReplaceAll(name, ".", "_"), labels, v)
+	}
+
+	if ctx == nil || !config.FromContext(ctx).SentryEnabled() {
+		return
+	}
+	span := sentry.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	for name, v := range values {
+		span.SetData(name, v)
+	}
+	if route != "" {
+		span.SetData("route", route)
+	}
+	if h.cfg.Service != "" {
+		span.SetData("service", h.cfg.Service)
+	}
+}
+
+// routeLabel bounds the route metric label to the configured routes
+func (h *attributeMetricsHandler) routeLabel(route string) string {
+	switch {
+	case len(h.cfg.Routes) == 0 || route == "":
+		return ""
+	case slices.Contains(h.cfg.Routes, route):
+		return route
+	default:
+		return otherRoute
+	}
+}
+
+// numericValue converts int, uint, float and duration (as milliseconds) values
+func numericValue(v slog.Value) (float64, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64()), true
+	case slog.KindUint64:
+		return float64(v.Uint64()), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	case slog.KindDuration:
+		return float64(v.Duration().Microseconds()) / 1000, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,69 @@
+package lgsentry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+func TestAttributeMetricsHandlerTracksGroupsAndBoundsRoutes(t *testing.T) {
+	registry := metrics.NewRegistry()
+	prev := metrics.GetRecorder()
+	metrics.SetRecorder(registry)
+	t.Cleanup(func() { metrics.SetRecorder(prev) })
+
+	h := NewAttributeMetricsHandler(slog.NewTextHandler(io.Discard, nil), AttributeMetricsConfig{
+		Attributes: []string{"duration_ms", "db.duration_ms"},
+		Service:    "billing",
+		Routes:     []string{"/users/:id"},
+	})
+	log := slog.New(h)
+
+	log.Info("request", "route", "/users/:id", "duration_ms", 12)
+	log.Info("request", "route", "/users/42", "duration_ms", 15)
+	log.WithGroup("db").Info("query", "duration_ms", 3)
+	log.With("route", "/users/:id").WithGroup("db").Info("query", slog.Group("pool", "duration_ms", 9))
+
+	got := map[string]uint64{}
+	for _, s := range registry.Snapshot() {
+		got[s.Name+" "+s.Labels["route"]] += s.Count
+	}
+	want := map[string]uint64{
+		"log_attribute_duration_ms /users/:id": 1,
+		"log_attribute_duration_ms other":      1,
+		"log_attribute_db_duration_ms ":        1,
+	}
+	for key, n := range want {
+		if got[key] != n {
+			t.Fatalf("%s observed %d times, want %d (all: %v)", key, got[key], n, got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("observations = %v, want %v", got, want)
+	}
+}
+
+func TestAttributeMetricsHandlerWithoutRoutesLeavesRouteEmpty(t *testing.T) {
+	registry := metrics.NewRegistry()
+	prev := metrics.GetRecorder()
+	metrics.SetRecorder(registry)
+	t.Cleanup(func() { metrics.SetRecorder(prev) })
+
+	h := NewAttributeMetricsHandler(slog.NewTextHandler(io.Discard, nil), AttributeMetricsConfig{Attributes: []string{"queue_depth"}})
+	_ = h.Handle(context.Background(), newRecord("route", "/users/42", "queue_depth", 4))
+
+	samples := registry.Snapshot()
+	if len(samples) != 1 || samples[0].Labels["route"] != "" {
+		t.Fatalf("samples = %+v, want one sample without a route label", samples)
+	}
+}
+
+func newRecord(args ...any) slog.Record {
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "msg", 0)
+	r.Add(args...)
+	return r
+}
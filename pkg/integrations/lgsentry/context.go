@@ -0,0 +1,44 @@
+package lgsentry
+
+import (
+	"context"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Register the Sentry hub, span and baggage with core.DumpContext
+// Under Fiber the hub and span live on the *fiber.Ctx (see requestHub), which is found under "fiber_ctx"
+func init() {
+	core.RegisterContextInspector("sentry_hub", func(ctx context.Context) (any, bool) {
+		hub := requestHub(ctx)
+		if hub == nil {
+			return nil, false
+		}
+		return map[string]any{"client_bound": hub.Client() != nil}, true
+	})
+
+	core.RegisterContextInspector("sentry_span", func(ctx context.Context) (any, bool) {
+		span := requestSpan(ctx, fiberCtxFromContext(ctx))
+		if span == nil {
+			return nil, false
+		}
+		return map[string]any{
+			"trace_id":     span.TraceID.String(),
+			"span_id":      span.SpanID.String(),
+			"op":           span.Op,
+			"sampled":      span.Sampled.Bool(),
+			"sentry_trace": span.ToSentryTrace(),
+		}, true
+	})
+
+	// Only the incoming baggage of the hub scope is reported: a span's ToBaggage freezes the
+	// transaction's dynamic sampling context, which a debug dump must not do
+	core.RegisterContextInspector("baggage", func(ctx context.Context) (any, bool) {
+		hub := requestHub(ctx)
+		if hub == nil || hub.Scope().GetSpan() != nil {
+			return nil, false
+		}
+		baggage := hub.GetBaggage()
+		return baggage, baggage != ""
+	})
+}
@@ -0,0 +1,28 @@
+package lgsentry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+func TestDumpContextFindsFiberHub(t *testing.T) {
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	sentryfiber.SetHubOnContext(c, sentry.NewHub(nil, sentry.NewScope()))
+
+	if _, ok := core.DumpContext(context.Background())["sentry_hub"]; ok {
+		t.Fatal("sentry_hub reported without a request hub")
+	}
+	dump := core.DumpContext(context.WithValue(c.UserContext(), "fiber_ctx", c))
+	if _, ok := dump["sentry_hub"]; !ok {
+		t.Fatalf("sentry_hub missing for a Fiber request: %v", dump)
+	}
+}
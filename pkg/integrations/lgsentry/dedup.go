@@ -0,0 +1,121 @@
+package lgsentry
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// dedupMaxKeys bounds the number of tracked event keys
+const dedupMaxKeys = 10000
+
+type dedupEntry struct {
+	windowStart time.Time
+	suppressed  int
+}
+
+var (
+	dedupMu      sync.Mutex
+	dedupWindow  time.Duration
+	dedupEntries = make(map[string]*dedupEntry)
+)
+
+// EnableDeduplication collapses identical events (same fingerprint and message) within window:
+// the first is sent, later ones are dropped until the window ends. The next event sent for that key
+// carries the number of dropped duplicates in extra "deduplicated_count", so a tight retry loop
+// produces one event per window instead of thousands. Runs as the "dedup" event processor, after
+// filters, so dropped events are not counted. A window <= 0 disables deduplication
+//
+// Usage:
+//
+//	lgsentry.EnableDeduplication(30 * time.Second)
+func EnableDeduplication(window time.Duration) {
+	dedupMu.Lock()
+	dedupWindow = window
+	dedupEntries = make(map[string]*dedupEntry)
+	dedupMu.Unlock()
+
+	if window <= 0 {
+		RemoveEventProcessor("dedup")
+		return
+	}
+
+	AddEventProcessor("dedup", func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		return deduplicateEvent(event, core.Now())
+	}, OrderDedup)
+}
+
+// deduplicateEvent returns nil for duplicates inside the current window
+func deduplicateEvent(event *sentry.Event, now time.Time) *sentry.Event {
+	if event == nil || event.Type == "transaction" || event.Type == "check_in" {
+		return event
+	}
+
+	dedupMu.Lock()
+	defer dedupMu.Unlock()
+
+	if dedupWindow <= 0 {
+		return event
+	}
+
+	key := dedupKey(event)
+	entry, ok := dedupEntries[key]
+	if ok && now.Sub(entry.windowStart) < dedupWindow {
+		entry.suppressed++
+		metrics.IncCounter("sentry_events_deduplicated_total", nil)
+		return nil
+	}
+
+	if ok && entry.suppressed > 0 {
+		if event.Extra == nil {
+			event.Extra = make(map[string]any, 1)
+		}
+		event.Extra["deduplicated_count"] = entry.suppressed
+	}
+
+	if !ok {
+		if len(dedupEntries) >= dedupMaxKeys {
+			pruneDedupEntries(now)
+		}
+		entry = &dedupEntry{}
+		dedupEntries[key] = entry
+	}
+	entry.windowStart = now
+	entry.suppressed = 0
+
+	return event
+}
+
+// dedupKey identifies an event by fingerprint and message (or the innermost exception)
+func dedupKey(event *sentry.Event) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(event.Fingerprint, "\x1f"))
+	b.WriteByte('|')
+	b.WriteString(event.Message)
+	if len(event.Exception) > 0 {
+		last := event.Exception[len(event.Exception)-1]
+		b.WriteByte('|')
+		b.WriteString(last.Type)
+		b.WriteByte(':')
+		b.WriteString(last.Value)
+	}
+	return b.String()
+}
+
+// pruneDedupEntries drops expired entries, or everything if none have expired (caller holds dedupMu)
+// Dropping an entry loses its suppressed count, which only happens under extreme key cardinality
+func pruneDedupEntries(now time.Time) {
+	for key, entry := range dedupEntries {
+		if now.Sub(entry.windowStart) >= dedupWindow {
+			delete(dedupEntries, key)
+		}
+	}
+	if len(dedupEntries) >= dedupMaxKeys {
+		dedupEntries = make(map[string]*dedupEntry)
+	}
+}
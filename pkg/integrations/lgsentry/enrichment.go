@@ -0,0 +1,26 @@
+package lgsentry
+
+import (
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// EnableRuntimeMetadata adds host.name, process.pid, go.version and app.version tags
+// to every Sentry event via the "runtime_metadata" event processor. Calling it again is a no-op
+func EnableRuntimeMetadata() {
+	tags := core.RuntimeMetadataTags()
+
+	AddEventProcessor("runtime_metadata", func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		if event.Tags == nil {
+			event.Tags = make(map[string]string, len(tags))
+		}
+		for k, v := range tags {
+			// Explicitly set tags take precedence
+			if _, exists := event.Tags[k]; !exists {
+				event.Tags[k] = v
+			}
+		}
+		return event
+	}, OrderEnrich)
+}
@@ -0,0 +1,78 @@
+package lgsentry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/chaos"
+)
+
+// defaultRetryAfter is the back-off after a 429 response without rate limit headers
+const defaultRetryAfter = 60 * time.Second
+
+// postEnvelope sends a serialized envelope to the DSN's envelope endpoint and returns the HTTP status
+// and how long Sentry asked the client to back off (see retryAfter)
+func postEnvelope(ctx context.Context, client *http.Client, dsn *sentry.Dsn, data []byte) (int, time.Duration, error) {
+	if err := chaos.SentrySend(); err != nil {
+		return 0, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dsn.GetAPIURL().String(), bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=logbundle/%s, sentry_key=%s",
+		sentry.SDKVersion, dsn.GetPublicKey())
+	if secret := dsn.GetSecretKey(); secret != "" {
+		auth += ", sentry_secret=" + secret
+	}
+	req.Header.Set("X-Sentry-Auth", auth)
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, retryAfter(resp, time.Now()), nil
+}
+
+// retryAfter returns the back-off requested by resp: the longest limit of X-Sentry-Rate-Limits, else
+// Retry-After (seconds or HTTP date), else defaultRetryAfter for 429; zero when there is none
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if limits := resp.Header.Get("X-Sentry-Rate-Limits"); limits != "" {
+		var longest time.Duration
+		// Limits look like "60:error;transaction:organization, 2700:default:organization"
+		for _, limit := range strings.Split(limits, ",") {
+			seconds, _, _ := strings.Cut(strings.TrimSpace(limit), ":")
+			if n, err := strconv.ParseFloat(seconds, 64); err == nil && n > 0 {
+				longest = max(longest, time.Duration(n*float64(time.Second)))
+			}
+		}
+		if longest > 0 {
+			return longest
+		}
+	}
+
+	if header := resp.Header.Get("Retry-After"); header != "" {
+		if n, err := strconv.Atoi(header); err == nil && n > 0 {
+			return time.Duration(n) * time.Second
+		}
+		if at, err := http.ParseTime(header); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return defaultRetryAfter
+	}
+	return 0
+}
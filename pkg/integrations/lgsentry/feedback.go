@@ -0,0 +1,120 @@
+package lgsentry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// UserFeedback is a user's report attached to a captured Sentry event
+type UserFeedback struct {
+	EventID  string `json:"event_id"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Comments string `json:"comments"`
+}
+
+// ErrFeedbackUnavailable is returned when Sentry is disabled or not initialized with a DSN
+var ErrFeedbackUnavailable = errors.New("sentry feedback unavailable")
+
+// feedbackHTTPClient sends feedback envelopes
+var feedbackHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// CaptureUserFeedback sends feedback for an event to Sentry's user feedback API
+// In local dummy mode (InitLocal) the feedback is written to the local event log instead
+func CaptureUserFeedback(ctx context.Context, feedback UserFeedback) error {
+	if !config.FromContext(ctx).SentryEnabled() {
+		return ErrFeedbackUnavailable
+	}
+
+	client := sentry.CurrentHub().Client()
+	if client == nil {
+		return ErrFeedbackUnavailable
+	}
+
+	if local, ok := client.Transport.(*LocalTransport); ok {
+		local.sendFeedback(feedback)
+		return nil
+	}
+
+	dsn, err := sentry.NewDsn(client.Options().Dsn)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFeedbackUnavailable, err)
+	}
+
+	envelope, err := feedbackEnvelope(dsn, feedback)
+	if err != nil {
+		return err
+	}
+
+	status, _, err := postEnvelope(ctx, feedbackHTTPClient, dsn, envelope)
+	if err != nil {
+		return fmt.Errorf("send feedback: %w", err)
+	}
+	if status >= 300 {
+		return fmt.Errorf("send feedback: status %d", status)
+	}
+	return nil
+}
+
+// feedbackEnvelope builds a "user_report" envelope
+func feedbackEnvelope(dsn *sentry.Dsn, feedback UserFeedback) ([]byte, error) {
+	header, err := json.Marshal(map[string]any{
+		"event_id": feedback.EventID,
+		"sent_at":  core.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      dsn.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(feedback)
+	if err != nil {
+		return nil, err
+	}
+
+	itemHeader, err := json.Marshal(map[string]any{"type": "user_report", "length": len(payload)})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, len(header)+len(itemHeader)+len(payload)+3)
+	data = append(data, header...)
+	data = append(data, '\n')
+	data = append(data, itemHeader...)
+	data = append(data, '\n')
+	data = append(data, payload...)
+	return append(data, '\n'), nil
+}
+
+// sendFeedback records feedback locally in the same way as events
+func (t *LocalTransport) sendFeedback(feedback UserFeedback) {
+	if t.toFile {
+		data, err := json.Marshal(map[string]any{"type": "user_report", "user_report": feedback})
+		if err != nil {
+			return
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.writer != nil {
+			_, _ = t.writer.Write(append(data, '\n'))
+		}
+		return
+	}
+
+	t.log().Info("Sentry user feedback (local)",
+		slog.String("event_id", feedback.EventID),
+		slog.String("name", feedback.Name),
+		slog.String("comments", feedback.Comments),
+	)
+}
@@ -0,0 +1,54 @@
+package lgsentry
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Init initializes the Sentry SDK and enables logbundle's Sentry integration
+// If options.Release and SENTRY_RELEASE are empty, the release is taken from the binary's build info
+// ("module@v1.2.3", or "module@<revision>[-dirty]" for untagged builds), so release tracking works
+// without extra configuration. The request data of events is bounded by DefaultRequestContextBudget
+// (see LimitRequestContext). Registered event processors (see AddEventProcessor) run before options.BeforeSend.
+// A startup banner is logged to the middleware logger if one is configured
+func Init(options sentry.ClientOptions) error {
+	options.BeforeSend = wrapBeforeSend(options.BeforeSend)
+	AddEventProcessor("query_scrub", scrubEventQueries, OrderScrub)
+	requestBudgetMu.RLock()
+	budgetSet := requestKeptHeaders != nil
+	requestBudgetMu.RUnlock()
+	if !budgetSet {
+		LimitRequestContext(DefaultRequestContextBudget)
+	}
+
+	if options.Release == "" && os.Getenv("SENTRY_RELEASE") == "" {
+		options.Release = core.GetBuildInfo().Release()
+	}
+
+	if err := sentry.Init(options); err != nil {
+		return fmt.Errorf("sentry init: %w", err)
+	}
+
+	config.Default().SetSentryEnabled(true)
+
+	if log := config.Default().MiddlewareLogger(); log != nil {
+		release := options.Release
+		if client := sentry.CurrentHub().Client(); client != nil {
+			release = client.Options().Release
+		}
+
+		fields := append([]any{
+			slog.String("sentry_release", release),
+			slog.String("sentry_environment", options.Environment),
+		}, core.BuildInfoAttrs()...)
+		log.Info("Sentry initialized", fields...)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package lgsentry
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// ipHeaders are request headers carrying client IPs
+var ipHeaders = []string{"X-Forwarded-For", "X-Real-Ip", "X-Client-Ip", "Cf-Connecting-Ip", "True-Client-Ip"}
+
+// EnableIPAnonymization truncates client IPs (see core.AnonymizeIP) in events via the "ip_anonymize"
+// event processor: the user IP, REMOTE_ADDR, forwarding headers, the "ip" field of the request
+// context and of breadcrumbs. Calling it again is a no-op
+//
+// Usage:
+//
+//	logbundle.SetIPAnonymization(true) // logs and logbundle's request context
+//	lgsentry.EnableIPAnonymization()   // data collected by the Sentry SDK
+func EnableIPAnonymization() {
+	AddEventProcessor("ip_anonymize", anonymizeEventIPs, OrderScrub)
+}
+
+// anonymizeEventIPs truncates the client IPs of an event
+func anonymizeEventIPs(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	if event.Request != nil {
+		if addr, ok := event.Request.Env["REMOTE_ADDR"]; ok {
+			event.Request.Env["REMOTE_ADDR"] = core.AnonymizeIP(addr)
+		}
+		for name, value := range event.Request.Headers {
+			for _, h := range ipHeaders {
+				if http.CanonicalHeaderKey(name) == h {
+					event.Request.Headers[name] = core.AnonymizeIP(value)
+				}
+			}
+		}
+	}
+
+	switch event.User.IPAddress {
+	case "":
+	case "{{auto}}":
+		// "{{auto}}" lets Sentry infer the full address server-side; send the truncated one instead
+		event.User.IPAddress = ""
+		if event.Request != nil {
+			event.User.IPAddress = core.AnonymizeIP(event.Request.Env["REMOTE_ADDR"])
+		}
+	default:
+		event.User.IPAddress = core.AnonymizeIP(event.User.IPAddress)
+	}
+
+	if ip, ok := event.Contexts["request"]["ip"].(string); ok {
+		event.Contexts["request"]["ip"] = core.AnonymizeIP(ip)
+	}
+	for _, b := range event.Breadcrumbs {
+		if ip, ok := b.Data["ip"].(string); ok {
+			b.Data["ip"] = core.AnonymizeIP(ip)
+		}
+	}
+	return event
+}
@@ -0,0 +1,42 @@
+package lgsentry
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func TestAnonymizeEventIPs(t *testing.T) {
+	event := &sentry.Event{
+		Request: &sentry.Request{
+			Env: map[string]string{"REMOTE_ADDR": "203.0.113.42"},
+			Headers: map[string]string{
+				"X-Forwarded-For": "198.51.100.7, 203.0.113.42",
+				"x-real-ip":       "2001:db8:1:2:3:4:5:6",
+				"User-Agent":      "curl/8.0",
+			},
+		},
+		User:     sentry.User{IPAddress: "{{auto}}"},
+		Contexts: map[string]sentry.Context{"request": {"ip": "203.0.113.42"}},
+		Breadcrumbs: []*sentry.Breadcrumb{
+			{Data: map[string]any{"ip": "198.51.100.7"}},
+		},
+	}
+
+	event = anonymizeEventIPs(event, nil)
+
+	checks := map[string][2]string{
+		"REMOTE_ADDR":     {event.Request.Env["REMOTE_ADDR"], "203.0.113.0"},
+		"X-Forwarded-For": {event.Request.Headers["X-Forwarded-For"], "198.51.100.0, 203.0.113.0"},
+		"x-real-ip":       {event.Request.Headers["x-real-ip"], "2001:db8:1:2::"},
+		"User-Agent":      {event.Request.Headers["User-Agent"], "curl/8.0"},
+		"user":            {event.User.IPAddress, "203.0.113.0"},
+		"context":         {event.Contexts["request"]["ip"].(string), "203.0.113.0"},
+		"breadcrumb":      {event.Breadcrumbs[0].Data["ip"].(string), "198.51.100.0"},
+	}
+	for name, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("%s = %q, want %q", name, c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package lgsentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// LocalTransport is a sentry.Transport that never sends anything: would-be events are written
+// as JSON lines to a file, or summarized to a logger, so event contents, fingerprints and tags
+// can be checked locally without a DSN
+type LocalTransport struct {
+	mu     sync.Mutex
+	toFile bool
+	writer io.Writer
+	closer io.Closer
+	logger *slog.Logger
+}
+
+// NewFileTransport appends one JSON-encoded event per line to path
+func NewFileTransport(path string) (*LocalTransport, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("sentry local transport: %w", err)
+	}
+	return &LocalTransport{toFile: true, writer: f, closer: f}, nil
+}
+
+// NewLogTransport logs a summary of each event (level, message, fingerprint, tags) at Info
+// If logger is nil, uses the middleware logger or the internal logger
+func NewLogTransport(logger *slog.Logger) *LocalTransport {
+	return &LocalTransport{logger: logger}
+}
+
+// InitLocal initializes Sentry in dummy mode: capture paths run as usual but events go to a local
+// JSONL file (or the log when path is empty) instead of Sentry. Options other than Dsn and Transport
+// are honored, so BeforeSend, sampling and release behave as in production
+//
+// Usage:
+//
+//	if os.Getenv("SENTRY_DSN") == "" {
+//	    err = lgsentry.InitLocal("sentry-events.jsonl", sentry.ClientOptions{Environment: "dev"})
+//	}
+func InitLocal(path string, options ...sentry.ClientOptions) error {
+	var opts sentry.ClientOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	if path == "" {
+		opts.Transport = NewLogTransport(nil)
+	} else {
+		transport, err := NewFileTransport(path)
+		if err != nil {
+			return err
+		}
+		opts.Transport = transport
+	}
+	opts.Dsn = ""
+
+	return Init(opts)
+}
+
+// Configure implements sentry.Transport
+func (t *LocalTransport) Configure(sentry.ClientOptions) {}
+
+// SendEvent implements sentry.Transport
+func (t *LocalTransport) SendEvent(event *sentry.Event) {
+	if t.toFile {
+		data, err := json.Marshal(event)
+		if err != nil {
+			sentry.DebugLogger.Printf("sentry local transport: %v", err)
+			return
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.writer != nil {
+			_, _ = t.writer.Write(append(data, '\n'))
+		}
+		return
+	}
+
+	message := event.Message
+	if message == "" && len(event.Exception) > 0 {
+		last := event.Exception[len(event.Exception)-1]
+		message = last.Type + ": " + last.Value
+	}
+
+	t.log().Info("Sentry event (local)",
+		slog.String("event_id", string(event.EventID)),
+		slog.String("level", string(event.Level)),
+		slog.String("message", message),
+		slog.String("fingerprint", strings.Join(event.Fingerprint, ",")),
+		slog.Any("tags", event.Tags),
+	)
+}
+
+// log returns the configured logger, the middleware logger or the internal logger
+func (t *LocalTransport) log() *slog.Logger {
+	if t.logger != nil {
+		return t.logger
+	}
+	if log := config.Default().MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
+
+// Flush implements sentry.Transport
+func (t *LocalTransport) Flush(time.Duration) bool { return true }
+
+// FlushWithContext implements sentry.Transport
+func (t *LocalTransport) FlushWithContext(context.Context) bool { return true }
+
+// Close implements sentry.Transport, closing the file if one was opened
+func (t *LocalTransport) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closer != nil {
+		_ = t.closer.Close()
+		t.closer = nil
+		t.writer = nil
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"log/slog"
 
 	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
-	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
 	"github.com/getsentry/sentry-go"
 )
 
@@ -43,7 +43,7 @@ func Warn(ctx context.Context, log *slog.Logger, msg string, err error, extraDat
 
 	if err != nil {
 		allArgs := make([]any, 0, len(extraData)+1)
-		allArgs = append(allArgs, core.ErrAttr(err))
+		allArgs = append(allArgs, fields.Err(err))
 		allArgs = append(allArgs, extraData...)
 		logger.LogWithSourceCtx(ctx, log, slog.LevelWarn, msg, allArgs...)
 	} else {
@@ -63,7 +63,7 @@ func Error(ctx context.Context, log *slog.Logger, msg string, err error, extraDa
 
 	if err != nil {
 		allArgs := make([]any, 0, len(extraData)+1)
-		allArgs = append(allArgs, core.ErrAttr(err))
+		allArgs = append(allArgs, fields.Err(err))
 		allArgs = append(allArgs, extraData...)
 		logger.LogWithSourceCtx(ctx, log, slog.LevelError, msg, allArgs...)
 	} else {
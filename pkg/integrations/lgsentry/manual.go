@@ -11,11 +11,32 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
 )
 
+// requestHub returns the Sentry hub of the request in ctx: the hub sentryfiber stores on the
+// *fiber.Ctx found under "fiber_ctx", else the hub bound to ctx; nil outside a request
+func requestHub(ctx context.Context) *sentry.Hub {
+	if ctx == nil {
+		return nil
+	}
+	if fc := fiberCtxFromContext(ctx); fc != nil {
+		if hub := sentryfiber.GetHubFromContext(fc); hub != nil {
+			return hub
+		}
+	}
+	return sentry.GetHubFromContext(ctx)
+}
+
+// fiberCtxFromContext returns the *fiber.Ctx stored under "fiber_ctx", or nil
+func fiberCtxFromContext(ctx context.Context) *fiber.Ctx {
+	fc, _ := ctx.Value("fiber_ctx").(*fiber.Ctx)
+	return fc
+}
+
 func CaptureEvent(ctx context.Context, level sentry.Level, msg string, err error, extraData ...any) {
 	// Check if Sentry is globally enabled
-	if !config.IsSentryEnabled() {
+	if !config.FromContext(ctx).SentryEnabled() {
 		return
 	}
 
@@ -45,7 +66,12 @@ func CaptureEvent(ctx context.Context, level sentry.Level, msg string, err error
 	tags, extra := parseExtraData(extraData)
 
 	captureFunc := func(scope *sentry.Scope) {
+		// Link the event to the request trace (see requestSpan)
+		if span := requestSpan(ctx, fiberCtx); span != nil {
+			scope.SetSpan(span)
+		}
 		scope.SetLevel(level)
+		scope.SetTags(core.SentryScopeTags(ctx))
 
 		for key, value := range tags {
 			scope.SetTag(key, value)
@@ -57,16 +83,16 @@ func CaptureEvent(ctx context.Context, level sentry.Level, msg string, err error
 
 		if fiberCtx != nil {
 			scope.SetContext("request", map[string]any{
-				"url":        fiberCtx.OriginalURL(),
+				"url":        core.ScrubURL(fiberCtx.OriginalURL()),
 				"method":     fiberCtx.Method(),
 				"path":       fiberCtx.Path(),
 				"route":      fiberCtx.Route().Path,
-				"ip":         fiberCtx.IP(),
+				"ip":         config.FromContext(ctx).ClientIP(fiberCtx.IP()),
 				"user_agent": fiberCtx.Get("User-Agent"),
 			})
 
 			if queries := fiberCtx.Queries(); len(queries) > 0 {
-				scope.SetExtra("query_params", queries)
+				scope.SetExtra("query_params", core.ScrubQueryParams(queries))
 			}
 			if params := fiberCtx.AllParams(); len(params) > 0 {
 				scope.SetExtra("route_params", params)
@@ -92,6 +118,19 @@ func CaptureEvent(ctx context.Context, level sentry.Level, msg string, err error
 	hub.WithScope(captureFunc)
 }
 
+// requestSpan returns the span of ctx or the transaction started by the sentryfiber middleware
+func requestSpan(ctx context.Context, fiberCtx *fiber.Ctx) *sentry.Span {
+	if ctx != nil {
+		if span := sentry.SpanFromContext(ctx); span != nil {
+			return span
+		}
+	}
+	if fiberCtx != nil {
+		return sentryfiber.GetSpanFromContext(fiberCtx)
+	}
+	return nil
+}
+
 func parseExtraData(extraData []any) (map[string]string, map[string]any) {
 	if len(extraData) == 0 {
 		return nil, nil
@@ -0,0 +1,141 @@
+package lgsentry
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// EventProcessor inspects or modifies an event before it is sent; returning nil drops the event
+type EventProcessor func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+
+// Suggested orders for processors; lower values run first
+const (
+	OrderEnrich = 100  // Add tags, contexts and metadata
+	OrderScrub  = 200  // Remove or mask sensitive data
+	OrderFilter = 300  // Drop unwanted events
+	OrderDedup  = 1000 // Collapse duplicates of events that will actually be sent
+)
+
+type namedProcessor struct {
+	name  string
+	order int
+	seq   int
+	fn    EventProcessor
+}
+
+var (
+	processors   []namedProcessor
+	processorSeq int
+	processorsMu sync.RWMutex
+)
+
+// AddEventProcessor registers fn under name, replacing any processor with the same name
+// Processors run in ascending order (ties in registration order) from the BeforeSend installed by Init,
+// before options.BeforeSend. A panicking processor is skipped and logged; the event continues unchanged
+//
+// Usage:
+//
+//	lgsentry.AddEventProcessor("drop_healthchecks", func(e *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+//	    if e.Request != nil && e.Request.URL == "/health" {
+//	        return nil
+//	    }
+//	    return e
+//	}, lgsentry.OrderFilter)
+func AddEventProcessor(name string, fn EventProcessor, order int) {
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+
+	removeProcessorLocked(name)
+	processorSeq++
+	processors = append(processors, namedProcessor{name: name, order: order, seq: processorSeq, fn: fn})
+	sort.SliceStable(processors, func(i, j int) bool {
+		if processors[i].order != processors[j].order {
+			return processors[i].order < processors[j].order
+		}
+		return processors[i].seq < processors[j].seq
+	})
+}
+
+// RemoveEventProcessor unregisters the named processor, reporting whether it existed
+func RemoveEventProcessor(name string) bool {
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+	return removeProcessorLocked(name)
+}
+
+// EventProcessorNames returns the registered processor names in execution order
+func EventProcessorNames() []string {
+	processorsMu.RLock()
+	defer processorsMu.RUnlock()
+
+	names := make([]string, len(processors))
+	for i, p := range processors {
+		names[i] = p.name
+	}
+	return names
+}
+
+// RunEventProcessors runs the registered processors on event
+// Init installs it automatically; set it as ClientOptions.BeforeSend when calling sentry.Init directly
+func RunEventProcessors(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	processorsMu.RLock()
+	pipeline := make([]namedProcessor, len(processors))
+	copy(pipeline, processors)
+	processorsMu.RUnlock()
+
+	for _, p := range pipeline {
+		if event = runProcessor(p, event, hint); event == nil {
+			return nil
+		}
+	}
+	return event
+}
+
+// runProcessor calls a single processor, recovering from panics
+func runProcessor(p namedProcessor, event *sentry.Event, hint *sentry.EventHint) (result *sentry.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log := config.Default().MiddlewareLogger()
+			if log == nil {
+				log = handler.GetInternalLogger()
+			}
+			logger.LogNoSource(log, slog.LevelError, "Sentry event processor panicked",
+				slog.String("processor", p.name),
+				slog.String("panic", fmt.Sprint(r)),
+			)
+			result = event
+		}
+	}()
+	return p.fn(event, hint)
+}
+
+// wrapBeforeSend chains the processor pipeline with the user's BeforeSend
+func wrapBeforeSend(userBeforeSend func(*sentry.Event, *sentry.EventHint) *sentry.Event) func(*sentry.Event, *sentry.EventHint) *sentry.Event {
+	return func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+		if event = RunEventProcessors(event, hint); event == nil {
+			return nil
+		}
+		if userBeforeSend != nil {
+			return userBeforeSend(event, hint)
+		}
+		return event
+	}
+}
+
+func removeProcessorLocked(name string) bool {
+	for i, p := range processors {
+		if p.name == name {
+			processors = append(processors[:i], processors[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package lgsentry
+
+import (
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// scrubEventQueries scrubs sensitive query parameters (see core.IsSensitiveQueryParam) from the
+// request URL and query string collected by the Sentry SDK, the request context and breadcrumb URLs
+func scrubEventQueries(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	if event.Request != nil {
+		event.Request.URL = core.ScrubURL(event.Request.URL)
+		event.Request.QueryString = core.ScrubQuery(event.Request.QueryString)
+	}
+
+	if u, ok := event.Contexts["request"]["url"].(string); ok {
+		event.Contexts["request"]["url"] = core.ScrubURL(u)
+	}
+	if params, ok := event.Extra["query_params"].(map[string]string); ok {
+		event.Extra["query_params"] = core.ScrubQueryParams(params)
+	}
+	for _, b := range event.Breadcrumbs {
+		if u, ok := b.Data["url"].(string); ok {
+			b.Data["url"] = core.ScrubURL(u)
+		}
+	}
+	return event
+}
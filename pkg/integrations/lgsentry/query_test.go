@@ -0,0 +1,37 @@
+package lgsentry
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func TestScrubEventQueries(t *testing.T) {
+	event := &sentry.Event{
+		Request: &sentry.Request{
+			URL:         "https://api.example.com/cb?access_token=abc&page=2",
+			QueryString: "access_token=abc&page=2",
+		},
+		Contexts: map[string]sentry.Context{"request": {"url": "/cb?client_secret=s"}},
+		Extra:    map[string]any{"query_params": map[string]string{"sig": "x", "page": "2"}},
+		Breadcrumbs: []*sentry.Breadcrumb{
+			{Data: map[string]any{"url": "/login?password=hunter2"}},
+		},
+	}
+
+	event = scrubEventQueries(event, nil)
+
+	checks := map[string][2]string{
+		"request url":    {event.Request.URL, "https://api.example.com/cb?access_token=[Filtered]&page=2"},
+		"query string":   {event.Request.QueryString, "access_token=[Filtered]&page=2"},
+		"context url":    {event.Contexts["request"]["url"].(string), "/cb?client_secret=[Filtered]"},
+		"query_params":   {event.Extra["query_params"].(map[string]string)["sig"], "[Filtered]"},
+		"other param":    {event.Extra["query_params"].(map[string]string)["page"], "2"},
+		"breadcrumb url": {event.Breadcrumbs[0].Data["url"].(string), "/login?password=[Filtered]"},
+	}
+	for name, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("%s = %q, want %q", name, c[0], c[1])
+		}
+	}
+}
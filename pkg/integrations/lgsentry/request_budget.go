@@ -0,0 +1,177 @@
+package lgsentry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// DefaultRequestContextBudget is the request data budget applied by Init
+const DefaultRequestContextBudget = 32 << 10
+
+// defaultKeptHeaders survive the first trimming stage
+var defaultKeptHeaders = []string{"Content-Type", "Content-Length", "User-Agent", "Accept", "Host", "X-Request-Id"}
+
+var (
+	requestBudgetMu    sync.RWMutex
+	requestBudget      int
+	requestKeptHeaders map[string]bool
+)
+
+// LimitRequestContext bounds the request data of events (the SDK request interface with headers, query
+// and cookies, the "request" context and the query_params/route_params extras) to maxBytes of JSON.
+// The budget is measured uncompressed, the size Sentry enforces its limits on. Over budget, data is
+// trimmed by priority until it fits:
+//  1. headers except keepHeaders (default: Content-Type, Content-Length, User-Agent, Accept, Host, X-Request-Id)
+//  2. the remaining headers
+//  3. query strings and parameters
+//  4. the request body and cookies
+//
+// Trimmed stages are listed in extra "request_trimmed" and counted in sentry_request_trimmed_total{stage}
+// Init applies DefaultRequestContextBudget; a maxBytes <= 0 disables the limit
+//
+// Usage:
+//
+//	lgsentry.LimitRequestContext(16<<10, "Content-Type", "User-Agent", "X-Forwarded-For")
+func LimitRequestContext(maxBytes int, keepHeaders ...string) {
+	if len(keepHeaders) == 0 {
+		keepHeaders = defaultKeptHeaders
+	}
+	kept := make(map[string]bool, len(keepHeaders))
+	for _, h := range keepHeaders {
+		kept[http.CanonicalHeaderKey(h)] = true
+	}
+
+	requestBudgetMu.Lock()
+	requestBudget = maxBytes
+	requestKeptHeaders = kept
+	requestBudgetMu.Unlock()
+
+	if maxBytes <= 0 {
+		RemoveEventProcessor("request_budget")
+		return
+	}
+	// After scrubbing, so the budget applies to the data actually sent
+	AddEventProcessor("request_budget", trimEventRequest, OrderScrub+50)
+}
+
+// requestTrimStage removes one class of request data and reports whether anything was removed
+type requestTrimStage struct {
+	name string
+	trim func(event *sentry.Event, kept map[string]bool) bool
+}
+
+var requestTrimStages = []requestTrimStage{
+	{"headers", func(event *sentry.Event, kept map[string]bool) bool {
+		if event.Request == nil {
+			return false
+		}
+		trimmed := false
+		for k := range event.Request.Headers {
+			if !kept[http.CanonicalHeaderKey(k)] {
+				delete(event.Request.Headers, k)
+				trimmed = true
+			}
+		}
+		return trimmed
+	}},
+	{"all_headers", func(event *sentry.Event, _ map[string]bool) bool {
+		if event.Request == nil || len(event.Request.Headers) == 0 {
+			return false
+		}
+		event.Request.Headers = nil
+		return true
+	}},
+	{"query", func(event *sentry.Event, _ map[string]bool) bool {
+		trimmed := false
+		if event.Request != nil {
+			if event.Request.QueryString != "" {
+				event.Request.QueryString = ""
+				trimmed = true
+			}
+			if base, _, ok := strings.Cut(event.Request.URL, "?"); ok {
+				event.Request.URL = base
+				trimmed = true
+			}
+		}
+		if u, ok := event.Contexts["request"]["url"].(string); ok {
+			if base, _, cut := strings.Cut(u, "?"); cut {
+				event.Contexts["request"]["url"] = base
+				trimmed = true
+			}
+		}
+		for _, key := range []string{"query_params", "route_params"} {
+			if _, ok := event.Extra[key]; ok {
+				delete(event.Extra, key)
+				trimmed = true
+			}
+		}
+		return trimmed
+	}},
+	{"body", func(event *sentry.Event, _ map[string]bool) bool {
+		if event.Request == nil || (event.Request.Data == "" && event.Request.Cookies == "") {
+			return false
+		}
+		event.Request.Data = ""
+		event.Request.Cookies = ""
+		return true
+	}},
+}
+
+// trimEventRequest applies the trimming stages until the request data fits the budget
+func trimEventRequest(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	requestBudgetMu.RLock()
+	budget, kept := requestBudget, requestKeptHeaders
+	requestBudgetMu.RUnlock()
+
+	if budget <= 0 || requestDataSize(event) <= budget {
+		return event
+	}
+
+	var stages []string
+	for _, stage := range requestTrimStages {
+		if stage.trim(event, kept) {
+			stages = append(stages, stage.name)
+			metrics.IncCounter("sentry_request_trimmed_total", metrics.Labels{"stage": stage.name})
+		}
+		if requestDataSize(event) <= budget {
+			break
+		}
+	}
+
+	if len(stages) > 0 {
+		if event.Extra == nil {
+			event.Extra = make(map[string]any, 1)
+		}
+		event.Extra["request_trimmed"] = stages
+	}
+	return event
+}
+
+// requestDataSize returns the JSON size of the request data of an event
+func requestDataSize(event *sentry.Event) int {
+	size := 0
+	add := func(v any) {
+		if data, err := json.Marshal(v); err == nil {
+			size += len(data)
+		}
+	}
+
+	if event.Request != nil {
+		add(event.Request)
+	}
+	if ctx, ok := event.Contexts["request"]; ok {
+		add(ctx)
+	}
+	for _, key := range []string{"query_params", "route_params"} {
+		if v, ok := event.Extra[key]; ok {
+			add(v)
+		}
+	}
+	return size
+}
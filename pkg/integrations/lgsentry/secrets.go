@@ -0,0 +1,104 @@
+package lgsentry
+
+import (
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// EnableSecretScrubbing masks likely secrets (see core.MaskSecrets) in event messages, exception values,
+// tags, extra data, contexts, breadcrumbs and the request (URL, query string, body, cookies, headers and
+// env) via the "secret_scrub" event processor
+// Events with masked values get a "secrets_masked" tag. Calling it again is a no-op
+func EnableSecretScrubbing() {
+	AddEventProcessor("secret_scrub", scrubSecrets, OrderScrub)
+}
+
+// scrubSecrets masks secrets in the string fields of an event
+func scrubSecrets(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	masked := false
+	scrub := func(s string) string {
+		out, kinds := core.MaskSecrets(s)
+		if len(kinds) > 0 {
+			masked = true
+			for _, k := range kinds {
+				metrics.IncCounter("sentry_secrets_masked_total", metrics.Labels{"kind": k})
+			}
+		}
+		return out
+	}
+
+	event.Message = scrub(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = scrub(event.Exception[i].Value)
+	}
+	for k, v := range event.Tags {
+		event.Tags[k] = scrub(v)
+	}
+	for k, v := range event.Extra {
+		event.Extra[k] = scrubValue(v, scrub)
+	}
+	for name, ctx := range event.Contexts {
+		for k, v := range ctx {
+			ctx[k] = scrubValue(v, scrub)
+		}
+		event.Contexts[name] = ctx
+	}
+	if req := event.Request; req != nil {
+		req.URL = scrub(req.URL)
+		req.QueryString = scrub(req.QueryString)
+		req.Data = scrub(req.Data)
+		req.Cookies = scrub(req.Cookies)
+		for k, v := range req.Headers {
+			req.Headers[k] = scrub(v)
+		}
+		for k, v := range req.Env {
+			req.Env[k] = scrub(v)
+		}
+	}
+	for _, b := range event.Breadcrumbs {
+		b.Message = scrub(b.Message)
+		for k, v := range b.Data {
+			b.Data[k] = scrubValue(v, scrub)
+		}
+	}
+
+	if masked {
+		if event.Tags == nil {
+			event.Tags = make(map[string]string, 1)
+		}
+		event.Tags["secrets_masked"] = "true"
+	}
+	return event
+}
+
+// scrubValue masks strings inside event data, recursing into maps and slices
+func scrubValue(v any, scrub func(string) string) any {
+	switch val := v.(type) {
+	case string:
+		return scrub(val)
+	case map[string]any:
+		for k, item := range val {
+			val[k] = scrubValue(item, scrub)
+		}
+		return val
+	case map[string]string:
+		for k, item := range val {
+			val[k] = scrub(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = scrubValue(item, scrub)
+		}
+		return val
+	case []string:
+		for i, item := range val {
+			val[i] = scrub(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
@@ -0,0 +1,48 @@
+package lgsentry
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const testStripeKey = "sk_live_0123456789abcdefABCDEF"
+
+func TestScrubSecretsMasksRequest(t *testing.T) {
+	event := &sentry.Event{
+		Message: "charge failed",
+		Request: &sentry.Request{
+			URL:         "https://api.example.com/charge?key=" + testStripeKey,
+			QueryString: "key=" + testStripeKey,
+			Data:        `{"api_key":"` + testStripeKey + `"}`,
+			Cookies:     "session=" + testStripeKey,
+			Headers:     map[string]string{"Authorization": "Bearer " + testStripeKey},
+			Env:         map[string]string{"REMOTE_ADDR": "10.0.0.1"},
+		},
+		Extra: map[string]any{"error": errors.New(testStripeKey).Error()},
+	}
+
+	event = scrubSecrets(event, nil)
+
+	req := event.Request
+	for name, value := range map[string]string{
+		"url":          req.URL,
+		"query string": req.QueryString,
+		"data":         req.Data,
+		"cookies":      req.Cookies,
+		"header":       req.Headers["Authorization"],
+		"extra":        event.Extra["error"].(string),
+	} {
+		if strings.Contains(value, testStripeKey) {
+			t.Errorf("%s not scrubbed: %q", name, value)
+		}
+	}
+	if req.Env["REMOTE_ADDR"] != "10.0.0.1" {
+		t.Errorf("clean env value changed: %q", req.Env["REMOTE_ADDR"])
+	}
+	if event.Tags["secrets_masked"] != "true" {
+		t.Errorf("secrets_masked tag missing: %v", event.Tags)
+	}
+}
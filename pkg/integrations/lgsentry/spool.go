@@ -0,0 +1,310 @@
+package lgsentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/chaos"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+const spoolFileExt = ".envelope"
+
+// SpoolConfig holds configuration for the disk-backed Sentry transport
+type SpoolConfig struct {
+	// Dir stores undelivered envelopes (required, created if missing)
+	Dir string
+	// MaxFiles bounds the spool; the oldest envelopes are dropped first (default: 1000)
+	MaxFiles int
+	// RetryInterval between attempts to replay the spool (default: 30 seconds)
+	RetryInterval time.Duration
+	// QueueSize is the in-memory send queue; events overflow straight to disk (default: 100)
+	QueueSize int
+	// HTTPClient used to send envelopes (default: client with 10 second timeout)
+	HTTPClient *http.Client
+}
+
+// SpoolTransport is a sentry.Transport that writes events to a bounded on-disk queue when Sentry is
+// unreachable and replays them once it is reachable again, so network blips during incidents don't lose events
+//
+// Usage:
+//
+//	transport, err := lgsentry.NewSpoolTransport(lgsentry.SpoolConfig{Dir: "/var/lib/app/sentry-spool"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = lgsentry.Init(sentry.ClientOptions{Dsn: dsn, Transport: transport})
+type SpoolTransport struct {
+	cfg SpoolConfig
+
+	dsn     *sentry.Dsn
+	queue   chan []byte
+	done    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+	spool   sync.Mutex // Serializes spool directory access
+	seq     atomic.Uint64
+	queued  atomic.Int64 // Envelopes accepted but not yet sent or spooled
+	pending atomic.Bool  // Spool may contain envelopes (avoids listing the directory per event)
+	blocked atomic.Int64 // Unix nanoseconds until which Sentry asked not to send (rate limits)
+}
+
+// errRetryLater marks failures that should keep the envelope in the spool
+var errRetryLater = errors.New("sentry unavailable")
+
+// NewSpoolTransport creates the spool directory and returns the transport
+func NewSpoolTransport(cfg SpoolConfig) (*SpoolTransport, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("sentry spool: Dir is required")
+	}
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = 1000
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 30 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("sentry spool: %w", err)
+	}
+
+	return &SpoolTransport{
+		cfg:   cfg,
+		queue: make(chan []byte, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}, nil
+}
+
+// Configure implements sentry.Transport; it is called by sentry.Init
+func (t *SpoolTransport) Configure(options sentry.ClientOptions) {
+	dsn, err := sentry.NewDsn(options.Dsn)
+	if err != nil {
+		sentry.DebugLogger.Printf("sentry spool: %v", err)
+		return
+	}
+	t.dsn = dsn
+
+	t.wg.Add(1)
+	go t.worker()
+}
+
+// SendEvent implements sentry.Transport
+func (t *SpoolTransport) SendEvent(event *sentry.Event) {
+	if t.dsn == nil {
+		return
+	}
+
+	envelope, err := event.ToEnvelope(&t.dsn.Dsn)
+	if err != nil {
+		sentry.DebugLogger.Printf("sentry spool: %v", err)
+		return
+	}
+	data, err := envelope.Serialize()
+	if err != nil {
+		sentry.DebugLogger.Printf("sentry spool: %v", err)
+		return
+	}
+
+	t.queued.Add(1)
+	if !chaos.SentryQueueFull() {
+		select {
+		case t.queue <- data:
+			return
+		default:
+		}
+	}
+	// Queue full: keep the event rather than dropping it
+	t.writeSpool(data)
+	t.queued.Add(-1)
+}
+
+// Flush implements sentry.Transport, waiting for queued events to be sent or spooled
+func (t *SpoolTransport) Flush(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return t.FlushWithContext(ctx)
+}
+
+// FlushWithContext implements sentry.Transport
+func (t *SpoolTransport) FlushWithContext(ctx context.Context) bool {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for t.queued.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// Close implements sentry.Transport; unsent queued events are written to the spool
+func (t *SpoolTransport) Close() {
+	t.once.Do(func() {
+		close(t.done)
+		t.wg.Wait()
+
+		for {
+			select {
+			case data := <-t.queue:
+				t.writeSpool(data)
+				t.queued.Add(-1)
+			default:
+				return
+			}
+		}
+	})
+}
+
+// SpooledCount returns the number of envelopes waiting on disk
+func (t *SpoolTransport) SpooledCount() int {
+	t.spool.Lock()
+	defer t.spool.Unlock()
+	return len(t.spoolFiles())
+}
+
+func (t *SpoolTransport) worker() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.cfg.RetryInterval)
+	defer ticker.Stop()
+
+	// Replay whatever a previous process left behind
+	t.pending.Store(true)
+	t.replay()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case data := <-t.queue:
+			if err := t.send(data); err != nil {
+				t.writeSpool(data)
+			} else if t.pending.Load() {
+				// Sentry is reachable again
+				t.replay()
+			}
+			t.queued.Add(-1)
+		case <-ticker.C:
+			t.replay()
+		}
+	}
+}
+
+// send posts an envelope, returning errRetryLater for network errors, rate limits and server errors
+// While a rate limit requested by Sentry (429, Retry-After, X-Sentry-Rate-Limits) is in effect nothing
+// is posted. Other rejections (invalid payload) are dropped since retrying cannot succeed
+func (t *SpoolTransport) send(data []byte) error {
+	if until := t.blocked.Load(); until > 0 && time.Now().UnixNano() < until {
+		return fmt.Errorf("%w: rate limited", errRetryLater)
+	}
+
+	status, backoff, err := postEnvelope(context.Background(), t.cfg.HTTPClient, t.dsn, data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errRetryLater, err)
+	}
+	if backoff > 0 {
+		t.blocked.Store(time.Now().Add(backoff).UnixNano())
+	}
+
+	if status == http.StatusTooManyRequests || status >= 500 {
+		return fmt.Errorf("%w: status %d", errRetryLater, status)
+	}
+	if status >= 400 {
+		sentry.DebugLogger.Printf("sentry spool: envelope rejected with status %d", status)
+	}
+	return nil
+}
+
+// replay sends spooled envelopes oldest first, stopping at the first retryable failure
+// The file list is taken under t.spool and the envelopes are sent without it, so SendEvent calls
+// spooling from other goroutines are not blocked behind the network
+func (t *SpoolTransport) replay() {
+	t.spool.Lock()
+	names := t.spoolFiles()
+	t.spool.Unlock()
+
+	for _, name := range names {
+		path := filepath.Join(t.cfg.Dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Evicted by writeSpool meanwhile, or unreadable
+			_ = os.Remove(path)
+			continue
+		}
+
+		if err := t.send(data); err != nil {
+			return
+		}
+		_ = os.Remove(path)
+		metrics.IncCounter("sentry_spool_replayed_total", nil)
+	}
+
+	// Envelopes spooled during the replay are left for the next one
+	t.spool.Lock()
+	t.pending.Store(len(t.spoolFiles()) > 0)
+	t.spool.Unlock()
+}
+
+// writeSpool stores an envelope on disk, evicting the oldest ones beyond MaxFiles
+func (t *SpoolTransport) writeSpool(data []byte) {
+	t.spool.Lock()
+	defer t.spool.Unlock()
+
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), t.seq.Add(1)%1_000_000, spoolFileExt)
+	tmp := filepath.Join(t.cfg.Dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		sentry.DebugLogger.Printf("sentry spool: %v", err)
+		metrics.IncCounter("sentry_spool_dropped_total", metrics.Labels{"reason": "write_failed"})
+		return
+	}
+	// Rename makes the file visible to replay only once it is complete
+	if err := os.Rename(tmp, filepath.Join(t.cfg.Dir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	metrics.IncCounter("sentry_spooled_total", nil)
+	t.pending.Store(true)
+
+	files := t.spoolFiles()
+	for i := 0; i < len(files)-t.cfg.MaxFiles; i++ {
+		_ = os.Remove(filepath.Join(t.cfg.Dir, files[i]))
+		metrics.IncCounter("sentry_spool_dropped_total", metrics.Labels{"reason": "full"})
+	}
+}
+
+// spoolFiles returns spooled envelope names, oldest first (caller holds t.spool)
+func (t *SpoolTransport) spoolFiles() []string {
+	entries, err := os.ReadDir(t.cfg.Dir)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), spoolFileExt) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
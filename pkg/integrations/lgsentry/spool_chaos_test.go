@@ -0,0 +1,28 @@
+//go:build logbundle_chaos
+
+package lgsentry
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/chaos"
+)
+
+func TestSpoolTransportSpoolsOnInjectedQueueOverflow(t *testing.T) {
+	transport := newTestSpool(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer chaos.Inject(chaos.Faults{SentryQueueFull: true})()
+
+	transport.SendEvent(&sentry.Event{EventID: "0123456789abcdef0123456789abcdef", Message: "overflow"})
+
+	if n := transport.SpooledCount(); n != 1 {
+		t.Fatalf("SpooledCount() = %d, want 1", n)
+	}
+	if n := len(transport.queue); n != 0 {
+		t.Fatalf("queue holds %d envelopes, want 0", n)
+	}
+}
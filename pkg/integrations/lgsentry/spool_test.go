@@ -0,0 +1,111 @@
+package lgsentry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func newTestSpool(t *testing.T, handler http.HandlerFunc) *SpoolTransport {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	transport, err := NewSpoolTransport(SpoolConfig{Dir: t.TempDir(), RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dsn := "http://public@" + strings.TrimPrefix(server.URL, "http://") + "/1"
+	if transport.dsn, err = sentry.NewDsn(dsn); err != nil {
+		t.Fatal(err)
+	}
+	return transport
+}
+
+func TestSpoolTransportHonorsRetryAfter(t *testing.T) {
+	var posts atomic.Int32
+	transport := newTestSpool(t, func(w http.ResponseWriter, _ *http.Request) {
+		posts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	for range 3 {
+		if err := transport.send([]byte("{}")); err == nil {
+			t.Fatal("send succeeded while rate limited")
+		}
+	}
+	if n := posts.Load(); n != 1 {
+		t.Fatalf("posted %d envelopes during the rate limit, want 1", n)
+	}
+}
+
+func TestSpoolReplayDoesNotBlockSpooling(t *testing.T) {
+	posting := make(chan struct{})
+	release := make(chan struct{})
+	transport := newTestSpool(t, func(w http.ResponseWriter, _ *http.Request) {
+		select {
+		case posting <- struct{}{}:
+		default:
+		}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	transport.writeSpool([]byte("{}"))
+
+	replayed := make(chan struct{})
+	go func() {
+		transport.replay()
+		close(replayed)
+	}()
+	<-posting
+
+	spooled := make(chan struct{})
+	go func() {
+		transport.writeSpool([]byte("{}"))
+		close(spooled)
+	}()
+	select {
+	case <-spooled:
+	case <-time.After(time.Second):
+		t.Fatal("writeSpool blocked behind the replay")
+	}
+
+	close(release)
+	<-replayed
+	if !transport.pending.Load() {
+		t.Fatal("envelope spooled during the replay is not pending")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   time.Duration
+	}{
+		{"none", http.StatusOK, nil, 0},
+		{"429 default", http.StatusTooManyRequests, nil, defaultRetryAfter},
+		{"seconds", http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, 30 * time.Second},
+		{"date", http.StatusServiceUnavailable, map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute},
+		{"sentry limits", http.StatusOK, map[string]string{"X-Sentry-Rate-Limits": "60:error:organization, 2700:default:organization"}, 2700 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			if got := retryAfter(resp, now); got != tt.want {
+				t.Fatalf("retryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package lgsentry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// TitleResolver controls Sentry issue titles for events whose exception type carries no information
+// (e.g. "*errors.errorString" from errors.New). Sentry builds the title from the innermost exception's
+// type, so the resolver replaces that type with a value taken from the event
+type TitleResolver struct {
+	// GenericTypes lists exception types to rewrite (default: errors.New, fmt.Errorf and errors.Join types)
+	// Add your own wrapper error types here, e.g. "*apperr.Wrapped"
+	GenericTypes []string
+	// ContextPaths is a priority list of event values to use as title; the first non-empty one wins
+	// Supported paths: "contexts.<name>.<key>", "tags.<key>", "extra.<key>", "message"
+	// Default: "contexts.error_context.operation", "tags.error_type", "message"
+	ContextPaths []string
+	// MaxLength truncates resolved titles (default: 100)
+	MaxLength int
+	// Custom resolves the title before ContextPaths; returning "" falls back to them
+	Custom func(event *sentry.Event) string
+}
+
+// defaultGenericTypes are exception types produced by the standard library error constructors
+var defaultGenericTypes = []string{
+	"*errors.errorString",
+	"*fmt.wrapError",
+	"*fmt.wrapErrors",
+	"*errors.joinError",
+}
+
+// SetTitleResolver installs the resolver as the "title" event processor; nil removes it
+//
+// Usage:
+//
+//	lgsentry.SetTitleResolver(&lgsentry.TitleResolver{
+//	    GenericTypes: []string{"*apperr.Error"},
+//	    ContextPaths: []string{"tags.operation", "contexts.error_context.operation"},
+//	})
+func SetTitleResolver(r *TitleResolver) {
+	if r == nil {
+		RemoveEventProcessor("title")
+		return
+	}
+
+	resolver := *r
+	if len(resolver.GenericTypes) == 0 {
+		resolver.GenericTypes = defaultGenericTypes
+	}
+	if len(resolver.ContextPaths) == 0 {
+		resolver.ContextPaths = []string{"contexts.error_context.operation", "tags.error_type", "message"}
+	}
+	if resolver.MaxLength <= 0 {
+		resolver.MaxLength = 100
+	}
+
+	AddEventProcessor("title", func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		resolver.Apply(event)
+		return event
+	}, OrderEnrich)
+}
+
+// Apply rewrites the innermost exception type of event if it is generic and a title can be resolved
+func (r *TitleResolver) Apply(event *sentry.Event) {
+	if event == nil || len(event.Exception) == 0 {
+		return
+	}
+
+	exc := &event.Exception[len(event.Exception)-1]
+	if !slices.Contains(r.GenericTypes, exc.Type) {
+		return
+	}
+
+	title := ""
+	if r.Custom != nil {
+		title = r.Custom(event)
+	}
+	for _, path := range r.ContextPaths {
+		if title != "" {
+			break
+		}
+		title = resolveEventPath(event, path)
+	}
+	if title == "" {
+		return
+	}
+
+	if r.MaxLength > 0 && len(title) > r.MaxLength {
+		title = strings.ToValidUTF8(title[:r.MaxLength], "")
+	}
+	if !utf8.ValidString(title) {
+		return
+	}
+
+	// Keep the original type discoverable
+	if exc.Mechanism == nil {
+		exc.Mechanism = &sentry.Mechanism{Type: "generic"}
+	}
+	if exc.Mechanism.Data == nil {
+		exc.Mechanism.Data = make(map[string]any, 1)
+	}
+	exc.Mechanism.Data["original_type"] = exc.Type
+	exc.Type = title
+}
+
+// resolveEventPath returns the string value at path, or "" if missing
+func resolveEventPath(event *sentry.Event, path string) string {
+	section, rest, _ := strings.Cut(path, ".")
+
+	switch section {
+	case "message":
+		return firstLine(event.Message)
+	case "tags":
+		return event.Tags[rest]
+	case "extra":
+		if v, ok := event.Extra[rest]; ok && v != nil {
+			return firstLine(fmt.Sprint(v))
+		}
+	case "contexts":
+		name, key, ok := strings.Cut(rest, ".")
+		if !ok {
+			return ""
+		}
+		if v, ok := event.Contexts[name][key]; ok && v != nil {
+			return firstLine(fmt.Sprint(v))
+		}
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
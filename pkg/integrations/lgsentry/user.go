@@ -0,0 +1,56 @@
+package lgsentry
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// userIDTags are event tags that carry user IDs and are hashed along with event.User.ID
+var userIDTags = []string{"user_id", "user.id"}
+
+// EnableUserIDHashing sends an HMAC of user IDs (keyed with key) to Sentry instead of the raw IDs
+// The "user_id_hash" event processor hashes event.User.ID and the user_id/user.id tags; use
+// core.HashUserID or fields.HashedUserID to log the same value. Calling it again replaces the key
+//
+// Usage:
+//
+//	lgsentry.EnableUserIDHashing([]byte(os.Getenv("USER_ID_HASH_KEY")))
+//	lgsentry.SetUser(ctx, sentry.User{ID: user.ID})        // Sentry receives the hash
+//	log.Info("order created", fields.HashedUserID(user.ID)) // same hash in logs
+func EnableUserIDHashing(key []byte) {
+	core.SetUserIDHashKey(key)
+	AddEventProcessor("user_id_hash", hashUserIDs, OrderScrub)
+}
+
+// DisableUserIDHashing removes the "user_id_hash" processor and clears the key
+func DisableUserIDHashing() {
+	RemoveEventProcessor("user_id_hash")
+	core.SetUserIDHashKey(nil)
+}
+
+// SetUser sets the user on the Sentry scope of the request in ctx (see requestHub); without a request
+// hub it does nothing, so the user never leaks into the shared current hub and other requests' events
+// With EnableUserIDHashing the ID is hashed when the event is sent
+func SetUser(ctx context.Context, user sentry.User) {
+	if hub := requestHub(ctx); hub != nil {
+		hub.Scope().SetUser(user)
+	}
+}
+
+// hashUserIDs replaces user IDs in the event with their keyed hash
+func hashUserIDs(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	if !core.UserIDHashingEnabled() {
+		return event
+	}
+
+	event.User.ID = core.HashUserID(event.User.ID)
+	for _, tag := range userIDTags {
+		if id, ok := event.Tags[tag]; ok {
+			event.Tags[tag] = core.HashUserID(id)
+		}
+	}
+	return event
+}
@@ -0,0 +1,39 @@
+package lgsentry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestSetUserUsesRequestHub(t *testing.T) {
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	hub := sentry.NewHub(nil, sentry.NewScope())
+	sentryfiber.SetHubOnContext(c, hub)
+	ctx := context.WithValue(context.Background(), "fiber_ctx", c)
+
+	SetUser(ctx, sentry.User{ID: "42"})
+
+	event := hub.Scope().ApplyToEvent(sentry.NewEvent(), nil, nil)
+	if event.User.ID != "42" {
+		t.Fatalf("request hub user = %q, want 42", event.User.ID)
+	}
+}
+
+func TestSetUserWithoutRequestHub(t *testing.T) {
+	before := sentry.CurrentHub().Scope().ApplyToEvent(sentry.NewEvent(), nil, nil).User
+
+	SetUser(context.Background(), sentry.User{ID: "leaked"})
+
+	after := sentry.CurrentHub().Scope().ApplyToEvent(sentry.NewEvent(), nil, nil).User
+	if after.ID != before.ID {
+		t.Fatalf("current hub user changed to %q", after.ID)
+	}
+}
@@ -0,0 +1,297 @@
+// Package lgtemporal instruments Temporal (or Cadence) workflows and activities: logs and Sentry events
+// carry the workflow and run IDs, activity panics are recovered into errors, and failed activities are
+// reported to Sentry once per retry sequence rather than once per attempt. Like lgcron it does not depend
+// on the SDK; a worker interceptor passes the execution info (see RunActivity and RunWorkflow)
+package lgtemporal
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/logger"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgreport"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Execution identifies a workflow run or an activity attempt, as reported by workflow.GetInfo and
+// activity.GetInfo
+type Execution struct {
+	WorkflowID   string
+	RunID        string
+	WorkflowType string
+	// ActivityType and ActivityID are empty for workflows
+	ActivityType string
+	ActivityID   string
+	// Attempt is the current attempt, starting at 1
+	Attempt int
+	// MaxAttempts is MaximumAttempts of the retry policy (0 means unlimited)
+	MaxAttempts int
+}
+
+// Config holds configuration for RunActivity and RunWorkflow
+type Config struct {
+	// Logger for execution logs (if nil, uses the middleware logger)
+	Logger *slog.Logger
+	// Settings are isolated settings (e.g. from logbundle.LogBundle.Settings); nil uses the defaults
+	Settings *config.Settings
+	// NonRetryable reports errors the SDK will not retry (e.g. temporal.ApplicationError with
+	// NonRetryable set), so they are reported to Sentry on any attempt
+	NonRetryable func(error) bool
+	// ReportAttempt is the attempt at which a failure under an unlimited retry policy (MaxAttempts 0) is
+	// reported to Sentry, once per retry sequence; earlier and later attempts are logged at Warn
+	// (default: 5; negative reports only NonRetryable failures)
+	ReportAttempt int
+}
+
+// defaultReportAttempt is the default Config.ReportAttempt
+const defaultReportAttempt = 5
+
+// WithExecution returns ctx carrying the execution: the run ID becomes the trace ID unless ctx already
+// has one, and the IDs are added as Sentry tags (temporal_workflow_id, temporal_run_id, ...)
+func WithExecution(ctx context.Context, e Execution) context.Context {
+	if core.TraceIDFromContext(ctx) == "" && e.RunID != "" {
+		ctx = core.WithTraceID(ctx, e.RunID)
+	}
+	return core.WithSentryTags(ctx, e.tags())
+}
+
+// RunActivity runs one activity attempt: the context gets the execution (see WithExecution) and a
+// Sentry hub, a panic is recovered and reported like a goroutine panic (see lgreport.RecoverGoroutinePanic)
+// and returned as an lgerr.Internal error, and "Temporal activity finished" is logged with the duration
+// (temporal_activity_duration_ms{activity,status}). A failed attempt that will be retried is logged at
+// Warn; the final attempt (MaxAttempts reached or a NonRetryable error) is handled like
+// lgreport.HandleError, with a fingerprint that ignores the attempt so all retries of a failure group
+// into one Sentry issue. Under an unlimited policy the failure is reported once, at Config.ReportAttempt
+//
+// Usage (a worker interceptor, registered with worker.Options.Interceptors):
+//
+//	type activityInterceptor struct{ interceptor.ActivityInboundInterceptorBase }
+//
+//	func (a *activityInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error) {
+//	    info := activity.GetInfo(ctx)
+//	    return lgtemporal.RunActivity(ctx, lgtemporal.Execution{
+//	        WorkflowID:   info.WorkflowExecution.ID,
+//	        RunID:        info.WorkflowExecution.RunID,
+//	        WorkflowType: info.WorkflowType.Name,
+//	        ActivityType: info.ActivityType.Name,
+//	        ActivityID:   info.ActivityID,
+//	        Attempt:      int(info.Attempt),
+//	    }, func(ctx context.Context) (any, error) {
+//	        return a.Next.ExecuteActivity(ctx, in)
+//	    })
+//	}
+func RunActivity(ctx context.Context, e Execution, fn func(ctx context.Context) (any, error), cfg ...Config) (result any, err error) {
+	var c Config
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	ctx = c.context(ctx, e)
+
+	name := "temporal:" + e.ActivityType
+	start := core.Now()
+	completed := false
+	// Runs after RecoverGoroutinePanic, so a panic leaves completed unset
+	defer func() {
+		if !completed {
+			err = lgerr.Internal("activity "+e.ActivityType+" panicked",
+				lgerr.WithFingerprint("temporal_activity_panic", e.ActivityType),
+				lgerr.WithIgnoreSentry(),
+			)
+		}
+		c.finishActivity(ctx, e, start, completed, err)
+	}()
+	defer lgreport.RecoverGoroutinePanic(ctx, name)
+
+	result, err = fn(ctx)
+	completed = true
+	return result, err
+}
+
+// RunWorkflow runs a workflow execution and logs "Temporal workflow finished" with its status; a
+// failure is handled like lgreport.HandleError. ctx carries the settings and Sentry tags of the worker
+// (e.g. bundle.Context(context.Background())), since workflow.Context is not a context.Context; the
+// execution is added to it. isReplaying is workflow.IsReplaying bound to the workflow context: nothing is
+// logged or reported for a completion that is being replayed. Workflow panics are not recovered, the
+// SDK fails the workflow task and retries it
+//
+// Usage:
+//
+//	func (w *workflowInterceptor) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (any, error) {
+//	    info := workflow.GetInfo(ctx)
+//	    return lgtemporal.RunWorkflow(w.ctx, lgtemporal.Execution{
+//	        WorkflowID:   info.WorkflowExecution.ID,
+//	        RunID:        info.WorkflowExecution.RunID,
+//	        WorkflowType: info.WorkflowType.Name,
+//	        Attempt:      int(info.Attempt),
+//	    }, func() bool { return workflow.IsReplaying(ctx) }, func() (any, error) {
+//	        return w.Next.ExecuteWorkflow(ctx, in)
+//	    })
+//	}
+func RunWorkflow(ctx context.Context, e Execution, isReplaying func() bool, fn func() (any, error), cfg ...Config) (any, error) {
+	var c Config
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	result, err := fn()
+	if isReplaying != nil && isReplaying() {
+		return result, err
+	}
+
+	ctx = c.context(ctx, e)
+	status := "ok"
+	level := slog.LevelInfo
+	if err != nil {
+		status, level = "error", slog.LevelError
+		lgreport.HandleError(ctx, reportError(e, err, true))
+	}
+	logger.LogNoSourceCtx(ctx, c.logger(ctx), level, "Temporal workflow finished",
+		append(e.attrs(), slog.String("status", status))...,
+	)
+	return result, err
+}
+
+// context attaches the settings, the execution and a Sentry hub scoped to the execution
+func (c Config) context(ctx context.Context, e Execution) context.Context {
+	if c.Settings != nil {
+		ctx = config.WithSettings(ctx, c.Settings)
+	}
+	ctx = WithExecution(ctx, e)
+
+	if config.FromContext(ctx).SentryEnabled() {
+		hub := sentry.CurrentHub().Clone()
+		hub.Scope().SetTags(core.SentryScopeTags(ctx))
+		ctx = sentry.SetHubOnContext(ctx, hub)
+	}
+	return ctx
+}
+
+// finishActivity logs the attempt, records its duration and reports final failures
+func (c Config) finishActivity(ctx context.Context, e Execution, start time.Time, completed bool, err error) {
+	duration := core.Since(start)
+
+	status := "ok"
+	level := slog.LevelInfo
+	switch {
+	case !completed:
+		// Already logged and reported by RecoverGoroutinePanic
+		status, level = "panic", slog.LevelError
+	case err != nil && shouldReport(e, c.NonRetryable != nil && c.NonRetryable(err), c.reportAttempt()):
+		status, level = "error", slog.LevelError
+		lgreport.HandleError(ctx, reportError(e, err, false))
+	case err != nil:
+		status, level = "retry", slog.LevelWarn
+	}
+
+	metrics.Observe("temporal_activity_duration_ms", metrics.Labels{"activity": e.ActivityType, "status": status},
+		float64(duration.Microseconds())/1000)
+
+	attrs := append(e.attrs(), slog.String("status", status), fields.DurationMS(duration))
+	if status == "retry" {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	logger.LogNoSourceCtx(ctx, c.logger(ctx), level, "Temporal activity finished", attrs...)
+}
+
+// reportError converts err for HandleError with a fingerprint that is the same for every attempt
+// err itself is wrapped, not modified, because it is returned to the SDK
+func reportError(e Execution, err error, workflow bool) *lgerr.Error {
+	lgErr := lgerr.FromError(err)
+	fingerprint := lgErr.Fingerprint()
+	if len(fingerprint) == 0 {
+		fingerprint = []string{string(lgErr.Type()), lgErr.Message()}
+	}
+
+	kind, name := "temporal_activity", e.ActivityType
+	if workflow {
+		kind, name = "temporal_workflow", e.WorkflowType
+	}
+
+	report := lgerr.WrapWithType(err, lgErr.Type(), kind+" "+name+" failed",
+		lgerr.WithFingerprint(append([]string{kind, name}, fingerprint...)...),
+		lgerr.WithContext("temporal_attempt", e.Attempt),
+	)
+	if lgErr.ShouldIgnoreSentry() {
+		report.IgnoreSentry()
+	}
+	return report
+}
+
+// shouldReport reports whether a failed attempt is the last one of its retry sequence; with an unlimited
+// policy only reportAttempt is reported (none when it is negative), the others belong to the same issue
+func shouldReport(e Execution, nonRetryable bool, reportAttempt int) bool {
+	switch {
+	case nonRetryable:
+		return true
+	case e.MaxAttempts > 0:
+		return e.Attempt >= e.MaxAttempts
+	default:
+		return reportAttempt > 0 && e.Attempt == reportAttempt
+	}
+}
+
+// reportAttempt returns ReportAttempt or its default
+func (c Config) reportAttempt() int {
+	if c.ReportAttempt == 0 {
+		return defaultReportAttempt
+	}
+	return c.ReportAttempt
+}
+
+// tags returns the Sentry tags of the execution
+func (e Execution) tags() map[string]string {
+	tags := make(map[string]string)
+	for _, a := range e.fields() {
+		tags[a.Key] = a.Value.String()
+	}
+	return tags
+}
+
+// attrs returns the execution fields as log attributes
+func (e Execution) attrs() []any {
+	fields := e.fields()
+	attrs := make([]any, len(fields))
+	for i, a := range fields {
+		attrs[i] = a
+	}
+	return attrs
+}
+
+// fields returns the non-empty execution fields
+func (e Execution) fields() []slog.Attr {
+	var fields []slog.Attr
+	add := func(key, value string) {
+		if value != "" {
+			fields = append(fields, slog.String(key, value))
+		}
+	}
+	add("temporal_workflow_id", e.WorkflowID)
+	add("temporal_run_id", e.RunID)
+	add("temporal_workflow_type", e.WorkflowType)
+	add("temporal_activity_type", e.ActivityType)
+	add("temporal_activity_id", e.ActivityID)
+	if e.Attempt > 0 {
+		add("temporal_attempt", strconv.Itoa(e.Attempt))
+	}
+	return fields
+}
+
+// logger returns the configured logger, falling back to the middleware logger
+func (c Config) logger(ctx context.Context) *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	if log := config.FromContext(ctx).MiddlewareLogger(); log != nil {
+		return log
+	}
+	return handler.GetInternalLogger()
+}
@@ -0,0 +1,135 @@
+package lgtemporal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+func TestShouldReport(t *testing.T) {
+	tests := []struct {
+		name          string
+		attempt, max  int
+		nonRetryable  bool
+		reportAttempt int
+		want          bool
+	}{
+		{name: "retry pending", attempt: 1, max: 3, want: false},
+		{name: "last attempt", attempt: 3, max: 3, want: true},
+		{name: "non retryable", attempt: 1, max: 3, nonRetryable: true, want: true},
+		{name: "unlimited first attempt", attempt: 1, reportAttempt: 5, want: false},
+		{name: "unlimited report attempt", attempt: 5, reportAttempt: 5, want: true},
+		{name: "unlimited after report attempt", attempt: 6, reportAttempt: 5, want: false},
+		{name: "unlimited never reported", attempt: 5, reportAttempt: -1, want: false},
+		{name: "unlimited non retryable", attempt: 2, nonRetryable: true, reportAttempt: -1, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Execution{Attempt: tt.attempt, MaxAttempts: tt.max}
+			if got := shouldReport(e, tt.nonRetryable, tt.reportAttempt); got != tt.want {
+				t.Fatalf("shouldReport = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunActivityCorrelatesContext(t *testing.T) {
+	e := Execution{WorkflowID: "order-42", RunID: "run-1", ActivityType: "Charge", Attempt: 1}
+	var buf bytes.Buffer
+	cfg := Config{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+
+	_, err := RunActivity(context.Background(), e, func(ctx context.Context) (any, error) {
+		if got := core.TraceIDFromContext(ctx); got != "run-1" {
+			t.Errorf("trace ID = %q, want the run ID", got)
+		}
+		if got := core.SentryTagsFromContext(ctx)["temporal_workflow_id"]; got != "order-42" {
+			t.Errorf("workflow tag = %q", got)
+		}
+		return nil, nil
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "temporal_workflow_id=order-42") || !strings.Contains(buf.String(), "status=ok") {
+		t.Fatalf("finish log missing execution fields:\n%s", buf.String())
+	}
+}
+
+func TestRunActivityRecoversPanic(t *testing.T) {
+	e := Execution{RunID: "run-2", ActivityType: "Ship", Attempt: 1}
+	cfg := Config{Logger: slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))}
+
+	_, err := RunActivity(context.Background(), e, func(context.Context) (any, error) {
+		panic("boom")
+	}, cfg)
+
+	var lgErr *lgerr.Error
+	if !errors.As(err, &lgErr) || lgErr.Type() != lgerr.TypeInternal {
+		t.Fatalf("err = %v, want an internal lgerr.Error", err)
+	}
+}
+
+func TestRunActivityRetryLogsWarn(t *testing.T) {
+	e := Execution{RunID: "run-3", ActivityType: "Charge", Attempt: 1, MaxAttempts: 3}
+	var buf bytes.Buffer
+	cfg := Config{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+
+	want := errors.New("gateway down")
+	_, err := RunActivity(context.Background(), e, func(context.Context) (any, error) {
+		return nil, want
+	}, cfg)
+	if err != want {
+		t.Fatalf("err = %v, want the activity error unchanged", err)
+	}
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "status=retry") {
+		t.Fatalf("retried attempt not logged at Warn:\n%s", buf.String())
+	}
+}
+
+func TestReportErrorFingerprintIgnoresAttempt(t *testing.T) {
+	err := errors.New("gateway down")
+	first := reportError(Execution{ActivityType: "Charge", Attempt: 1}, err, false)
+	last := reportError(Execution{ActivityType: "Charge", Attempt: 5}, err, false)
+	if strings.Join(first.Fingerprint(), "|") != strings.Join(last.Fingerprint(), "|") {
+		t.Fatalf("fingerprints differ: %v vs %v", first.Fingerprint(), last.Fingerprint())
+	}
+	if !errors.Is(first, err) {
+		t.Fatal("report does not wrap the activity error")
+	}
+}
+
+func TestRunActivityUnlimitedFirstFailureIsRetry(t *testing.T) {
+	e := Execution{RunID: "run-4", ActivityType: "Sync", Attempt: 1}
+	var buf bytes.Buffer
+	cfg := Config{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+
+	_, _ = RunActivity(context.Background(), e, func(context.Context) (any, error) {
+		return nil, errors.New("temporarily unavailable")
+	}, cfg)
+	if !strings.Contains(buf.String(), "status=retry") {
+		t.Fatalf("first failure of an unlimited policy not logged as a retry:\n%s", buf.String())
+	}
+}
+
+func TestRunWorkflowKeepsCallerContext(t *testing.T) {
+	var buf bytes.Buffer
+	settings := config.NewSettings()
+	settings.SetMiddlewareLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx := core.WithSentryTags(config.WithSettings(context.Background(), settings), map[string]string{"tenant_id": "t-1"})
+
+	e := Execution{WorkflowID: "order-7", RunID: "run-5", WorkflowType: "Order"}
+	_, err := RunWorkflow(ctx, e, nil, func() (any, error) { return "done", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Temporal workflow finished") || !strings.Contains(buf.String(), "temporal_workflow_id=order-7") {
+		t.Fatalf("finish log not written with the caller's settings:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package lgtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Clock is a manually advanced core.Clock for deterministic tests
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock stopped at start
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// UseClock installs a Clock stopped at start as the logbundle clock for the duration of the test
+//
+// Usage:
+//
+//	clock := lgtest.UseClock(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	handler(ctx)
+//	clock.Advance(2 * time.Second)
+func UseClock(t testing.TB, start time.Time) *Clock {
+	t.Helper()
+
+	c := NewClock(start)
+	core.SetClock(c)
+	t.Cleanup(func() { core.SetClock(nil) })
+	return c
+}
@@ -0,0 +1,147 @@
+// Package lgtest captures log records in tests and queries them
+//
+// Usage:
+//
+//	rec := lgtest.NewRecorder()
+//	svc := NewService(rec.Logger())
+//	svc.CreateOrder(ctx, order)
+//
+//	errs := rec.Records().AtLeast(slog.LevelWarn).ByAttr("user_id", "u1")
+//	if errs.Count() != 0 {
+//	    t.Fatalf("unexpected warnings:\n%s", errs.Snapshot())
+//	}
+package lgtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Record is a captured log record; attributes inside groups are keyed "group.key"
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr // Resolved, flattened, in logging order
+}
+
+// Attr returns the value of the attribute key
+func (r Record) Attr(key string) (slog.Value, bool) {
+	for _, a := range r.Attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// recorderState is shared by a Recorder and the handlers derived from it with WithAttrs/WithGroup
+type recorderState struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// Recorder is a slog.Handler that captures every record (all levels) for later queries
+type Recorder struct {
+	state  *recorderState
+	attrs  []slog.Attr
+	prefix string
+}
+
+// NewRecorder returns an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{state: &recorderState{}}
+}
+
+// Logger returns a logger writing to the recorder
+func (r *Recorder) Logger() *slog.Logger {
+	return slog.New(r)
+}
+
+// Records returns a copy of the captured records
+func (r *Recorder) Records() Records {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	return slices.Clone(r.state.records)
+}
+
+// Reset discards the captured records
+func (r *Recorder) Reset() {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.records = nil
+}
+
+func (r *Recorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle captures the record; the context trace ID is added under core.GetTraceIDFieldName()
+// unless the record already carries it
+func (r *Recorder) Handle(ctx context.Context, record slog.Record) error {
+	rec := Record{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+		Attrs:   slices.Clone(r.attrs),
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		rec.Attrs = appendFlattened(rec.Attrs, r.prefix, a)
+		return true
+	})
+
+	traceKey := core.GetTraceIDFieldName()
+	if _, ok := rec.Attr(traceKey); !ok && ctx != nil {
+		if traceID := core.TraceIDFromContext(ctx); traceID != "" {
+			rec.Attrs = append(rec.Attrs, slog.String(traceKey, traceID))
+		}
+	}
+
+	r.state.mu.Lock()
+	r.state.records = append(r.state.records, rec)
+	r.state.mu.Unlock()
+	return nil
+}
+
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *r
+	clone.attrs = slices.Clone(r.attrs)
+	for _, a := range attrs {
+		clone.attrs = appendFlattened(clone.attrs, r.prefix, a)
+	}
+	return &clone
+}
+
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	clone := *r
+	clone.prefix = r.prefix + name + "."
+	return &clone
+}
+
+// appendFlattened resolves a and appends it, expanding groups into "group.key" attributes
+func appendFlattened(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendFlattened(attrs, groupPrefix, ga)
+		}
+		return attrs
+	}
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	a.Key = prefix + a.Key
+	return append(attrs, a)
+}
@@ -0,0 +1,72 @@
+package lgtest_test
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgtest"
+)
+
+func TestRecorderQueries(t *testing.T) {
+	rec := lgtest.NewRecorder()
+	log := rec.Logger().With("service", "orders")
+
+	ctx := core.WithTraceID(context.Background(), "trace-1")
+	log.DebugContext(ctx, "cart loaded", slog.Int("items", 3))
+	log.WithGroup("order").InfoContext(ctx, "order created", slog.Int("id", 42))
+	log.Warn("stock low", slog.String("sku", "A-1"))
+
+	records := rec.Records()
+	if records.Count() != 3 {
+		t.Fatalf("captured %d records, want 3:\n%s", records.Count(), records)
+	}
+	if got := records.AtLeast(slog.LevelInfo).Messages(); !slices.Equal(got, []string{"order created", "stock low"}) {
+		t.Errorf("AtLeast(Info) = %v", got)
+	}
+	if got := records.WithinTrace("trace-1").Count(); got != 2 {
+		t.Errorf("WithinTrace = %d records, want 2", got)
+	}
+	if got := records.ByAttr("order.id", 42).Count(); got != 1 {
+		t.Errorf("ByAttr(order.id) = %d records, want 1", got)
+	}
+	if got := records.HasAttr("service").Count(); got != 3 {
+		t.Errorf("HasAttr(service) = %d records, want 3", got)
+	}
+	if last, ok := records.Last(); !ok || last.Message != "stock low" {
+		t.Errorf("Last = %+v, %v", last, ok)
+	}
+
+	rec.Reset()
+	if !rec.Records().Empty() {
+		t.Error("Reset kept records")
+	}
+}
+
+func TestSnapshotGolden(t *testing.T) {
+	rec := lgtest.NewRecorder()
+	ctx := core.WithTraceID(context.Background(), "trace-2")
+	rec.Logger().InfoContext(ctx, "order created",
+		slog.Int("order_id", 42),
+		slog.String("user_id", "u1"),
+		slog.Duration("duration", 3*time.Millisecond),
+	)
+
+	lgtest.AssertGolden(t, "testdata/order_created.golden", rec.Records().Snapshot("duration"))
+}
+
+func TestUseClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := lgtest.UseClock(t, start)
+	clock.Advance(2 * time.Second)
+
+	if got := core.Now(); !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("core.Now() = %v, want %v", got, start.Add(2*time.Second))
+	}
+	if got := core.Since(start); got != 2*time.Second {
+		t.Errorf("core.Since = %v, want 2s", got)
+	}
+}
@@ -0,0 +1,105 @@
+package lgtest
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// Records is a query over captured records; every filter returns a new Records
+type Records []Record
+
+// Filter keeps the records matching fn
+func (rs Records) Filter(fn func(Record) bool) Records {
+	var out Records
+	for _, r := range rs {
+		if fn(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// ByLevel keeps the records logged at exactly level
+func (rs Records) ByLevel(level slog.Level) Records {
+	return rs.Filter(func(r Record) bool { return r.Level == level })
+}
+
+// AtLeast keeps the records logged at level or above
+func (rs Records) AtLeast(level slog.Level) Records {
+	return rs.Filter(func(r Record) bool { return r.Level >= level })
+}
+
+// ByMessage keeps the records with exactly this message
+func (rs Records) ByMessage(msg string) Records {
+	return rs.Filter(func(r Record) bool { return r.Message == msg })
+}
+
+// MessageContains keeps the records whose message contains substr
+func (rs Records) MessageContains(substr string) Records {
+	return rs.Filter(func(r Record) bool { return strings.Contains(r.Message, substr) })
+}
+
+// HasAttr keeps the records carrying the attribute key (grouped keys are "group.key")
+func (rs Records) HasAttr(key string) Records {
+	return rs.Filter(func(r Record) bool {
+		_, ok := r.Attr(key)
+		return ok
+	})
+}
+
+// ByAttr keeps the records whose attribute key equals value
+// Values are compared as slog values (so 42 matches slog.Int), falling back to their string form
+func (rs Records) ByAttr(key string, value any) Records {
+	want := slog.AnyValue(value)
+	return rs.Filter(func(r Record) bool {
+		got, ok := r.Attr(key)
+		return ok && (got.Equal(want) || got.String() == want.String())
+	})
+}
+
+// WithinTrace keeps the records of one trace (see core.GetTraceIDFieldName)
+func (rs Records) WithinTrace(traceID string) Records {
+	return rs.ByAttr(core.GetTraceIDFieldName(), traceID)
+}
+
+// Count returns the number of records
+func (rs Records) Count() int {
+	return len(rs)
+}
+
+// Empty reports whether no record matched
+func (rs Records) Empty() bool {
+	return len(rs) == 0
+}
+
+// First returns the first record
+func (rs Records) First() (Record, bool) {
+	if len(rs) == 0 {
+		return Record{}, false
+	}
+	return rs[0], true
+}
+
+// Last returns the last record
+func (rs Records) Last() (Record, bool) {
+	if len(rs) == 0 {
+		return Record{}, false
+	}
+	return rs[len(rs)-1], true
+}
+
+// Messages returns the record messages in logging order
+func (rs Records) Messages() []string {
+	msgs := make([]string, len(rs))
+	for i, r := range rs {
+		msgs[i] = r.Message
+	}
+	return msgs
+}
+
+// String returns the snapshot of the records (see Snapshot)
+func (rs Records) String() string {
+	return rs.Snapshot()
+}
@@ -0,0 +1,86 @@
+package lgtest
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+)
+
+// UpdateGoldenEnv rewrites golden files in AssertGolden when set to "1"
+const UpdateGoldenEnv = "LGTEST_UPDATE"
+
+// maskedValue replaces masked attribute values in snapshots
+const maskedValue = "<masked>"
+
+// Snapshot renders the records in a stable, diff-friendly text format: one "[LEVEL] message" line per
+// record followed by one indented "key=value" line per attribute, sorted by key. Timestamps are
+// omitted; the trace ID and the attributes listed in mask (e.g. "duration") keep their key but
+// their value is replaced with <masked>
+//
+//	[INFO] order created
+//	    order_id=42
+//	    user_id="u1"
+func (rs Records) Snapshot(mask ...string) string {
+	masked := append([]string{core.GetTraceIDFieldName()}, mask...)
+
+	var b strings.Builder
+	for _, r := range rs {
+		b.WriteString("[" + r.Level.String() + "] " + r.Message + "\n")
+
+		attrs := slices.Clone(r.Attrs)
+		slices.SortStableFunc(attrs, func(x, y slog.Attr) int { return strings.Compare(x.Key, y.Key) })
+		for _, a := range attrs {
+			b.WriteString("    " + a.Key + "=")
+			if slices.Contains(masked, a.Key) {
+				b.WriteString(maskedValue)
+			} else {
+				b.WriteString(snapshotValue(a))
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// snapshotValue quotes strings so that empty values and spaces stay unambiguous
+func snapshotValue(a slog.Attr) string {
+	if a.Value.Kind() == slog.KindString {
+		return strconv.Quote(a.Value.String())
+	}
+	return a.Value.String()
+}
+
+// AssertGolden compares got with the golden file at path and fails t with both versions on mismatch
+// Run the tests with LGTEST_UPDATE=1 to create or rewrite the golden files
+//
+// Usage:
+//
+//	lgtest.AssertGolden(t, "testdata/checkout.golden", rec.Records().Snapshot("duration"))
+func AssertGolden(t testing.TB, path string, got string) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("lgtest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("lgtest: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("lgtest: reading golden file (run with %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+	if !bytes.Equal(want, []byte(got)) {
+		t.Errorf("lgtest: log snapshot differs from %s (run with %s=1 to update)\n--- want\n%s--- got\n%s", path, UpdateGoldenEnv, want, got)
+	}
+}
@@ -0,0 +1,277 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Labels are key/value pairs attached to a metric sample
+type Labels map[string]string
+
+// Recorder receives metric samples emitted by logbundle
+// Implement this interface to forward metrics to Prometheus, StatsD, OpenTelemetry, etc.
+type Recorder interface {
+	// IncCounter adds delta to a monotonically increasing counter
+	IncCounter(name string, labels Labels, delta float64)
+	// Observe records a value in a histogram (durations, sizes)
+	Observe(name string, labels Labels, value float64)
+	// SetGauge sets the current value of a gauge
+	SetGauge(name string, labels Labels, value float64)
+}
+
+var (
+	recorder   Recorder = NewRegistry()
+	recorderMu sync.RWMutex
+)
+
+// SetRecorder sets the global metrics recorder
+// If nil, metrics are discarded
+func SetRecorder(r Recorder) {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	recorder = r
+}
+
+// GetRecorder returns the global metrics recorder, or nil if metrics are disabled
+func GetRecorder() Recorder {
+	recorderMu.RLock()
+	defer recorderMu.RUnlock()
+	return recorder
+}
+
+// IncCounter increments a counter on the global recorder
+func IncCounter(name string, labels Labels) {
+	if r := GetRecorder(); r != nil {
+		r.IncCounter(name, labels, 1)
+	}
+}
+
+// AddCounter adds delta to a counter on the global recorder
+func AddCounter(name string, labels Labels, delta float64) {
+	if r := GetRecorder(); r != nil {
+		r.IncCounter(name, labels, delta)
+	}
+}
+
+// Observe records a histogram value on the global recorder
+func Observe(name string, labels Labels, value float64) {
+	if r := GetRecorder(); r != nil {
+		r.Observe(name, labels, value)
+	}
+}
+
+// SetGauge sets a gauge value on the global recorder
+func SetGauge(name string, labels Labels, value float64) {
+	if r := GetRecorder(); r != nil {
+		r.SetGauge(name, labels, value)
+	}
+}
+
+// DefaultBuckets are the histogram upper bounds used when none are registered for a metric
+var DefaultBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Kind identifies the metric type of a Sample
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+)
+
+// Sample is a point-in-time snapshot of a single metric series
+type Sample struct {
+	Name    string    `json:"name"`
+	Kind    Kind      `json:"kind"`
+	Labels  Labels    `json:"labels,omitempty"`
+	Value   float64   `json:"value,omitempty"`
+	Count   uint64    `json:"count,omitempty"`
+	Sum     float64   `json:"sum,omitempty"`
+	Min     float64   `json:"min,omitempty"`
+	Max     float64   `json:"max,omitempty"`
+	Buckets []float64 `json:"buckets,omitempty"`
+	Counts  []uint64  `json:"bucket_counts,omitempty"`
+}
+
+type series struct {
+	sample Sample
+}
+
+// DefaultMaxSeries bounds the series of a Registry created with NewRegistry
+const DefaultMaxSeries = 10000
+
+// DroppedSeriesMetric counts the samples a Registry discarded because it was full
+const DroppedSeriesMetric = "metrics_series_dropped_total"
+
+// Registry is an in-memory Recorder, used by default so metrics can be inspected without a backend
+// It keeps at most MaxSeries series; samples for new series beyond that are discarded and counted in
+// metrics_series_dropped_total{metric}, so label values taken from requests cannot grow memory unbounded
+type Registry struct {
+	mu        sync.Mutex
+	series    map[string]*series
+	buckets   map[string][]float64
+	maxSeries int
+}
+
+// NewRegistry creates an empty in-memory registry holding at most DefaultMaxSeries series
+func NewRegistry() *Registry {
+	return &Registry{
+		series:    make(map[string]*series, 32),
+		buckets:   make(map[string][]float64),
+		maxSeries: DefaultMaxSeries,
+	}
+}
+
+// SetMaxSeries changes the series limit (n <= 0 restores DefaultMaxSeries); existing series are kept
+func (r *Registry) SetMaxSeries(n int) {
+	if n <= 0 {
+		n = DefaultMaxSeries
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxSeries = n
+}
+
+// SetBuckets configures histogram upper bounds for a metric name
+// Must be called before the first observation of that metric
+func (r *Registry) SetBuckets(name string, buckets []float64) {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buckets[name] = sorted
+}
+
+// IncCounter implements Recorder
+func (r *Registry) IncCounter(name string, labels Labels, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.get(name, KindCounter, labels); s != nil {
+		s.sample.Value += delta
+	}
+}
+
+// SetGauge implements Recorder
+func (r *Registry) SetGauge(name string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.get(name, KindGauge, labels); s != nil {
+		s.sample.Value = value
+	}
+}
+
+// Observe implements Recorder
+func (r *Registry) Observe(name string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name, KindHistogram, labels)
+	if s == nil {
+		return
+	}
+	if s.sample.Count == 0 || value < s.sample.Min {
+		s.sample.Min = value
+	}
+	if s.sample.Count == 0 || value > s.sample.Max {
+		s.sample.Max = value
+	}
+	s.sample.Count++
+	s.sample.Sum += value
+	for i, upper := range s.sample.Buckets {
+		if value <= upper {
+			s.sample.Counts[i]++
+			break
+		}
+	}
+}
+
+// Snapshot returns a copy of all series sorted by name and labels
+func (r *Registry) Snapshot() []Sample {
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.series))
+	for k := range r.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	samples := make([]Sample, 0, len(keys))
+	for _, k := range keys {
+		s := r.series[k].sample
+		s.Counts = append([]uint64(nil), s.Counts...)
+		samples = append(samples, s)
+	}
+	r.mu.Unlock()
+
+	return samples
+}
+
+// Reset removes all recorded series
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series = make(map[string]*series, 32)
+}
+
+// get returns the series for name/labels, creating it if needed; nil when the registry is full
+// (caller must hold r.mu)
+func (r *Registry) get(name string, kind Kind, labels Labels) *series {
+	key := seriesKey(name, labels)
+	if s, ok := r.series[key]; ok {
+		return s
+	}
+	if r.maxSeries > 0 && len(r.series) >= r.maxSeries && name != DroppedSeriesMetric {
+		// The drop counters are exempt from the limit, bounded by the number of metric names
+		dropped := r.get(DroppedSeriesMetric, KindCounter, Labels{"metric": name})
+		dropped.sample.Value++
+		return nil
+	}
+
+	copied := make(Labels, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+
+	s := &series{sample: Sample{Name: name, Kind: kind, Labels: copied}}
+	if kind == KindHistogram {
+		buckets, ok := r.buckets[name]
+		if !ok {
+			buckets = DefaultBuckets
+		}
+		s.sample.Buckets = buckets
+		s.sample.Counts = make([]uint64, len(buckets))
+	}
+	r.series[key] = s
+	return s
+}
+
+// seriesKey builds a stable identifier from a metric name and its sorted labels
+func seriesKey(name string, labels Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	builder.WriteString(name)
+	for _, k := range keys {
+		builder.WriteByte('|')
+		builder.WriteString(k)
+		builder.WriteByte('=')
+		builder.WriteString(labels[k])
+	}
+	return builder.String()
+}
+
+// Snapshot returns the samples of the global recorder if it is a *Registry, otherwise nil
+func Snapshot() []Sample {
+	if reg, ok := GetRecorder().(*Registry); ok {
+		return reg.Snapshot()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRegistryMaxSeries(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxSeries(3)
+
+	for i := range 10 {
+		r.IncCounter("requests_total", Labels{"path": "/p" + strconv.Itoa(i)}, 1)
+	}
+	r.IncCounter("requests_total", Labels{"path": "/p0"}, 1)
+
+	var kept, dropped float64
+	for _, s := range r.Snapshot() {
+		switch s.Name {
+		case "requests_total":
+			kept += s.Value
+		case DroppedSeriesMetric:
+			if s.Labels["metric"] != "requests_total" {
+				t.Errorf("dropped series labels = %v", s.Labels)
+			}
+			dropped = s.Value
+		}
+	}
+	if kept != 4 || dropped != 7 {
+		t.Fatalf("kept %v samples and dropped %v, want 4 and 7:\n%+v", kept, dropped, r.Snapshot())
+	}
+	if n := len(r.Snapshot()); n != 4 {
+		t.Fatalf("%d series, want 3 plus the drop counter", n)
+	}
+}
@@ -0,0 +1,74 @@
+package logbundle
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+)
+
+// Preset names accepted by Preset
+const (
+	PresetProduction  = "production"
+	PresetStaging     = "staging"
+	PresetDevelopment = "development"
+	PresetTest        = "test"
+)
+
+// Preset returns a Builder with the defaults of an environment, so services share one baseline
+// instead of copy-pasted init code; adjust it with the With* methods before Build:
+//   - production: Info, JSON with runtime metadata, secrets masked, PII dropped, client IPs anonymized,
+//     Sentry enabled for 5xx, no sampling
+//   - staging: as production at Debug, with Debug records sampled at 10% of traces
+//   - development: Debug, text with source locations, PII kept, Sentry disabled
+//   - test: Warn, text, Sentry disabled
+//
+// Names are case-insensitive ("prod", "stage", "dev" are accepted); unknown names get the production
+// preset, the safe choice for data handling. Sentry itself is initialized separately (lgsentry.Init)
+//
+// Usage:
+//
+//	bundle := logbundle.Preset(os.Getenv("APP_ENV")).
+//	    WithLevel(slog.LevelWarn).
+//	    Build()
+func Preset(env string) *Builder {
+	b := NewBuilder()
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case PresetDevelopment, "dev", "local":
+		return b.WithConfig(LoggerConfig{
+			Level:         slog.LevelDebug,
+			AddSource:     true,
+			Format:        handler.FormatText,
+			DetectSecrets: true,
+		})
+	case PresetTest:
+		return b.WithConfig(LoggerConfig{
+			Level:  slog.LevelWarn,
+			Format: handler.FormatText,
+		})
+	case PresetStaging, "stage":
+		cfg := productionConfig()
+		cfg.Level = slog.LevelDebug
+		cfg.Sampling = &handler.SamplingOptions{Rate: 0.1, KeepLevel: slog.LevelInfo}
+		return b.WithConfig(cfg).
+			WithSentry(true).
+			WithSentryMinHTTPStatus(500).
+			WithIPAnonymization(true)
+	default:
+		return b.WithConfig(productionConfig()).
+			WithSentry(true).
+			WithSentryMinHTTPStatus(500).
+			WithIPAnonymization(true)
+	}
+}
+
+// productionConfig is the logger configuration of the production preset
+func productionConfig() LoggerConfig {
+	return LoggerConfig{
+		Level:              slog.LevelInfo,
+		Format:             handler.FormatJSON,
+		AddRuntimeMetadata: true,
+		DetectSecrets:      true,
+		PII:                &handler.PIIOptions{Mode: handler.PIIDrop},
+	}
+}
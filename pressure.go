@@ -0,0 +1,31 @@
+package logbundle
+
+import "github.com/aeternitas-infinita/logbundle-go/pkg/handler"
+
+// Pressure returns how saturated the async log pipeline is, from 0 to 1: the fill ratio of the fullest
+// sink queue of the open handler.TeeWriters, 1 once records spill to the disk overflow. Never blocks;
+// synchronous outputs (InitLog's stdout) report 0
+//
+// Usage:
+//
+//	if logbundle.Pressure() < 0.8 {
+//	    log.Debug("request dump", logbundle.JSONAttr("body", body))
+//	}
+func Pressure() float64 {
+	return handler.Pressure()
+}
+
+// OnPressure registers fn, called with overloaded=true when Pressure reaches threshold and with
+// overloaded=false once it drops below half of it, so applications can shed their own optional logging
+// before the pipeline starts dropping records. fn runs on the logging goroutine and must be quick;
+// a nil fn removes the callback
+//
+// Usage:
+//
+//	var shedDebug atomic.Bool
+//	logbundle.OnPressure(0.8, func(score float64, overloaded bool) {
+//	    shedDebug.Store(overloaded)
+//	})
+func OnPressure(threshold float64, fn func(score float64, overloaded bool)) {
+	handler.SetPressureCallback(threshold, fn)
+}
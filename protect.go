@@ -0,0 +1,93 @@
+package logbundle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/internal/strutil"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// Protect calls fn and turns a panic inside it into an lgerr.Internal error instead of crashing the
+// goroutine: the panic is logged at Error with its location and stack, reported to Sentry when enabled
+// and counted in panics_recovered_total{source="protect"}. Errors returned by fn pass through unchanged.
+// Use it at library boundaries (plugins, reflection-heavy code) inside workers
+//
+// Usage:
+//
+//	err := logbundle.Protect(ctx, func() error {
+//	    return plugin.Handle(ctx, msg)
+//	})
+func Protect(ctx context.Context, fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		err = protectPanic(ctx, r)
+	}()
+	return fn()
+}
+
+// protectPanic builds, logs and reports the error of a recovered panic
+// It must run in the deferred function so the live stack still contains the panicking frames
+func protectPanic(ctx context.Context, r any) *lgerr.Error {
+	stackTrace := string(debug.Stack())
+	errorLoc, _, _ := core.CallerErrorLocation()
+
+	msg := fmt.Sprintf("panic: %v", r)
+	cause, isErr := r.(error)
+	if isErr {
+		// Error() renders the wrapped panic error after the message
+		msg = "panic"
+	}
+
+	lgErr := lgerr.Internal(msg,
+		lgerr.WithContext("panic_value", fmt.Sprintf("%v", r)),
+		lgerr.WithContext("error_location", errorLoc),
+		lgerr.WithFingerprint("protect_panic", errorLoc),
+	)
+	if isErr {
+		lgErr.Wrap(cause)
+	}
+
+	metrics.IncCounter("panics_recovered_total", metrics.Labels{"source": "protect"})
+
+	attrs := []any{
+		slog.Any("panic_value", r),
+		slog.String("error_location", errorLoc),
+		slog.String("stack_trace", strutil.Truncate(stackTrace, 5000)),
+	}
+
+	if config.FromContext(ctx).SentryEnabled() {
+		hub := sentryHub(ctx)
+		hub.WithScope(func(scope *sentry.Scope) {
+			scope.SetTags(core.SentryScopeTags(ctx))
+			scope.SetLevel(sentry.LevelError)
+			scope.SetTag("error_source", "protect")
+			scope.SetTag("panic_recovered", "true")
+			scope.SetContext("panic_details", map[string]any{
+				"recovered_value": fmt.Sprintf("%v", r),
+				"stack_trace":     strutil.Truncate(stackTrace, 5000),
+				"error_location":  errorLoc,
+			})
+			scope.SetFingerprint(lgErr.Fingerprint())
+			if eventID := hub.CaptureException(lgErr); eventID != nil {
+				attrs = append(attrs, slog.String("sentry_event_id", string(*eventID)))
+			}
+		})
+	}
+
+	GetLogger().ErrorContext(ctx, "Recovered panic", attrs...)
+	return lgErr
+}
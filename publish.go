@@ -0,0 +1,245 @@
+package logbundle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/fields"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// PublishFailure describes a failed event publication (outbox relay, message bus, webhook)
+type PublishFailure struct {
+	// Destination is the topic, queue or endpoint the event was published to
+	Destination string
+	EventType   string
+	AggregateID string
+	// EventID identifies the event, e.g. the outbox row (optional)
+	EventID string
+	// RetryCount is the number of earlier failed attempts (0 for the first failure)
+	RetryCount int
+	// Final marks a failure that will not be retried (e.g. the event moved to a dead-letter table)
+	Final bool
+}
+
+// attrs returns the standardized attributes of the failure
+func (f PublishFailure) attrs() []any {
+	attrs := []any{
+		fields.Destination(f.Destination),
+		fields.EventType(f.EventType),
+		fields.AggregateID(f.AggregateID),
+		fields.RetryCount(f.RetryCount),
+	}
+	if f.EventID != "" {
+		attrs = append(attrs, slog.String("event_id", f.EventID))
+	}
+	return attrs
+}
+
+// LogPublishFailure logs a failed event publication with destination, event_type, aggregate_id and
+// retry_count, and counts it in event_publish_failures_total{destination,event_type,final}. Retried
+// failures are logged at Warn, or folded into the periodic report while a summary runs (see
+// StartPublishFailureSummary). Final failures are logged at Error and reported to Sentry, grouped by
+// destination and event type; the returned event ID is nil otherwise
+//
+// Usage:
+//
+//	if err := bus.Publish(ctx, row.Topic, row.Payload); err != nil {
+//	    logbundle.LogPublishFailure(ctx, logbundle.PublishFailure{
+//	        Destination: row.Topic,
+//	        EventType:   row.Type,
+//	        AggregateID: row.AggregateID,
+//	        RetryCount:  row.Attempts,
+//	        Final:       row.Attempts+1 >= maxAttempts,
+//	    }, err)
+//	}
+func LogPublishFailure(ctx context.Context, f PublishFailure, err error) *sentry.EventID {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	metrics.IncCounter("event_publish_failures_total", metrics.Labels{
+		"destination": f.Destination,
+		"event_type":  f.EventType,
+		"final":       fmt.Sprint(f.Final),
+	})
+
+	if !f.Final {
+		if summary := currentPublishSummary(); summary != nil {
+			summary.record(f, err)
+			return nil
+		}
+		GetLogger().WarnContext(ctx, "Event publication failed", append(f.attrs(), fields.Err(err))...)
+		return nil
+	}
+
+	attrs := append(f.attrs(), fields.Err(err))
+	var eventID *sentry.EventID
+	if config.FromContext(ctx).SentryEnabled() {
+		hub := sentryHub(ctx)
+		hub.WithScope(func(scope *sentry.Scope) {
+			scope.SetTags(core.SentryScopeTags(ctx))
+			scope.SetLevel(sentry.LevelError)
+			scope.SetTag("error_source", "event_publish")
+			scope.SetTag("destination", f.Destination)
+			scope.SetTag("event_type", f.EventType)
+			scope.SetContext("publication", map[string]any{
+				"destination":  f.Destination,
+				"event_type":   f.EventType,
+				"aggregate_id": f.AggregateID,
+				"event_id":     f.EventID,
+				"retry_count":  f.RetryCount,
+			})
+			scope.SetFingerprint([]string{"event_publish_failed", f.Destination, f.EventType})
+			eventID = hub.CaptureException(fmt.Errorf("publish %s to %s: %w", f.EventType, f.Destination, err))
+		})
+	}
+	if eventID != nil {
+		attrs = append(attrs, fields.SentryEventID(string(*eventID)))
+	}
+
+	GetLogger().ErrorContext(ctx, "Event publication failed permanently", attrs...)
+	return eventID
+}
+
+// sentryHub returns the hub bound to ctx, else a clone of the current hub, so scopes pushed for one report
+// never interleave with concurrent reports on the shared global hub
+func sentryHub(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return sentry.CurrentHub().Clone()
+}
+
+// destinationFailures accumulates the retried failures of a destination between reports
+type destinationFailures struct {
+	count      int
+	eventTypes map[string]int
+	aggregates map[string]struct{}
+	maxRetry   int
+	lastError  string
+}
+
+// publishSummary batches retried publication failures per destination
+type publishSummary struct {
+	mu           sync.Mutex
+	destinations map[string]*destinationFailures
+}
+
+var (
+	globalPublishSummary   *publishSummary
+	globalPublishSummaryMu sync.RWMutex
+)
+
+// StartPublishFailureSummary batches retried publication failures: instead of one Warn per failure,
+// LogPublishFailure counts them and every interval (default: 1 minute) a single Warn "Event publication
+// failures" is logged per failing destination, with the failure count, counts per event type, the
+// number of distinct aggregates, the highest retry count and the last error. Final failures are still
+// logged and reported one by one. Stop flushes the pending report
+//
+// Usage:
+//
+//	stop := logbundle.StartPublishFailureSummary(time.Minute)
+//	defer stop()
+func StartPublishFailureSummary(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	s := &publishSummary{destinations: make(map[string]*destinationFailures)}
+
+	globalPublishSummaryMu.Lock()
+	globalPublishSummary = s
+	globalPublishSummaryMu.Unlock()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.report()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			globalPublishSummaryMu.Lock()
+			if globalPublishSummary == s {
+				globalPublishSummary = nil
+			}
+			globalPublishSummaryMu.Unlock()
+			s.report()
+		})
+	}
+}
+
+// currentPublishSummary returns the running summary, or nil
+func currentPublishSummary() *publishSummary {
+	globalPublishSummaryMu.RLock()
+	defer globalPublishSummaryMu.RUnlock()
+	return globalPublishSummary
+}
+
+// record adds a retried failure to its destination
+func (s *publishSummary) record(f PublishFailure, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.destinations[f.Destination]
+	if !ok {
+		d = &destinationFailures{eventTypes: make(map[string]int), aggregates: make(map[string]struct{})}
+		s.destinations[f.Destination] = d
+	}
+	d.count++
+	d.eventTypes[f.EventType]++
+	if f.AggregateID != "" {
+		d.aggregates[f.AggregateID] = struct{}{}
+	}
+	d.maxRetry = max(d.maxRetry, f.RetryCount)
+	if err != nil {
+		d.lastError = err.Error()
+	}
+}
+
+// report logs and resets the accumulated failures, one record per destination in name order
+func (s *publishSummary) report() {
+	s.mu.Lock()
+	destinations := s.destinations
+	s.destinations = make(map[string]*destinationFailures)
+	s.mu.Unlock()
+
+	names := make([]string, 0, len(destinations))
+	for name := range destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	log := GetLogger()
+	for _, name := range names {
+		d := destinations[name]
+		log.Warn("Event publication failures",
+			fields.Destination(name),
+			slog.Int("failures", d.count),
+			slog.Any("event_types", d.eventTypes),
+			slog.Int("aggregates", len(d.aggregates)),
+			slog.Int("max_retry_count", d.maxRetry),
+			slog.String("last_error", d.lastError),
+		)
+	}
+}
@@ -0,0 +1,64 @@
+package logbundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+)
+
+func TestSentryHubNeverReturnsTheGlobalHub(t *testing.T) {
+	bound := sentry.NewHub(nil, sentry.NewScope())
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want func(*sentry.Hub) bool
+	}{
+		{name: "context hub", ctx: sentry.SetHubOnContext(context.Background(), bound), want: func(h *sentry.Hub) bool { return h == bound }},
+		{name: "no hub", ctx: context.Background(), want: func(h *sentry.Hub) bool { return h != nil && h != sentry.CurrentHub() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if hub := sentryHub(tt.ctx); !tt.want(hub) {
+				t.Fatalf("sentryHub returned %p (global %p)", hub, sentry.CurrentHub())
+			}
+		})
+	}
+}
+
+func TestLogPublishFailureReportsOnAClonedHub(t *testing.T) {
+	transport := &sentry.MockTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "http://public@localhost/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	global := sentry.CurrentHub()
+	global.BindClient(client)
+	t.Cleanup(func() { global.BindClient(nil) })
+
+	global.PushScope()
+	defer global.PopScope()
+	// Tags of the global scope are inherited by the clone, the publication tags are not written back
+	global.Scope().SetTag("request", "other")
+
+	settings := config.NewSettings()
+	settings.SetSentryEnabled(true)
+	ctx := config.WithSettings(context.Background(), settings)
+	LogPublishFailure(ctx, PublishFailure{Destination: "orders", EventType: "created", Final: true}, context.Canceled)
+
+	if len(transport.Events()) != 1 {
+		t.Fatalf("captured %d events, want 1", len(transport.Events()))
+	}
+	if tags := transport.Events()[0].Tags; tags["destination"] != "orders" || tags["request"] != "other" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	global.WithScope(func(scope *sentry.Scope) {
+		event := scope.ApplyToEvent(&sentry.Event{}, nil, client)
+		if _, leaked := event.Tags["destination"]; leaked {
+			t.Fatalf("publication tags leaked into the global scope: %v", event.Tags)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package logbundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+// RetryPolicy configures Retry
+type RetryPolicy struct {
+	// Operation names the retried call in logs and Sentry grouping (default: "operation")
+	Operation string
+	// Logger for attempts and the outcome (if nil, uses the default bundle logger)
+	Logger *slog.Logger
+	// MaxAttempts is the total number of attempts, including the first (default: 3)
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt (default: 100ms)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts (default: 5s)
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each attempt (default: 2)
+	Multiplier float64
+	// Jitter randomizes each backoff within [backoff/2, backoff] to spread retries from many callers
+	Jitter bool
+	// RetryIf decides whether an error is worth retrying (default: all errors except context.Canceled and
+	// context.DeadlineExceeded, so an attempt timing out on its own deadline inside fn is not retried)
+	RetryIf func(error) bool
+	// DisableSentry skips the Sentry event on failure
+	DisableSentry bool
+}
+
+// RetryAttempt describes one failed attempt
+type RetryAttempt struct {
+	Attempt  int
+	Error    string
+	Duration time.Duration
+	Backoff  time.Duration // Wait after this attempt (0 for the last one)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Operation == "" {
+		p.Operation = "operation"
+	}
+	if p.Logger == nil {
+		p.Logger = Default().Logger()
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.RetryIf == nil {
+		p.RetryIf = func(err error) bool {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+	return p
+}
+
+// Retry calls fn until it succeeds, the policy is exhausted, the error is not retryable or ctx is done
+// Each failed attempt is logged at Warn with its number, error and backoff. A success after failures is
+// logged at Info and the final failure at Error, both with the full attempt history. The final failure
+// sends a single Sentry event grouped by operation instead of one per attempt, subject to the same rules as
+// request errors: lgerr.WithIgnoreSentry and the minimum HTTP status of the error (see lgerr.FromError) are
+// honored, and nothing is sent once ctx is canceled or past its deadline. The last error is returned
+//
+// Usage:
+//
+//	err := logbundle.Retry(ctx, logbundle.RetryPolicy{Operation: "fetch_rates", MaxAttempts: 5},
+//	    func(ctx context.Context) error {
+//	        return ratesClient.Fetch(ctx)
+//	    })
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	p := policy.withDefaults()
+	log := p.Logger
+
+	var history []RetryAttempt
+	backoff := p.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		start := core.Now()
+		err := fn(ctx)
+		if err == nil {
+			if len(history) > 0 {
+				log.InfoContext(ctx, "Retry succeeded",
+					slog.String("operation", p.Operation),
+					slog.Int("attempts", attempt),
+					slog.Any("history", history),
+				)
+			}
+			return nil
+		}
+
+		entry := RetryAttempt{Attempt: attempt, Error: err.Error(), Duration: core.Since(start)}
+		retryable := p.RetryIf(err)
+		last := attempt >= p.MaxAttempts || !retryable
+
+		if !last {
+			entry.Backoff = backoff
+			if p.Jitter {
+				entry.Backoff = backoff/2 + rand.N(backoff/2+1)
+			}
+		}
+		history = append(history, entry)
+
+		if last {
+			retryFailed(ctx, p, history, err, retryable)
+			return err
+		}
+
+		log.WarnContext(ctx, "Retry attempt failed",
+			slog.String("operation", p.Operation),
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", p.MaxAttempts),
+			slog.Duration("backoff", entry.Backoff),
+			slog.String("error", err.Error()),
+		)
+
+		timer := time.NewTimer(entry.Backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			history[len(history)-1].Backoff = 0
+			retryFailed(ctx, p, history, err, false)
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(time.Duration(float64(backoff)*p.Multiplier), p.MaxBackoff)
+	}
+}
+
+// retryFailed logs the final failure and sends the consolidated Sentry event
+func retryFailed(ctx context.Context, p RetryPolicy, history []RetryAttempt, err error, retryable bool) {
+	reason := "exhausted"
+	if !retryable {
+		reason = "not_retryable"
+	}
+	if ctx.Err() != nil {
+		reason = "context_done"
+	}
+
+	p.Logger.ErrorContext(ctx, "Retry failed",
+		slog.String("operation", p.Operation),
+		slog.String("reason", reason),
+		slog.Int("attempts", len(history)),
+		slog.Any("history", history),
+		slog.String("error", err.Error()),
+	)
+
+	if p.DisableSentry || !shouldSendToSentry(ctx, err) {
+		return
+	}
+
+	hub := sentryHub(ctx)
+
+	attempts := make([]map[string]any, 0, len(history))
+	for _, a := range history {
+		attempts = append(attempts, map[string]any{
+			"attempt":  a.Attempt,
+			"error":    a.Error,
+			"duration": a.Duration.String(),
+			"backoff":  a.Backoff.String(),
+		})
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(core.SentryScopeTags(ctx))
+		scope.SetLevel(sentry.LevelError)
+		scope.SetTag("error_source", "retry")
+		scope.SetTag("retry_operation", p.Operation)
+		scope.SetTag("retry_reason", reason)
+		scope.SetContext("retry", map[string]any{
+			"operation":    p.Operation,
+			"attempts":     len(history),
+			"max_attempts": p.MaxAttempts,
+			"history":      attempts,
+		})
+		scope.SetFingerprint([]string{"retry_failed", p.Operation})
+		hub.CaptureException(fmt.Errorf("retry %s failed after %d attempts: %w", p.Operation, len(history), err))
+	})
+}
+
+// shouldSendToSentry applies the Sentry reporting rules of request errors to err: Sentry is enabled, ctx
+// is not done, err does not ignore Sentry and its HTTP status reaches the configured minimum
+func shouldSendToSentry(ctx context.Context, err error) bool {
+	settings := config.FromContext(ctx)
+	if !settings.SentryEnabled() {
+		return false
+	}
+
+	// Canceled work is the caller's decision, not a failure worth an event
+	if ctx.Err() != nil {
+		return false
+	}
+
+	lgErr := lgerr.FromError(err)
+	if lgErr.ShouldIgnoreSentry() {
+		return false
+	}
+
+	minStatus := settings.SentryMinHTTPStatus()
+	return minStatus == 0 || lgErr.HTTPStatus() >= minStatus
+}
@@ -0,0 +1,47 @@
+package logbundle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/config"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/integrations/lgerr"
+)
+
+func TestRetrySentryRules(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		enabled   bool
+		minStatus int
+		err       error
+		want      bool
+	}{
+		{name: "plain error", enabled: true, minStatus: 500, err: errors.New("down"), want: true},
+		{name: "sentry disabled", enabled: false, minStatus: 500, err: errors.New("down"), want: false},
+		{name: "ignore sentry", enabled: true, minStatus: 500, err: lgerr.Internal("down", lgerr.WithIgnoreSentry()), want: false},
+		{name: "below min status", enabled: true, minStatus: 500, err: lgerr.NotFound("order", 42), want: false},
+		{name: "min status zero reports all", enabled: true, minStatus: 0, err: lgerr.NotFound("order", 42), want: true},
+		{name: "deadline exceeded", enabled: true, minStatus: 500, err: context.DeadlineExceeded, want: true},
+		{name: "context done", ctx: canceled, enabled: true, minStatus: 500, err: errors.New("down"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config.NewSettings()
+			settings.SetSentryEnabled(tt.enabled)
+			settings.SetSentryMinHTTPStatus(tt.minStatus)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if got := shouldSendToSentry(config.WithSettings(ctx, settings), tt.err); got != tt.want {
+				t.Fatalf("shouldSendToSentry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package logbundle
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/aeternitas-infinita/logbundle-go/pkg/breaker"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/core"
+	"github.com/aeternitas-infinita/logbundle-go/pkg/metrics"
+)
+
+// SignalControlConfig holds configuration for signal-based log control
+type SignalControlConfig struct {
+	// Logger receives notifications and diagnostics (if nil, uses middleware logger or slog.Default())
+	Logger *slog.Logger
+	// DebugDuration is how long SIGUSR1 keeps Debug enabled (default: 10 minutes)
+	// Sending SIGUSR1 again restarts the period
+	DebugDuration time.Duration
+}
+
+// EnableSignalControl lets operators adjust logging without an admin endpoint:
+//   - SIGUSR1 switches all logbundle handlers to Debug for DebugDuration (see SetLevelOverride)
+//   - SIGUSR2 logs pipeline diagnostics (see LogDiagnostics)
+//
+// Returns a function that stops listening. On platforms without SIGUSR1/SIGUSR2 (Windows) it is a no-op
+//
+// Usage:
+//
+//	stop := logbundle.EnableSignalControl()
+//	defer stop()
+//
+//	// kill -USR1 <pid>  -> debug logging for 10 minutes
+//	// kill -USR2 <pid>  -> diagnostics dump
+func EnableSignalControl(cfg ...SignalControlConfig) (stop func()) {
+	var c SignalControlConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.DebugDuration <= 0 {
+		c.DebugDuration = 10 * time.Minute
+	}
+
+	if debugSignal == nil || diagnosticsSignal == nil {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, debugSignal, diagnosticsSignal)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				log := c.Logger
+				if log == nil {
+					log = Default().Logger()
+				}
+
+				if sig == debugSignal {
+					core.SetLevelOverride(slog.LevelDebug, c.DebugDuration)
+					log.Warn("Debug logging enabled by signal",
+						slog.String("signal", sig.String()),
+						slog.Duration("duration", c.DebugDuration),
+					)
+				} else {
+					LogDiagnostics(log)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// SetLevelOverride replaces the minimum level of every logbundle handler for ttl (ttl <= 0 means until reset)
+func SetLevelOverride(level slog.Level, ttl time.Duration) {
+	core.SetLevelOverride(level, ttl)
+}
+
+// ResetLevelOverride ends a temporary override, restoring the global or configured handler levels
+func ResetLevelOverride() {
+	core.ResetLevelOverride()
+}
+
+// LogDiagnostics logs the current state of the logging pipeline at Warn so it is always visible:
+// level override, per-module levels, Sentry settings, metric series count, circuit breakers and runtime metadata
+func LogDiagnostics(logger *slog.Logger) {
+	if logger == nil {
+		logger = Default().Logger()
+	}
+
+	args := []any{
+		slog.String("module_levels", core.GetModuleLevels()),
+		slog.Bool("middleware_logger_set", Default().MiddlewareLogger() != nil),
+		slog.Bool("sentry_enabled", Default().IsSentryEnabled()),
+		slog.Int("sentry_min_http_status", Default().SentryMinHTTPStatus()),
+		slog.Int("metric_series", len(metrics.Snapshot())),
+		slog.Int("goroutines", runtime.NumGoroutine()),
+	}
+
+	if level, ok := core.GetGlobalLevel(); ok {
+		args = append(args, slog.String("global_level", level.String()))
+	}
+	if level, expiresAt, ok := core.GetLevelOverride(); ok {
+		args = append(args, slog.String("level_override", level.String()))
+		if !expiresAt.IsZero() {
+			args = append(args, slog.Duration("level_override_remaining", time.Until(expiresAt).Round(time.Second)))
+		}
+	}
+
+	if breakers := breaker.Summary(); len(breakers) > 0 {
+		args = append(args, slog.Any("circuit_breakers", breakers))
+	}
+
+	for _, a := range core.RuntimeMetadataAttrs() {
+		args = append(args, a)
+	}
+
+	logger.Warn("Logging diagnostics", args...)
+}
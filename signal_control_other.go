@@ -0,0 +1,14 @@
+//go:build !unix
+
+package logbundle
+
+import "os"
+
+// SIGUSR1 and SIGUSR2 are not available, so signal control is disabled
+var (
+	debugSignal       os.Signal
+	diagnosticsSignal os.Signal
+)
+
+// reloadSignal is not available; WatchConfigFile relies on polling only
+var reloadSignal os.Signal
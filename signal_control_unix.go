@@ -0,0 +1,16 @@
+//go:build unix
+
+package logbundle
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	debugSignal       os.Signal = syscall.SIGUSR1
+	diagnosticsSignal os.Signal = syscall.SIGUSR2
+)
+
+// reloadSignal triggers a config file reload in WatchConfigFile
+var reloadSignal os.Signal = syscall.SIGHUP